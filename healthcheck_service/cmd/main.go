@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -72,10 +73,13 @@ func main() {
 
 	semaphore := make(chan struct{}, maxGoroutines)
 
+	// Only probe the servers matching this label selector, e.g. to split the fleet between several instances
+	labelSelector := env.GetEnv("HEALTHCHECK_LABEL_SELECTOR", "")
+
 	for {
 		logging.LogMessage("healthcheck_service", "Get all addresses of all servers", "INFO")
 
-		serverAddressesList, err := serverAdministrationGRPCClient.GetAddressAndStatus(context.Background(), &proto.EmptyRequest{})
+		serverAddressesList, err := serverAdministrationGRPCClient.GetAddressAndStatus(context.Background(), &proto.AddressRequest{LabelSelector: labelSelector})
 		if err != nil {
 			logging.LogMessage("healthcheck_service", "Failed to receive addresses and status of all servers, err: " + err.Error(), "ERROR")
 		} else {
@@ -93,24 +97,31 @@ func main() {
 					}()
 
 					server_id := serverAddress.ServerId
-					address := serverAddress.Address
-					status := serverAddress.Status
+					status := healthcheck.NormalizeStatus(serverAddress.Status)
+
+					// Try every address of the server, the primary one first
+					addresses := []string{}
+					for _, networkAddress := range serverAddress.Addresses {
+						addresses = append(addresses, networkAddress.Address)
+					}
+					if len(addresses) == 0 {
+						addresses = append(addresses, serverAddress.Address)
+					}
+					address := strings.Join(addresses, ", ")
 
 					logging.LogMessage("healthcheck_service", "Pinging server " + server_id + " at address " + address, "INFO")
-					newStatusBool, err := healthcheck.IsHostUp(address)
+					newStatus, answered, err := healthcheck.ProbeAddresses(addresses)
 					if err != nil {
 						logging.LogMessage("healthcheck_service", "Pinging server " + server_id + " at address " + address + " has error: " + err.Error(), "ERROR")
 					}
-
-					newStatus := "Off"
-					if newStatusBool {
-						newStatus = "On"
+					if answered != "" {
+						address = answered
 					}
 
 					logging.LogMessage("healthcheck_service", "Pinging server " + server_id + " at address " + address + " has status: " + newStatus, "INFO")
 
 					// Send message to Kafka if newStatus != status
-					if status != newStatus {
+					if healthcheck.IsStatusChange(status, newStatus) {
 						healthcheckResult := map[string]interface{}{
 							"server_id":	server_id,
 							"status":		newStatus,
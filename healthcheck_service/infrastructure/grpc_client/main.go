@@ -9,7 +9,7 @@ import (
 )
 
 type ServerAdministrationGRPCClient interface {
-	GetAddressAndStatus(ctx context.Context, req *proto.EmptyRequest) (*proto.IDAddressAndStatusList, error)
+	GetAddressAndStatus(ctx context.Context, req *proto.AddressRequest) (*proto.IDAddressAndStatusList, error)
 	UpdateStatus(ctx context.Context, req *proto.ServerStatusList) (*proto.EmptyResponse, error)
 }
 
@@ -17,7 +17,7 @@ type serverAdministrationGRPCClientWrapper struct {
 	client proto.ServerAdministrationServiceClient
 }
 
-func (w *serverAdministrationGRPCClientWrapper) GetAddressAndStatus(ctx context.Context, req *proto.EmptyRequest) (*proto.IDAddressAndStatusList, error) {
+func (w *serverAdministrationGRPCClientWrapper) GetAddressAndStatus(ctx context.Context, req *proto.AddressRequest) (*proto.IDAddressAndStatusList, error) {
 	return w.client.GetAddressAndStatus(ctx, req)
 }
 
@@ -2,13 +2,75 @@ package healthcheck
 
 import (
 	"os/exec"
+	"regexp"
+	"strconv"
 )
 
-func IsHostUp(ipv4 string) (bool, error) {
-	cmd := exec.Command("ping", "-c", "3", "-w", "5", ipv4)
-	err := cmd.Run()
-	if err != nil {
-		return false, err
+const (
+	StatusUp       = "Up"
+	StatusDegraded = "Degraded"
+	StatusDown     = "Down"
+
+	// StatusUnreachable is set by the server administration service on a Down server behind a down dependency
+	StatusUnreachable = "Unreachable"
+)
+
+var pingSummary = regexp.MustCompile(`(\d+) packets transmitted, (\d+) (?:packets )?received`)
+
+// ProbeHost pings an IPv4 address, IPv6 address or host name and reports it Up when every ping is answered,
+// Degraded when only some are and Down when none are.
+func ProbeHost(address string) (string, error) {
+	cmd := exec.Command("ping", "-c", "3", "-w", "5", address)
+	// ping exits with an error on any packet loss, the summary tells how much was lost
+	output, err := cmd.Output()
+
+	match := pingSummary.FindSubmatch(output)
+	if match == nil {
+		return StatusDown, err
+	}
+
+	transmitted, _ := strconv.Atoi(string(match[1]))
+	received, _ := strconv.Atoi(string(match[2]))
+	switch {
+	case received == 0:
+		return StatusDown, err
+	case received < transmitted:
+		return StatusDegraded, nil
+	}
+	return StatusUp, nil
+}
+
+// ProbeAddresses probes the addresses of a host in turn and stops at the first one that isn't Down,
+// returning its status and the address that answered. The host is Down when none of them answers.
+func ProbeAddresses(addresses []string) (string, string, error) {
+	var lastErr error
+	for _, address := range addresses {
+		status, err := ProbeHost(address)
+		if status != StatusDown {
+			return status, address, nil
+		}
+		lastErr = err
+	}
+	return StatusDown, "", lastErr
+}
+
+// NormalizeStatus maps the legacy On/Off statuses onto Up/Down.
+func NormalizeStatus(status string) string {
+	switch status {
+	case "On":
+		return StatusUp
+	case "Off":
+		return StatusDown
+	}
+	return status
+}
+
+// IsStatusChange tells whether a probed status differs from the stored one and has to be published.
+// An Unreachable server is already known to be down, so a Down probe of it isn't a change; the server
+// administration service moves it back to Down once the servers it depends on are back.
+func IsStatusChange(stored, probed string) bool {
+	if stored == StatusUnreachable && probed == StatusDown {
+		return false
 	}
-	return true, nil
-}
\ No newline at end of file
+	return stored != probed
+}
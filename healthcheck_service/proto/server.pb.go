@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
 // 	protoc-gen-go v1.36.6
-// 	protoc        v3.21.12
+// 	protoc        v3.12.4
 // source: proto/server.proto
 
 package proto
@@ -57,18 +57,125 @@ func (*EmptyRequest) Descriptor() ([]byte, []int) {
 	return file_proto_server_proto_rawDescGZIP(), []int{0}
 }
 
+// An empty label selector matches every server
+type AddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelSelector string                 `protobuf:"bytes,1,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressRequest) Reset() {
+	*x = AddressRequest{}
+	mi := &file_proto_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRequest) ProtoMessage() {}
+
+func (x *AddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRequest.ProtoReflect.Descriptor instead.
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddressRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+type NetworkAddress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Primary       bool                   `protobuf:"varint,3,opt,name=primary,proto3" json:"primary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAddress) Reset() {
+	*x = NetworkAddress{}
+	mi := &file_proto_server_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAddress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAddress) ProtoMessage() {}
+
+func (x *NetworkAddress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAddress.ProtoReflect.Descriptor instead.
+func (*NetworkAddress) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NetworkAddress) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NetworkAddress) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *NetworkAddress) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+// address is the primary address; addresses lists every address of the server, the primary one first
 type IDAddressAndStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
 	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Addresses     []*NetworkAddress      `protobuf:"bytes,4,rep,name=addresses,proto3" json:"addresses,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *IDAddressAndStatus) Reset() {
 	*x = IDAddressAndStatus{}
-	mi := &file_proto_server_proto_msgTypes[1]
+	mi := &file_proto_server_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -80,7 +187,7 @@ func (x *IDAddressAndStatus) String() string {
 func (*IDAddressAndStatus) ProtoMessage() {}
 
 func (x *IDAddressAndStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[1]
+	mi := &file_proto_server_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -93,7 +200,7 @@ func (x *IDAddressAndStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IDAddressAndStatus.ProtoReflect.Descriptor instead.
 func (*IDAddressAndStatus) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{1}
+	return file_proto_server_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *IDAddressAndStatus) GetServerId() string {
@@ -117,6 +224,13 @@ func (x *IDAddressAndStatus) GetStatus() string {
 	return ""
 }
 
+func (x *IDAddressAndStatus) GetAddresses() []*NetworkAddress {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
 type IDAddressAndStatusList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ServerList    []*IDAddressAndStatus  `protobuf:"bytes,1,rep,name=serverList,proto3" json:"serverList,omitempty"`
@@ -126,7 +240,7 @@ type IDAddressAndStatusList struct {
 
 func (x *IDAddressAndStatusList) Reset() {
 	*x = IDAddressAndStatusList{}
-	mi := &file_proto_server_proto_msgTypes[2]
+	mi := &file_proto_server_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -138,7 +252,7 @@ func (x *IDAddressAndStatusList) String() string {
 func (*IDAddressAndStatusList) ProtoMessage() {}
 
 func (x *IDAddressAndStatusList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[2]
+	mi := &file_proto_server_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -151,7 +265,7 @@ func (x *IDAddressAndStatusList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IDAddressAndStatusList.ProtoReflect.Descriptor instead.
 func (*IDAddressAndStatusList) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{2}
+	return file_proto_server_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *IDAddressAndStatusList) GetServerList() []*IDAddressAndStatus {
@@ -171,7 +285,7 @@ type ServerStatus struct {
 
 func (x *ServerStatus) Reset() {
 	*x = ServerStatus{}
-	mi := &file_proto_server_proto_msgTypes[3]
+	mi := &file_proto_server_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -183,7 +297,7 @@ func (x *ServerStatus) String() string {
 func (*ServerStatus) ProtoMessage() {}
 
 func (x *ServerStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[3]
+	mi := &file_proto_server_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -196,7 +310,7 @@ func (x *ServerStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerStatus.ProtoReflect.Descriptor instead.
 func (*ServerStatus) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{3}
+	return file_proto_server_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ServerStatus) GetServerId() string {
@@ -222,7 +336,7 @@ type ServerStatusList struct {
 
 func (x *ServerStatusList) Reset() {
 	*x = ServerStatusList{}
-	mi := &file_proto_server_proto_msgTypes[4]
+	mi := &file_proto_server_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -234,7 +348,7 @@ func (x *ServerStatusList) String() string {
 func (*ServerStatusList) ProtoMessage() {}
 
 func (x *ServerStatusList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[4]
+	mi := &file_proto_server_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -247,7 +361,7 @@ func (x *ServerStatusList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerStatusList.ProtoReflect.Descriptor instead.
 func (*ServerStatusList) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{4}
+	return file_proto_server_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ServerStatusList) GetStatusList() []*ServerStatus {
@@ -265,7 +379,7 @@ type EmptyResponse struct {
 
 func (x *EmptyResponse) Reset() {
 	*x = EmptyResponse{}
-	mi := &file_proto_server_proto_msgTypes[5]
+	mi := &file_proto_server_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -277,7 +391,7 @@ func (x *EmptyResponse) String() string {
 func (*EmptyResponse) ProtoMessage() {}
 
 func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[5]
+	mi := &file_proto_server_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -290,7 +404,7 @@ func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyResponse.ProtoReflect.Descriptor instead.
 func (*EmptyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{5}
+	return file_proto_server_proto_rawDescGZIP(), []int{7}
 }
 
 var File_proto_server_proto protoreflect.FileDescriptor
@@ -298,11 +412,18 @@ var File_proto_server_proto protoreflect.FileDescriptor
 const file_proto_server_proto_rawDesc = "" +
 	"\n" +
 	"\x12proto/server.proto\x12\x1dserver_administration_service\"\x0e\n" +
-	"\fEmptyRequest\"c\n" +
+	"\fEmptyRequest\"7\n" +
+	"\x0eAddressRequest\x12%\n" +
+	"\x0elabel_selector\x18\x01 \x01(\tR\rlabelSelector\"X\n" +
+	"\x0eNetworkAddress\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x18\n" +
+	"\aprimary\x18\x03 \x01(\bR\aprimary\"\xb0\x01\n" +
 	"\x12IDAddressAndStatus\x12\x1b\n" +
 	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\tR\x06status\"k\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12K\n" +
+	"\taddresses\x18\x04 \x03(\v2-.server_administration_service.NetworkAddressR\taddresses\"k\n" +
 	"\x16IDAddressAndStatusList\x12Q\n" +
 	"\n" +
 	"serverList\x18\x01 \x03(\v21.server_administration_service.IDAddressAndStatusR\n" +
@@ -314,9 +435,9 @@ const file_proto_server_proto_rawDesc = "" +
 	"\n" +
 	"statusList\x18\x01 \x03(\v2+.server_administration_service.ServerStatusR\n" +
 	"statusList\"\x0f\n" +
-	"\rEmptyResponse2\x87\x02\n" +
-	"\x1bServerAdministrationService\x12y\n" +
-	"\x13GetAddressAndStatus\x12+.server_administration_service.EmptyRequest\x1a5.server_administration_service.IDAddressAndStatusList\x12m\n" +
+	"\rEmptyResponse2\x89\x02\n" +
+	"\x1bServerAdministrationService\x12{\n" +
+	"\x13GetAddressAndStatus\x12-.server_administration_service.AddressRequest\x1a5.server_administration_service.IDAddressAndStatusList\x12m\n" +
 	"\fUpdateStatus\x12/.server_administration_service.ServerStatusList\x1a,.server_administration_service.EmptyResponseB\tZ\a./protob\x06proto3"
 
 var (
@@ -331,27 +452,30 @@ func file_proto_server_proto_rawDescGZIP() []byte {
 	return file_proto_server_proto_rawDescData
 }
 
-var file_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_proto_server_proto_goTypes = []any{
 	(*EmptyRequest)(nil),           // 0: server_administration_service.EmptyRequest
-	(*IDAddressAndStatus)(nil),     // 1: server_administration_service.IDAddressAndStatus
-	(*IDAddressAndStatusList)(nil), // 2: server_administration_service.IDAddressAndStatusList
-	(*ServerStatus)(nil),           // 3: server_administration_service.ServerStatus
-	(*ServerStatusList)(nil),       // 4: server_administration_service.ServerStatusList
-	(*EmptyResponse)(nil),          // 5: server_administration_service.EmptyResponse
+	(*AddressRequest)(nil),         // 1: server_administration_service.AddressRequest
+	(*NetworkAddress)(nil),         // 2: server_administration_service.NetworkAddress
+	(*IDAddressAndStatus)(nil),     // 3: server_administration_service.IDAddressAndStatus
+	(*IDAddressAndStatusList)(nil), // 4: server_administration_service.IDAddressAndStatusList
+	(*ServerStatus)(nil),           // 5: server_administration_service.ServerStatus
+	(*ServerStatusList)(nil),       // 6: server_administration_service.ServerStatusList
+	(*EmptyResponse)(nil),          // 7: server_administration_service.EmptyResponse
 }
 var file_proto_server_proto_depIdxs = []int32{
-	1, // 0: server_administration_service.IDAddressAndStatusList.serverList:type_name -> server_administration_service.IDAddressAndStatus
-	3, // 1: server_administration_service.ServerStatusList.statusList:type_name -> server_administration_service.ServerStatus
-	0, // 2: server_administration_service.ServerAdministrationService.GetAddressAndStatus:input_type -> server_administration_service.EmptyRequest
-	4, // 3: server_administration_service.ServerAdministrationService.UpdateStatus:input_type -> server_administration_service.ServerStatusList
-	2, // 4: server_administration_service.ServerAdministrationService.GetAddressAndStatus:output_type -> server_administration_service.IDAddressAndStatusList
-	5, // 5: server_administration_service.ServerAdministrationService.UpdateStatus:output_type -> server_administration_service.EmptyResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	2, // 0: server_administration_service.IDAddressAndStatus.addresses:type_name -> server_administration_service.NetworkAddress
+	3, // 1: server_administration_service.IDAddressAndStatusList.serverList:type_name -> server_administration_service.IDAddressAndStatus
+	5, // 2: server_administration_service.ServerStatusList.statusList:type_name -> server_administration_service.ServerStatus
+	1, // 3: server_administration_service.ServerAdministrationService.GetAddressAndStatus:input_type -> server_administration_service.AddressRequest
+	6, // 4: server_administration_service.ServerAdministrationService.UpdateStatus:input_type -> server_administration_service.ServerStatusList
+	4, // 5: server_administration_service.ServerAdministrationService.GetAddressAndStatus:output_type -> server_administration_service.IDAddressAndStatusList
+	7, // 6: server_administration_service.ServerAdministrationService.UpdateStatus:output_type -> server_administration_service.EmptyResponse
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proto_server_proto_init() }
@@ -365,7 +489,7 @@ func file_proto_server_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_server_proto_rawDesc), len(file_proto_server_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -27,7 +27,7 @@ const (
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ServerAdministrationServiceClient interface {
-	GetAddressAndStatus(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error)
+	GetAddressAndStatus(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error)
 	UpdateStatus(ctx context.Context, in *ServerStatusList, opts ...grpc.CallOption) (*EmptyResponse, error)
 }
 
@@ -39,7 +39,7 @@ func NewServerAdministrationServiceClient(cc grpc.ClientConnInterface) ServerAdm
 	return &serverAdministrationServiceClient{cc}
 }
 
-func (c *serverAdministrationServiceClient) GetAddressAndStatus(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error) {
+func (c *serverAdministrationServiceClient) GetAddressAndStatus(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(IDAddressAndStatusList)
 	err := c.cc.Invoke(ctx, ServerAdministrationService_GetAddressAndStatus_FullMethodName, in, out, cOpts...)
@@ -63,7 +63,7 @@ func (c *serverAdministrationServiceClient) UpdateStatus(ctx context.Context, in
 // All implementations must embed UnimplementedServerAdministrationServiceServer
 // for forward compatibility.
 type ServerAdministrationServiceServer interface {
-	GetAddressAndStatus(context.Context, *EmptyRequest) (*IDAddressAndStatusList, error)
+	GetAddressAndStatus(context.Context, *AddressRequest) (*IDAddressAndStatusList, error)
 	UpdateStatus(context.Context, *ServerStatusList) (*EmptyResponse, error)
 	mustEmbedUnimplementedServerAdministrationServiceServer()
 }
@@ -75,7 +75,7 @@ type ServerAdministrationServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedServerAdministrationServiceServer struct{}
 
-func (UnimplementedServerAdministrationServiceServer) GetAddressAndStatus(context.Context, *EmptyRequest) (*IDAddressAndStatusList, error) {
+func (UnimplementedServerAdministrationServiceServer) GetAddressAndStatus(context.Context, *AddressRequest) (*IDAddressAndStatusList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAddressAndStatus not implemented")
 }
 func (UnimplementedServerAdministrationServiceServer) UpdateStatus(context.Context, *ServerStatusList) (*EmptyResponse, error) {
@@ -104,7 +104,7 @@ func RegisterServerAdministrationServiceServer(s grpc.ServiceRegistrar, srv Serv
 }
 
 func _ServerAdministrationService_GetAddressAndStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyRequest)
+	in := new(AddressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -116,7 +116,7 @@ func _ServerAdministrationService_GetAddressAndStatus_Handler(srv interface{}, c
 		FullMethod: ServerAdministrationService_GetAddressAndStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServerAdministrationServiceServer).GetAddressAndStatus(ctx, req.(*EmptyRequest))
+		return srv.(ServerAdministrationServiceServer).GetAddressAndStatus(ctx, req.(*AddressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
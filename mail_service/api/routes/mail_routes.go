@@ -8,6 +8,13 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func RegisterRoutes(r *mux.Router, mailHandler handler.MailHandler) {
+func RegisterRoutes(r *mux.Router, mailHandler handler.MailHandler, webhookHandler handler.WebhookHandler) {
 	r.Handle("/send", middlewares.AdminMiddleware(http.HandlerFunc(mailHandler.SendEmail))).Methods("POST")
-}
\ No newline at end of file
+
+	r.Handle("/webhooks", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.CreateSubscription))).Methods("POST")
+	r.Handle("/webhooks", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.GetSubscriptions))).Methods("GET")
+	r.Handle("/webhooks/{id}", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.GetSubscription))).Methods("GET")
+	r.Handle("/webhooks/{id}", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.UpdateSubscription))).Methods("PUT")
+	r.Handle("/webhooks/{id}", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.DeleteSubscription))).Methods("DELETE")
+	r.Handle("/webhooks/{id}/deliveries", middlewares.AdminMiddleware(http.HandlerFunc(webhookHandler.GetDeliveries))).Methods("GET")
+}
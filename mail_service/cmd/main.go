@@ -5,14 +5,19 @@ import (
 	"mail_service/api/routes"
 	grpcclient "mail_service/infrastructure/grpc_client"
 	mailsending "mail_service/infrastructure/mail_sending"
+	"mail_service/infrastructure/postgres"
+	webhooksending "mail_service/infrastructure/webhook_sending"
 	"mail_service/internal/handler"
 	"mail_service/internal/repository"
 	"mail_service/internal/service"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/flashhhhh/pkg/env"
+	"github.com/flashhhhh/pkg/kafka"
 	"github.com/flashhhhh/pkg/logging"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
@@ -50,11 +55,64 @@ func main() {
 	mailService := service.NewMailService(mailSending, mailGRPCClientRepository)
 	mailHandler := handler.NewMailHandler(mailService)
 
+	// Connect to the database
+	dsn := "host=" + env.GetEnv("PG_HOST", "localhost") +
+		" user=" + env.GetEnv("PG_USER", "postgres") +
+		" password=" + env.GetEnv("PG_PASSWORD", "password") +
+		" dbname=" + env.GetEnv("PG_NAME", "mail_service_db") +
+		" port=" + env.GetEnv("PG_PORT", "5432") +
+		" sslmode=disable"
+	db := postgres.ConnectDB(dsn)
+
+	// Migrate the database
+	if environment == "local" {
+		logging.LogMessage("mail_service", "Running database migrations in local environment", "INFO")
+		postgres.Migrate(db)
+	} else {
+		logging.LogMessage("mail_service", "Skipping database migrations in non-local environment", "INFO")
+	}
+
+	// Initialize webhook notifications
+	webhookTimeout, _ := strconv.Atoi(env.GetEnv("WEBHOOK_TIMEOUT_SECONDS", "10"))
+	webhookRetryBackoff, _ := strconv.Atoi(env.GetEnv("WEBHOOK_RETRY_BACKOFF_MS", "1000"))
+
+	webhookSending := webhooksending.NewWebhookSending(time.Duration(webhookTimeout) * time.Second)
+	webhookRepository := repository.NewWebhookRepository(db)
+	incidentRepository := repository.NewIncidentRepository(db)
+	webhookService := service.NewWebhookService(webhookRepository, incidentRepository, mailGRPCClientRepository,
+		webhookSending, time.Duration(webhookRetryBackoff) * time.Millisecond)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+
+	// Webhooks are queued by the consumer and delivered here, so a slow subscriber never holds up the consumer
+	webhookPollInterval, _ := strconv.Atoi(env.GetEnv("WEBHOOK_POLL_INTERVAL_MS", "1000"))
+	go func() {
+		for {
+			// A full batch means more are likely due
+			if attempted, _ := webhookService.DeliverQueued(); attempted < service.WebhookBatchSize {
+				time.Sleep(time.Duration(webhookPollInterval) * time.Millisecond)
+			}
+		}
+	}()
+	webhookConsumerHandler := handler.NewWebhookConsumerHandler(webhookService)
+
+	// Consume status changes from the healthcheck pipeline
+	kafka_address := env.GetEnv("KAFKA_HOST", "localhost") + ":" + env.GetEnv("KAFKA_PORT", "9092")
+	kafka_topic := env.GetEnv("KAFKA_TOPIC", "healthcheck_topic")
+
+	logging.LogMessage("mail_service", "Connecting to Kafka brokers: "+kafka_address, "INFO")
+	consumerGroup, err := kafka.NewKafkaConsumerGroup([]string{kafka_address}, "mail_service_group", []string{kafka_topic})
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to connect to Kafka: "+err.Error(), "FATAL")
+		logging.LogMessage("mail_service", "Exiting the program...", "FATAL")
+		os.Exit(1)
+	}
+	consumerGroup.StartConsuming(webhookConsumerHandler)
+
 	mailServerHost := env.GetEnv("MAIL_SERVICE_HOST", "localhost")
 	mailServerPort := env.GetEnv("MAIL_SERVICE_PORT", "10003")
 
 	r := mux.NewRouter()
-	routes.RegisterRoutes(r, mailHandler)
+	routes.RegisterRoutes(r, mailHandler, webhookHandler)
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins, change this for security
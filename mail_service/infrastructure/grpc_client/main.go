@@ -10,6 +10,9 @@ import (
 
 type MailGRPCClient interface {
 	GetServersInformation(ctx context.Context, req *proto.TimeRequest) (*proto.ServersInformationResponse, error)
+	GetNotificationStatus(ctx context.Context, req *proto.ServerIDRequest) (*proto.NotificationStatus, error)
+	GetSLACompliance(ctx context.Context, req *proto.TimeRequest) (*proto.SLAComplianceList, error)
+	GetGroupsInformation(ctx context.Context, req *proto.TimeRequest) (*proto.GroupSummaryList, error)
 }
 
 type mailGRPCClientWrapper struct {
@@ -20,6 +23,18 @@ func (w *mailGRPCClientWrapper) GetServersInformation(ctx context.Context, req *
 	return w.client.GetServersInformation(ctx, req)
 }
 
+func (w *mailGRPCClientWrapper) GetNotificationStatus(ctx context.Context, req *proto.ServerIDRequest) (*proto.NotificationStatus, error) {
+	return w.client.GetNotificationStatus(ctx, req)
+}
+
+func (w *mailGRPCClientWrapper) GetSLACompliance(ctx context.Context, req *proto.TimeRequest) (*proto.SLAComplianceList, error) {
+	return w.client.GetSLACompliance(ctx, req)
+}
+
+func (w *mailGRPCClientWrapper) GetGroupsInformation(ctx context.Context, req *proto.TimeRequest) (*proto.GroupSummaryList, error) {
+	return w.client.GetGroupsInformation(ctx, req)
+}
+
 func StartGRPCClient() (MailGRPCClient, error) {
 	// Create a connection to the server.
 	conn, err := grpc.Dial(env.GetEnv("GRPC_SERVER_ADMINISTRATION_SERVER", "localhost") + ":" + env.GetEnv("GRPC_SERVER_ADMINISTRATION_PORT", "50052"), grpc.WithInsecure())
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"mail_service/internal/domain"
+	"os"
+
+	"github.com/flashhhhh/pkg/logging"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func ConnectDB(dsn string) *gorm.DB {
+	logging.LogMessage("mail_service", "Connecting to the database...", "INFO")
+	logging.LogMessage("mail_service", "Database connection string: "+dsn, "DEBUG")
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to connect to the database: "+err.Error(), "FATAL")
+		logging.LogMessage("mail_service", "Exiting the program...", "FATAL")
+		os.Exit(1)
+	}
+
+	logging.LogMessage("mail_service", "Connected to the database successfully", "INFO")
+	return db
+}
+
+func Migrate(db *gorm.DB) {
+	logging.LogMessage("mail_service", "Migrating the database...", "INFO")
+
+	err := db.AutoMigrate(&domain.WebhookSubscription{}, &domain.WebhookDelivery{}, &domain.QueuedWebhook{}, &domain.Incident{})
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to migrate the database: "+err.Error(), "FATAL")
+		logging.LogMessage("mail_service", "Exiting the program...", "FATAL")
+		os.Exit(1)
+	}
+
+	logging.LogMessage("mail_service", "Database migrated successfully", "INFO")
+}
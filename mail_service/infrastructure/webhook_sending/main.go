@@ -0,0 +1,68 @@
+package webhooksending
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+const SignatureHeader = "X-Webhook-Signature"
+
+type WebhookSending interface {
+	SendWebhook(url string, headers map[string]string, secret string, payload []byte) (int, error)
+}
+
+type webhookSending struct {
+	client *http.Client
+}
+
+func NewWebhookSending(timeout time.Duration) WebhookSending {
+	return &webhookSending{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of payload, prefixed with the algorithm name.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (ws *webhookSending) SendWebhook(url string, headers map[string]string, secret string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to build webhook request for "+url+". Err: "+err.Error(), "ERROR")
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, payload))
+	}
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to send webhook to "+url+". Err: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logging.LogMessage("mail_service", "Webhook "+url+" responded with status "+strconv.Itoa(resp.StatusCode), "ERROR")
+		return resp.StatusCode, errors.New("webhook responded with status " + strconv.Itoa(resp.StatusCode))
+	}
+
+	logging.LogMessage("mail_service", "Successfully send webhook to "+url, "INFO")
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,19 @@
+package domain
+
+// Server statuses reported by the healthcheck pipeline
+const (
+	StatusUp       = "Up"
+	StatusDegraded = "Degraded"
+	StatusDown     = "Down"
+)
+
+// NormalizeStatus maps the legacy On/Off statuses onto Up/Down.
+func NormalizeStatus(status string) string {
+	switch status {
+	case "On":
+		return StatusUp
+	case "Off":
+		return StatusDown
+	}
+	return status
+}
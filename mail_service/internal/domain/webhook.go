@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+const (
+	EventServerStatusChanged = "server.status_changed"
+	EventIncidentOpened      = "incident.opened"
+	EventIncidentResolved    = "incident.resolved"
+)
+
+type WebhookSubscription struct {
+	ID          string            `json:"id" gorm:"primaryKey;type:uuid"`
+	Name        string            `json:"name" gorm:"not null"`
+	URL         string            `json:"url" gorm:"not null"`
+	Headers     map[string]string `json:"headers" gorm:"serializer:json"`
+	Secret      string            `json:"-"`
+	Events      []string          `json:"events" gorm:"serializer:json"`
+	MaxRetries  int               `json:"max_retries" gorm:"not null;default:3"`
+	Enabled     bool              `json:"enabled" gorm:"not null;default:true"`
+	CreatedTime time.Time         `json:"created_time" gorm:"autoCreateTime"`
+	LastUpdated time.Time         `json:"last_updated" gorm:"autoUpdateTime"`
+}
+
+// Accepts reports whether the subscription wants the given event type.
+// An empty event list means the subscription receives every event.
+func (s *WebhookSubscription) Accepts(event string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string    `json:"subscription_id" gorm:"type:uuid;index;not null"`
+	Event          string    `json:"event" gorm:"not null"`
+	Payload        string    `json:"payload" gorm:"not null"`
+	Attempt        int       `json:"attempt" gorm:"not null"`
+	StatusCode     int       `json:"status_code"`
+	Success        bool      `json:"success" gorm:"not null"`
+	Error          string    `json:"error"`
+	CreatedTime    time.Time `json:"created_time" gorm:"autoCreateTime"`
+}
+
+// QueuedWebhook is a delivery of an event to a subscription waiting for its next attempt. It stays queued
+// until it succeeds or runs out of retries, so deliveries survive a restart.
+type QueuedWebhook struct {
+	ID             uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubscriptionID string `json:"subscription_id" gorm:"type:uuid;index;not null"`
+	Event          string `json:"event" gorm:"not null"`
+	Payload        string `json:"payload" gorm:"not null"`
+	// Attempt is how many attempts were made so far
+	Attempt       int       `json:"attempt" gorm:"not null;default:0"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index;not null"`
+	CreatedTime   time.Time `json:"created_time" gorm:"autoCreateTime"`
+}
+
+type Incident struct {
+	ID         string     `json:"id" gorm:"primaryKey;type:uuid"`
+	ServerID   string     `json:"server_id" gorm:"index;not null"`
+	OpenedAt   time.Time  `json:"opened_at" gorm:"not null"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	// Suppressed incidents were opened while notifications were suppressed, subscribers hear of neither end
+	Suppressed bool `json:"suppressed" gorm:"not null;default:false"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+type GroupSummary struct {
+	GroupID     string  `json:"group_id"`
+	Name        string  `json:"name"`
+	Path        string  `json:"path"`
+	Status      string  `json:"status"`
+	NumServers  int     `json:"num_servers"`
+	NumUp       int     `json:"num_up"`
+	NumDown     int     `json:"num_down"`
+	UpTimeRatio float64 `json:"uptime_ratio"`
+	SLATarget   float64 `json:"sla_target"`
+	Breached    bool    `json:"breached"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type SLACompliance struct {
+	ServerID             string  `json:"server_id"`
+	ServerName           string  `json:"server_name"`
+	SLATarget            float64 `json:"sla_target"`
+	Achieved             float64 `json:"achieved"`
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	BurnRate             float64 `json:"burn_rate"`
+	Breached             bool    `json:"breached"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+type WebhookEvent struct {
+	ID         string    `json:"id"`
+	Event      string    `json:"event"`
+	ServerID   string    `json:"server_id"`
+	Status     string    `json:"status"`
+	IncidentID string    `json:"incident_id,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
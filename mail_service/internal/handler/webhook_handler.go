@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mail_service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxRetries    = 3
+	defaultDeliveryLimit = 50
+)
+
+type WebhookHandler interface {
+	CreateSubscription(w http.ResponseWriter, r *http.Request)
+	GetSubscriptions(w http.ResponseWriter, r *http.Request)
+	GetSubscription(w http.ResponseWriter, r *http.Request)
+	UpdateSubscription(w http.ResponseWriter, r *http.Request)
+	DeleteSubscription(w http.ResponseWriter, r *http.Request)
+	GetDeliveries(w http.ResponseWriter, r *http.Request)
+}
+
+type webhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) WebhookHandler {
+	return &webhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+type webhookSubscriptionRequest struct {
+	Name       *string            `json:"name"`
+	URL        *string            `json:"url"`
+	Headers    *map[string]string `json:"headers"`
+	Secret     *string            `json:"secret"`
+	Events     *[]string          `json:"events"`
+	MaxRetries *int               `json:"max_retries"`
+	Enabled    *bool              `json:"enabled"`
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (h *webhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var requestBody webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		logging.LogMessage("mail_service", "Failed to decode request body for request CreateSubscription: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Name == nil || requestBody.URL == nil {
+		logging.LogMessage("mail_service", "Missing 'name' or 'url' in webhook subscription", "ERROR")
+		http.Error(w, "Missing 'name' or 'url'", http.StatusBadRequest)
+		return
+	}
+
+	var headers map[string]string
+	if requestBody.Headers != nil {
+		headers = *requestBody.Headers
+	}
+	secret := ""
+	if requestBody.Secret != nil {
+		secret = *requestBody.Secret
+	}
+	var events []string
+	if requestBody.Events != nil {
+		events = *requestBody.Events
+	}
+	maxRetries := defaultMaxRetries
+	if requestBody.MaxRetries != nil {
+		maxRetries = *requestBody.MaxRetries
+	}
+
+	id, err := h.webhookService.CreateSubscription(*requestBody.Name, *requestBody.URL, headers, secret, events, maxRetries)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to create webhook subscription: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to create webhook subscription: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logging.LogMessage("mail_service", "Webhook subscription created successfully with ID: "+id, "INFO")
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Webhook subscription created successfully",
+		"id":      id,
+	})
+}
+
+func (h *webhookHandler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.GetSubscriptions()
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get webhook subscriptions: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get webhook subscriptions", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptions)
+}
+
+func (h *webhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	subscription, err := h.webhookService.GetSubscription(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Webhook subscription not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get webhook subscription "+id+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get webhook subscription", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscription)
+}
+
+func (h *webhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var requestBody webhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		logging.LogMessage("mail_service", "Failed to decode request body for request UpdateSubscription: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updatedData := make(map[string]interface{})
+	if requestBody.Name != nil {
+		updatedData["name"] = *requestBody.Name
+	}
+	if requestBody.URL != nil {
+		updatedData["url"] = *requestBody.URL
+	}
+	if requestBody.Headers != nil {
+		updatedData["headers"] = *requestBody.Headers
+	}
+	if requestBody.Secret != nil {
+		updatedData["secret"] = *requestBody.Secret
+	}
+	if requestBody.Events != nil {
+		updatedData["events"] = *requestBody.Events
+	}
+	if requestBody.MaxRetries != nil {
+		updatedData["max_retries"] = *requestBody.MaxRetries
+	}
+	if requestBody.Enabled != nil {
+		updatedData["enabled"] = *requestBody.Enabled
+	}
+
+	err := h.webhookService.UpdateSubscription(id, updatedData)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Webhook subscription not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to update webhook subscription "+id+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to update webhook subscription: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	logging.LogMessage("mail_service", "Webhook subscription updated successfully with ID: "+id, "INFO")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Webhook subscription updated successfully"))
+}
+
+func (h *webhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	err := h.webhookService.DeleteSubscription(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Webhook subscription not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to delete webhook subscription "+id+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to delete webhook subscription", http.StatusInternalServerError)
+		return
+	}
+
+	logging.LogMessage("mail_service", "Webhook subscription deleted successfully with ID: "+id, "INFO")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Webhook subscription deleted successfully"))
+}
+
+func (h *webhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	limit := defaultDeliveryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			logging.LogMessage("mail_service", "Invalid 'limit' query parameter: "+limitStr, "ERROR")
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(id, limit)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get deliveries of webhook subscription "+id+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get webhook deliveries", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,207 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"mail_service/internal/domain"
+	"mail_service/internal/dto"
+	"mail_service/internal/handler"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// mockWebhookService implements service.WebhookService for testing
+type mockWebhookService struct {
+	mock.Mock
+}
+
+func (m *mockWebhookService) CreateSubscription(name, webhookURL string, headers map[string]string, secret string, events []string, maxRetries int) (string, error) {
+	args := m.Called(name, webhookURL, headers, secret, events, maxRetries)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockWebhookService) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *mockWebhookService) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *mockWebhookService) UpdateSubscription(id string, updatedData map[string]interface{}) error {
+	args := m.Called(id, updatedData)
+	return args.Error(0)
+}
+
+func (m *mockWebhookService) DeleteSubscription(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockWebhookService) GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockWebhookService) HandleStatusChange(serverID, status string, timestamp time.Time) error {
+	args := m.Called(serverID, status, timestamp)
+	return args.Error(0)
+}
+
+func (m *mockWebhookService) Dispatch(event dto.WebhookEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *mockWebhookService) DeliverQueued() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestCreateSubscription_Success(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	body := `{"name":"chat","url":"https://chat.example.com/hook","headers":{"X-Token":"abc"},"secret":"s3cret","events":["incident.opened"]}`
+	mockSvc.On("CreateSubscription", "chat", "https://chat.example.com/hook", map[string]string{"X-Token": "abc"},
+		"s3cret", []string{"incident.opened"}, 3).Return("sub-1", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp map[string]interface{}
+	json.NewDecoder(rr.Body).Decode(&resp)
+	assert.Equal(t, "sub-1", resp["id"])
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateSubscription_MissingURL(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"name":"chat"}`))
+	rr := httptest.NewRecorder()
+	h.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestCreateSubscription_ServiceError(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("CreateSubscription", "chat", "not a url", map[string]string(nil), "", []string(nil), 3).
+		Return("", errors.New("invalid webhook url: not a url"))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"name":"chat","url":"not a url"}`))
+	rr := httptest.NewRecorder()
+	h.CreateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid webhook url")
+}
+
+func TestGetSubscription_NotFound(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("GetSubscription", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/webhooks/missing", nil), map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.GetSubscription(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetSubscriptions_Success(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("GetSubscriptions").Return([]domain.WebhookSubscription{{ID: "sub-1", Name: "chat", Secret: "hidden"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	rr := httptest.NewRecorder()
+	h.GetSubscriptions(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "sub-1")
+	assert.NotContains(t, rr.Body.String(), "hidden")
+}
+
+func TestUpdateSubscription_Success(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("UpdateSubscription", "sub-1", map[string]interface{}{
+		"enabled":     false,
+		"max_retries": 5,
+	}).Return(nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/webhooks/sub-1", strings.NewReader(`{"enabled":false,"max_retries":5}`)),
+		map[string]string{"id": "sub-1"})
+	rr := httptest.NewRecorder()
+	h.UpdateSubscription(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteSubscription_NotFound(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("DeleteSubscription", "missing").Return(gorm.ErrRecordNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/webhooks/missing", nil), map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.DeleteSubscription(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetDeliveries_InvalidLimit(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/webhooks/sub-1/deliveries?limit=abc", nil), map[string]string{"id": "sub-1"})
+	rr := httptest.NewRecorder()
+	h.GetDeliveries(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetDeliveries_Success(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookHandler(mockSvc)
+
+	mockSvc.On("GetDeliveries", "sub-1", 50).Return([]domain.WebhookDelivery{{ID: 1, SubscriptionID: "sub-1", Success: true}}, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/webhooks/sub-1/deliveries", nil), map[string]string{"id": "sub-1"})
+	rr := httptest.NewRecorder()
+	h.GetDeliveries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"mail_service/internal/service"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/flashhhhh/pkg/logging"
+)
+
+type WebhookConsumerHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookConsumerHandler(webhookService service.WebhookService) *WebhookConsumerHandler {
+	return &WebhookConsumerHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h WebhookConsumerHandler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h WebhookConsumerHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h WebhookConsumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	// Messages are handled one at a time so incidents of a server are opened and resolved in order
+	for message := range claim.Messages() {
+		logging.LogMessage("mail_service", "Received message: "+string(message.Value), "INFO")
+
+		var statusMessage struct {
+			ServerID string `json:"server_id"`
+			Status   string `json:"status"`
+		}
+
+		if err := json.Unmarshal(message.Value, &statusMessage); err != nil {
+			logging.LogMessage("mail_service", "Error parsing message: "+err.Error(), "ERROR")
+			session.MarkMessage(message, "")
+			continue
+		}
+
+		timestamp := message.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		if err := h.webhookService.HandleStatusChange(statusMessage.ServerID, statusMessage.Status, timestamp); err != nil {
+			logging.LogMessage("mail_service", "Failed to notify webhooks for server id: "+statusMessage.ServerID+
+				", err: "+err.Error(), "ERROR")
+		}
+
+		session.MarkMessage(message, "")
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"mail_service/internal/handler"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockConsumerGroupSession struct {
+	mock.Mock
+}
+
+func (m *mockConsumerGroupSession) Claims() map[string][]int32 { return nil }
+func (m *mockConsumerGroupSession) MemberID() string           { return "" }
+func (m *mockConsumerGroupSession) GenerationID() int32        { return 0 }
+func (m *mockConsumerGroupSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (m *mockConsumerGroupSession) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+}
+func (m *mockConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	m.Called(msg, metadata)
+}
+func (m *mockConsumerGroupSession) Context() context.Context { return nil }
+func (m *mockConsumerGroupSession) Commit()                  {}
+
+type mockConsumerGroupClaim struct {
+	messages chan *sarama.ConsumerMessage
+}
+
+func (m *mockConsumerGroupClaim) Topic() string              { return "test-topic" }
+func (m *mockConsumerGroupClaim) Partition() int32           { return 0 }
+func (m *mockConsumerGroupClaim) InitialOffset() int64       { return 0 }
+func (m *mockConsumerGroupClaim) HighWaterMarkOffset() int64 { return 0 }
+func (m *mockConsumerGroupClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return m.messages
+}
+
+func TestWebhookConsumeClaim(t *testing.T) {
+	mockSvc := new(mockWebhookService)
+	h := handler.NewWebhookConsumerHandler(mockSvc)
+
+	mockSession := new(mockConsumerGroupSession)
+	mockClaim := &mockConsumerGroupClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+
+	timestamp := time.Date(2025, 6, 24, 10, 0, 0, 0, time.UTC)
+	valid := &sarama.ConsumerMessage{Value: []byte(`{"server_id":"srv-1","status":"Off"}`), Timestamp: timestamp}
+	invalid := &sarama.ConsumerMessage{Value: []byte(`not json`)}
+
+	mockSvc.On("HandleStatusChange", "srv-1", "Off", timestamp).Return(errors.New("db error"))
+	mockSession.On("MarkMessage", valid, "").Return()
+	mockSession.On("MarkMessage", invalid, "").Return()
+
+	mockClaim.messages <- valid
+	mockClaim.messages <- invalid
+	close(mockClaim.messages)
+
+	err := h.ConsumeClaim(mockSession, mockClaim)
+	assert.NoError(t, err)
+	mockSvc.AssertExpectations(t)
+	mockSession.AssertExpectations(t)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	grpcclient "mail_service/infrastructure/grpc_client"
+	"mail_service/internal/dto"
 	"mail_service/proto"
 
 	"github.com/flashhhhh/pkg/logging"
@@ -10,6 +11,9 @@ import (
 
 type MailGRPCClientRepository interface {
 	GetServersInformation(startTime, endTime string) (int, int, int, float64, error)
+	GetNotificationStatus(serverID string) (bool, string, error)
+	GetSLACompliance(startTime, endTime string) ([]dto.SLACompliance, error)
+	GetGroupSummaries(startTime, endTime string) ([]dto.GroupSummary, error)
 }
 
 type mailGRPCClientRepository struct {
@@ -35,4 +39,75 @@ func (r *mailGRPCClientRepository) GetServersInformation(startTime, endTime stri
 
 	logging.LogMessage("mail_service", "Get server information from Server Administration's GRPC server successfully!", "INFO")
 	return int(resp.NumServers), int(resp.NumOnServers), int(resp.NumOffServers), resp.MeanUpTimeRatio, nil
-}
\ No newline at end of file
+}
+
+// GetNotificationStatus asks Server Administration whether notifications about a server are currently suppressed, and why.
+func (r *mailGRPCClientRepository) GetNotificationStatus(serverID string) (bool, string, error) {
+	resp, err := r.mailGRPCClient.GetNotificationStatus(context.Background(), &proto.ServerIDRequest{
+		ServerId: serverID,
+	})
+
+	if err != nil {
+		logging.LogMessage("mail_service", "Cannot get notification status of server " + serverID + " from Server Administration's GRPC server. Err: " + err.Error(), "ERROR")
+		return false, "", err
+	}
+
+	return resp.Suppressed, resp.Reason, nil
+}
+
+func (r *mailGRPCClientRepository) GetSLACompliance(startTime, endTime string) ([]dto.SLACompliance, error) {
+	resp, err := r.mailGRPCClient.GetSLACompliance(context.Background(), &proto.TimeRequest{
+		StartTime: startTime,
+		EndTime: endTime,
+	})
+
+	if err != nil {
+		logging.LogMessage("mail_service", "Cannot get SLA compliance from Server Administration's GRPC server. Err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+
+	compliances := make([]dto.SLACompliance, 0, len(resp.ComplianceList))
+	for _, compliance := range resp.ComplianceList {
+		compliances = append(compliances, dto.SLACompliance{
+			ServerID: compliance.ServerId,
+			ServerName: compliance.ServerName,
+			SLATarget: compliance.SlaTarget,
+			Achieved: compliance.Achieved,
+			ErrorBudgetRemaining: compliance.ErrorBudgetRemaining,
+			BurnRate: compliance.BurnRate,
+			Breached: compliance.Breached,
+		})
+	}
+
+	return compliances, nil
+}
+
+func (r *mailGRPCClientRepository) GetGroupSummaries(startTime, endTime string) ([]dto.GroupSummary, error) {
+	resp, err := r.mailGRPCClient.GetGroupsInformation(context.Background(), &proto.TimeRequest{
+		StartTime: startTime,
+		EndTime: endTime,
+	})
+
+	if err != nil {
+		logging.LogMessage("mail_service", "Cannot get group summaries from Server Administration's GRPC server. Err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+
+	summaries := make([]dto.GroupSummary, 0, len(resp.GroupList))
+	for _, group := range resp.GroupList {
+		summaries = append(summaries, dto.GroupSummary{
+			GroupID: group.GroupId,
+			Name: group.Name,
+			Path: group.Path,
+			Status: group.Status,
+			NumServers: int(group.NumServers),
+			NumUp: int(group.NumUp),
+			NumDown: int(group.NumDown),
+			UpTimeRatio: group.UptimeRatio,
+			SLATarget: group.SlaTarget,
+			Breached: group.Breached,
+		})
+	}
+
+	return summaries, nil
+}
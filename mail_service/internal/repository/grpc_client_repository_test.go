@@ -25,6 +25,30 @@ func (m *mockMailGRPCClient) GetServersInformation(ctx context.Context, req *pro
 	return args.Get(0).(*proto.ServersInformationResponse), args.Error(1)
 }
 
+func (m *mockMailGRPCClient) GetNotificationStatus(ctx context.Context, req *proto.ServerIDRequest) (*proto.NotificationStatus, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*proto.NotificationStatus), args.Error(1)
+}
+
+func (m *mockMailGRPCClient) GetSLACompliance(ctx context.Context, req *proto.TimeRequest) (*proto.SLAComplianceList, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*proto.SLAComplianceList), args.Error(1)
+}
+
+func (m *mockMailGRPCClient) GetGroupsInformation(ctx context.Context, req *proto.TimeRequest) (*proto.GroupSummaryList, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*proto.GroupSummaryList), args.Error(1)
+}
+
 func TestMailGRPCClientRepository_GetServersInformation_Success(t *testing.T) {
 	mockClient := new(mockMailGRPCClient)
 	repo := repository.NewMailGRPCClientRepository(mockClient)
@@ -79,4 +103,99 @@ func TestMailGRPCClientRepository_GetServersInformation_Error(t *testing.T) {
 	assert.Equal(t, 0.0, meanRatio)
 	assert.Equal(t, expectedErr, err)
 	mockClient.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestMailGRPCClientRepository_GetNotificationStatus_Success(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetNotificationStatus", mock.Anything, &proto.ServerIDRequest{ServerId: "srv-1"}).
+		Return(&proto.NotificationStatus{Suppressed: true, Reason: "maintenance", MaintenanceWindowId: "window-1"}, nil).
+		Once()
+
+	suppressed, reason, err := repo.GetNotificationStatus("srv-1")
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+	assert.Equal(t, "maintenance", reason)
+	mockClient.AssertExpectations(t)
+}
+
+func TestMailGRPCClientRepository_GetNotificationStatus_Error(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetNotificationStatus", mock.Anything, &proto.ServerIDRequest{ServerId: "srv-1"}).
+		Return(nil, errors.New("unavailable")).
+		Once()
+
+	suppressed, _, err := repo.GetNotificationStatus("srv-1")
+	assert.Error(t, err)
+	assert.False(t, suppressed)
+}
+
+func TestMailGRPCClientRepository_GetSLACompliance_Success(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetSLACompliance", mock.Anything, &proto.TimeRequest{StartTime: "s", EndTime: "e"}).
+		Return(&proto.SLAComplianceList{ComplianceList: []*proto.SLACompliance{
+			{ServerId: "srv-1", SlaTarget: 99.9, Achieved: 98, Breached: true},
+		}}, nil).
+		Once()
+
+	compliances, err := repo.GetSLACompliance("s", "e")
+	assert.NoError(t, err)
+	if assert.Len(t, compliances, 1) {
+		assert.Equal(t, "srv-1", compliances[0].ServerID)
+		assert.True(t, compliances[0].Breached)
+	}
+}
+
+func TestMailGRPCClientRepository_GetSLACompliance_Error(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetSLACompliance", mock.Anything, &proto.TimeRequest{StartTime: "s", EndTime: "e"}).
+		Return(nil, errors.New("unavailable")).
+		Once()
+
+	_, err := repo.GetSLACompliance("s", "e")
+	assert.Error(t, err)
+}
+
+func TestMailGRPCClientRepository_GetGroupSummaries_Success(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetGroupsInformation", mock.Anything, &proto.TimeRequest{StartTime: "s", EndTime: "e"}).
+		Return(&proto.GroupSummaryList{GroupList: []*proto.GroupSummary{
+			{GroupId: "rack-1", Path: "dc-1 / rack-1", Status: "PartiallyDown", NumServers: 4, NumUp: 3, NumDown: 1, Breached: true},
+		}}, nil).
+		Once()
+
+	summaries, err := repo.GetGroupSummaries("s", "e")
+	assert.NoError(t, err)
+	if assert.Len(t, summaries, 1) {
+		assert.Equal(t, "dc-1 / rack-1", summaries[0].Path)
+		assert.Equal(t, 3, summaries[0].NumUp)
+		assert.True(t, summaries[0].Breached)
+	}
+}
+
+func TestMailGRPCClientRepository_GetGroupSummaries_Error(t *testing.T) {
+	mockClient := new(mockMailGRPCClient)
+	repo := repository.NewMailGRPCClientRepository(mockClient)
+
+	mockClient.
+		On("GetGroupsInformation", mock.Anything, &proto.TimeRequest{StartTime: "s", EndTime: "e"}).
+		Return(nil, errors.New("unavailable")).
+		Once()
+
+	_, err := repo.GetGroupSummaries("s", "e")
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"mail_service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IncidentRepository interface {
+	GetOpenIncident(serverID string) (*domain.Incident, error)
+	OpenIncident(incident *domain.Incident) error
+	ResolveIncident(id string, resolvedAt time.Time) error
+}
+
+type incidentRepository struct {
+	db *gorm.DB
+}
+
+func NewIncidentRepository(db *gorm.DB) IncidentRepository {
+	return &incidentRepository{
+		db: db,
+	}
+}
+
+// GetOpenIncident returns the unresolved incident of a server, or nil if the server has none.
+func (r *incidentRepository) GetOpenIncident(serverID string) (*domain.Incident, error) {
+	var incident domain.Incident
+	err := r.db.Where("server_id = ? AND resolved_at IS NULL", serverID).First(&incident).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &incident, nil
+}
+
+func (r *incidentRepository) OpenIncident(incident *domain.Incident) error {
+	return r.db.Create(incident).Error
+}
+
+func (r *incidentRepository) ResolveIncident(id string, resolvedAt time.Time) error {
+	return r.db.Model(&domain.Incident{}).Where("id = ?", id).Update("resolved_at", resolvedAt).Error
+}
@@ -0,0 +1,95 @@
+package repository_test
+
+import (
+	"errors"
+	"mail_service/internal/domain"
+	"mail_service/internal/repository"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncidentRepository_GetOpenIncident_Found(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewIncidentRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "server_id", "opened_at"}).
+		AddRow("inc-1", "srv-1", time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "incidents" WHERE server_id = $1 AND resolved_at IS NULL`)).
+		WithArgs("srv-1", 1).
+		WillReturnRows(rows)
+
+	incident, err := repo.GetOpenIncident("srv-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "inc-1", incident.ID)
+}
+
+func TestIncidentRepository_GetOpenIncident_None(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewIncidentRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "incidents" WHERE server_id = $1 AND resolved_at IS NULL`)).
+		WithArgs("srv-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	incident, err := repo.GetOpenIncident("srv-1")
+	assert.NoError(t, err)
+	assert.Nil(t, incident)
+}
+
+func TestIncidentRepository_GetOpenIncident_Error(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewIncidentRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "incidents"`)).
+		WillReturnError(errors.New("db error"))
+
+	incident, err := repo.GetOpenIncident("srv-1")
+	assert.Error(t, err)
+	assert.Nil(t, incident)
+}
+
+func TestIncidentRepository_OpenIncident(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewIncidentRepository(gdb)
+	incident := &domain.Incident{ID: "inc-1", ServerID: "srv-1", OpenedAt: time.Now(), Suppressed: true}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "incidents"`).
+		WithArgs(incident.ID, incident.ServerID, incident.OpenedAt, nil, true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.OpenIncident(incident)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIncidentRepository_ResolveIncident(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewIncidentRepository(gdb)
+	resolvedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "incidents" SET "resolved_at"=$1 WHERE id = $2`)).
+		WithArgs(resolvedAt, "inc-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ResolveIncident("inc-1", resolvedAt)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
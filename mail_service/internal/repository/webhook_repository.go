@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"mail_service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(subscription *domain.WebhookSubscription) (string, error)
+	GetSubscription(id string) (*domain.WebhookSubscription, error)
+	GetSubscriptions() ([]domain.WebhookSubscription, error)
+	GetEnabledSubscriptions() ([]domain.WebhookSubscription, error)
+	UpdateSubscription(subscription *domain.WebhookSubscription) error
+	DeleteSubscription(id string) error
+	CreateDelivery(delivery *domain.WebhookDelivery) error
+	GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error)
+	QueueWebhooks(webhooks []domain.QueuedWebhook) error
+	ClaimDueWebhooks(now time.Time, lease time.Duration, limit int) ([]domain.QueuedWebhook, error)
+	RescheduleWebhook(id uint, attempt int, nextAttemptAt time.Time) error
+	DeleteQueuedWebhook(id uint) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) (string, error) {
+	if err := r.db.Create(subscription).Error; err != nil {
+		return "", err
+	}
+
+	return subscription.ID, nil
+}
+
+func (r *webhookRepository) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	if err := r.db.Where("id = ?", id).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (r *webhookRepository) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	if err := r.db.Order("created_time").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) GetEnabledSubscriptions() ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	if err := r.db.Where("enabled = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(subscription *domain.WebhookSubscription) error {
+	return r.db.Save(subscription).Error
+}
+
+func (r *webhookRepository) DeleteSubscription(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&domain.WebhookSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *webhookRepository) GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	if err := r.db.Where("subscription_id = ?", subscriptionID).
+		Order("created_time desc").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+			return nil, err
+		}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) QueueWebhooks(webhooks []domain.QueuedWebhook) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	return r.db.Create(&webhooks).Error
+}
+
+// ClaimDueWebhooks returns the queued webhooks due by now, oldest first, and pushes their next attempt
+// back by the lease so no other worker picks them up while they're being delivered. Rows another worker
+// is claiming at the same time are skipped.
+func (r *webhookRepository) ClaimDueWebhooks(now time.Time, lease time.Duration, limit int) ([]domain.QueuedWebhook, error) {
+	due := r.db.Model(&domain.QueuedWebhook{}).Select("id").
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var webhooks []domain.QueuedWebhook
+	if err := r.db.Model(&webhooks).Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("next_attempt_at", now.Add(lease)).Error; err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *webhookRepository) RescheduleWebhook(id uint, attempt int, nextAttemptAt time.Time) error {
+	return r.db.Model(&domain.QueuedWebhook{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"attempt": attempt, "next_attempt_at": nextAttemptAt}).Error
+}
+
+func (r *webhookRepository) DeleteQueuedWebhook(id uint) error {
+	return r.db.Where("id = ?", id).Delete(&domain.QueuedWebhook{}).Error
+}
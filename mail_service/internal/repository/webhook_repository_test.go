@@ -0,0 +1,243 @@
+package repository_test
+
+import (
+	"errors"
+	"mail_service/internal/domain"
+	"mail_service/internal/repository"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestWebhookRepository_CreateSubscription_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+	subscription := &domain.WebhookSubscription{
+		ID:         "sub-1",
+		Name:       "chat",
+		URL:        "https://chat.example.com/hook",
+		Headers:    map[string]string{"X-Token": "abc"},
+		Secret:     "s3cret",
+		Events:     []string{domain.EventIncidentOpened},
+		MaxRetries: 3,
+		Enabled:    true,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "webhook_subscriptions"`).
+		WithArgs(
+			subscription.ID,
+			subscription.Name,
+			subscription.URL,
+			`{"X-Token":"abc"}`,
+			subscription.Secret,
+			`["incident.opened"]`,
+			subscription.MaxRetries,
+			subscription.Enabled,
+			sqlmock.AnyArg(), // created_time
+			sqlmock.AnyArg(), // last_updated
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateSubscription(subscription)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_CreateSubscription_FailDB(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "webhook_subscriptions"`).WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	id, err := repo.CreateSubscription(&domain.WebhookSubscription{ID: "sub-1"})
+	assert.Error(t, err)
+	assert.Empty(t, id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetSubscription_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "url", "headers", "events", "max_retries", "enabled"}).
+		AddRow("sub-1", "chat", "https://chat.example.com/hook", `{"X-Token":"abc"}`, `["incident.opened"]`, 3, true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE id = $1`)).
+		WithArgs("sub-1", 1).
+		WillReturnRows(rows)
+
+	subscription, err := repo.GetSubscription("sub-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "chat", subscription.Name)
+	assert.Equal(t, "abc", subscription.Headers["X-Token"])
+	assert.Equal(t, []string{"incident.opened"}, subscription.Events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetSubscription_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE id = $1`)).
+		WithArgs("missing", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	subscription, err := repo.GetSubscription("missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, subscription)
+}
+
+func TestWebhookRepository_GetEnabledSubscriptions(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "enabled"}).
+		AddRow("sub-1", "chat", true).
+		AddRow("sub-2", "tickets", true)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE enabled = $1`)).
+		WithArgs(true).
+		WillReturnRows(rows)
+
+	subscriptions, err := repo.GetEnabledSubscriptions()
+	assert.NoError(t, err)
+	assert.Len(t, subscriptions, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_DeleteSubscription_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_subscriptions" WHERE id = $1`)).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.DeleteSubscription("missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_DeleteSubscription_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_subscriptions" WHERE id = $1`)).
+		WithArgs("sub-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.DeleteSubscription("sub-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_CreateDelivery(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+	delivery := &domain.WebhookDelivery{
+		SubscriptionID: "sub-1",
+		Event:          domain.EventServerStatusChanged,
+		Payload:        `{"server_id":"srv-1"}`,
+		Attempt:        1,
+		StatusCode:     200,
+		Success:        true,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "webhook_deliveries"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	err := repo.CreateDelivery(delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), delivery.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetDeliveries(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "subscription_id", "attempt", "success"}).
+		AddRow(2, "sub-1", 2, true).
+		AddRow(1, "sub-1", 1, false)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE subscription_id = $1 ORDER BY created_time desc LIMIT $2`)).
+		WithArgs("sub-1", 10).
+		WillReturnRows(rows)
+
+	deliveries, err := repo.GetDeliveries("sub-1", 10)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.False(t, deliveries[1].Success)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_ClaimDueWebhooks(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+	now := time.Date(2025, 6, 24, 10, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "subscription_id", "event", "payload", "attempt"}).
+		AddRow(1, "sub-1", domain.EventServerStatusChanged, `{}`, 0).
+		AddRow(2, "sub-2", domain.EventIncidentOpened, `{}`, 2)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "queued_webhooks" SET "next_attempt_at"=$1 WHERE id IN (SELECT "id" FROM "queued_webhooks" WHERE next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED) RETURNING *`)).
+		WithArgs(now.Add(5*time.Minute), now, 100).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
+
+	webhooks, err := repo.ClaimDueWebhooks(now, 5*time.Minute, 100)
+	assert.NoError(t, err)
+	assert.Len(t, webhooks, 2)
+	assert.Equal(t, 2, webhooks[1].Attempt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_RescheduleWebhook(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewWebhookRepository(gdb)
+	next := time.Date(2025, 6, 24, 10, 0, 4, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "queued_webhooks" SET "attempt"=$1,"next_attempt_at"=$2 WHERE id = $3`)).
+		WithArgs(3, next, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.RescheduleWebhook(7, 3, next)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"fmt"
 	mailsending "mail_service/infrastructure/mail_sending"
+	"mail_service/internal/dto"
 	"mail_service/internal/repository"
+	"strings"
 	"time"
 
 	"github.com/flashhhhh/pkg/logging"
@@ -32,8 +34,25 @@ func (ms *mailService) SendServersReportEmail(to string, startTime, endTime stri
 		return err
 	}
 
+	// A missing SLA section shouldn't hold back the rest of the report
+	slaSection := "SLA compliance is currently unavailable."
+	compliances, err := ms.mailGRPCClientRepository.GetSLACompliance(startTime, endTime)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get SLA compliance in range [" + startTime + ", " + endTime + "]. Err: " + err.Error(), "ERROR")
+	} else {
+		slaSection = formatSLABreaches(compliances)
+	}
+
+	groupSection := "\n\nGroup status is currently unavailable."
+	groups, err := ms.mailGRPCClientRepository.GetGroupSummaries(startTime, endTime)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get group summaries in range [" + startTime + ", " + endTime + "]. Err: " + err.Error(), "ERROR")
+	} else {
+		groupSection = formatGroups(groups)
+	}
+
 	subject := "Daily Server Status Report for " + time.Now().Format("2006-01-02")
-	body := fmt.Sprintf("Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\nBest regards,\nYour Server Monitoring System", numServers, numOnServers, numOffServers, meanUpTimeRatio)
+	body := fmt.Sprintf("Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\n%s%s\n\nBest regards,\nYour Server Monitoring System", numServers, numOnServers, numOffServers, meanUpTimeRatio, slaSection, groupSection)
 
 	err = ms.mailSending.SendEmail(to, subject, body)
 	if err != nil {
@@ -43,4 +62,38 @@ func (ms *mailService) SendServersReportEmail(to string, startTime, endTime stri
 
 	logging.LogMessage("mail_service", "Send email successfully for days in range [" + startTime + ", " + endTime + "]!", "INFO")
 	return nil
-}
\ No newline at end of file
+}
+
+func formatSLABreaches(compliances []dto.SLACompliance) string {
+	var breaches strings.Builder
+	for _, compliance := range compliances {
+		if !compliance.Breached {
+			continue
+		}
+		fmt.Fprintf(&breaches, "\n- [BREACH] %s (%s): %.3f%% achieved, target %.3f%%, burn rate %.1fx",
+			compliance.ServerName, compliance.ServerID, compliance.Achieved, compliance.SLATarget, compliance.BurnRate)
+	}
+
+	if breaches.Len() == 0 {
+		return "All servers met their SLA targets."
+	}
+	return "SLA breaches:" + breaches.String()
+}
+
+// formatGroups lists every group with its rolled up status; the section is left out when no groups are defined.
+func formatGroups(groups []dto.GroupSummary) string {
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var section strings.Builder
+	section.WriteString("\n\nGroups:")
+	for _, group := range groups {
+		fmt.Fprintf(&section, "\n- %s: %s, %d/%d up, uptime %.3f%% (target %.3f%%)",
+			group.Path, group.Status, group.NumUp, group.NumServers, group.UpTimeRatio, group.SLATarget)
+		if group.Breached {
+			section.WriteString(" [BREACH]")
+		}
+	}
+	return section.String()
+}
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"fmt"
+	"mail_service/internal/dto"
 	"mail_service/internal/service"
 	"testing"
 	"time"
@@ -30,6 +31,27 @@ func (m *mockMailGRPCClientRepository) GetServersInformation(startTime, endTime
 	return args.Int(0), args.Int(1), args.Int(2), args.Get(3).(float64), args.Error(4)
 }
 
+func (m *mockMailGRPCClientRepository) GetNotificationStatus(serverID string) (bool, string, error) {
+	args := m.Called(serverID)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
+func (m *mockMailGRPCClientRepository) GetSLACompliance(startTime, endTime string) ([]dto.SLACompliance, error) {
+	args := m.Called(startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.SLACompliance), args.Error(1)
+}
+
+func (m *mockMailGRPCClientRepository) GetGroupSummaries(startTime, endTime string) ([]dto.GroupSummary, error) {
+	args := m.Called(startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.GroupSummary), args.Error(1)
+}
+
 func TestSendServersReportEmail_Success(t *testing.T) {
 	mockMail := new(mockMailSending)
 	mockRepo := new(mockMailGRPCClientRepository)
@@ -44,10 +66,13 @@ func TestSendServersReportEmail_Success(t *testing.T) {
 
 	mockRepo.On("GetServersInformation", startTime, endTime).
 		Return(numServers, numOnServers, numOffServers, meanUpTimeRatio, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return([]dto.GroupSummary{}, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).
+		Return([]dto.SLACompliance{{ServerID: "srv-1", SLATarget: 99.9, Achieved: 99.95}}, nil)
 
 	expectedSubject := "Daily Server Status Report for " + time.Now().Format("2006-01-02")
 	expectedBody := fmt.Sprintf(
-		"Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\nBest regards,\nYour Server Monitoring System",
+		"Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\nAll servers met their SLA targets.\n\nBest regards,\nYour Server Monitoring System",
 		numServers, numOnServers, numOffServers, meanUpTimeRatio,
 	)
 	mockMail.On("SendEmail", to, expectedSubject, expectedBody).Return(nil)
@@ -92,10 +117,13 @@ func TestSendServersReportEmail_SendEmailError(t *testing.T) {
 
 	mockRepo.On("GetServersInformation", startTime, endTime).
 		Return(numServers, numOnServers, numOffServers, meanUpTimeRatio, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return([]dto.GroupSummary{}, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).
+		Return([]dto.SLACompliance{{ServerID: "srv-1", SLATarget: 99.9, Achieved: 99.95}}, nil)
 
 	expectedSubject := "Daily Server Status Report for " + time.Now().Format("2006-01-02")
 	expectedBody := fmt.Sprintf(
-		"Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\nBest regards,\nYour Server Monitoring System",
+		"Dear server administrator,\n\nThe server status is as follows:\n\nTotal servers: %d\nServers on: %d\nServers off: %d\nMean uptime rate: %.2f%%\n\nAll servers met their SLA targets.\n\nBest regards,\nYour Server Monitoring System",
 		numServers, numOnServers, numOffServers, meanUpTimeRatio,
 	)
 	mockMail.On("SendEmail", to, expectedSubject, expectedBody).Return(expectedErr)
@@ -105,4 +133,99 @@ func TestSendServersReportEmail_SendEmailError(t *testing.T) {
 	assert.EqualError(t, err, expectedErr.Error())
 	mockMail.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestSendServersReportEmail_FlagsSLABreaches(t *testing.T) {
+	mockMail := new(mockMailSending)
+	mockRepo := new(mockMailGRPCClientRepository)
+
+	startTime := "2024-06-01"
+	endTime := "2024-06-02"
+
+	mockRepo.On("GetServersInformation", startTime, endTime).Return(2, 1, 1, 80.0, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return([]dto.GroupSummary{}, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).Return([]dto.SLACompliance{
+		{ServerID: "srv-1", ServerName: "web", SLATarget: 99.9, Achieved: 99.95},
+		{ServerID: "srv-2", ServerName: "db", SLATarget: 99.9, Achieved: 97.5, BurnRate: 25, Breached: true},
+	}, nil)
+
+	var body string
+	mockMail.On("SendEmail", "admin@example.com", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { body = args.String(2) }).
+		Return(nil)
+
+	svc := service.NewMailService(mockMail, mockRepo)
+	err := svc.SendServersReportEmail("admin@example.com", startTime, endTime)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "SLA breaches:\n- [BREACH] db (srv-2): 97.500% achieved, target 99.900%, burn rate 25.0x")
+	assert.NotContains(t, body, "web (srv-1)")
+}
+
+func TestSendServersReportEmail_SLAComplianceUnavailable(t *testing.T) {
+	mockMail := new(mockMailSending)
+	mockRepo := new(mockMailGRPCClientRepository)
+
+	startTime := "2024-06-01"
+	endTime := "2024-06-02"
+
+	mockRepo.On("GetServersInformation", startTime, endTime).Return(2, 1, 1, 80.0, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return([]dto.GroupSummary{}, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).Return(nil, assert.AnError)
+
+	var body string
+	mockMail.On("SendEmail", "admin@example.com", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { body = args.String(2) }).
+		Return(nil)
+
+	svc := service.NewMailService(mockMail, mockRepo)
+	err := svc.SendServersReportEmail("admin@example.com", startTime, endTime)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "SLA compliance is currently unavailable.")
+}
+
+func TestSendServersReportEmail_Groups(t *testing.T) {
+	mockMail := new(mockMailSending)
+	mockRepo := new(mockMailGRPCClientRepository)
+
+	startTime := "2024-06-01"
+	endTime := "2024-06-02"
+
+	mockRepo.On("GetServersInformation", startTime, endTime).Return(4, 3, 1, 90.0, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).Return([]dto.SLACompliance{}, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return([]dto.GroupSummary{
+		{Path: "dc-1", Status: "PartiallyDown", NumServers: 4, NumUp: 3, NumDown: 1, UpTimeRatio: 97.5, SLATarget: 99.9, Breached: true},
+		{Path: "dc-1 / rack-1", Status: "Up", NumServers: 2, NumUp: 2, UpTimeRatio: 100, SLATarget: 99.9},
+	}, nil)
+
+	var body string
+	mockMail.On("SendEmail", "admin@example.com", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { body = args.String(2) }).
+		Return(nil)
+
+	svc := service.NewMailService(mockMail, mockRepo)
+	err := svc.SendServersReportEmail("admin@example.com", startTime, endTime)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "Groups:\n- dc-1: PartiallyDown, 3/4 up, uptime 97.500% (target 99.900%) [BREACH]\n- dc-1 / rack-1: Up, 2/2 up, uptime 100.000% (target 99.900%)\n\nBest regards")
+}
+
+func TestSendServersReportEmail_GroupsUnavailable(t *testing.T) {
+	mockMail := new(mockMailSending)
+	mockRepo := new(mockMailGRPCClientRepository)
+
+	startTime := "2024-06-01"
+	endTime := "2024-06-02"
+
+	mockRepo.On("GetServersInformation", startTime, endTime).Return(2, 1, 1, 80.0, nil)
+	mockRepo.On("GetSLACompliance", startTime, endTime).Return([]dto.SLACompliance{}, nil)
+	mockRepo.On("GetGroupSummaries", startTime, endTime).Return(nil, assert.AnError)
+
+	var body string
+	mockMail.On("SendEmail", "admin@example.com", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { body = args.String(2) }).
+		Return(nil)
+
+	svc := service.NewMailService(mockMail, mockRepo)
+	err := svc.SendServersReportEmail("admin@example.com", startTime, endTime)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "Group status is currently unavailable.")
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	webhooksending "mail_service/infrastructure/webhook_sending"
+	"mail_service/internal/domain"
+	"mail_service/internal/dto"
+	"mail_service/internal/repository"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+)
+
+var supportedEvents = map[string]bool{
+	domain.EventServerStatusChanged: true,
+	domain.EventIncidentOpened:      true,
+	domain.EventIncidentResolved:    true,
+}
+
+// WebhookBatchSize is how many queued deliveries DeliverQueued attempts at once.
+const WebhookBatchSize = 100
+
+// webhookClaimLease keeps a claimed delivery from other workers, well past the time an attempt may take.
+const webhookClaimLease = 5 * time.Minute
+
+type WebhookService interface {
+	CreateSubscription(name, webhookURL string, headers map[string]string, secret string, events []string, maxRetries int) (string, error)
+	GetSubscriptions() ([]domain.WebhookSubscription, error)
+	GetSubscription(id string) (*domain.WebhookSubscription, error)
+	UpdateSubscription(id string, updatedData map[string]interface{}) error
+	DeleteSubscription(id string) error
+	GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error)
+	HandleStatusChange(serverID, status string, timestamp time.Time) error
+	Dispatch(event dto.WebhookEvent) error
+	DeliverQueued() (int, error)
+}
+
+type webhookService struct {
+	webhookRepository        repository.WebhookRepository
+	incidentRepository       repository.IncidentRepository
+	mailGRPCClientRepository repository.MailGRPCClientRepository
+	webhookSending           webhooksending.WebhookSending
+	retryBackoff             time.Duration
+}
+
+func NewWebhookService(webhookRepository repository.WebhookRepository, incidentRepository repository.IncidentRepository,
+	mailGRPCClientRepository repository.MailGRPCClientRepository, webhookSending webhooksending.WebhookSending,
+	retryBackoff time.Duration) WebhookService {
+	return &webhookService{
+		webhookRepository:        webhookRepository,
+		incidentRepository:       incidentRepository,
+		mailGRPCClientRepository: mailGRPCClientRepository,
+		webhookSending:           webhookSending,
+		retryBackoff:             retryBackoff,
+	}
+}
+
+func validateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid webhook url: " + webhookURL)
+	}
+	return nil
+}
+
+func validateEvents(events []string) error {
+	for _, event := range events {
+		if !supportedEvents[event] {
+			return errors.New("unsupported event: " + event)
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) CreateSubscription(name, webhookURL string, headers map[string]string, secret string, events []string, maxRetries int) (string, error) {
+	if name == "" {
+		return "", errors.New("subscription name is required")
+	}
+	if err := validateWebhookURL(webhookURL); err != nil {
+		return "", err
+	}
+	if err := validateEvents(events); err != nil {
+		return "", err
+	}
+	if maxRetries < 0 {
+		return "", errors.New("max_retries must not be negative")
+	}
+
+	subscription := &domain.WebhookSubscription{
+		ID:         uuid.New().String(),
+		Name:       name,
+		URL:        webhookURL,
+		Headers:    headers,
+		Secret:     secret,
+		Events:     events,
+		MaxRetries: maxRetries,
+		Enabled:    true,
+	}
+
+	id, err := s.webhookRepository.CreateSubscription(subscription)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to create webhook subscription. Err: "+err.Error(), "ERROR")
+		return "", err
+	}
+
+	logging.LogMessage("mail_service", "Webhook subscription "+id+" created", "INFO")
+	return id, nil
+}
+
+func (s *webhookService) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	return s.webhookRepository.GetSubscriptions()
+}
+
+func (s *webhookService) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	return s.webhookRepository.GetSubscription(id)
+}
+
+func (s *webhookService) UpdateSubscription(id string, updatedData map[string]interface{}) error {
+	subscription, err := s.webhookRepository.GetSubscription(id)
+	if err != nil {
+		return err
+	}
+
+	if name, ok := updatedData["name"].(string); ok {
+		subscription.Name = name
+	}
+	if webhookURL, ok := updatedData["url"].(string); ok {
+		if err := validateWebhookURL(webhookURL); err != nil {
+			return err
+		}
+		subscription.URL = webhookURL
+	}
+	if headers, ok := updatedData["headers"].(map[string]string); ok {
+		subscription.Headers = headers
+	}
+	if secret, ok := updatedData["secret"].(string); ok {
+		subscription.Secret = secret
+	}
+	if events, ok := updatedData["events"].([]string); ok {
+		if err := validateEvents(events); err != nil {
+			return err
+		}
+		subscription.Events = events
+	}
+	if maxRetries, ok := updatedData["max_retries"].(int); ok {
+		if maxRetries < 0 {
+			return errors.New("max_retries must not be negative")
+		}
+		subscription.MaxRetries = maxRetries
+	}
+	if enabled, ok := updatedData["enabled"].(bool); ok {
+		subscription.Enabled = enabled
+	}
+
+	return s.webhookRepository.UpdateSubscription(subscription)
+}
+
+func (s *webhookService) DeleteSubscription(id string) error {
+	return s.webhookRepository.DeleteSubscription(id)
+}
+
+func (s *webhookService) GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error) {
+	return s.webhookRepository.GetDeliveries(id, limit)
+}
+
+// HandleStatusChange turns a status change reported by the healthcheck pipeline into webhook events.
+// Every change produces a status event; a server going Down opens an incident and coming back Up or Degraded resolves it.
+// While notifications are suppressed (e.g. during maintenance) incidents are still tracked but nothing is sent.
+// An incident is resolved the way it was opened: subscribers get incident.resolved exactly when they got
+// incident.opened, whether notifications are suppressed by the time the server is back or not.
+func (s *webhookService) HandleStatusChange(serverID, status string, timestamp time.Time) error {
+	status = domain.NormalizeStatus(status)
+
+	notify := s.Dispatch
+	skip := func(dto.WebhookEvent) error { return nil }
+	suppressed, reason, err := s.mailGRPCClientRepository.GetNotificationStatus(serverID)
+	if err != nil {
+		// Better to notify during maintenance than to miss a real outage
+		logging.LogMessage("mail_service", "Failed to check notification status of server "+serverID+", notifying anyway. Err: "+err.Error(), "ERROR")
+		suppressed = false
+	} else if suppressed {
+		logging.LogMessage("mail_service", "Notifications for server "+serverID+" are suppressed: "+reason, "INFO")
+		notify = skip
+	}
+
+	event := dto.WebhookEvent{
+		ID:        uuid.New().String(),
+		Event:     domain.EventServerStatusChanged,
+		ServerID:  serverID,
+		Status:    status,
+		Timestamp: timestamp,
+	}
+	if err := notify(event); err != nil {
+		return err
+	}
+
+	incident, err := s.incidentRepository.GetOpenIncident(serverID)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get open incident of server "+serverID+". Err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	switch {
+	case status == domain.StatusDown && incident == nil:
+		incident = &domain.Incident{
+			ID:         uuid.New().String(),
+			ServerID:   serverID,
+			OpenedAt:   timestamp,
+			Suppressed: suppressed,
+		}
+		if err := s.incidentRepository.OpenIncident(incident); err != nil {
+			logging.LogMessage("mail_service", "Failed to open incident for server "+serverID+". Err: "+err.Error(), "ERROR")
+			return err
+		}
+		event.Event = domain.EventIncidentOpened
+	case (status == domain.StatusUp || status == domain.StatusDegraded) && incident != nil:
+		if err := s.incidentRepository.ResolveIncident(incident.ID, timestamp); err != nil {
+			logging.LogMessage("mail_service", "Failed to resolve incident "+incident.ID+". Err: "+err.Error(), "ERROR")
+			return err
+		}
+		event.Event = domain.EventIncidentResolved
+		notify = s.Dispatch
+		if incident.Suppressed {
+			notify = skip
+		}
+	default:
+		return nil
+	}
+
+	event.ID = uuid.New().String()
+	event.IncidentID = incident.ID
+	return notify(event)
+}
+
+// Dispatch queues an event for every enabled subscription interested in it, for DeliverQueued to send.
+// It returns once the deliveries are stored, so a slow or dead subscriber never holds up the caller.
+func (s *webhookService) Dispatch(event dto.WebhookEvent) error {
+	subscriptions, err := s.webhookRepository.GetEnabledSubscriptions()
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get webhook subscriptions. Err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var webhooks []domain.QueuedWebhook
+	for i := range subscriptions {
+		if !subscriptions[i].Accepts(event.Event) {
+			continue
+		}
+
+		webhooks = append(webhooks, domain.QueuedWebhook{
+			SubscriptionID: subscriptions[i].ID,
+			Event:          event.Event,
+			Payload:        string(payload),
+			NextAttemptAt:  now,
+		})
+	}
+
+	if err := s.webhookRepository.QueueWebhooks(webhooks); err != nil {
+		logging.LogMessage("mail_service", "Failed to queue webhooks for event "+event.ID+". Err: "+err.Error(), "ERROR")
+		return err
+	}
+	return nil
+}
+
+// DeliverQueued makes the next attempt of the queued deliveries that are due, at most WebhookBatchSize of
+// them side by side, and returns how many it attempted. A failed attempt is queued again after an
+// exponential backoff until the subscription's retries run out.
+func (s *webhookService) DeliverQueued() (int, error) {
+	webhooks, err := s.webhookRepository.ClaimDueWebhooks(time.Now(), webhookClaimLease, WebhookBatchSize)
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to claim queued webhooks. Err: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	if len(webhooks) == 0 {
+		return 0, nil
+	}
+
+	// The claimed deliveries are attempted again once their lease runs out
+	subscriptions, err := s.webhookRepository.GetEnabledSubscriptions()
+	if err != nil {
+		logging.LogMessage("mail_service", "Failed to get webhook subscriptions. Err: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	subscriptionsByID := make(map[string]*domain.WebhookSubscription, len(subscriptions))
+	for i := range subscriptions {
+		subscriptionsByID[subscriptions[i].ID] = &subscriptions[i]
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		subscription, ok := subscriptionsByID[webhook.SubscriptionID]
+		if !ok {
+			// Deleted or disabled since the event was queued
+			logging.LogMessage("mail_service", "Dropping webhook "+strconv.FormatUint(uint64(webhook.ID), 10)+" of subscription "+webhook.SubscriptionID+", it's no longer enabled", "INFO")
+			s.dequeue(webhook)
+			continue
+		}
+
+		wg.Add(1)
+		go func(webhook domain.QueuedWebhook) {
+			defer wg.Done()
+			s.deliver(subscription, webhook)
+		}(webhook)
+	}
+	wg.Wait()
+
+	return len(webhooks), nil
+}
+
+// deliver makes one attempt to post a queued webhook to its subscription and logs it. The webhook leaves the
+// queue once delivered or out of retries, otherwise it's queued again after twice the previous backoff.
+func (s *webhookService) deliver(subscription *domain.WebhookSubscription, webhook domain.QueuedWebhook) {
+	attempt := webhook.Attempt + 1
+	statusCode, err := s.webhookSending.SendWebhook(subscription.URL, subscription.Headers, subscription.Secret, []byte(webhook.Payload))
+
+	delivery := &domain.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		Event:          webhook.Event,
+		Payload:        webhook.Payload,
+		Attempt:        attempt,
+		StatusCode:     statusCode,
+		Success:        err == nil,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if logErr := s.webhookRepository.CreateDelivery(delivery); logErr != nil {
+		logging.LogMessage("mail_service", "Failed to record webhook delivery for subscription "+subscription.ID+". Err: "+logErr.Error(), "ERROR")
+	}
+
+	if err == nil {
+		s.dequeue(webhook)
+		return
+	}
+
+	logging.LogMessage("mail_service", "Webhook delivery to subscription "+subscription.ID+" failed on attempt "+strconv.Itoa(attempt)+". Err: "+err.Error(), "ERROR")
+	if attempt > subscription.MaxRetries {
+		logging.LogMessage("mail_service", "Giving up webhook delivery to subscription "+subscription.ID+" for event "+webhook.Event, "ERROR")
+		s.dequeue(webhook)
+		return
+	}
+
+	backoff := s.retryBackoff << (attempt - 1)
+	if err := s.webhookRepository.RescheduleWebhook(webhook.ID, attempt, time.Now().Add(backoff)); err != nil {
+		logging.LogMessage("mail_service", "Failed to reschedule webhook delivery to subscription "+subscription.ID+". Err: "+err.Error(), "ERROR")
+	}
+}
+
+func (s *webhookService) dequeue(webhook domain.QueuedWebhook) {
+	if err := s.webhookRepository.DeleteQueuedWebhook(webhook.ID); err != nil {
+		logging.LogMessage("mail_service", "Failed to remove webhook "+strconv.FormatUint(uint64(webhook.ID), 10)+" from the queue. Err: "+err.Error(), "ERROR")
+	}
+}
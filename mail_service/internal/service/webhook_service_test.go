@@ -0,0 +1,472 @@
+package service_test
+
+import (
+	"encoding/json"
+	"errors"
+	"mail_service/internal/domain"
+	"mail_service/internal/dto"
+	"mail_service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock for repository.WebhookRepository
+type mockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *mockWebhookRepository) CreateSubscription(subscription *domain.WebhookSubscription) (string, error) {
+	args := m.Called(subscription)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockWebhookRepository) GetSubscription(id string) (*domain.WebhookSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) GetEnabledSubscriptions() ([]domain.WebhookSubscription, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *mockWebhookRepository) UpdateSubscription(subscription *domain.WebhookSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) DeleteSubscription(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) CreateDelivery(delivery *domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *mockWebhookRepository) QueueWebhooks(webhooks []domain.QueuedWebhook) error {
+	args := m.Called(webhooks)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) ClaimDueWebhooks(now time.Time, lease time.Duration, limit int) ([]domain.QueuedWebhook, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.QueuedWebhook), args.Error(1)
+}
+
+func (m *mockWebhookRepository) RescheduleWebhook(id uint, attempt int, nextAttemptAt time.Time) error {
+	args := m.Called(id, attempt, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *mockWebhookRepository) DeleteQueuedWebhook(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// Mock for repository.IncidentRepository
+type mockIncidentRepository struct {
+	mock.Mock
+}
+
+func (m *mockIncidentRepository) GetOpenIncident(serverID string) (*domain.Incident, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Incident), args.Error(1)
+}
+
+func (m *mockIncidentRepository) OpenIncident(incident *domain.Incident) error {
+	args := m.Called(incident)
+	return args.Error(0)
+}
+
+func (m *mockIncidentRepository) ResolveIncident(id string, resolvedAt time.Time) error {
+	args := m.Called(id, resolvedAt)
+	return args.Error(0)
+}
+
+// Mock for webhooksending.WebhookSending
+type mockWebhookSending struct {
+	mock.Mock
+}
+
+func (m *mockWebhookSending) SendWebhook(url string, headers map[string]string, secret string, payload []byte) (int, error) {
+	args := m.Called(url, headers, secret, payload)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCreateSubscription_Success(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	mockRepo.On("CreateSubscription", mock.MatchedBy(func(s *domain.WebhookSubscription) bool {
+		return s.Name == "chat" && s.URL == "https://chat.example.com/hook" && s.Enabled && s.ID != ""
+	})).Return("sub-1", nil)
+
+	id, err := svc.CreateSubscription("chat", "https://chat.example.com/hook", nil, "s3cret", []string{domain.EventIncidentOpened}, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-1", id)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateSubscription_InvalidURL(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	_, err := svc.CreateSubscription("chat", "ftp://chat.example.com", nil, "", nil, 3)
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything)
+}
+
+func TestCreateSubscription_UnsupportedEvent(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	_, err := svc.CreateSubscription("chat", "https://chat.example.com/hook", nil, "", []string{"server.exploded"}, 3)
+	assert.EqualError(t, err, "unsupported event: server.exploded")
+}
+
+func TestUpdateSubscription_Success(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	existing := &domain.WebhookSubscription{ID: "sub-1", Name: "chat", URL: "https://chat.example.com/hook", Enabled: true}
+	mockRepo.On("GetSubscription", "sub-1").Return(existing, nil)
+	mockRepo.On("UpdateSubscription", mock.MatchedBy(func(s *domain.WebhookSubscription) bool {
+		return s.Name == "renamed" && !s.Enabled && s.MaxRetries == 5
+	})).Return(nil)
+
+	err := svc.UpdateSubscription("sub-1", map[string]interface{}{
+		"name":        "renamed",
+		"enabled":     false,
+		"max_retries": 5,
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateSubscription_NotFound(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	mockRepo.On("GetSubscription", "missing").Return(nil, errors.New("record not found"))
+
+	err := svc.UpdateSubscription("missing", map[string]interface{}{"name": "renamed"})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateSubscription", mock.Anything)
+}
+
+func TestDispatch_QueuesForInterestedSubscriptions(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), mockSending, 0)
+
+	subscriptions := []domain.WebhookSubscription{
+		{ID: "sub-1", URL: "https://a.example.com", Secret: "a", MaxRetries: 0},
+		{ID: "sub-2", URL: "https://b.example.com", Events: []string{domain.EventIncidentOpened}, MaxRetries: 0},
+	}
+	mockRepo.On("GetEnabledSubscriptions").Return(subscriptions, nil)
+	mockRepo.On("QueueWebhooks", mock.MatchedBy(func(webhooks []domain.QueuedWebhook) bool {
+		return len(webhooks) == 1 && webhooks[0].SubscriptionID == "sub-1" && webhooks[0].Attempt == 0 &&
+			webhooks[0].Event == domain.EventServerStatusChanged && !webhooks[0].NextAttemptAt.IsZero()
+	})).Return(nil)
+
+	err := svc.Dispatch(dto.WebhookEvent{ID: "evt-1", Event: domain.EventServerStatusChanged, ServerID: "srv-1", Status: "Off"})
+	assert.NoError(t, err)
+	mockSending.AssertNotCalled(t, "SendWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeliverQueued_Success(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), mockSending, 0)
+
+	mockRepo.On("ClaimDueWebhooks").Return([]domain.QueuedWebhook{
+		{ID: 1, SubscriptionID: "sub-1", Event: domain.EventServerStatusChanged, Payload: `{"id":"evt-1"}`},
+		{ID: 2, SubscriptionID: "sub-gone", Event: domain.EventServerStatusChanged, Payload: `{"id":"evt-1"}`},
+	}, nil)
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{
+		{ID: "sub-1", URL: "https://a.example.com", Secret: "a"},
+	}, nil)
+	mockSending.On("SendWebhook", "https://a.example.com", map[string]string(nil), "a", []byte(`{"id":"evt-1"}`)).Return(200, nil)
+	mockRepo.On("CreateDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.SubscriptionID == "sub-1" && d.Success && d.Attempt == 1
+	})).Return(nil)
+	mockRepo.On("DeleteQueuedWebhook", uint(1)).Return(nil)
+	mockRepo.On("DeleteQueuedWebhook", uint(2)).Return(nil)
+
+	attempted, err := svc.DeliverQueued()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempted)
+	mockSending.AssertNumberOfCalls(t, "SendWebhook", 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeliverQueued_ReschedulesWithBackoff(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), mockSending, time.Second)
+
+	mockRepo.On("ClaimDueWebhooks").Return([]domain.QueuedWebhook{
+		{ID: 1, SubscriptionID: "sub-1", Event: domain.EventServerStatusChanged, Attempt: 2},
+	}, nil)
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{
+		{ID: "sub-1", URL: "https://a.example.com", MaxRetries: 3},
+	}, nil)
+	mockSending.On("SendWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(503, errors.New("unavailable"))
+	mockRepo.On("CreateDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return !d.Success && d.Attempt == 3 && d.Error == "unavailable"
+	})).Return(nil)
+
+	before := time.Now()
+	mockRepo.On("RescheduleWebhook", uint(1), 3, mock.MatchedBy(func(next time.Time) bool {
+		// Third attempt failed, the fourth waits four times the backoff
+		return !next.Before(before.Add(4*time.Second)) && next.Before(time.Now().Add(4*time.Second+time.Minute))
+	})).Return(nil)
+
+	_, err := svc.DeliverQueued()
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteQueuedWebhook", mock.Anything)
+}
+
+func TestDeliverQueued_GivesUpAfterMaxRetries(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), mockSending, 0)
+
+	mockRepo.On("ClaimDueWebhooks").Return([]domain.QueuedWebhook{
+		{ID: 1, SubscriptionID: "sub-1", Event: domain.EventServerStatusChanged, Attempt: 2},
+	}, nil)
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{
+		{ID: "sub-1", URL: "https://a.example.com", MaxRetries: 2},
+	}, nil)
+	mockSending.On("SendWebhook", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(500, errors.New("boom"))
+	mockRepo.On("CreateDelivery", mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return !d.Success && d.Error == "boom"
+	})).Return(nil)
+	mockRepo.On("DeleteQueuedWebhook", uint(1)).Return(nil)
+
+	_, err := svc.DeliverQueued()
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "RescheduleWebhook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeliverQueued_NothingDue(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	mockRepo.On("ClaimDueWebhooks").Return([]domain.QueuedWebhook{}, nil)
+
+	attempted, err := svc.DeliverQueued()
+	assert.NoError(t, err)
+	assert.Zero(t, attempted)
+	mockRepo.AssertNotCalled(t, "GetEnabledSubscriptions")
+}
+
+func TestDispatch_RepositoryError(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	svc := service.NewWebhookService(mockRepo, new(mockIncidentRepository), new(mockMailGRPCClientRepository), new(mockWebhookSending), 0)
+
+	mockRepo.On("GetEnabledSubscriptions").Return(nil, errors.New("db error"))
+
+	err := svc.Dispatch(dto.WebhookEvent{Event: domain.EventServerStatusChanged})
+	assert.Error(t, err)
+}
+
+func TestHandleStatusChange_OpensIncident(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, notSuppressed(), mockSending, 0)
+
+	timestamp := time.Date(2025, 6, 24, 10, 0, 0, 0, time.UTC)
+	subscriptions := []domain.WebhookSubscription{{ID: "sub-1", URL: "https://a.example.com"}}
+
+	var events []string
+	mockRepo.On("GetEnabledSubscriptions").Return(subscriptions, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, webhook := range args.Get(0).([]domain.QueuedWebhook) {
+				var event dto.WebhookEvent
+				json.Unmarshal([]byte(webhook.Payload), &event)
+				events = append(events, event.Event)
+			}
+		}).Return(nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(nil, nil)
+	mockIncidents.On("OpenIncident", mock.MatchedBy(func(i *domain.Incident) bool {
+		return i.ServerID == "srv-1" && i.OpenedAt.Equal(timestamp)
+	})).Return(nil)
+
+	err := svc.HandleStatusChange("srv-1", domain.StatusDown, timestamp)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{domain.EventServerStatusChanged, domain.EventIncidentOpened}, events)
+	mockIncidents.AssertExpectations(t)
+}
+
+func TestHandleStatusChange_ResolvesIncident(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockSending := new(mockWebhookSending)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, notSuppressed(), mockSending, 0)
+
+	timestamp := time.Now()
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{}, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).Return(nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(&domain.Incident{ID: "inc-1", ServerID: "srv-1"}, nil)
+	mockIncidents.On("ResolveIncident", "inc-1", timestamp).Return(nil)
+
+	err := svc.HandleStatusChange("srv-1", domain.StatusDegraded, timestamp)
+	assert.NoError(t, err)
+	mockIncidents.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "GetEnabledSubscriptions", 2)
+}
+
+func TestHandleStatusChange_NoIncidentChange(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, notSuppressed(), new(mockWebhookSending), 0)
+
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{}, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).Return(nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(nil, nil)
+
+	err := svc.HandleStatusChange("srv-1", domain.StatusUp, time.Now())
+	assert.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "GetEnabledSubscriptions", 1)
+	mockIncidents.AssertNotCalled(t, "ResolveIncident", mock.Anything, mock.Anything)
+}
+
+func notSuppressed() *mockMailGRPCClientRepository {
+	mockGRPC := new(mockMailGRPCClientRepository)
+	mockGRPC.On("GetNotificationStatus", mock.Anything).Return(false, "", nil)
+	return mockGRPC
+}
+
+func TestHandleStatusChange_SuppressedDuringMaintenance(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockGRPC := new(mockMailGRPCClientRepository)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, mockGRPC, new(mockWebhookSending), 0)
+
+	timestamp := time.Now()
+	mockGRPC.On("GetNotificationStatus", "srv-1").Return(true, "Server is in maintenance window Patching", nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(nil, nil)
+	mockIncidents.On("OpenIncident", mock.Anything).Return(nil)
+
+	err := svc.HandleStatusChange("srv-1", "Off", timestamp)
+	assert.NoError(t, err)
+	mockIncidents.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetEnabledSubscriptions")
+}
+
+func TestHandleStatusChange_OpenedWhileSuppressedResolvedAfter(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockGRPC := new(mockMailGRPCClientRepository)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, mockGRPC, new(mockWebhookSending), 0)
+
+	down := time.Date(2025, 6, 24, 10, 0, 0, 0, time.UTC)
+	up := down.Add(2 * time.Hour)
+
+	var events []string
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{{ID: "sub-1", URL: "https://a.example.com"}}, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, webhook := range args.Get(0).([]domain.QueuedWebhook) {
+				events = append(events, webhook.Event)
+			}
+		}).Return(nil)
+
+	// Down in the maintenance window: the incident is opened without a word to subscribers
+	var opened *domain.Incident
+	mockGRPC.On("GetNotificationStatus", "srv-1").Return(true, "Server is in maintenance window Patching", nil).Once()
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(nil, nil).Once()
+	mockIncidents.On("OpenIncident", mock.Anything).Run(func(args mock.Arguments) {
+		opened = args.Get(0).(*domain.Incident)
+	}).Return(nil)
+	assert.NoError(t, svc.HandleStatusChange("srv-1", domain.StatusDown, down))
+	assert.True(t, opened.Suppressed)
+
+	// Back up after the window: the status change is sent, the resolve of an incident nobody heard of isn't
+	mockGRPC.On("GetNotificationStatus", "srv-1").Return(false, "", nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(opened, nil)
+	mockIncidents.On("ResolveIncident", opened.ID, up).Return(nil)
+	assert.NoError(t, svc.HandleStatusChange("srv-1", domain.StatusUp, up))
+
+	assert.Equal(t, []string{domain.EventServerStatusChanged}, events)
+	mockIncidents.AssertExpectations(t)
+}
+
+func TestHandleStatusChange_ResolvedWhileSuppressed(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockGRPC := new(mockMailGRPCClientRepository)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, mockGRPC, new(mockWebhookSending), 0)
+
+	timestamp := time.Now()
+	var events []string
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{{ID: "sub-1", URL: "https://a.example.com"}}, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).
+		Run(func(args mock.Arguments) {
+			for _, webhook := range args.Get(0).([]domain.QueuedWebhook) {
+				events = append(events, webhook.Event)
+			}
+		}).Return(nil)
+	// The incident was announced before the window started, its end is announced too
+	mockGRPC.On("GetNotificationStatus", "srv-1").Return(true, "Server is in maintenance window Patching", nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(&domain.Incident{ID: "inc-1", ServerID: "srv-1"}, nil)
+	mockIncidents.On("ResolveIncident", "inc-1", timestamp).Return(nil)
+
+	assert.NoError(t, svc.HandleStatusChange("srv-1", domain.StatusUp, timestamp))
+	assert.Equal(t, []string{domain.EventIncidentResolved}, events)
+}
+
+func TestHandleStatusChange_NotificationStatusError(t *testing.T) {
+	mockRepo := new(mockWebhookRepository)
+	mockIncidents := new(mockIncidentRepository)
+	mockGRPC := new(mockMailGRPCClientRepository)
+	svc := service.NewWebhookService(mockRepo, mockIncidents, mockGRPC, new(mockWebhookSending), 0)
+
+	mockGRPC.On("GetNotificationStatus", "srv-1").Return(false, "", errors.New("unavailable"))
+	mockRepo.On("GetEnabledSubscriptions").Return([]domain.WebhookSubscription{}, nil)
+	mockRepo.On("QueueWebhooks", mock.Anything).Return(nil)
+	mockIncidents.On("GetOpenIncident", "srv-1").Return(nil, nil)
+
+	err := svc.HandleStatusChange("srv-1", "On", time.Now())
+	assert.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "GetEnabledSubscriptions", 1)
+}
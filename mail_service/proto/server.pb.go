@@ -57,6 +57,50 @@ func (*EmptyRequest) Descriptor() ([]byte, []int) {
 	return file_proto_server_proto_rawDescGZIP(), []int{0}
 }
 
+type AddressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelSelector string                 `protobuf:"bytes,1,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddressRequest) Reset() {
+	*x = AddressRequest{}
+	mi := &file_proto_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRequest) ProtoMessage() {}
+
+func (x *AddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRequest.ProtoReflect.Descriptor instead.
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddressRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
 type IDAddressAndStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -68,7 +112,7 @@ type IDAddressAndStatus struct {
 
 func (x *IDAddressAndStatus) Reset() {
 	*x = IDAddressAndStatus{}
-	mi := &file_proto_server_proto_msgTypes[1]
+	mi := &file_proto_server_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -80,7 +124,7 @@ func (x *IDAddressAndStatus) String() string {
 func (*IDAddressAndStatus) ProtoMessage() {}
 
 func (x *IDAddressAndStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[1]
+	mi := &file_proto_server_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -93,7 +137,7 @@ func (x *IDAddressAndStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IDAddressAndStatus.ProtoReflect.Descriptor instead.
 func (*IDAddressAndStatus) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{1}
+	return file_proto_server_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *IDAddressAndStatus) GetId() int64 {
@@ -126,7 +170,7 @@ type IDAddressAndStatusList struct {
 
 func (x *IDAddressAndStatusList) Reset() {
 	*x = IDAddressAndStatusList{}
-	mi := &file_proto_server_proto_msgTypes[2]
+	mi := &file_proto_server_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -138,7 +182,7 @@ func (x *IDAddressAndStatusList) String() string {
 func (*IDAddressAndStatusList) ProtoMessage() {}
 
 func (x *IDAddressAndStatusList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[2]
+	mi := &file_proto_server_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -151,7 +195,7 @@ func (x *IDAddressAndStatusList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IDAddressAndStatusList.ProtoReflect.Descriptor instead.
 func (*IDAddressAndStatusList) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{2}
+	return file_proto_server_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *IDAddressAndStatusList) GetServerList() []*IDAddressAndStatus {
@@ -169,7 +213,7 @@ type EmptyResponse struct {
 
 func (x *EmptyResponse) Reset() {
 	*x = EmptyResponse{}
-	mi := &file_proto_server_proto_msgTypes[3]
+	mi := &file_proto_server_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -181,7 +225,7 @@ func (x *EmptyResponse) String() string {
 func (*EmptyResponse) ProtoMessage() {}
 
 func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[3]
+	mi := &file_proto_server_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,7 +238,7 @@ func (x *EmptyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyResponse.ProtoReflect.Descriptor instead.
 func (*EmptyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{3}
+	return file_proto_server_proto_rawDescGZIP(), []int{4}
 }
 
 type TimeRequest struct {
@@ -207,7 +251,7 @@ type TimeRequest struct {
 
 func (x *TimeRequest) Reset() {
 	*x = TimeRequest{}
-	mi := &file_proto_server_proto_msgTypes[4]
+	mi := &file_proto_server_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -219,7 +263,7 @@ func (x *TimeRequest) String() string {
 func (*TimeRequest) ProtoMessage() {}
 
 func (x *TimeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[4]
+	mi := &file_proto_server_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,7 +276,7 @@ func (x *TimeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TimeRequest.ProtoReflect.Descriptor instead.
 func (*TimeRequest) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{4}
+	return file_proto_server_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *TimeRequest) GetStartTime() string {
@@ -261,7 +305,7 @@ type ServersInformationResponse struct {
 
 func (x *ServersInformationResponse) Reset() {
 	*x = ServersInformationResponse{}
-	mi := &file_proto_server_proto_msgTypes[5]
+	mi := &file_proto_server_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -273,7 +317,7 @@ func (x *ServersInformationResponse) String() string {
 func (*ServersInformationResponse) ProtoMessage() {}
 
 func (x *ServersInformationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_server_proto_msgTypes[5]
+	mi := &file_proto_server_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +330,7 @@ func (x *ServersInformationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServersInformationResponse.ProtoReflect.Descriptor instead.
 func (*ServersInformationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_server_proto_rawDescGZIP(), []int{5}
+	return file_proto_server_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ServersInformationResponse) GetNumServers() int64 {
@@ -317,12 +361,414 @@ func (x *ServersInformationResponse) GetMeanUpTimeRatio() float64 {
 	return 0
 }
 
+type ServerIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServerIDRequest) Reset() {
+	*x = ServerIDRequest{}
+	mi := &file_proto_server_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerIDRequest) ProtoMessage() {}
+
+func (x *ServerIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerIDRequest.ProtoReflect.Descriptor instead.
+func (*ServerIDRequest) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ServerIDRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+type NotificationStatus struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Suppressed          bool                   `protobuf:"varint,1,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+	Reason              string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	MaintenanceWindowId string                 `protobuf:"bytes,3,opt,name=maintenance_window_id,json=maintenanceWindowId,proto3" json:"maintenance_window_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NotificationStatus) Reset() {
+	*x = NotificationStatus{}
+	mi := &file_proto_server_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationStatus) ProtoMessage() {}
+
+func (x *NotificationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationStatus.ProtoReflect.Descriptor instead.
+func (*NotificationStatus) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NotificationStatus) GetSuppressed() bool {
+	if x != nil {
+		return x.Suppressed
+	}
+	return false
+}
+
+func (x *NotificationStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetMaintenanceWindowId() string {
+	if x != nil {
+		return x.MaintenanceWindowId
+	}
+	return ""
+}
+
+type SLACompliance struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	ServerId             string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	ServerName           string                 `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	SlaTarget            float64                `protobuf:"fixed64,3,opt,name=sla_target,json=slaTarget,proto3" json:"sla_target,omitempty"`
+	Achieved             float64                `protobuf:"fixed64,4,opt,name=achieved,proto3" json:"achieved,omitempty"`
+	ErrorBudgetRemaining float64                `protobuf:"fixed64,5,opt,name=error_budget_remaining,json=errorBudgetRemaining,proto3" json:"error_budget_remaining,omitempty"`
+	BurnRate             float64                `protobuf:"fixed64,6,opt,name=burn_rate,json=burnRate,proto3" json:"burn_rate,omitempty"`
+	Breached             bool                   `protobuf:"varint,7,opt,name=breached,proto3" json:"breached,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SLACompliance) Reset() {
+	*x = SLACompliance{}
+	mi := &file_proto_server_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLACompliance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLACompliance) ProtoMessage() {}
+
+func (x *SLACompliance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLACompliance.ProtoReflect.Descriptor instead.
+func (*SLACompliance) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SLACompliance) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *SLACompliance) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *SLACompliance) GetSlaTarget() float64 {
+	if x != nil {
+		return x.SlaTarget
+	}
+	return 0
+}
+
+func (x *SLACompliance) GetAchieved() float64 {
+	if x != nil {
+		return x.Achieved
+	}
+	return 0
+}
+
+func (x *SLACompliance) GetErrorBudgetRemaining() float64 {
+	if x != nil {
+		return x.ErrorBudgetRemaining
+	}
+	return 0
+}
+
+func (x *SLACompliance) GetBurnRate() float64 {
+	if x != nil {
+		return x.BurnRate
+	}
+	return 0
+}
+
+func (x *SLACompliance) GetBreached() bool {
+	if x != nil {
+		return x.Breached
+	}
+	return false
+}
+
+type SLAComplianceList struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ComplianceList []*SLACompliance       `protobuf:"bytes,1,rep,name=complianceList,proto3" json:"complianceList,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SLAComplianceList) Reset() {
+	*x = SLAComplianceList{}
+	mi := &file_proto_server_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLAComplianceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLAComplianceList) ProtoMessage() {}
+
+func (x *SLAComplianceList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLAComplianceList.ProtoReflect.Descriptor instead.
+func (*SLAComplianceList) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SLAComplianceList) GetComplianceList() []*SLACompliance {
+	if x != nil {
+		return x.ComplianceList
+	}
+	return nil
+}
+
+type GroupSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	NumServers    int64                  `protobuf:"varint,5,opt,name=num_servers,json=numServers,proto3" json:"num_servers,omitempty"`
+	NumUp         int64                  `protobuf:"varint,6,opt,name=num_up,json=numUp,proto3" json:"num_up,omitempty"`
+	NumDown       int64                  `protobuf:"varint,7,opt,name=num_down,json=numDown,proto3" json:"num_down,omitempty"`
+	UptimeRatio   float64                `protobuf:"fixed64,8,opt,name=uptime_ratio,json=uptimeRatio,proto3" json:"uptime_ratio,omitempty"`
+	SlaTarget     float64                `protobuf:"fixed64,9,opt,name=sla_target,json=slaTarget,proto3" json:"sla_target,omitempty"`
+	Breached      bool                   `protobuf:"varint,10,opt,name=breached,proto3" json:"breached,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupSummary) Reset() {
+	*x = GroupSummary{}
+	mi := &file_proto_server_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupSummary) ProtoMessage() {}
+
+func (x *GroupSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupSummary.ProtoReflect.Descriptor instead.
+func (*GroupSummary) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GroupSummary) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GroupSummary) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GroupSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GroupSummary) GetNumServers() int64 {
+	if x != nil {
+		return x.NumServers
+	}
+	return 0
+}
+
+func (x *GroupSummary) GetNumUp() int64 {
+	if x != nil {
+		return x.NumUp
+	}
+	return 0
+}
+
+func (x *GroupSummary) GetNumDown() int64 {
+	if x != nil {
+		return x.NumDown
+	}
+	return 0
+}
+
+func (x *GroupSummary) GetUptimeRatio() float64 {
+	if x != nil {
+		return x.UptimeRatio
+	}
+	return 0
+}
+
+func (x *GroupSummary) GetSlaTarget() float64 {
+	if x != nil {
+		return x.SlaTarget
+	}
+	return 0
+}
+
+func (x *GroupSummary) GetBreached() bool {
+	if x != nil {
+		return x.Breached
+	}
+	return false
+}
+
+type GroupSummaryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupList     []*GroupSummary        `protobuf:"bytes,1,rep,name=groupList,proto3" json:"groupList,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupSummaryList) Reset() {
+	*x = GroupSummaryList{}
+	mi := &file_proto_server_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupSummaryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupSummaryList) ProtoMessage() {}
+
+func (x *GroupSummaryList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_server_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupSummaryList.ProtoReflect.Descriptor instead.
+func (*GroupSummaryList) Descriptor() ([]byte, []int) {
+	return file_proto_server_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GroupSummaryList) GetGroupList() []*GroupSummary {
+	if x != nil {
+		return x.GroupList
+	}
+	return nil
+}
+
 var File_proto_server_proto protoreflect.FileDescriptor
 
 const file_proto_server_proto_rawDesc = "" +
 	"\n" +
 	"\x12proto/server.proto\x12\x1dserver_administration_service\"\x0e\n" +
-	"\fEmptyRequest\"V\n" +
+	"\fEmptyRequest\"7\n" +
+	"\x0eAddressRequest\x12%\n" +
+	"\x0elabel_selector\x18\x01 \x01(\tR\rlabelSelector\"V\n" +
 	"\x12IDAddressAndStatus\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
@@ -341,10 +787,49 @@ const file_proto_server_proto_rawDesc = "" +
 	"numServers\x12\"\n" +
 	"\fnumOnServers\x18\x02 \x01(\x03R\fnumOnServers\x12$\n" +
 	"\rnumOffServers\x18\x03 \x01(\x03R\rnumOffServers\x12(\n" +
-	"\x0fmeanUpTimeRatio\x18\x04 \x01(\x01R\x0fmeanUpTimeRatio2\x98\x02\n" +
-	"\x1bServerAdministrationService\x12y\n" +
-	"\x13GetAddressAndStatus\x12+.server_administration_service.EmptyRequest\x1a5.server_administration_service.IDAddressAndStatusList\x12~\n" +
-	"\x15GetServersInformation\x12*.server_administration_service.TimeRequest\x1a9.server_administration_service.ServersInformationResponseB\tZ\a./protob\x06proto3"
+	"\x0fmeanUpTimeRatio\x18\x04 \x01(\x01R\x0fmeanUpTimeRatio\".\n" +
+	"\x0fServerIDRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\"\x80\x01\n" +
+	"\x12NotificationStatus\x12\x1e\n" +
+	"\n" +
+	"suppressed\x18\x01 \x01(\bR\n" +
+	"suppressed\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x122\n" +
+	"\x15maintenance_window_id\x18\x03 \x01(\tR\x13maintenanceWindowId\"\xf7\x01\n" +
+	"\rSLACompliance\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12\x1f\n" +
+	"\vserver_name\x18\x02 \x01(\tR\n" +
+	"serverName\x12\x1d\n" +
+	"\n" +
+	"sla_target\x18\x03 \x01(\x01R\tslaTarget\x12\x1a\n" +
+	"\bachieved\x18\x04 \x01(\x01R\bachieved\x124\n" +
+	"\x16error_budget_remaining\x18\x05 \x01(\x01R\x14errorBudgetRemaining\x12\x1b\n" +
+	"\tburn_rate\x18\x06 \x01(\x01R\bburnRate\x12\x1a\n" +
+	"\bbreached\x18\a \x01(\bR\bbreached\"i\n" +
+	"\x11SLAComplianceList\x12T\n" +
+	"\x0ecomplianceList\x18\x01 \x03(\v2,.server_administration_service.SLAComplianceR\x0ecomplianceList\"\x9a\x02\n" +
+	"\fGroupSummary\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1f\n" +
+	"\vnum_servers\x18\x05 \x01(\x03R\n" +
+	"numServers\x12\x15\n" +
+	"\x06num_up\x18\x06 \x01(\x03R\x05numUp\x12\x19\n" +
+	"\bnum_down\x18\a \x01(\x03R\anumDown\x12!\n" +
+	"\fuptime_ratio\x18\b \x01(\x01R\vuptimeRatio\x12\x1d\n" +
+	"\n" +
+	"sla_target\x18\t \x01(\x01R\tslaTarget\x12\x1a\n" +
+	"\bbreached\x18\n" +
+	" \x01(\bR\bbreached\"]\n" +
+	"\x10GroupSummaryList\x12I\n" +
+	"\tgroupList\x18\x01 \x03(\v2+.server_administration_service.GroupSummaryR\tgroupList2\xfd\x04\n" +
+	"\x1bServerAdministrationService\x12{\n" +
+	"\x13GetAddressAndStatus\x12-.server_administration_service.AddressRequest\x1a5.server_administration_service.IDAddressAndStatusList\x12~\n" +
+	"\x15GetServersInformation\x12*.server_administration_service.TimeRequest\x1a9.server_administration_service.ServersInformationResponse\x12z\n" +
+	"\x15GetNotificationStatus\x12..server_administration_service.ServerIDRequest\x1a1.server_administration_service.NotificationStatus\x12p\n" +
+	"\x10GetSLACompliance\x12*.server_administration_service.TimeRequest\x1a0.server_administration_service.SLAComplianceList\x12s\n" +
+	"\x14GetGroupsInformation\x12*.server_administration_service.TimeRequest\x1a/.server_administration_service.GroupSummaryListB\tZ\a./protob\x06proto3"
 
 var (
 	file_proto_server_proto_rawDescOnce sync.Once
@@ -358,26 +843,41 @@ func file_proto_server_proto_rawDescGZIP() []byte {
 	return file_proto_server_proto_rawDescData
 }
 
-var file_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_server_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_server_proto_goTypes = []any{
 	(*EmptyRequest)(nil),               // 0: server_administration_service.EmptyRequest
-	(*IDAddressAndStatus)(nil),         // 1: server_administration_service.IDAddressAndStatus
-	(*IDAddressAndStatusList)(nil),     // 2: server_administration_service.IDAddressAndStatusList
-	(*EmptyResponse)(nil),              // 3: server_administration_service.EmptyResponse
-	(*TimeRequest)(nil),                // 4: server_administration_service.TimeRequest
-	(*ServersInformationResponse)(nil), // 5: server_administration_service.ServersInformationResponse
+	(*AddressRequest)(nil),             // 1: server_administration_service.AddressRequest
+	(*IDAddressAndStatus)(nil),         // 2: server_administration_service.IDAddressAndStatus
+	(*IDAddressAndStatusList)(nil),     // 3: server_administration_service.IDAddressAndStatusList
+	(*EmptyResponse)(nil),              // 4: server_administration_service.EmptyResponse
+	(*TimeRequest)(nil),                // 5: server_administration_service.TimeRequest
+	(*ServersInformationResponse)(nil), // 6: server_administration_service.ServersInformationResponse
+	(*ServerIDRequest)(nil),            // 7: server_administration_service.ServerIDRequest
+	(*NotificationStatus)(nil),         // 8: server_administration_service.NotificationStatus
+	(*SLACompliance)(nil),              // 9: server_administration_service.SLACompliance
+	(*SLAComplianceList)(nil),          // 10: server_administration_service.SLAComplianceList
+	(*GroupSummary)(nil),               // 11: server_administration_service.GroupSummary
+	(*GroupSummaryList)(nil),           // 12: server_administration_service.GroupSummaryList
 }
 var file_proto_server_proto_depIdxs = []int32{
-	1, // 0: server_administration_service.IDAddressAndStatusList.serverList:type_name -> server_administration_service.IDAddressAndStatus
-	0, // 1: server_administration_service.ServerAdministrationService.GetAddressAndStatus:input_type -> server_administration_service.EmptyRequest
-	4, // 2: server_administration_service.ServerAdministrationService.GetServersInformation:input_type -> server_administration_service.TimeRequest
-	2, // 3: server_administration_service.ServerAdministrationService.GetAddressAndStatus:output_type -> server_administration_service.IDAddressAndStatusList
-	5, // 4: server_administration_service.ServerAdministrationService.GetServersInformation:output_type -> server_administration_service.ServersInformationResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2,  // 0: server_administration_service.IDAddressAndStatusList.serverList:type_name -> server_administration_service.IDAddressAndStatus
+	9,  // 1: server_administration_service.SLAComplianceList.complianceList:type_name -> server_administration_service.SLACompliance
+	11, // 2: server_administration_service.GroupSummaryList.groupList:type_name -> server_administration_service.GroupSummary
+	1,  // 3: server_administration_service.ServerAdministrationService.GetAddressAndStatus:input_type -> server_administration_service.AddressRequest
+	5,  // 4: server_administration_service.ServerAdministrationService.GetServersInformation:input_type -> server_administration_service.TimeRequest
+	7,  // 5: server_administration_service.ServerAdministrationService.GetNotificationStatus:input_type -> server_administration_service.ServerIDRequest
+	5,  // 6: server_administration_service.ServerAdministrationService.GetSLACompliance:input_type -> server_administration_service.TimeRequest
+	5,  // 7: server_administration_service.ServerAdministrationService.GetGroupsInformation:input_type -> server_administration_service.TimeRequest
+	3,  // 8: server_administration_service.ServerAdministrationService.GetAddressAndStatus:output_type -> server_administration_service.IDAddressAndStatusList
+	6,  // 9: server_administration_service.ServerAdministrationService.GetServersInformation:output_type -> server_administration_service.ServersInformationResponse
+	8,  // 10: server_administration_service.ServerAdministrationService.GetNotificationStatus:output_type -> server_administration_service.NotificationStatus
+	10, // 11: server_administration_service.ServerAdministrationService.GetSLACompliance:output_type -> server_administration_service.SLAComplianceList
+	12, // 12: server_administration_service.ServerAdministrationService.GetGroupsInformation:output_type -> server_administration_service.GroupSummaryList
+	8,  // [8:13] is the sub-list for method output_type
+	3,  // [3:8] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_proto_server_proto_init() }
@@ -391,7 +891,7 @@ func file_proto_server_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_server_proto_rawDesc), len(file_proto_server_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
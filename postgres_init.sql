@@ -26,3 +26,5 @@ CREATE TABLE IF NOT EXISTS servers (
     last_updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     ipv4 VARCHAR(255) NOT NULL
 );
+
+CREATE DATABASE mail_service_db;
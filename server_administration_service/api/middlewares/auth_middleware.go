@@ -36,6 +36,7 @@ func AdminMiddleware(next http.Handler) http.Handler {
 
 		logging.LogMessage("server_administration_service", "This user is an admin! Forwarding to next handler.", "INFO")
 
+		setUserID(r, data)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -82,6 +83,14 @@ func UserMiddleware(next http.Handler) http.Handler {
 		// } else {
 		// 	r.Header.Set("userID", data["id"].(string))
 		// }
+		setUserID(r, data)
 		next.ServeHTTP(w, r)
 	})
+}
+
+// setUserID passes the user ID of the token on to the handlers, which record it in the audit log.
+// It replaces any userID header the client sent.
+func setUserID(r *http.Request, data map[string]any) {
+	userID, _ := data["id"].(string)
+	r.Header.Set("userID", userID)
 }
\ No newline at end of file
@@ -8,11 +8,62 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func RegisterRoutes(r *mux.Router, serverHandler handler.ServerRestHandler) {
+func RegisterRoutes(r *mux.Router, serverHandler handler.ServerRestHandler, maintenanceHandler handler.MaintenanceHandler, slaHandler handler.SLAHandler, statusHandler handler.StatusHandler, groupHandler handler.ServerGroupHandler, dependencyHandler handler.DependencyHandler, discoveryHandler handler.DiscoveryHandler, jobHandler handler.JobHandler, searchHandler handler.SearchHandler, auditHandler handler.AuditHandler, trashHandler handler.TrashHandler, v1Handler handler.ServerV1Handler) {
 	r.Handle("/create", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.CreateServer))).Methods("POST")
 	r.Handle("/view", middlewares.UserMiddleware(http.HandlerFunc(serverHandler.ViewServers))).Methods("GET")
+	r.Handle("/servers/{server_id}", middlewares.UserMiddleware(http.HandlerFunc(serverHandler.GetServer))).Methods("GET")
 	r.Handle("/update", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.UpdateServer))).Methods("PUT")
+	r.Handle("/status", middlewares.AdminMiddleware(http.HandlerFunc(statusHandler.ChangeStatus))).Methods("PUT")
 	r.Handle("/delete", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.DeleteServer))).Methods("DELETE")
+	r.Handle("/bulk/update", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.BulkUpdateServers))).Methods("POST")
+	r.Handle("/bulk/delete", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.BulkDeleteServers))).Methods("POST")
+	// async=true runs the import or export as a job, registered first so that it takes precedence
+	r.Handle("/import", middlewares.AdminMiddleware(http.HandlerFunc(jobHandler.StartImportJob))).Methods("POST").Queries("async", "true")
 	r.Handle("/import", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.ImportServers))).Methods("POST")
+	r.Handle("/import/progress/{id}", middlewares.AdminMiddleware(http.HandlerFunc(serverHandler.ImportProgress))).Methods("GET")
+	r.Handle("/export", middlewares.UserMiddleware(http.HandlerFunc(jobHandler.StartExportJob))).Methods("GET").Queries("async", "true")
 	r.Handle("/export", middlewares.UserMiddleware(http.HandlerFunc(serverHandler.ExportServers))).Methods("GET")
-}
\ No newline at end of file
+	r.Handle("/jobs/{id}", middlewares.UserMiddleware(http.HandlerFunc(jobHandler.GetJob))).Methods("GET")
+	r.Handle("/search", middlewares.UserMiddleware(http.HandlerFunc(searchHandler.SearchServers))).Methods("GET")
+	r.Handle("/search/reindex", middlewares.AdminMiddleware(http.HandlerFunc(searchHandler.Reindex))).Methods("POST")
+	r.Handle("/jobs/{id}/result", middlewares.UserMiddleware(http.HandlerFunc(jobHandler.GetJobResult))).Methods("GET")
+	r.Handle("/audit", middlewares.AdminMiddleware(http.HandlerFunc(auditHandler.GetAuditLog))).Methods("GET")
+	r.Handle("/trash", middlewares.AdminMiddleware(http.HandlerFunc(trashHandler.GetTrash))).Methods("GET")
+	r.Handle("/trash/{server_id}/restore", middlewares.AdminMiddleware(http.HandlerFunc(trashHandler.RestoreServer))).Methods("POST")
+
+	r.Handle("/maintenance_windows", middlewares.AdminMiddleware(http.HandlerFunc(maintenanceHandler.CreateMaintenanceWindow))).Methods("POST")
+	r.Handle("/maintenance_windows", middlewares.UserMiddleware(http.HandlerFunc(maintenanceHandler.GetMaintenanceWindows))).Methods("GET")
+	r.Handle("/maintenance_windows/{id}", middlewares.UserMiddleware(http.HandlerFunc(maintenanceHandler.GetMaintenanceWindow))).Methods("GET")
+	r.Handle("/maintenance_windows/{id}", middlewares.AdminMiddleware(http.HandlerFunc(maintenanceHandler.UpdateMaintenanceWindow))).Methods("PUT")
+	r.Handle("/maintenance_windows/{id}", middlewares.AdminMiddleware(http.HandlerFunc(maintenanceHandler.DeleteMaintenanceWindow))).Methods("DELETE")
+
+	r.Handle("/groups", middlewares.AdminMiddleware(http.HandlerFunc(groupHandler.CreateGroup))).Methods("POST")
+	r.Handle("/groups", middlewares.UserMiddleware(http.HandlerFunc(groupHandler.GetGroups))).Methods("GET")
+	r.Handle("/groups/summary", middlewares.UserMiddleware(http.HandlerFunc(groupHandler.GetGroupSummaries))).Methods("GET")
+	r.Handle("/groups/{id}", middlewares.UserMiddleware(http.HandlerFunc(groupHandler.GetGroup))).Methods("GET")
+	r.Handle("/groups/{id}", middlewares.AdminMiddleware(http.HandlerFunc(groupHandler.UpdateGroup))).Methods("PUT")
+	r.Handle("/groups/{id}", middlewares.AdminMiddleware(http.HandlerFunc(groupHandler.DeleteGroup))).Methods("DELETE")
+	r.Handle("/groups/{id}/summary", middlewares.UserMiddleware(http.HandlerFunc(groupHandler.GetGroupSummary))).Methods("GET")
+	r.Handle("/groups/{id}/members", middlewares.UserMiddleware(http.HandlerFunc(groupHandler.GetGroupMembers))).Methods("GET")
+	r.Handle("/groups/{id}/members", middlewares.AdminMiddleware(http.HandlerFunc(groupHandler.AddGroupMembers))).Methods("POST")
+	r.Handle("/groups/{id}/members/{server_id}", middlewares.AdminMiddleware(http.HandlerFunc(groupHandler.RemoveGroupMember))).Methods("DELETE")
+
+	r.Handle("/dependencies", middlewares.UserMiddleware(http.HandlerFunc(dependencyHandler.GetDependencyGraph))).Methods("GET")
+	r.Handle("/dependencies", middlewares.AdminMiddleware(http.HandlerFunc(dependencyHandler.AddDependency))).Methods("POST")
+	r.Handle("/dependencies/{server_id}/{depends_on}", middlewares.AdminMiddleware(http.HandlerFunc(dependencyHandler.RemoveDependency))).Methods("DELETE")
+
+	r.Handle("/discovery/scans", middlewares.AdminMiddleware(http.HandlerFunc(discoveryHandler.StartScan))).Methods("POST")
+	r.Handle("/discovery/scans", middlewares.AdminMiddleware(http.HandlerFunc(discoveryHandler.GetScans))).Methods("GET")
+	r.Handle("/discovery/scans/{id}", middlewares.AdminMiddleware(http.HandlerFunc(discoveryHandler.GetScan))).Methods("GET")
+	r.Handle("/discovery/scans/{id}/accept", middlewares.AdminMiddleware(http.HandlerFunc(discoveryHandler.AcceptHosts))).Methods("POST")
+
+	r.Handle("/sla/compliance", middlewares.UserMiddleware(http.HandlerFunc(slaHandler.GetCompliance))).Methods("GET")
+
+	// The servers as a resource, the routes above stay for the clients that use them
+	v1 := r.PathPrefix("/api/v1").Subrouter()
+	v1.Handle("/servers", middlewares.UserMiddleware(http.HandlerFunc(v1Handler.ListServers))).Methods("GET")
+	v1.Handle("/servers", middlewares.AdminMiddleware(http.HandlerFunc(v1Handler.CreateServer))).Methods("POST")
+	v1.Handle("/servers/{server_id}", middlewares.UserMiddleware(http.HandlerFunc(v1Handler.GetServer))).Methods("GET")
+	v1.Handle("/servers/{server_id}", middlewares.AdminMiddleware(http.HandlerFunc(v1Handler.PatchServer))).Methods("PATCH")
+	v1.Handle("/servers/{server_id}", middlewares.AdminMiddleware(http.HandlerFunc(v1Handler.DeleteServer))).Methods("DELETE")
+}
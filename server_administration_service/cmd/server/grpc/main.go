@@ -61,9 +61,15 @@ func main() {
 	serverGRPCRepository := repository.NewServerGRPCRepository(db)
 	serverGRPCService := service.NewServerGRPCService(serverGRPCRepository)
 
+	maintenanceRepository := repository.NewMaintenanceRepository(db)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepository)
+
 	serverInfoRepository := repository.NewServerInfoRepository(db, esc)
-	serverInfoService := service.NewServerInfoService(serverInfoRepository)
-	serverGRPCHandler := handler.NewServerGRPCHandler(serverGRPCService, serverInfoService)
+	serverInfoService := service.NewServerInfoService(serverInfoRepository, maintenanceRepository)
+	slaService := service.NewSLAService(serverInfoRepository, maintenanceRepository)
+	serverGroupService := service.NewServerGroupService(repository.NewServerGroupRepository(db), serverInfoRepository, maintenanceRepository)
+	dependencyService := service.NewDependencyService(repository.NewDependencyRepository(db))
+	serverGRPCHandler := handler.NewServerGRPCHandler(serverGRPCService, serverInfoService, maintenanceService, slaService, serverGroupService, dependencyService)
 
 	serverGRPCPort := env.GetEnv("SERVER_ADMINISTRATION_GPRC_PORT", "50051")
 	logging.LogMessage("server_administration_service", "Starting gRPC server on port " + serverGRPCPort, "INFO")
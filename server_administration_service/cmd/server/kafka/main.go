@@ -68,7 +68,9 @@ func main() {
 
 	// Initialize the server
 	serverKafkaRepository := repository.NewServerKafkaRepository(db, esc)
-	serverKafkaService := service.NewServerKafaService(serverKafkaRepository)
+	maintenanceRepository := repository.NewMaintenanceRepository(db)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepository)
+	serverKafkaService := service.NewServerKafaService(serverKafkaRepository, maintenanceService)
 	serverKafkaHandler := handler.NewServerConsumerHandler(serverKafkaService)
 
 	logging.LogMessage("server_administration_service", "Connecting to Kafka brokers: "+brokers[0], "INFO")
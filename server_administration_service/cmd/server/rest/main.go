@@ -5,10 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"server_administration_service/api/routes"
+	"server_administration_service/infrastructure/discovery"
+	"server_administration_service/infrastructure/elasticsearch"
 	"server_administration_service/infrastructure/postgres"
 	"server_administration_service/internal/handler"
 	"server_administration_service/internal/repository"
 	"server_administration_service/internal/service"
+	"strconv"
+	"time"
 
 	"github.com/flashhhhh/pkg/env"
 	"github.com/flashhhhh/pkg/logging"
@@ -53,21 +57,91 @@ func main() {
 		logging.LogMessage("server_administration_service", "Skipping database migrations in non-local environment", "INFO")
 	}
 
-	// Initialize the server
-	serverRepository := repository.NewServerCRUDRepository(db)
-	serverService := service.NewServerCRUDService(serverRepository)
+	maintenanceRepository := repository.NewMaintenanceRepository(db)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepository)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+
+	// Initialize ES client
+	esAddress := env.GetEnv("ES_HOST", "http://localhost") +
+				":" + env.GetEnv("ES_PORT", "9200")
+	es := elasticsearch.ConnectES(esAddress)
+	esc := elasticsearch.NewElasticsearchClient(es)
+
+	// Initialize the server, its writes mirrored into the search index
+	serverSearchRepository := repository.NewServerSearchRepository(esc, env.GetEnv("ES_SERVER_INDEX", "servers"))
+	serverRepository := repository.NewIndexedServerCRUDRepository(repository.NewServerCRUDRepository(db), serverSearchRepository)
+	serverSearchService := service.NewServerSearchService(serverSearchRepository, serverRepository)
+	if err := serverSearchService.PrepareIndex(); err != nil {
+		logging.LogMessage("server_administration_service", "Search index not ready, /search/reindex fills it: "+err.Error(), "ERROR")
+	}
+	searchHandler := handler.NewSearchHandler(serverSearchService)
+
+	serverInfoRepository := repository.NewServerInfoRepository(db, esc)
+	slaService := service.NewSLAService(serverInfoRepository, maintenanceRepository)
+
+	serverKafkaRepository := repository.NewServerKafkaRepository(db, esc)
+	serverStatusService := service.NewServerStatusService(serverKafkaRepository, maintenanceService)
+	statusHandler := handler.NewStatusHandler(serverStatusService)
+
+	serverInfoService := service.NewServerInfoService(serverInfoRepository, maintenanceRepository)
+	auditRepository := repository.NewAuditRepository(db)
+	serverService := service.NewServerCRUDService(serverRepository, serverStatusService, serverInfoService, auditRepository)
 	serverHandler := handler.NewServerRestHandler(serverService)
+	serverV1Handler := handler.NewServerV1Handler(serverService)
+	auditHandler := handler.NewAuditHandler(service.NewAuditService(auditRepository))
+
+	serverGroupRepository := repository.NewServerGroupRepository(db)
+	serverGroupService := service.NewServerGroupService(serverGroupRepository, serverInfoRepository, maintenanceRepository)
+	serverGroupHandler := handler.NewServerGroupHandler(serverGroupService)
+	slaHandler := handler.NewSLAHandler(slaService, serverGroupService)
+
+	dependencyService := service.NewDependencyService(repository.NewDependencyRepository(db))
+	dependencyHandler := handler.NewDependencyHandler(dependencyService)
+
+	discoveryTimeout, _ := strconv.Atoi(env.GetEnv("DISCOVERY_TIMEOUT_MS", "1000"))
+	prober := discovery.NewProber(time.Duration(discoveryTimeout) * time.Millisecond)
+	discoveryService := service.NewDiscoveryService(repository.NewDiscoveryRepository(db), serverRepository, prober)
+	discoveryHandler := handler.NewDiscoveryHandler(discoveryService)
+
+	// Jobs interrupted by the last shutdown run again, finished ones are purged with the trash once past the retention
+	jobRetention := service.DefaultJobRetention
+	if days, err := strconv.Atoi(env.GetEnv("JOB_RETENTION_DAYS", "7")); err == nil && days > 0 {
+		jobRetention = time.Duration(days) * 24 * time.Hour
+	}
+	jobService := service.NewJobService(repository.NewJobRepository(db), serverService, jobRetention)
+	if err := jobService.ResumeJobs(); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to resume jobs: "+err.Error(), "ERROR")
+	}
+	jobHandler := handler.NewJobHandler(jobService)
+
+	// Deleted servers wait in the trash, purged every hour once past the retention
+	retention := service.DefaultTrashRetention
+	if days, err := strconv.Atoi(env.GetEnv("TRASH_RETENTION_DAYS", "30")); err == nil && days > 0 {
+		retention = time.Duration(days) * 24 * time.Hour
+	}
+	purgeHistory, _ := strconv.ParseBool(env.GetEnv("TRASH_PURGE_STATUS_HISTORY", "false"))
+	trashService := service.NewTrashService(repository.NewTrashRepository(db, esc), serverSearchRepository, auditRepository, retention, purgeHistory)
+	go func() {
+		for {
+			trashService.PurgeExpired()
+			jobService.PurgeFinishedJobs()
+			time.Sleep(time.Hour)
+		}
+	}()
+	trashHandler := handler.NewTrashHandler(trashService)
 
 	// Initialize the HTTP server
 	serverPort := env.GetEnv("SERVER_ADMINISTRATION_PORT", "10002")
 	
 	r := mux.NewRouter()
-	routes.RegisterRoutes(r, serverHandler)
+	routes.RegisterRoutes(r, serverHandler, maintenanceHandler, slaHandler, statusHandler, serverGroupHandler, dependencyHandler, discoveryHandler, jobHandler, searchHandler, auditHandler, trashHandler, serverV1Handler)
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins, change this for security
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "If-Match", "If-None-Match"},
+		// Clients read the version of a server from its ETag to send it back as If-Match
+		ExposedHeaders:   []string{"ETag"},
 		AllowCredentials: true,
 	}).Handler(r)
 
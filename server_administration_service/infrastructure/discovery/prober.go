@@ -0,0 +1,58 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prober checks whether a host answers and what it is called.
+type Prober interface {
+	Ping(address string) bool
+	ConnectTCP(address string, port int) bool
+	LookupHostname(address string) string
+}
+
+type prober struct {
+	timeout time.Duration
+}
+
+func NewProber(timeout time.Duration) Prober {
+	return &prober{
+		timeout: timeout,
+	}
+}
+
+// Ping sends a single ICMP echo request through the system ping, which doesn't need raw sockets here.
+func (p *prober) Ping(address string) bool {
+	seconds := int(p.timeout.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return exec.Command("ping", "-c", "1", "-w", strconv.Itoa(seconds), address).Run() == nil
+}
+
+// ConnectTCP reports whether a TCP connection to the port can be opened.
+func (p *prober) ConnectTCP(address string, port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, strconv.Itoa(port)), p.timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// LookupHostname returns the first reverse DNS name of the address without the trailing dot, "" when there is none.
+func (p *prober) LookupHostname(address string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	names, err := net.DefaultResolver.LookupAddr(ctx, address)
+	if err != nil || len(names) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(names[0], ".")
+}
@@ -3,7 +3,9 @@ package elasticsearch
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
 
 	"github.com/elastic/go-elasticsearch/v9"
 	"github.com/elastic/go-elasticsearch/v9/esapi"
@@ -13,6 +15,11 @@ import (
 type ElasticsearchClient interface {
 	Index(ctx context.Context, index string, body []byte) (error)
 	Search(ctx context.Context, index string, buf bytes.Buffer) (*esapi.Response, error)
+	Bulk(ctx context.Context, index string, body []byte) error
+	CreateIndex(ctx context.Context, index string, body []byte) (bool, error)
+	DeleteByQuery(ctx context.Context, index string, body []byte) (int64, error)
+	OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error)
+	ClosePointInTime(ctx context.Context, id string) error
 }
 
 type elasticsearchClient struct {
@@ -45,12 +52,17 @@ func (esc *elasticsearchClient) Index(ctx context.Context, index string, data []
 	return nil
 }
 
+// Search runs the query of buf against index. A search through a point in time, which names its own
+// index, goes without one.
 func (esc *elasticsearchClient) Search(ctx context.Context, index string, buf bytes.Buffer) (*esapi.Response, error) {
-	resp, err := esc.es.Search(
+	options := []func(*esapi.SearchRequest){
 		esc.es.Search.WithContext(ctx),
-		esc.es.Search.WithIndex(index),
 		esc.es.Search.WithBody(&buf),
-	)
+	}
+	if index != "" {
+		options = append(options, esc.es.Search.WithIndex(index))
+	}
+	resp, err := esc.es.Search(options...)
 	if err != nil {
 		return nil, errors.New("can't send search request to ES")
 	}
@@ -59,4 +71,139 @@ func (esc *elasticsearchClient) Search(ctx context.Context, index string, buf by
 		return nil, errors.New("Error response from ES: " + resp.String())
 	}
 	return resp, nil
-}
\ No newline at end of file
+}
+
+// Bulk runs the newline-delimited actions of body against index. It fails when any of them does,
+// with the reason of the first; deleting a missing document is not a failure.
+func (esc *elasticsearchClient) Bulk(ctx context.Context, index string, body []byte) error {
+	req := esapi.BulkRequest{
+		Index: index,
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, esc.es)
+	if err != nil {
+		return errors.New("can't send bulk request to ES")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.New("Error response from ES: " + res.String())
+	}
+
+	var answer struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error *struct {
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return errors.New("can't decode bulk response from ES: " + err.Error())
+	}
+	if !answer.Errors {
+		return nil
+	}
+	for _, item := range answer.Items {
+		for action, result := range item {
+			if result.Error != nil {
+				return errors.New("ES bulk " + action + " failed: " + result.Error.Reason)
+			}
+		}
+	}
+	return errors.New("ES bulk request failed")
+}
+
+// CreateIndex creates index with the settings and mappings of body unless it exists already. It
+// reports whether it created the index.
+func (esc *elasticsearchClient) CreateIndex(ctx context.Context, index string, body []byte) (bool, error) {
+	exists, err := esapi.IndicesExistsRequest{Index: []string{index}}.Do(ctx, esc.es)
+	if err != nil {
+		return false, errors.New("can't send request to ES")
+	}
+	exists.Body.Close()
+	if exists.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	res, err := esapi.IndicesCreateRequest{Index: index, Body: bytes.NewReader(body)}.Do(ctx, esc.es)
+	if err != nil {
+		return false, errors.New("can't send request to ES")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return false, errors.New("Error response from ES: " + res.String())
+	}
+	return true, nil
+}
+
+// DeleteByQuery deletes the documents of index matching the query of body and returns how many it deleted.
+// Documents changed while it runs are skipped rather than failing the request.
+func (esc *elasticsearchClient) DeleteByQuery(ctx context.Context, index string, body []byte) (int64, error) {
+	res, err := esapi.DeleteByQueryRequest{
+		Index:     []string{index},
+		Body:      bytes.NewReader(body),
+		Conflicts: "proceed",
+	}.Do(ctx, esc.es)
+	if err != nil {
+		return 0, errors.New("can't send delete by query request to ES")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return 0, errors.New("Error response from ES: " + res.String())
+	}
+
+	var answer struct {
+		Deleted int64 `json:"deleted"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return 0, errors.New("can't decode delete by query response from ES: " + err.Error())
+	}
+	return answer.Deleted, nil
+}
+
+// OpenPointInTime opens a point in time on index, a view of it that searches can page through while
+// it changes, and returns its ID. It's kept for keepAlive after each search using it.
+func (esc *elasticsearchClient) OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error) {
+	res, err := esapi.OpenPointInTimeRequest{
+		Index:     []string{index},
+		KeepAlive: keepAlive,
+	}.Do(ctx, esc.es)
+	if err != nil {
+		return "", errors.New("can't send open point in time request to ES")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", errors.New("Error response from ES: " + res.String())
+	}
+
+	var answer struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&answer); err != nil {
+		return "", errors.New("can't decode open point in time response from ES: " + err.Error())
+	}
+	return answer.ID, nil
+}
+
+func (esc *elasticsearchClient) ClosePointInTime(ctx context.Context, id string) error {
+	body, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := esapi.ClosePointInTimeRequest{Body: bytes.NewReader(body)}.Do(ctx, esc.es)
+	if err != nil {
+		return errors.New("can't send close point in time request to ES")
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return errors.New("Error response from ES: " + res.String())
+	}
+	return nil
+}
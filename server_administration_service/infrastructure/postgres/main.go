@@ -13,7 +13,8 @@ func ConnectDB(dsn string) *gorm.DB {
 	logging.LogMessage("server_administration_service", "Connecting to the database...", "INFO")
 	logging.LogMessage("server_administration_service", "Database connection string: "+dsn, "DEBUG")
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique violations into gorm.ErrDuplicatedKey, told apart from other failures
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to connect to the database: "+err.Error(), "FATAL")
 		logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
@@ -27,20 +28,71 @@ func ConnectDB(dsn string) *gorm.DB {
 func Migrate(db *gorm.DB) {
 	logging.LogMessage("server_administration_service", "Migrating the database...", "INFO")
 
-	// Check if the table exists
-	tableExists := db.Migrator().HasTable(&domain.Server{})
-	if !tableExists {
+	models := []interface{}{&domain.Server{}, &domain.MaintenanceWindow{}, &domain.ServerGroup{}, &domain.ServerGroupMember{}, &domain.ServerDependency{}, &domain.DiscoveryScan{}, &domain.DiscoveredHost{}, &domain.Job{}, &domain.AuditEntry{}}
+	for _, model := range models {
+		// Check if the table exists
+		if db.Migrator().HasTable(model) {
+			continue
+		}
+
 		logging.LogMessage("server_administration_service", "Tables don't exist, migrating...", "INFO")
-		
-		err := db.AutoMigrate(&domain.Server{})
-		if err != nil {
+		if err := db.AutoMigrate(model); err != nil {
 			logging.LogMessage("server_administration_service", "Failed to migrate the database: "+err.Error(), "FATAL")
 			logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
 			os.Exit(1)
 		}
-		
-		logging.LogMessage("server_administration_service", "Database migrated successfully", "INFO")
-	} else {
-		logging.LogMessage("server_administration_service", "Tables already exist, skipping migration", "INFO")
 	}
-}
\ No newline at end of file
+
+	// Servers used to have a single IPv4 address, which becomes their primary address
+	if db.Migrator().HasColumn(&domain.Server{}, "ipv4") {
+		if err := db.Migrator().RenameColumn(&domain.Server{}, "ipv4", "PrimaryAddress"); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to rename column ipv4: "+err.Error(), "FATAL")
+			logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
+			os.Exit(1)
+		}
+	}
+
+	// Columns added after the tables were first created
+	columns := []struct {
+		model  interface{}
+		column string
+	}{
+		{&domain.Server{}, "SLATarget"},
+		{&domain.Server{}, "Labels"},
+		{&domain.Server{}, "Addresses"},
+		{&domain.Server{}, "Notes"},
+		{&domain.Server{}, "DeletedAt"},
+		{&domain.Server{}, "Version"},
+		{&domain.MaintenanceWindow{}, "GroupIDs"},
+	}
+	for _, c := range columns {
+		model, column := c.model, c.column
+		if db.Migrator().HasColumn(model, column) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, column); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to add column "+column+": "+err.Error(), "FATAL")
+			logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
+			os.Exit(1)
+		}
+	}
+
+	if err := db.Model(&domain.Server{}).Where("addresses = '[]'").
+		Update("addresses", gorm.Expr("jsonb_build_array(jsonb_build_object('type', ?::text, 'address', primary_address, 'primary', true))", domain.AddressTypeIPv4)).Error; err != nil {
+		logging.LogMessage("server_administration_service", "Failed to migrate the server addresses: "+err.Error(), "FATAL")
+		logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
+		os.Exit(1)
+	}
+
+	// Map the legacy On/Off statuses onto the state model
+	legacyStatuses := map[string]string{"On": domain.StatusUp, "Off": domain.StatusDown}
+	for legacy, status := range legacyStatuses {
+		if err := db.Model(&domain.Server{}).Where("status = ?", legacy).Update("status", status).Error; err != nil {
+			logging.LogMessage("server_administration_service", "Failed to migrate the status "+legacy+": "+err.Error(), "FATAL")
+			logging.LogMessage("server_administration_service", "Exiting the program...", "FATAL")
+			os.Exit(1)
+		}
+	}
+
+	logging.LogMessage("server_administration_service", "Database migrated successfully", "INFO")
+}
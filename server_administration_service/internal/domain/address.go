@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+const (
+	AddressTypeIPv4 = "ipv4"
+	AddressTypeIPv6 = "ipv6"
+	AddressTypeFQDN = "fqdn"
+)
+
+// A DNS name of dot separated labels, each at most 63 characters, with an optional trailing dot.
+var fqdnLabelPattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9]{0,61}[A-Za-z0-9])?$`)
+
+// Address is one way to reach a server. The primary address is the one probed first.
+type Address struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Primary bool   `json:"primary"`
+}
+
+// Addresses are the network addresses of a server, stored as jsonb.
+type Addresses []Address
+
+func (a Addresses) Value() (driver.Value, error) {
+	return a.JSON(), nil
+}
+
+// JSON encodes the addresses as a JSON array, [] when there are none.
+func (a Addresses) JSON() string {
+	if a == nil {
+		return "[]"
+	}
+	data, _ := json.Marshal(a)
+	return string(data)
+}
+
+func (a *Addresses) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Addresses{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Addresses", value)
+	}
+	return json.Unmarshal(data, a)
+}
+
+// String renders the addresses comma separated with the primary one first, the format used by imports and exports.
+func (a Addresses) String() string {
+	values := make([]string, 0, len(a))
+	if primary, ok := a.Primary(); ok {
+		values = append(values, primary.Address)
+	}
+	for _, address := range a {
+		if !address.Primary {
+			values = append(values, address.Address)
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// ParseAddresses reads comma separated addresses, the first one being the primary address.
+// The type of every address is detected from its syntax.
+func ParseAddresses(s string) (Addresses, error) {
+	addresses := Addresses{}
+	for _, value := range strings.Split(s, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		addresses = append(addresses, Address{Address: value, Primary: len(addresses) == 0})
+	}
+	if err := addresses.Normalize(); err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// Primary returns the primary address, false when there is none.
+func (a Addresses) Primary() (Address, bool) {
+	for _, address := range a {
+		if address.Primary {
+			return address, true
+		}
+	}
+	return Address{}, false
+}
+
+// Normalize detects missing types, makes the first address primary when none is marked and validates the result:
+// at least one address, every address valid for its type, no duplicates and exactly one primary address.
+func (a Addresses) Normalize() error {
+	if len(a) == 0 {
+		return fmt.Errorf("%w: at least one address is required", ErrInvalidAddress)
+	}
+
+	seen := make(map[string]bool, len(a))
+	numPrimary := 0
+	for i := range a {
+		a[i].Address = strings.TrimSpace(a[i].Address)
+		if a[i].Type == "" {
+			a[i].Type = DetectAddressType(a[i].Address)
+		}
+		a[i].Type = strings.ToLower(a[i].Type)
+		if err := validateAddress(a[i]); err != nil {
+			return err
+		}
+
+		key := strings.ToLower(a[i].Address)
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate address %q", ErrInvalidAddress, a[i].Address)
+		}
+		seen[key] = true
+
+		if a[i].Primary {
+			numPrimary++
+		}
+	}
+
+	switch numPrimary {
+	case 0:
+		a[0].Primary = true
+	case 1:
+	default:
+		return fmt.Errorf("%w: only one address can be primary", ErrInvalidAddress)
+	}
+	return nil
+}
+
+// DetectAddressType tells IPv4 and IPv6 literals apart from host names.
+func DetectAddressType(address string) string {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return AddressTypeFQDN
+	case ip.To4() != nil && !strings.Contains(address, ":"):
+		return AddressTypeIPv4
+	}
+	return AddressTypeIPv6
+}
+
+func validateAddress(address Address) error {
+	if address.Address == "" {
+		return fmt.Errorf("%w: address is empty", ErrInvalidAddress)
+	}
+
+	valid := false
+	switch address.Type {
+	case AddressTypeIPv4, AddressTypeIPv6:
+		valid = DetectAddressType(address.Address) == address.Type
+	case AddressTypeFQDN:
+		valid = validFQDN(address.Address)
+	default:
+		return fmt.Errorf("%w: unknown type %q for %q, expected ipv4, ipv6 or fqdn", ErrInvalidAddress, address.Type, address.Address)
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %q is not a valid %s address", ErrInvalidAddress, address.Address, address.Type)
+	}
+	return nil
+}
+
+func validFQDN(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 || net.ParseIP(name) != nil {
+		return false
+	}
+	labels := strings.Split(name, ".")
+	for _, label := range labels {
+		if !fqdnLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	// An all numeric top-level label is a mistyped IP address rather than a host name
+	return strings.Trim(labels[len(labels)-1], "0123456789") != ""
+}
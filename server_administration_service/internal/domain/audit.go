@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Audited actions on servers
+const (
+	AuditCreate       = "create"
+	AuditUpdate       = "update"
+	AuditDelete       = "delete"
+	AuditDecommission = "decommission"
+	AuditRestore      = "restore"
+	AuditPurge        = "purge"
+)
+
+// AuditEntry records a change to a server: who made it, from where, and the value of every
+// changed field before and after. The entries outlive the server, so a deleted server keeps its history.
+type AuditEntry struct {
+	ID          string       `json:"id" gorm:"primaryKey;type:uuid"`
+	ServerID    string       `json:"server_id" gorm:"not null;index"`
+	UserID      string       `json:"user_id" gorm:"index"`
+	Action      string       `json:"action" gorm:"not null"`
+	Changes     AuditChanges `json:"changes" gorm:"type:jsonb;not null;default:'{}'"`
+	SourceIP    string       `json:"source_ip"`
+	CreatedTime time.Time    `json:"created_time" gorm:"autoCreateTime;index"`
+}
+
+// AuditChange is the value of a field before and after a change, nil on the side where the server didn't exist.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges are the changed fields of a server by their JSON name, stored as jsonb.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*c = AuditChanges{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+	return json.Unmarshal(data, c)
+}
+
+// DiffServers returns the fields that differ between before and after. A nil before is a created
+// server and a nil after a deleted one, every field is then a change. The timestamps aren't audited,
+// the entry has its own.
+func DiffServers(before, after *Server) AuditChanges {
+	beforeFields, afterFields := auditedFields(before), auditedFields(after)
+	changes := AuditChanges{}
+	for _, field := range auditedServerFields {
+		if before != nil && after != nil && reflect.DeepEqual(beforeFields[field], afterFields[field]) {
+			continue
+		}
+		changes[field] = AuditChange{Before: beforeFields[field], After: afterFields[field]}
+	}
+	return changes
+}
+
+var auditedServerFields = []string{"server_name", "status", "primary_address", "addresses", "sla_target", "labels", "notes"}
+
+func auditedFields(server *Server) map[string]interface{} {
+	if server == nil {
+		return map[string]interface{}{}
+	}
+	addresses, labels := server.Addresses, server.Labels
+	if addresses == nil {
+		addresses = Addresses{}
+	}
+	if labels == nil {
+		labels = Labels{}
+	}
+	return map[string]interface{}{
+		"server_name":     server.ServerName,
+		"status":          server.Status,
+		"primary_address": server.PrimaryAddress,
+		"addresses":       addresses,
+		"sla_target":      server.SLATarget,
+		"labels":          labels,
+		"notes":           server.Notes,
+	}
+}
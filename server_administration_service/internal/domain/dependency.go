@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidDependency = errors.New("invalid server dependency")
+	ErrDependencyCycle   = errors.New("the dependency would create a cycle")
+)
+
+// ServerDependency says ServerID is only reachable through DependsOnID, e.g. a server behind a switch or gateway.
+type ServerDependency struct {
+	ServerID    string `json:"server_id" gorm:"primaryKey"`
+	DependsOnID string `json:"depends_on" gorm:"primaryKey;index"`
+}
+
+type DependencyNode struct {
+	ServerID   string `json:"server_id"`
+	ServerName string `json:"server_name"`
+	Status     string `json:"status"`
+}
+
+// DependencyGraph is the topology of the servers that take part in at least one dependency.
+type DependencyGraph struct {
+	Nodes []DependencyNode   `json:"nodes"`
+	Edges []ServerDependency `json:"edges"`
+}
+
+// NewDependencyGraph keeps the edges whose two servers are known and sorts nodes and edges for a stable output.
+func NewDependencyGraph(servers []Server, dependencies []ServerDependency) DependencyGraph {
+	known := make(map[string]Server, len(servers))
+	for _, server := range servers {
+		known[server.ServerID] = server
+	}
+
+	graph := DependencyGraph{Nodes: []DependencyNode{}, Edges: []ServerDependency{}}
+	inGraph := make(map[string]bool)
+	for _, dependency := range dependencies {
+		if _, ok := known[dependency.ServerID]; !ok {
+			continue
+		}
+		if _, ok := known[dependency.DependsOnID]; !ok {
+			continue
+		}
+		graph.Edges = append(graph.Edges, dependency)
+		inGraph[dependency.ServerID] = true
+		inGraph[dependency.DependsOnID] = true
+	}
+
+	for id := range inGraph {
+		server := known[id]
+		graph.Nodes = append(graph.Nodes, DependencyNode{
+			ServerID:   server.ServerID,
+			ServerName: server.ServerName,
+			Status:     NormalizeStatus(server.Status),
+		})
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].ServerID < graph.Nodes[j].ServerID })
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].ServerID != graph.Edges[j].ServerID {
+			return graph.Edges[i].ServerID < graph.Edges[j].ServerID
+		}
+		return graph.Edges[i].DependsOnID < graph.Edges[j].DependsOnID
+	})
+	return graph
+}
+
+// statusColors colors the DOT nodes by status; other statuses keep the default color.
+var statusColors = map[string]string{
+	StatusUp:          "green",
+	StatusDegraded:    "orange",
+	StatusDown:        "red",
+	StatusUnreachable: "gray",
+}
+
+// DOT renders the graph in Graphviz format, with edges pointing from a server to the server it depends on.
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	for _, node := range g.Nodes {
+		label := node.ServerID
+		if node.ServerName != "" {
+			label = node.ServerName + " (" + node.ServerID + ")"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s", dotQuote(node.ServerID), dotQuote(label+"\n"+node.Status))
+		if color, ok := statusColors[node.Status]; ok {
+			fmt.Fprintf(&b, ", color=%s", color)
+		}
+		b.WriteString("];\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(edge.ServerID), dotQuote(edge.DependsOnID))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// DependsOn reports whether serverID reaches target by following dependencies, i.e. whether an edge
+// target -> serverID would close a cycle.
+func DependsOn(dependencies []ServerDependency, serverID, target string) bool {
+	parents := make(map[string][]string)
+	for _, dependency := range dependencies {
+		parents[dependency.ServerID] = append(parents[dependency.ServerID], dependency.DependsOnID)
+	}
+
+	seen := map[string]bool{serverID: true}
+	queue := []string{serverID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == target {
+			return true
+		}
+		for _, parent := range parents[id] {
+			if !seen[parent] {
+				seen[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+var ErrInvalidDiscoveryScan = errors.New("invalid discovery scan")
+
+// MaxDiscoveryHosts bounds the size of a scanned range, a /20 for IPv4.
+const MaxDiscoveryHosts = 4096
+
+const (
+	ScanRunning   = "Running"
+	ScanCompleted = "Completed"
+	ScanFailed    = "Failed"
+)
+
+const (
+	// HostNew is a responding host that no server has among its addresses yet
+	HostNew = "New"
+	// HostKnown is a responding host that is already in the inventory
+	HostKnown    = "Known"
+	HostAccepted = "Accepted"
+)
+
+// DiscoveryScan sweeps a CIDR range with an ICMP probe and TCP connects to Ports,
+// and records the hosts that answered in DiscoveredHosts.
+type DiscoveryScan struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	CIDR        string    `json:"cidr" gorm:"not null"`
+	Ports       []int     `json:"ports" gorm:"serializer:json"`
+	Status      string    `json:"status" gorm:"not null"`
+	Error       string    `json:"error,omitempty"`
+	NumProbed   int       `json:"num_probed" gorm:"not null;default:0"`
+	NumFound    int       `json:"num_found" gorm:"not null;default:0"`
+	StartedAt   time.Time `json:"started_at" gorm:"not null"`
+	FinishedAt  time.Time `json:"finished_at"`
+	CreatedTime time.Time `json:"created_time" gorm:"autoCreateTime"`
+}
+
+// DiscoveredHost is a host that answered a discovery scan. Method tells which probe it answered first,
+// "icmp" or "tcp/<port>"; ServerID is the server it belongs to once it's Known or Accepted.
+type DiscoveredHost struct {
+	ScanID   string `json:"scan_id" gorm:"primaryKey;type:uuid"`
+	Address  string `json:"address" gorm:"primaryKey"`
+	Hostname string `json:"hostname"`
+	Method   string `json:"method" gorm:"not null"`
+	Status   string `json:"status" gorm:"not null;index"`
+	ServerID string `json:"server_id,omitempty"`
+}
+
+// DiscoveryHosts lists the hosts of a CIDR range to probe. The network and broadcast addresses
+// of IPv4 ranges are skipped except for /31 and /32, and ranges over MaxDiscoveryHosts are rejected.
+func DiscoveryHosts(cidr string) ([]string, error) {
+	ip, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDiscoveryScan, err.Error())
+	}
+
+	ones, bits := network.Mask.Size()
+	if bits-ones > 12 {
+		return nil, fmt.Errorf("%w: %s has more than %d addresses", ErrInvalidDiscoveryScan, cidr, MaxDiscoveryHosts)
+	}
+	isIPv4 := ip.To4() != nil
+	if isIPv4 {
+		network.IP = network.IP.To4()
+	}
+
+	hosts := []string{}
+	for current := cloneIP(network.IP); network.Contains(current); current = nextIP(current) {
+		hosts = append(hosts, current.String())
+	}
+	if isIPv4 && bits-ones >= 2 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+func cloneIP(ip net.IP) net.IP {
+	return append(net.IP(nil), ip...)
+}
+
+// nextIP returns the address after ip; it wraps around to zero after the last address.
+func nextIP(ip net.IP) net.IP {
+	next := cloneIP(ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// ValidDiscoveryPort reports whether port can be probed with a TCP connect.
+func ValidDiscoveryPort(port int) bool {
+	return port > 0 && port <= 65535
+}
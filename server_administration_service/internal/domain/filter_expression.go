@@ -0,0 +1,510 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidFilterExpression = errors.New("invalid filter expression")
+
+// Filter expressions longer or more deeply nested than this are rejected rather than compiled.
+const (
+	maxFilterExpressionLength = 4096
+	maxFilterExpressionDepth  = 32
+)
+
+// Filter expression groups
+const (
+	FilterAnd = "and"
+	FilterOr  = "or"
+	FilterNot = "not"
+)
+
+// Filter expression fields; a label is "labels." followed by its key.
+const (
+	FilterFieldServerID       = "server_id"
+	FilterFieldServerName     = "server_name"
+	FilterFieldStatus         = "status"
+	FilterFieldPrimaryAddress = "primary_address"
+	FilterFieldAddress        = "address"
+	FilterFieldSLATarget      = "sla_target"
+	FilterFieldCreatedTime    = "created_time"
+	FilterFieldLastUpdated    = "last_updated"
+	FilterFieldLabels         = "labels"
+)
+
+// Filter expression operators. FilterWithin is what = becomes for an address given as a CIDR block,
+// and FilterExists is a label on its own.
+const (
+	FilterEquals       = "="
+	FilterNotEquals    = "!="
+	FilterLess         = "<"
+	FilterLessEqual    = "<="
+	FilterGreater      = ">"
+	FilterGreaterEqual = ">="
+	FilterIn           = "in"
+	FilterNotIn        = "notin"
+	FilterMatches      = "~"
+	FilterNotMatches   = "!~"
+	FilterPrefix       = "^="
+	FilterWithin       = "within"
+	FilterExists       = "exists"
+)
+
+// The operators each kind of field takes.
+var (
+	filterTextOperators   = []string{FilterEquals, FilterNotEquals, FilterIn, FilterNotIn, FilterMatches, FilterNotMatches, FilterPrefix}
+	filterNumberOperators = []string{FilterEquals, FilterNotEquals, FilterLess, FilterLessEqual, FilterGreater, FilterGreaterEqual, FilterIn, FilterNotIn}
+	filterTimeOperators   = []string{FilterEquals, FilterNotEquals, FilterLess, FilterLessEqual, FilterGreater, FilterGreaterEqual}
+)
+
+var filterFieldOperators = map[string][]string{
+	FilterFieldServerID:       filterTextOperators,
+	FilterFieldServerName:     filterTextOperators,
+	FilterFieldStatus:         filterTextOperators,
+	FilterFieldPrimaryAddress: filterTextOperators,
+	FilterFieldAddress:        filterTextOperators,
+	FilterFieldLabels:         filterTextOperators,
+	FilterFieldSLATarget:      filterNumberOperators,
+	FilterFieldCreatedTime:    filterTimeOperators,
+	FilterFieldLastUpdated:    filterTimeOperators,
+}
+
+// FilterExpression is a parsed filter expression: a comparison when Field is set, otherwise Op applied
+// to its Operands. Values are typed for their field: strings, float64 for sla_target and time.Time for
+// the times, with statuses normalized and addresses checked. Label comparisons keep the key in Label.
+type FilterExpression struct {
+	Op       string
+	Operands []*FilterExpression
+
+	Field    string
+	Label    string
+	Operator string
+	Values   []interface{}
+}
+
+// ParseFilterExpression parses a filter expression such as
+//
+//	status in (Down, Unreachable) AND (address = 10.0.0.0/8 OR labels.env = prod) AND NOT server_name ^= tmp-
+//
+// Comparisons are a field, an operator and a value, or a list of values for in and not in; a label on
+// its own tests that the server has it. They combine with AND, OR, NOT and parentheses, AND binding
+// tighter than OR. Values with spaces or operator characters are double quoted. Times are RFC 3339 or
+// dates, = and != on a date meaning that whole UTC day. An empty expression matches every server.
+func ParseFilterExpression(s string) (*FilterExpression, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	if len(s) > maxFilterExpressionLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidFilterExpression, maxFilterExpressionLength)
+	}
+
+	tokens, err := lexFilterExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	expression, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != filterTokenEnd {
+		return nil, p.unexpected(token)
+	}
+	return expression, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEnd filterTokenKind = iota
+	filterTokenWord
+	filterTokenString
+	filterTokenOperator
+	filterTokenOpen
+	filterTokenClose
+	filterTokenComma
+)
+
+type filterToken struct {
+	kind     filterTokenKind
+	text     string
+	position int
+}
+
+// Longest first, so that <= isn't read as <
+var filterOperatorTokens = []string{"!=", "<=", ">=", "!~", "^=", "=", "<", ">", "~"}
+
+func lexFilterExpression(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, filterToken{filterTokenOpen, "(", i})
+			i++
+			continue
+		case c == ')':
+			tokens = append(tokens, filterToken{filterTokenClose, ")", i})
+			i++
+			continue
+		case c == ',':
+			tokens = append(tokens, filterToken{filterTokenComma, ",", i})
+			i++
+			continue
+		case c == '"':
+			text, end, err := lexFilterString(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, filterToken{filterTokenString, text, i})
+			i = end
+			continue
+		}
+
+		operator := ""
+		for _, candidate := range filterOperatorTokens {
+			if strings.HasPrefix(s[i:], candidate) {
+				operator = candidate
+				break
+			}
+		}
+		if operator != "" {
+			tokens = append(tokens, filterToken{filterTokenOperator, operator, i})
+			i += len(operator)
+			continue
+		}
+
+		start := i
+		for i < len(s) && !unicode.IsSpace(rune(s[i])) && !strings.ContainsRune(`(),"=!<>~^`, rune(s[i])) {
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidFilterExpression, s[i], i)
+		}
+		tokens = append(tokens, filterToken{filterTokenWord, s[start:i], start})
+	}
+	return append(tokens, filterToken{filterTokenEnd, "", len(s)}), nil
+}
+
+// lexFilterString reads the double quoted string at start, where \" and \\ are escapes.
+func lexFilterString(s string, start int) (string, int, error) {
+	var b strings.Builder
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("%w: unterminated string at position %d", ErrInvalidFilterExpression, start)
+}
+
+type filterParser struct {
+	tokens []filterToken
+	next   int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.next]
+}
+
+func (p *filterParser) take() filterToken {
+	token := p.tokens[p.next]
+	if token.kind != filterTokenEnd {
+		p.next++
+	}
+	return token
+}
+
+// keyword reports whether the next token is the given keyword, in any case, and takes it if so.
+func (p *filterParser) keyword(word string) bool {
+	token := p.peek()
+	if token.kind == filterTokenWord && strings.EqualFold(token.text, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) unexpected(token filterToken) error {
+	if token.kind == filterTokenEnd {
+		return fmt.Errorf("%w: unexpected end of expression", ErrInvalidFilterExpression)
+	}
+	return fmt.Errorf("%w: unexpected %q at position %d", ErrInvalidFilterExpression, token.text, token.position)
+}
+
+func (p *filterParser) parseOr(depth int) (*FilterExpression, error) {
+	return p.parseGroup(FilterOr, depth, p.parseAnd)
+}
+
+func (p *filterParser) parseAnd(depth int) (*FilterExpression, error) {
+	return p.parseGroup(FilterAnd, depth, p.parseNot)
+}
+
+// parseGroup parses operands joined by the op keyword, returning a lone operand as it is.
+func (p *filterParser) parseGroup(op string, depth int, operand func(depth int) (*FilterExpression, error)) (*FilterExpression, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	group := &FilterExpression{Op: op, Operands: []*FilterExpression{first}}
+	for p.keyword(op) {
+		next, err := operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		group.Operands = append(group.Operands, next)
+	}
+	if len(group.Operands) == 1 {
+		return first, nil
+	}
+	return group, nil
+}
+
+func (p *filterParser) parseNot(depth int) (*FilterExpression, error) {
+	if depth > maxFilterExpressionDepth {
+		return nil, fmt.Errorf("%w: nested deeper than %d", ErrInvalidFilterExpression, maxFilterExpressionDepth)
+	}
+	if p.keyword(FilterNot) {
+		operand, err := p.parseNot(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &FilterExpression{Op: FilterNot, Operands: []*FilterExpression{operand}}, nil
+	}
+	if p.peek().kind == filterTokenOpen {
+		p.take()
+		expression, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if token := p.take(); token.kind != filterTokenClose {
+			return nil, p.unexpected(token)
+		}
+		return expression, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (*FilterExpression, error) {
+	token := p.take()
+	if token.kind != filterTokenWord {
+		return nil, p.unexpected(token)
+	}
+
+	comparison := &FilterExpression{Field: strings.ToLower(token.text)}
+	if key, ok := strings.CutPrefix(token.text, FilterFieldLabels+"."); ok {
+		if !validLabelKey(key) {
+			return nil, fmt.Errorf("%w: invalid label key %q at position %d", ErrInvalidFilterExpression, key, token.position)
+		}
+		comparison.Field, comparison.Label = FilterFieldLabels, key
+	}
+	operators, ok := filterFieldOperators[comparison.Field]
+	if !ok || comparison.Field == FilterFieldLabels && comparison.Label == "" {
+		return nil, fmt.Errorf("%w: unknown field %q at position %d", ErrInvalidFilterExpression, token.text, token.position)
+	}
+
+	operator := p.peek()
+	switch {
+	case operator.kind == filterTokenOperator:
+		comparison.Operator = p.take().text
+	case p.keyword(FilterIn):
+		comparison.Operator = FilterIn
+	case p.keyword(FilterNot):
+		if !p.keyword(FilterIn) {
+			return nil, p.unexpected(p.peek())
+		}
+		comparison.Operator = FilterNotIn
+	case comparison.Field == FilterFieldLabels:
+		// A label on its own
+		comparison.Operator = FilterExists
+		return comparison, nil
+	default:
+		return nil, p.unexpected(operator)
+	}
+	if !containsString(operators, comparison.Operator) {
+		return nil, fmt.Errorf("%w: %s doesn't take %s at position %d", ErrInvalidFilterExpression, token.text, comparison.Operator, operator.position)
+	}
+
+	var values []filterToken
+	if comparison.Operator == FilterIn || comparison.Operator == FilterNotIn {
+		var err error
+		if values, err = p.parseList(); err != nil {
+			return nil, err
+		}
+	} else {
+		value := p.take()
+		if value.kind != filterTokenWord && value.kind != filterTokenString {
+			return nil, p.unexpected(value)
+		}
+		values = []filterToken{value}
+	}
+
+	for _, value := range values {
+		typed, err := filterValue(comparison, value)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = append(comparison.Values, typed)
+	}
+	return rewriteFilterComparison(comparison), nil
+}
+
+// parseList parses a parenthesized, comma separated list of values.
+func (p *filterParser) parseList() ([]filterToken, error) {
+	if token := p.take(); token.kind != filterTokenOpen {
+		return nil, p.unexpected(token)
+	}
+	var values []filterToken
+	for {
+		value := p.take()
+		if value.kind != filterTokenWord && value.kind != filterTokenString {
+			return nil, p.unexpected(value)
+		}
+		values = append(values, value)
+
+		switch token := p.take(); token.kind {
+		case filterTokenComma:
+		case filterTokenClose:
+			return values, nil
+		default:
+			return nil, p.unexpected(token)
+		}
+	}
+}
+
+const filterDateLayout = "2006-01-02"
+
+// filterDate is a time given as a date, which comparisons take as the whole UTC day: = and != that
+// day, <= and > up to its end, < and >= from its start.
+type filterDate time.Time
+
+// filterValue checks a value against its field and operator and returns it typed.
+func filterValue(comparison *FilterExpression, token filterToken) (interface{}, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: %q at position %d is not %s", ErrInvalidFilterExpression, token.text, token.position, expected)
+	}
+
+	switch comparison.Operator {
+	case FilterMatches, FilterNotMatches:
+		// Postgres regular expressions are close enough to Go's for this to catch most mistakes, the
+		// repository reports the rest as invalid expressions when the query runs
+		if _, err := regexp.Compile(token.text); err != nil {
+			return nil, invalid("a valid regular expression")
+		}
+		return token.text, nil
+	case FilterPrefix:
+		return token.text, nil
+	}
+
+	switch comparison.Field {
+	case FilterFieldSLATarget:
+		target, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, invalid("a number")
+		}
+		return target, nil
+	case FilterFieldCreatedTime, FilterFieldLastUpdated:
+		if at, err := time.Parse(time.RFC3339, token.text); err == nil {
+			return at, nil
+		}
+		if day, err := time.Parse(filterDateLayout, token.text); err == nil {
+			return filterDate(day), nil
+		}
+		return nil, invalid("an RFC 3339 time or a date")
+	case FilterFieldStatus:
+		return NormalizeStatus(token.text), nil
+	case FilterFieldAddress:
+		if strings.Contains(token.text, "/") {
+			_, network, err := net.ParseCIDR(token.text)
+			if err != nil {
+				return nil, invalid("an address or a CIDR block")
+			}
+			return network, nil
+		}
+		return token.text, nil
+	}
+	return token.text, nil
+}
+
+// rewriteFilterComparison turns the comparisons the database can't take as they are into ones it can:
+// comparisons with a date into ones with the start or end of the day, and address comparisons with
+// CIDR blocks into FilterWithin, lists of addresses becoming one comparison per address.
+func rewriteFilterComparison(comparison *FilterExpression) *FilterExpression {
+	switch comparison.Field {
+	case FilterFieldCreatedTime, FilterFieldLastUpdated:
+		date, ok := comparison.Values[0].(filterDate)
+		if !ok {
+			return comparison
+		}
+		start := time.Time(date)
+		end := start.AddDate(0, 0, 1)
+		switch comparison.Operator {
+		case FilterLess, FilterGreaterEqual:
+			comparison.Values = []interface{}{start}
+		case FilterLessEqual:
+			comparison.Operator, comparison.Values = FilterLess, []interface{}{end}
+		case FilterGreater:
+			comparison.Operator, comparison.Values = FilterGreaterEqual, []interface{}{end}
+		default:
+			day := &FilterExpression{Op: FilterAnd, Operands: []*FilterExpression{
+				{Field: comparison.Field, Operator: FilterGreaterEqual, Values: []interface{}{start}},
+				{Field: comparison.Field, Operator: FilterLess, Values: []interface{}{end}},
+			}}
+			if comparison.Operator == FilterNotEquals {
+				return &FilterExpression{Op: FilterNot, Operands: []*FilterExpression{day}}
+			}
+			return day
+		}
+		return comparison
+
+	case FilterFieldAddress:
+		switch comparison.Operator {
+		case FilterEquals, FilterNotEquals:
+			network, ok := comparison.Values[0].(*net.IPNet)
+			if !ok {
+				return comparison
+			}
+			within := &FilterExpression{Field: FilterFieldAddress, Operator: FilterWithin, Values: []interface{}{network.String()}}
+			if comparison.Operator == FilterNotEquals {
+				return &FilterExpression{Op: FilterNot, Operands: []*FilterExpression{within}}
+			}
+			return within
+		case FilterIn, FilterNotIn:
+			anyOf := &FilterExpression{Op: FilterOr}
+			for _, value := range comparison.Values {
+				anyOf.Operands = append(anyOf.Operands, rewriteFilterComparison(&FilterExpression{Field: FilterFieldAddress, Operator: FilterEquals, Values: []interface{}{value}}))
+			}
+			if comparison.Operator == FilterNotIn {
+				return &FilterExpression{Op: FilterNot, Operands: []*FilterExpression{anyOf}}
+			}
+			return anyOf
+		}
+	}
+	return comparison
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	JobImport = "Import"
+	JobExport = "Export"
+)
+
+const (
+	JobPending   = "Pending"
+	JobRunning   = "Running"
+	JobCompleted = "Completed"
+	JobFailed    = "Failed"
+)
+
+// Job is an import or export run in the background. Options and Input hold what it needs to run,
+// so that a job interrupted by a restart can run again from the start; Input, the uploaded file of an
+// import, is dropped once the job has finished. Result is the report of an import and FileOID the
+// Postgres large object holding the file of an export, which can be too big to keep in a row.
+type Job struct {
+	ID          string     `json:"id" gorm:"primaryKey;type:uuid"`
+	Kind        string     `json:"kind" gorm:"not null"`
+	Status      string     `json:"status" gorm:"not null;index"`
+	Phase       string     `json:"phase,omitempty"`
+	Processed   int        `json:"processed" gorm:"not null;default:0"`
+	Total       int        `json:"total" gorm:"not null;default:0"`
+	Error       string     `json:"error,omitempty"`
+	Options     []byte     `json:"-" gorm:"type:jsonb"`
+	Input       []byte     `json:"-"`
+	Result      []byte     `json:"-" gorm:"type:jsonb"`
+	FileOID     uint32     `json:"-" gorm:"column:file_oid"`
+	Filename    string     `json:"filename,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedTime time.Time  `json:"created_time" gorm:"autoCreateTime"`
+}
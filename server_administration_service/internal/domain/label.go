@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidLabel         = errors.New("invalid label")
+	ErrInvalidLabelSelector = errors.New("invalid label selector")
+)
+
+// Label keys and values follow the Kubernetes syntax: an optional DNS prefix and a name of at most 63 characters.
+var (
+	labelNamePattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	labelPrefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+	setSelectorPattern = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// Labels are free-form key/value pairs attached to a server, stored as jsonb.
+type Labels map[string]string
+
+func (l Labels) Value() (driver.Value, error) {
+	return l.JSON(), nil
+}
+
+// JSON encodes the labels as a JSON object, {} when there are none.
+func (l Labels) JSON() string {
+	if l == nil {
+		return "{}"
+	}
+	data, _ := json.Marshal(l)
+	return string(data)
+}
+
+func (l *Labels) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = Labels{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Labels", value)
+	}
+	return json.Unmarshal(data, l)
+}
+
+// String renders the labels as comma separated key=value pairs sorted by key, the format used by imports and exports.
+func (l Labels) String() string {
+	keys := make([]string, 0, len(l))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+l[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ParseLabels reads comma separated key=value pairs.
+func ParseLabels(s string) (Labels, error) {
+	labels := Labels{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: %q is not a key=value pair", ErrInvalidLabel, pair)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels, labels.Validate()
+}
+
+func (l Labels) Validate() error {
+	for key, value := range l {
+		if !validLabelKey(key) {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidLabel, key)
+		}
+		if !validLabelValue(value) {
+			return fmt.Errorf("%w: invalid value %q for key %q", ErrInvalidLabel, value, key)
+		}
+	}
+	return nil
+}
+
+func validLabelKey(key string) bool {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		name, prefix = prefix, ""
+	} else if prefix == "" || len(prefix) > 253 || !labelPrefixPattern.MatchString(prefix) {
+		return false
+	}
+	return len(name) <= 63 && labelNamePattern.MatchString(name)
+}
+
+func validLabelValue(value string) bool {
+	return value == "" || (len(value) <= 63 && labelNamePattern.MatchString(value))
+}
+
+// Label selector operators
+const (
+	SelectorEquals       = "="
+	SelectorNotEquals    = "!="
+	SelectorIn           = "in"
+	SelectorNotIn        = "notin"
+	SelectorExists       = "exists"
+	SelectorDoesNotExist = "!"
+)
+
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// LabelSelector is a conjunction of requirements, e.g. "env=prod,role in (db,cache),!deprecated".
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a Kubernetes-style label selector. An empty selector matches every server.
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var selector LabelSelector
+	for _, term := range splitSelector(s) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		requirement, err := parseLabelRequirement(term)
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, requirement)
+	}
+	return selector, nil
+}
+
+// splitSelector splits on the commas that aren't inside a set of values.
+func splitSelector(s string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, s[start:])
+}
+
+func parseLabelRequirement(term string) (LabelRequirement, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidLabelSelector, term)
+
+	var requirement LabelRequirement
+	switch {
+	case strings.HasPrefix(term, "!") && !strings.Contains(term, "="):
+		requirement = LabelRequirement{Key: strings.TrimSpace(term[1:]), Operator: SelectorDoesNotExist}
+	case setSelectorPattern.MatchString(term):
+		match := setSelectorPattern.FindStringSubmatch(term)
+		requirement = LabelRequirement{Key: match[1], Operator: match[2]}
+		for _, value := range strings.Split(match[3], ",") {
+			value = strings.TrimSpace(value)
+			if !validLabelValue(value) {
+				return LabelRequirement{}, invalid
+			}
+			requirement.Values = append(requirement.Values, value)
+		}
+	case strings.Contains(term, "!="):
+		key, value, _ := strings.Cut(term, "!=")
+		requirement = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorNotEquals, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(term, "="):
+		key, value, _ := strings.Cut(term, "=")
+		value = strings.TrimPrefix(value, "=")
+		requirement = LabelRequirement{Key: strings.TrimSpace(key), Operator: SelectorEquals, Values: []string{strings.TrimSpace(value)}}
+	default:
+		requirement = LabelRequirement{Key: term, Operator: SelectorExists}
+	}
+
+	if !validLabelKey(requirement.Key) {
+		return LabelRequirement{}, invalid
+	}
+	for _, value := range requirement.Values {
+		if !validLabelValue(value) {
+			return LabelRequirement{}, invalid
+		}
+	}
+	return requirement, nil
+}
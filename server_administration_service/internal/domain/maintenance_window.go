@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MaintenanceWindow is a planned period during which status changes of its servers are tagged,
+// notifications are suppressed and, if ExcludeFromSLA is set, the time doesn't count towards uptime.
+// A window targets its ServerIDs plus every server below its GroupIDs.
+//
+// A window without CronExpression is a one-off window from StartTime to EndTime.
+// A recurring window starts at every cron occurrence between StartTime and EndTime (open-ended
+// when EndTime is zero) and lasts DurationMinutes each time.
+type MaintenanceWindow struct {
+	ID              string    `json:"id" gorm:"primaryKey;type:uuid"`
+	Name            string    `json:"name" gorm:"not null"`
+	ServerIDs       []string  `json:"server_ids" gorm:"serializer:json"`
+	GroupIDs        []string  `json:"group_ids" gorm:"serializer:json"`
+	StartTime       time.Time `json:"start_time" gorm:"not null"`
+	EndTime         time.Time `json:"end_time"`
+	CronExpression  string    `json:"cron_expression"`
+	DurationMinutes int       `json:"duration_minutes"`
+	ExcludeFromSLA  bool      `json:"exclude_from_sla" gorm:"not null;default:false"`
+	CreatedTime     time.Time `json:"created_time" gorm:"autoCreateTime"`
+	LastUpdated     time.Time `json:"last_updated" gorm:"autoUpdateTime"`
+	// GroupServerIDs are the servers below GroupIDs, resolved by the repository when the window is loaded
+	GroupServerIDs []string `json:"-" gorm:"-"`
+}
+
+type TimeInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (w *MaintenanceWindow) IsRecurring() bool {
+	return w.CronExpression != ""
+}
+
+// TargetServerIDs returns the servers the window targets directly or through its groups.
+func (w *MaintenanceWindow) TargetServerIDs() []string {
+	if len(w.GroupServerIDs) == 0 {
+		return w.ServerIDs
+	}
+
+	targets := append([]string(nil), w.ServerIDs...)
+	seen := make(map[string]bool, len(targets))
+	for _, id := range targets {
+		seen[id] = true
+	}
+	for _, id := range w.GroupServerIDs {
+		if !seen[id] {
+			seen[id] = true
+			targets = append(targets, id)
+		}
+	}
+	return targets
+}
+
+func (w *MaintenanceWindow) AppliesTo(serverID string) bool {
+	for _, id := range w.TargetServerIDs() {
+		if id == serverID {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAt reports whether t falls inside one of the window's occurrences.
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	return len(w.Occurrences(t, t.Add(time.Nanosecond))) > 0
+}
+
+// Occurrences returns the parts of the window's occurrences that overlap [from, to), in chronological order.
+func (w *MaintenanceWindow) Occurrences(from, to time.Time) []TimeInterval {
+	if !w.IsRecurring() {
+		return clip(TimeInterval{Start: w.StartTime, End: w.EndTime}, from, to)
+	}
+
+	schedule, err := cron.ParseStandard(w.CronExpression)
+	if err != nil || w.DurationMinutes <= 0 {
+		return nil
+	}
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+
+	// An occurrence starting up to one duration before from can still overlap the range
+	cursor := from.Add(-duration)
+	if cursor.Before(w.StartTime) {
+		cursor = w.StartTime.Add(-time.Nanosecond)
+	}
+
+	var intervals []TimeInterval
+	for start := schedule.Next(cursor); start.Before(to); start = schedule.Next(start) {
+		if start.IsZero() || (!w.EndTime.IsZero() && !start.Before(w.EndTime)) {
+			break
+		}
+		intervals = append(intervals, clip(TimeInterval{Start: start, End: start.Add(duration)}, from, to)...)
+	}
+	return intervals
+}
+
+func clip(interval TimeInterval, from, to time.Time) []TimeInterval {
+	if interval.Start.Before(from) {
+		interval.Start = from
+	}
+	if interval.End.After(to) {
+		interval.End = to
+	}
+	if !interval.Start.Before(interval.End) {
+		return nil
+	}
+	return []TimeInterval{interval}
+}
+
+// MergeIntervals sorts intervals and joins the overlapping ones.
+func MergeIntervals(intervals []TimeInterval) []TimeInterval {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sorted := append([]TimeInterval(nil), intervals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	merged := []TimeInterval{sorted[0]}
+	for _, interval := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if interval.Start.After(last.End) {
+			merged = append(merged, interval)
+			continue
+		}
+		if interval.End.After(last.End) {
+			last.End = interval.End
+		}
+	}
+	return merged
+}
+
+// SLAExclusions returns, per server, the merged maintenance time inside [from, to) that must not count towards uptime.
+func SLAExclusions(windows []MaintenanceWindow, from, to time.Time) map[string][]TimeInterval {
+	exclusions := make(map[string][]TimeInterval)
+	for i := range windows {
+		if !windows[i].ExcludeFromSLA {
+			continue
+		}
+
+		occurrences := windows[i].Occurrences(from, to)
+		if len(occurrences) == 0 {
+			continue
+		}
+		for _, serverID := range windows[i].TargetServerIDs() {
+			exclusions[serverID] = append(exclusions[serverID], occurrences...)
+		}
+	}
+
+	for serverID, intervals := range exclusions {
+		exclusions[serverID] = MergeIntervals(intervals)
+	}
+	return exclusions
+}
+
+// ActiveMaintenanceWindow returns the first window covering serverID at t, or nil if the server isn't under maintenance.
+func ActiveMaintenanceWindow(windows []MaintenanceWindow, serverID string, t time.Time) *MaintenanceWindow {
+	for i := range windows {
+		if windows[i].AppliesTo(serverID) && windows[i].ActiveAt(t) {
+			return &windows[i]
+		}
+	}
+	return nil
+}
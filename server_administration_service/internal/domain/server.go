@@ -1,6 +1,26 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidServer = errors.New("invalid server")
+
+// Server IDs are used in URLs and file names: letters, digits, dots, colons, dashes and underscores.
+var serverIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,254}$`)
+
+// Notes longer than this are rejected, they are meant for a few lines about the server.
+const MaxServerNotesLength = 4096
+
+// DefaultSLATarget is the uptime percentage promised for a server unless configured otherwise.
+const DefaultSLATarget = 99.9
 
 type Server struct {
 	ServerID string `json:"server_id" gorm:"primary_key;unique"`
@@ -8,5 +28,48 @@ type Server struct {
 	Status string `json:"status" gorm:"not null"`
 	CreatedTime time.Time `json:"created_time" gorm:"autoCreateTime"`
 	LastUpdated time.Time `json:"last_updated" gorm:"autoUpdateTime"`
-	IPv4 string `json:"ipv4" gorm:"not null;unique"`
+	// PrimaryAddress mirrors the primary entry of Addresses so that no two servers share it
+	PrimaryAddress string `json:"primary_address" gorm:"not null;unique"`
+	Addresses Addresses `json:"addresses" gorm:"type:jsonb;not null;default:'[]'"`
+	SLATarget float64 `json:"sla_target" gorm:"not null;default:99.9"`
+	Labels Labels `json:"labels" gorm:"type:jsonb;not null;default:'{}'"`
+	// Notes are the operators' free text about the server, searchable through /search
+	Notes string `json:"notes" gorm:"not null;default:''"`
+	// DeletedAt puts a deleted server in the trash, gorm leaves it out of every query until it's restored or purged
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	// Version goes up with every edit of the server and is its ETag. Status changes don't count, health
+	// checks make them all the time and an edit doesn't overwrite them.
+	Version int64 `json:"version" gorm:"not null;default:1"`
+}
+
+// ValidSLATarget reports whether target is a usable uptime percentage. 100% leaves no error budget at all.
+func ValidSLATarget(target float64) bool {
+	return target > 0 && target < 100
+}
+
+func ValidateServerID(serverID string) error {
+	if !serverIDPattern.MatchString(serverID) {
+		return fmt.Errorf("%w: server_id %q must be 1 to 255 letters, digits, dots, colons, dashes or underscores, starting with a letter or digit", ErrInvalidServer, serverID)
+	}
+	return nil
+}
+
+// ValidateServerName accepts up to 255 printable characters with no surrounding spaces.
+func ValidateServerName(serverName string) error {
+	if serverName == "" || len(serverName) > 255 || strings.TrimSpace(serverName) != serverName {
+		return fmt.Errorf("%w: server_name %q must be 1 to 255 characters without surrounding spaces", ErrInvalidServer, serverName)
+	}
+	for _, r := range serverName {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: server_name %q can't contain control characters", ErrInvalidServer, serverName)
+		}
+	}
+	return nil
+}
+
+func ValidateServerNotes(notes string) error {
+	if len(notes) > MaxServerNotesLength {
+		return fmt.Errorf("%w: notes must be at most %d bytes", ErrInvalidServer, MaxServerNotesLength)
+	}
+	return nil
 }
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Aggregated statuses of a server group
+const (
+	GroupStatusUp            = "Up"
+	GroupStatusPartiallyDown = "PartiallyDown"
+	GroupStatusDown          = "Down"
+	GroupStatusUnknown       = "Unknown"
+)
+
+var (
+	ErrGroupCycle       = errors.New("a group can't be moved below itself or one of its subgroups")
+	ErrGroupHasChildren = errors.New("the group still has subgroups")
+)
+
+// ServerGroup is a node of the group tree, e.g. a datacenter, a rack or a cluster.
+// A group contains its own members and, transitively, the members of its subgroups.
+type ServerGroup struct {
+	ID          string    `json:"id" gorm:"primaryKey;type:uuid"`
+	Name        string    `json:"name" gorm:"not null"`
+	ParentID    *string   `json:"parent_id" gorm:"type:uuid;index"`
+	SLATarget   float64   `json:"sla_target" gorm:"not null;default:99.9"`
+	CreatedTime time.Time `json:"created_time" gorm:"autoCreateTime"`
+	LastUpdated time.Time `json:"last_updated" gorm:"autoUpdateTime"`
+}
+
+// ServerGroupMember puts a server directly in a group. A server may belong to several groups.
+type ServerGroupMember struct {
+	GroupID  string `json:"group_id" gorm:"primaryKey;type:uuid"`
+	ServerID string `json:"server_id" gorm:"primaryKey"`
+}
+
+// GroupSummary is the status and uptime of a group rolled up from every server below it.
+type GroupSummary struct {
+	GroupID     string    `json:"group_id"`
+	Name        string    `json:"name"`
+	Path        string    `json:"path"`
+	ParentID    *string   `json:"parent_id"`
+	Status      string    `json:"status"`
+	NumServers  int       `json:"num_servers"`
+	NumUp       int       `json:"num_up"`
+	NumDown     int       `json:"num_down"`
+	UpTimeRatio float64   `json:"uptime_ratio"`
+	SLATarget   float64   `json:"sla_target"`
+	Breached    bool      `json:"breached"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// GroupTree indexes groups by ID and by parent to walk the hierarchy.
+type GroupTree struct {
+	groups   map[string]ServerGroup
+	children map[string][]string
+}
+
+func NewGroupTree(groups []ServerGroup) *GroupTree {
+	tree := &GroupTree{
+		groups:   make(map[string]ServerGroup, len(groups)),
+		children: make(map[string][]string),
+	}
+	for _, group := range groups {
+		tree.groups[group.ID] = group
+		if group.ParentID != nil {
+			tree.children[*group.ParentID] = append(tree.children[*group.ParentID], group.ID)
+		}
+	}
+	return tree
+}
+
+// Subtree returns the group and all of its descendants.
+func (t *GroupTree) Subtree(groupID string) []string {
+	subtree := []string{groupID}
+	for i := 0; i < len(subtree); i++ {
+		subtree = append(subtree, t.children[subtree[i]]...)
+	}
+	return subtree
+}
+
+func (t *GroupTree) HasChildren(groupID string) bool {
+	return len(t.children[groupID]) > 0
+}
+
+// Path renders the names from the root down to the group, e.g. "dc1 / rack-2 / cluster-a".
+func (t *GroupTree) Path(groupID string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for id := groupID; id != "" && !seen[id]; {
+		seen[id] = true
+		group, ok := t.groups[id]
+		if !ok {
+			break
+		}
+		names = append([]string{group.Name}, names...)
+		id = ""
+		if group.ParentID != nil {
+			id = *group.ParentID
+		}
+	}
+	return strings.Join(names, " / ")
+}
+
+// CanMove reports whether groupID can be placed under parentID without creating a cycle.
+func (t *GroupTree) CanMove(groupID string, parentID *string) bool {
+	if parentID == nil {
+		return true
+	}
+	for _, id := range t.Subtree(groupID) {
+		if id == *parentID {
+			return false
+		}
+	}
+	return true
+}
+
+// AggregateGroupStatus rolls the statuses of a group's servers up: Up when none is down, Down when all
+// counted ones are, PartiallyDown in between. Servers that aren't counted (Unknown, in maintenance)
+// are left out, and a group with no counted server is Unknown.
+func AggregateGroupStatus(statuses []string) string {
+	counted, down := 0, 0
+	for _, status := range statuses {
+		if !IsCountedStatus(status) {
+			continue
+		}
+		counted++
+		if NormalizeStatus(status) == StatusDown {
+			down++
+		}
+	}
+
+	switch {
+	case counted == 0:
+		return GroupStatusUnknown
+	case down == 0:
+		return GroupStatusUp
+	case down == counted:
+		return GroupStatusDown
+	}
+	return GroupStatusPartiallyDown
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortKey orders servers on one column, descending when Desc.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// ServerSort orders servers on its keys in turn. A parsed sort always ends on server_id, so that
+// servers equal on every other key still come in the same order from one query to the next.
+type ServerSort []SortKey
+
+// sortableServerColumns are the columns of Server that servers can be sorted on, with the index of
+// their field: all of them but the jsonb ones, which have no useful order.
+var sortableServerColumns = serverColumns()
+
+func serverColumns() map[string]int {
+	columns := map[string]int{}
+	server := reflect.TypeOf(Server{})
+	for i := 0; i < server.NumField(); i++ {
+		field := server.Field(i)
+		column := strings.Split(field.Tag.Get("json"), ",")[0]
+		if column == "" || column == "-" || strings.Contains(field.Tag.Get("gorm"), "type:jsonb") {
+			continue
+		}
+		columns[column] = i
+	}
+	return columns
+}
+
+// ParseServerSort reads a comma-separated list of columns and their orders, asc or desc. A single
+// order applies to every column, otherwise there is one per column; no order means asc and no column
+// means server_id. Columns are checked against the Server schema, so a sort that parses is safe to
+// put in a query.
+func ParseServerSort(columns, orders string) (ServerSort, error) {
+	var columnList, orderList []string
+	if strings.TrimSpace(columns) != "" {
+		columnList = strings.Split(columns, ",")
+	}
+	if strings.TrimSpace(orders) != "" {
+		orderList = strings.Split(orders, ",")
+	}
+	if len(columnList) == 0 {
+		columnList = []string{"server_id"}
+	}
+	if len(orderList) > 1 && len(orderList) != len(columnList) {
+		return nil, fmt.Errorf("%w: %d sort orders for %d columns", ErrInvalidSort, len(orderList), len(columnList))
+	}
+
+	sort := make(ServerSort, 0, len(columnList)+1)
+	seen := map[string]bool{}
+	for i, column := range columnList {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if _, ok := sortableServerColumns[column]; !ok {
+			return nil, fmt.Errorf("%w: cannot sort on %q", ErrInvalidSort, column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("%w: %q is sorted on twice", ErrInvalidSort, column)
+		}
+		seen[column] = true
+
+		order := ""
+		switch len(orderList) {
+		case 0:
+		case 1:
+			order = orderList[0]
+		default:
+			order = orderList[i]
+		}
+		key := SortKey{Column: column}
+		switch strings.ToLower(strings.TrimSpace(order)) {
+		case "", "asc":
+		case "desc":
+			key.Desc = true
+		default:
+			return nil, fmt.Errorf("%w: sort order %q must be asc or desc", ErrInvalidSort, strings.TrimSpace(order))
+		}
+		sort = append(sort, key)
+	}
+
+	if !seen["server_id"] {
+		sort = append(sort, SortKey{Column: "server_id"})
+	}
+	return sort, nil
+}
+
+// Format returns the columns and orders ParseServerSort reads back into the sort.
+func (sort ServerSort) Format() (string, string) {
+	columns := make([]string, 0, len(sort))
+	orders := make([]string, 0, len(sort))
+	for _, key := range sort {
+		columns = append(columns, key.Column)
+		if key.Desc {
+			orders = append(orders, "desc")
+		} else {
+			orders = append(orders, "asc")
+		}
+	}
+	return strings.Join(columns, ","), strings.Join(orders, ",")
+}
+
+// Values returns the server's value in each column of the sort, which is where it stands in that order.
+func (sort ServerSort) Values(server Server) []interface{} {
+	fields := reflect.ValueOf(server)
+	values := make([]interface{}, 0, len(sort))
+	for _, key := range sort {
+		values = append(values, fields.Field(sortableServerColumns[key.Column]).Interface())
+	}
+	return values
+}
+
+// ParseValues reads the JSON of Values back into the types of the sort's columns.
+func (sort ServerSort) ParseValues(data []json.RawMessage) ([]interface{}, error) {
+	if len(data) != len(sort) {
+		return nil, fmt.Errorf("%w: %d values for %d sort columns", ErrInvalidSort, len(data), len(sort))
+	}
+
+	server := reflect.TypeOf(Server{})
+	values := make([]interface{}, 0, len(sort))
+	for i, key := range sort {
+		value := reflect.New(server.Field(sortableServerColumns[key.Column]).Type)
+		if err := json.Unmarshal(data[i], value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: bad value for %s", ErrInvalidSort, key.Column)
+		}
+		values = append(values, value.Elem().Interface())
+	}
+	return values, nil
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "errors"
+
+const (
+	StatusUnknown        = "Unknown"
+	StatusUp             = "Up"
+	StatusDegraded       = "Degraded"
+	StatusDown           = "Down"
+	StatusMaintenance    = "Maintenance"
+	StatusDecommissioned = "Decommissioned"
+	// StatusUnreachable replaces Down for a server while a server it depends on is down
+	StatusUnreachable = "Unreachable"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid server status")
+	ErrInvalidTransition = errors.New("invalid server status transition")
+)
+
+// statusTransitions lists, for every state, the states it may move to.
+// Decommissioned servers have to be brought back through Unknown before they are probed again.
+var statusTransitions = map[string][]string{
+	StatusUnknown:        {StatusUp, StatusDegraded, StatusDown, StatusUnreachable, StatusMaintenance, StatusDecommissioned},
+	StatusUp:             {StatusDegraded, StatusDown, StatusUnreachable, StatusMaintenance, StatusDecommissioned},
+	StatusDegraded:       {StatusUp, StatusDown, StatusUnreachable, StatusMaintenance, StatusDecommissioned},
+	StatusDown:           {StatusUp, StatusDegraded, StatusUnreachable, StatusMaintenance, StatusDecommissioned},
+	StatusUnreachable:    {StatusUp, StatusDegraded, StatusDown, StatusMaintenance, StatusDecommissioned},
+	StatusMaintenance:    {StatusUnknown, StatusUp, StatusDegraded, StatusDown, StatusDecommissioned},
+	StatusDecommissioned: {StatusUnknown},
+}
+
+// NormalizeStatus maps the legacy On/Off values still found in older records onto the state model.
+func NormalizeStatus(status string) string {
+	switch status {
+	case "On":
+		return StatusUp
+	case "Off":
+		return StatusDown
+	case "":
+		return StatusUnknown
+	}
+	return status
+}
+
+func IsValidStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[NormalizeStatus(from)] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProbedStatus reports whether health probes may change a server in this state.
+// Maintenance and Decommissioned are set by operators and only left through them.
+func IsProbedStatus(status string) bool {
+	status = NormalizeStatus(status)
+	return status != StatusMaintenance && status != StatusDecommissioned
+}
+
+// IsAvailableStatus reports whether the server is serving, possibly impaired.
+func IsAvailableStatus(status string) bool {
+	status = NormalizeStatus(status)
+	return status == StatusUp || status == StatusDegraded
+}
+
+// IsCountedStatus reports whether time spent in the state counts towards uptime at all.
+// Unknown, Unreachable, Maintenance and Decommissioned periods are neither up nor down; an
+// unreachable server's outage is accounted to the server it depends on.
+func IsCountedStatus(status string) bool {
+	status = NormalizeStatus(status)
+	return IsAvailableStatus(status) || status == StatusDown
+}
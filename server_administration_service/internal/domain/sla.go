@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// SLACompliance is how a server did against its SLA target over a period.
+type SLACompliance struct {
+	ServerID    string    `json:"server_id"`
+	ServerName  string    `json:"server_name"`
+	SLATarget   float64   `json:"sla_target"`
+	Achieved    float64   `json:"achieved"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// ErrorBudgetSeconds is the downtime the target allows in the counted part of the period
+	ErrorBudgetSeconds float64 `json:"error_budget_seconds"`
+	// ErrorBudgetRemaining is the percentage of that budget not yet spent, negative once overspent
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	// BurnRate is the observed error rate over the allowed one; above 1 the budget runs out before the period ends
+	BurnRate float64 `json:"burn_rate"`
+	Breached bool    `json:"breached"`
+}
+
+// GroupSLACompliance is how the servers below a group, those of its subgroups included, did together
+// against the group's SLA target over a period.
+type GroupSLACompliance struct {
+	GroupID              string    `json:"group_id"`
+	Name                 string    `json:"name"`
+	Path                 string    `json:"path"`
+	SLATarget            float64   `json:"sla_target"`
+	Achieved             float64   `json:"achieved"`
+	PeriodStart          time.Time `json:"period_start"`
+	PeriodEnd            time.Time `json:"period_end"`
+	ErrorBudgetSeconds   float64   `json:"error_budget_seconds"`
+	ErrorBudgetRemaining float64   `json:"error_budget_remaining"`
+	BurnRate             float64   `json:"burn_rate"`
+	Breached             bool      `json:"breached"`
+}
+
+// ComputeSLACompliance derives the compliance figures from the up and counted time of a period.
+func ComputeSLACompliance(target float64, up, counted time.Duration) SLACompliance {
+	compliance := SLACompliance{
+		SLATarget:            target,
+		Achieved:             100,
+		ErrorBudgetRemaining: 100,
+	}
+	if counted <= 0 {
+		return compliance
+	}
+
+	allowedErrorRate := 1 - target/100
+	errorRate := float64(counted-up) / float64(counted)
+
+	compliance.Achieved = (1 - errorRate) * 100
+	compliance.ErrorBudgetSeconds = counted.Seconds() * allowedErrorRate
+	compliance.ErrorBudgetRemaining = (1 - errorRate/allowedErrorRate) * 100
+	compliance.BurnRate = errorRate / allowedErrorRate
+	compliance.Breached = compliance.Achieved < target
+	return compliance
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// StatusChange is one document of the ping_status index.
+type StatusChange struct {
+	ID        string    `json:"ID"`
+	Status    string    `json:"Status"`
+	Timestamp time.Time `json:"Timestamp"`
+}
+
+// UpTime walks a server's chronological status history and returns how long it was available inside [from, to)
+// and how long was counted at all, leaving out the excluded intervals and the states that don't count.
+// The server is Unknown until its first status change.
+func UpTime(history []StatusChange, from, to time.Time, excluded []TimeInterval) (up, counted time.Duration) {
+	status := StatusUnknown
+	cursor := from
+
+	flush := func(until time.Time) {
+		if !until.After(cursor) {
+			return
+		}
+		if IsCountedStatus(status) {
+			span := until.Sub(cursor) - overlap(cursor, until, excluded)
+			counted += span
+			if IsAvailableStatus(status) {
+				up += span
+			}
+		}
+		cursor = until
+	}
+
+	for _, change := range history {
+		if change.Timestamp.After(to) {
+			break
+		}
+		if change.Timestamp.After(cursor) {
+			flush(change.Timestamp)
+		}
+		status = change.Status
+	}
+	flush(to)
+
+	return up, counted
+}
+
+func overlap(from, to time.Time, intervals []TimeInterval) time.Duration {
+	var total time.Duration
+	for _, interval := range intervals {
+		start, end := interval.Start, interval.End
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// Actor is who makes a change: the user ID of their token and the IP address the request came from.
+type Actor struct {
+	UserID   string `json:"user_id"`
+	SourceIP string `json:"source_ip"`
+}
+
+// AuditFilter narrows the audit log to a server, a user and a time range, any of which may be empty.
+// From is inclusive and To exclusive.
+type AuditFilter struct {
+	ServerID string
+	UserID   string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+}
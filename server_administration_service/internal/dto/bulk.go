@@ -0,0 +1,24 @@
+package dto
+
+// BulkRequest is the servers a bulk change targets, either by ID or by filter but not both.
+// A preview reports what the change would do without making it.
+type BulkRequest struct {
+	ServerIDs []string
+	Filter    *ServerFilter
+	Preview   bool
+}
+
+// BulkOutcome is what a bulk change did, or would do in a preview, to one of the targeted servers.
+type BulkOutcome struct {
+	ServerID string `json:"server_id"`
+	Outcome  string `json:"outcome"`
+}
+
+// BulkResult is the outcome of a bulk change for every targeted server. Matched counts the targeted
+// servers that exist and Affected the ones changed, or that would be in a preview.
+type BulkResult struct {
+	Preview  bool          `json:"preview"`
+	Matched  int           `json:"matched"`
+	Affected int           `json:"affected"`
+	Outcomes []BulkOutcome `json:"outcomes"`
+}
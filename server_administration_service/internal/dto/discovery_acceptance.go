@@ -0,0 +1,9 @@
+package dto
+
+// DiscoveryAcceptance picks a discovered host to add to the inventory.
+// ServerID and ServerName default to the host's reverse DNS name, or its address when it has none.
+type DiscoveryAcceptance struct {
+	Address string `json:"address"`
+	ServerID string `json:"server_id"`
+	ServerName string `json:"server_name"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// ExportOptions tell which servers to export and how. LabelSelector and FilterExpression are kept as
+// written, for export jobs that run after the request is gone, and parsed by the export. Format is xlsx, csv, json, ndjson
+// or pdf; UpTimeFrom and UpTimeTo add every server's uptime over that range. Report formats an xlsx
+// export and adds a Summary sheet with charts.
+type ExportOptions struct {
+	Filter ServerFilter `json:"filter"`
+	LabelSelector string `json:"label_selector"`
+	FilterExpression string `json:"filter_expression"`
+	From int `json:"from"`
+	To int `json:"to"`
+	SortColumn string `json:"sort_column"`
+	Order string `json:"order"`
+	Format string `json:"format"`
+	UpTimeFrom *time.Time `json:"uptime_from,omitempty"`
+	UpTimeTo *time.Time `json:"uptime_to,omitempty"`
+	Report bool `json:"report"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// ImportOptions tell how to read a servers file. Format is detected from Filename's extension
+// or the content when empty. Mapping maps server fields (server_id, server_name, addresses,
+// sla_target, labels) to the column or key holding them in the file, for files not using the export's names.
+// DryRun validates the file against the inventory without creating any server.
+//
+// Mode is insert (the default) to only create new servers, upsert to also update the servers
+// already in the inventory, or sync to also remove the servers missing from the file; Removal
+// tells whether sync decommissions them (the default) or deletes them. A sync only runs with
+// the ConfirmationToken returned by a dry run of the same file.
+//
+// ProgressID, chosen by the client, lets it poll the progress of a large import while it runs.
+// OnProgress, when set, is also told every progress update, e.g. to persist it.
+// Actor, who started the import, is recorded in the audit log of every server it changes.
+type ImportOptions struct {
+	Format string `json:"format"`
+	Filename string `json:"filename"`
+	Mapping map[string]string `json:"mapping"`
+	DryRun bool `json:"dry_run"`
+	Mode string `json:"mode"`
+	Removal string `json:"removal"`
+	ConfirmationToken string `json:"confirmation_token"`
+	ProgressID string `json:"progress_id"`
+	Actor Actor `json:"actor"`
+	OnProgress func(phase string, processed, total int) `json:"-"`
+}
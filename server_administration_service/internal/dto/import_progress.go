@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// ImportProgress is how far an import that was given a progress ID has got. Processed counts the
+// rows validated while Phase is validating, and the servers written while it's writing.
+type ImportProgress struct {
+	ID string `json:"id"`
+	Phase string `json:"phase"`
+	Processed int `json:"processed"`
+	Total int `json:"total"`
+	Error string `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "server_administration_service/internal/domain"
+
+// ImportRowError is why a row of a servers file wasn't imported. Row counts the header line of
+// tabular files and is the position of the object in JSON, YAML and nmap files.
+type ImportRowError struct {
+	Row int `json:"row"`
+	ServerID string `json:"server_id,omitempty"`
+	Field string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// ImportSummary lists the IDs of the servers an import created, updated, left unchanged
+// because the file matches them, and removed in a sync.
+type ImportSummary struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+	Removed []string `json:"removed"`
+}
+
+// ImportReport is the outcome of an import. For a dry run ImportedServers are the servers that would be
+// created or updated, and a sync dry run returns the ConfirmationToken needed to run it.
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	Mode string `json:"mode"`
+	ImportedServers []domain.Server `json:"imported_servers"`
+	NonImportedServers []domain.Server `json:"non_imported_servers"`
+	Errors []ImportRowError `json:"errors"`
+	Summary ImportSummary `json:"summary"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
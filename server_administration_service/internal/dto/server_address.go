@@ -1,7 +1,10 @@
 package dto
 
+import "server_administration_service/internal/domain"
+
 type ServerAddress struct {
 	ServerID string `json:"server_id"`
-	IPv4 string `json:"ipv4"`
+	PrimaryAddress string `json:"primary_address"`
+	Addresses domain.Addresses `json:"addresses"`
 	Status string `json:"status"`
-}
\ No newline at end of file
+}
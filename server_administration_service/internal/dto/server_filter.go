@@ -1,8 +1,14 @@
 package dto
 
+import "server_administration_service/internal/domain"
+
 type ServerFilter struct {
 	ServerID string `json:"server_id"`
 	ServerName string `json:"server_name"`
 	Status	 string `json:"status"`
-	IPv4	  string `json:"ipv4"`
+	// Address matches any of a server's addresses
+	Address string `json:"address"`
+	LabelSelector domain.LabelSelector `json:"-"`
+	// Expression is a parsed filter expression, matching along with the fields above
+	Expression *domain.FilterExpression `json:"-"`
 }
\ No newline at end of file
@@ -0,0 +1,36 @@
+package dto
+
+import "server_administration_service/internal/domain"
+
+// ServerPageRequest asks for a page of servers in the order of Sort. Cursor is one of the cursors
+// of a page returned before, which carries its own sort; an empty Cursor starts from the first server.
+// A nil Sort takes the cursor's, or sorts by server ID without a cursor.
+type ServerPageRequest struct {
+	Sort domain.ServerSort
+	Limit int
+	Cursor string
+}
+
+// ServerPage is a page of servers, the cursors of the pages next to it when there are any, and how many
+// servers match the filter in all.
+type ServerPage struct {
+	Items []domain.Server `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total int64 `json:"total"`
+}
+
+// ServerKeyset selects servers by their position in a sort order rather than by offset: the Limit
+// servers right after After, or right before it when Backward, or the first ones without After.
+// Sort is a parsed one, ending on server_id so that every server has its own position.
+type ServerKeyset struct {
+	Sort domain.ServerSort
+	After *ServerKey
+	Backward bool
+	Limit int
+}
+
+// ServerKey is a server's position in a sort order: its value in each column of the sort.
+type ServerKey struct {
+	Values []interface{}
+}
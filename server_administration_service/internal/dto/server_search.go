@@ -0,0 +1,18 @@
+package dto
+
+import "server_administration_service/internal/domain"
+
+// ServerSearchHit is a server found by a search, with its relevance score and the fragments of its
+// fields that matched, the matches wrapped in <em>.
+type ServerSearchHit struct {
+	ServerID string `json:"-"`
+	Server *domain.Server `json:"server"`
+	Score float64 `json:"score"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// ServerSearchResult is the best hits of a search, and how many servers matched in all.
+type ServerSearchResult struct {
+	Hits []ServerSearchHit `json:"hits"`
+	Total int64 `json:"total"`
+}
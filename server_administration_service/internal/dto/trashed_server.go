@@ -0,0 +1,13 @@
+package dto
+
+import (
+	"server_administration_service/internal/domain"
+	"time"
+)
+
+// TrashedServer is a deleted server waiting in the trash, with when it was deleted and when it will be purged.
+type TrashedServer struct {
+	domain.Server
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+// UpTimePoint is the mean uptime percentage of the fleet over the period starting at Time.
+type UpTimePoint struct {
+	Time time.Time `json:"time"`
+	UpTime float64 `json:"uptime"`
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+type AuditHandler interface {
+	GetAuditLog(w http.ResponseWriter, r *http.Request)
+}
+
+type auditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) AuditHandler {
+	return &auditHandler{
+		service: service,
+	}
+}
+
+// GetAuditLog answers the changes to servers, newest first, narrowed by the optional server_id, user_id,
+// from and to (RFC 3339 times) and limit query parameters.
+func (h *auditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter := dto.AuditFilter{
+		ServerID: r.URL.Query().Get("server_id"),
+		UserID:   r.URL.Query().Get("user_id"),
+	}
+	for name, at := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid '"+name+"' query parameter, expected an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		*at = &parsed
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(limitStr); err != nil || filter.Limit < 1 {
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries, err := h.service.GetAuditLog(filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuditFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to get audit log: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get audit log", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// actorFromRequest tells who makes a request: the user ID the auth middleware took from the token
+// and the address of the connection, not any forwarding header a client could set.
+func actorFromRequest(r *http.Request) dto.Actor {
+	sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		sourceIP = r.RemoteAddr
+	}
+	return dto.Actor{
+		UserID:   r.Header.Get("userID"),
+		SourceIP: sourceIP,
+	}
+}
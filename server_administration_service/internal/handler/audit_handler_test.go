@@ -0,0 +1,86 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockAuditService implements service.AuditService for testing
+type mockAuditService struct {
+	mock.Mock
+}
+
+func (m *mockAuditService) GetAuditLog(filter dto.AuditFilter) ([]domain.AuditEntry, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+func TestGetAuditLog_Success(t *testing.T) {
+	mockSvc := new(mockAuditService)
+	h := handler.NewAuditHandler(mockSvc)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("GetAuditLog", dto.AuditFilter{ServerID: "srv-1", UserID: "user-1", From: &from, To: &to, Limit: 10}).Return([]domain.AuditEntry{{
+		ID:       "entry-1",
+		ServerID: "srv-1",
+		UserID:   "user-1",
+		Action:   domain.AuditUpdate,
+		Changes:  domain.AuditChanges{"notes": {Before: "Rack 4", After: "Rack 5"}},
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?server_id=srv-1&user_id=user-1&from=2026-10-01T00:00:00Z&to=2026-10-02T00:00:00Z&limit=10", nil)
+	rr := httptest.NewRecorder()
+	h.GetAuditLog(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var entries []domain.AuditEntry
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&entries))
+	assert.Equal(t, "Rack 5", entries[0].Changes["notes"].After)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetAuditLog_Invalid(t *testing.T) {
+	mockSvc := new(mockAuditService)
+	h := handler.NewAuditHandler(mockSvc)
+
+	mockSvc.On("GetAuditLog", mock.Anything).Return(nil, fmt.Errorf("%w: from must be before to", service.ErrInvalidAuditFilter))
+
+	for _, query := range []string{"?from=yesterday", "?to=2026-10-02", "?limit=0", "?from=2026-10-02T00:00:00Z&to=2026-10-01T00:00:00Z"} {
+		req := httptest.NewRequest(http.MethodGet, "/audit"+query, nil)
+		rr := httptest.NewRecorder()
+		h.GetAuditLog(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	mockSvc.AssertNumberOfCalls(t, "GetAuditLog", 1)
+}
+
+func TestGetAuditLog_Error(t *testing.T) {
+	mockSvc := new(mockAuditService)
+	h := handler.NewAuditHandler(mockSvc)
+
+	mockSvc.On("GetAuditLog", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+	rr := httptest.NewRecorder()
+	h.GetAuditLog(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
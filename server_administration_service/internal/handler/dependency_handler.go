@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type DependencyHandler interface {
+	AddDependency(w http.ResponseWriter, r *http.Request)
+	RemoveDependency(w http.ResponseWriter, r *http.Request)
+	GetDependencyGraph(w http.ResponseWriter, r *http.Request)
+}
+
+type dependencyHandler struct {
+	service service.DependencyService
+}
+
+func NewDependencyHandler(service service.DependencyService) DependencyHandler {
+	return &dependencyHandler{
+		service: service,
+	}
+}
+
+func (h *dependencyHandler) AddDependency(w http.ResponseWriter, r *http.Request) {
+	var req domain.ServerDependency
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request AddDependency: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AddDependency(req.ServerID, req.DependsOnID); err != nil {
+		writeDependencyError(w, "add", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Dependency added successfully",
+	})
+}
+
+func (h *dependencyHandler) RemoveDependency(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	dependsOnID := mux.Vars(r)["depends_on"]
+
+	if err := h.service.RemoveDependency(serverID, dependsOnID); err != nil {
+		writeDependencyError(w, "remove", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Dependency removed successfully",
+	})
+}
+
+// GetDependencyGraph returns the graph as JSON, or as Graphviz DOT with format=dot.
+func (h *dependencyHandler) GetDependencyGraph(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "dot" {
+		http.Error(w, "Invalid 'format' query parameter, expected json or dot", http.StatusBadRequest)
+		return
+	}
+
+	graph, err := h.service.GetGraph()
+	if err != nil {
+		writeDependencyError(w, "get", err)
+		return
+	}
+
+	if format == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.Header().Set("Content-Disposition", "attachment; filename=dependencies.dot")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(graph.DOT()))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, graph)
+}
+
+// writeDependencyError maps invalid edges and cycles to 400 and missing servers or edges to 404.
+func writeDependencyError(w http.ResponseWriter, action string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+action+" server dependency: "+err.Error(), "ERROR")
+	switch {
+	case errors.Is(err, domain.ErrInvalidDependency), errors.Is(err, domain.ErrDependencyCycle):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrServerNotFound):
+		http.Error(w, "Server not found", http.StatusNotFound)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Dependency not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Failed to "+action+" server dependency", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,168 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// mockDependencyService implements service.DependencyService for testing
+type mockDependencyService struct {
+	mock.Mock
+}
+
+func (m *mockDependencyService) AddDependency(serverID, dependsOnID string) error {
+	args := m.Called(serverID, dependsOnID)
+	return args.Error(0)
+}
+
+func (m *mockDependencyService) RemoveDependency(serverID, dependsOnID string) error {
+	args := m.Called(serverID, dependsOnID)
+	return args.Error(0)
+}
+
+func (m *mockDependencyService) GetGraph() (*domain.DependencyGraph, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DependencyGraph), args.Error(1)
+}
+
+func (m *mockDependencyService) GetDownDependencies(serverID string) ([]string, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func testDependencyGraph() *domain.DependencyGraph {
+	return &domain.DependencyGraph{
+		Nodes: []domain.DependencyNode{
+			{ServerID: "srv-1", ServerName: "web-1", Status: domain.StatusUnreachable},
+			{ServerID: "switch-1", ServerName: "core switch", Status: domain.StatusDown},
+		},
+		Edges: []domain.ServerDependency{{ServerID: "srv-1", DependsOnID: "switch-1"}},
+	}
+}
+
+func TestAddDependency_Success(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("AddDependency", "srv-1", "switch-1").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/dependencies", strings.NewReader(`{"server_id":"srv-1","depends_on":"switch-1"}`))
+	rr := httptest.NewRecorder()
+	h.AddDependency(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestAddDependency_Cycle(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("AddDependency", "switch-1", "srv-1").Return(domain.ErrDependencyCycle)
+
+	req := httptest.NewRequest(http.MethodPost, "/dependencies", strings.NewReader(`{"server_id":"switch-1","depends_on":"srv-1"}`))
+	rr := httptest.NewRecorder()
+	h.AddDependency(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAddDependency_UnknownServer(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("AddDependency", "srv-1", "missing").Return(service.ErrServerNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/dependencies", strings.NewReader(`{"server_id":"srv-1","depends_on":"missing"}`))
+	rr := httptest.NewRecorder()
+	h.AddDependency(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestRemoveDependency_NotFound(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("RemoveDependency", "srv-1", "switch-1").Return(gorm.ErrRecordNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/dependencies/srv-1/switch-1", nil)
+	req = mux.SetURLVars(req, map[string]string{"server_id": "srv-1", "depends_on": "switch-1"})
+	rr := httptest.NewRecorder()
+	h.RemoveDependency(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetDependencyGraph_JSON(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("GetGraph").Return(testDependencyGraph(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/dependencies", nil)
+	rr := httptest.NewRecorder()
+	h.GetDependencyGraph(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), `"depends_on":"switch-1"`)
+}
+
+func TestGetDependencyGraph_DOT(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("GetGraph").Return(testDependencyGraph(), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/dependencies?format=dot", nil)
+	rr := httptest.NewRecorder()
+	h.GetDependencyGraph(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/vnd.graphviz", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), `"srv-1" -> "switch-1";`)
+}
+
+func TestGetDependencyGraph_InvalidFormat(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/dependencies?format=svg", nil)
+	rr := httptest.NewRecorder()
+	h.GetDependencyGraph(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "GetGraph")
+}
+
+func TestGetDependencyGraph_Error(t *testing.T) {
+	mockSvc := new(mockDependencyService)
+	h := handler.NewDependencyHandler(mockSvc)
+
+	mockSvc.On("GetGraph").Return(nil, errors.New("db error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/dependencies", nil)
+	rr := httptest.NewRecorder()
+	h.GetDependencyGraph(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+)
+
+type DiscoveryHandler interface {
+	StartScan(w http.ResponseWriter, r *http.Request)
+	GetScans(w http.ResponseWriter, r *http.Request)
+	GetScan(w http.ResponseWriter, r *http.Request)
+	AcceptHosts(w http.ResponseWriter, r *http.Request)
+}
+
+type discoveryHandler struct {
+	service service.DiscoveryService
+}
+
+func NewDiscoveryHandler(service service.DiscoveryService) DiscoveryHandler {
+	return &discoveryHandler{
+		service: service,
+	}
+}
+
+// StartScan answers 202 with the Running scan, its hosts are available once it's Completed.
+func (h *discoveryHandler) StartScan(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CIDR  string `json:"cidr"`
+		Ports []int  `json:"ports"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request StartScan: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scan, err := h.service.StartScan(req.CIDR, req.Ports)
+	if err != nil {
+		writeDiscoveryError(w, "start", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, scan)
+}
+
+func (h *discoveryHandler) GetScans(w http.ResponseWriter, r *http.Request) {
+	scans, err := h.service.GetScans()
+	if err != nil {
+		writeDiscoveryError(w, "get", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, scans)
+}
+
+// GetScan returns a scan with the hosts it found, only those in the given status with ?status=New|Known|Accepted.
+func (h *discoveryHandler) GetScan(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	status := r.URL.Query().Get("status")
+	if status != "" && status != domain.HostNew && status != domain.HostKnown && status != domain.HostAccepted {
+		http.Error(w, "Invalid 'status' query parameter, expected New, Known or Accepted", http.StatusBadRequest)
+		return
+	}
+
+	scan, err := h.service.GetScan(id)
+	if err != nil {
+		writeDiscoveryError(w, "get", err)
+		return
+	}
+	hosts, err := h.service.GetHosts(id, status)
+	if err != nil {
+		writeDiscoveryError(w, "get", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"scan":  scan,
+		"hosts": hosts,
+	})
+}
+
+// AcceptHosts creates servers for the chosen New hosts of a scan and reports them like an import does.
+func (h *discoveryHandler) AcceptHosts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Hosts []dto.DiscoveryAcceptance `json:"hosts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request AcceptHosts: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	importedServers, nonImportedServers, err := h.service.AcceptHosts(mux.Vars(r)["id"], req.Hosts)
+	if err != nil {
+		writeDiscoveryError(w, "accept hosts of", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"imported_servers":     importedServers,
+		"non_imported_servers": nonImportedServers,
+	})
+}
+
+// writeDiscoveryError maps invalid ranges, ports and acceptances to 400 and unknown scans to 404.
+func writeDiscoveryError(w http.ResponseWriter, action string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+action+" discovery scan: "+err.Error(), "ERROR")
+	switch {
+	case errors.Is(err, domain.ErrInvalidDiscoveryScan):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrScanNotFound):
+		http.Error(w, "Discovery scan not found", http.StatusNotFound)
+	default:
+		http.Error(w, "Failed to "+action+" discovery scan", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockDiscoveryService implements service.DiscoveryService for testing
+type mockDiscoveryService struct {
+	mock.Mock
+}
+
+func (m *mockDiscoveryService) StartScan(cidr string, ports []int) (*domain.DiscoveryScan, error) {
+	args := m.Called(cidr, ports)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DiscoveryScan), args.Error(1)
+}
+
+func (m *mockDiscoveryService) GetScans() ([]domain.DiscoveryScan, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DiscoveryScan), args.Error(1)
+}
+
+func (m *mockDiscoveryService) GetScan(id string) (*domain.DiscoveryScan, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DiscoveryScan), args.Error(1)
+}
+
+func (m *mockDiscoveryService) GetHosts(scanID, status string) ([]domain.DiscoveredHost, error) {
+	args := m.Called(scanID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DiscoveredHost), args.Error(1)
+}
+
+func (m *mockDiscoveryService) AcceptHosts(scanID string, acceptances []dto.DiscoveryAcceptance) ([]domain.Server, []domain.Server, error) {
+	args := m.Called(scanID, acceptances)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]domain.Server), args.Get(1).([]domain.Server), args.Error(2)
+}
+
+func TestStartScan_Accepted(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	mockSvc.On("StartScan", "10.0.0.0/24", []int{22}).Return(&domain.DiscoveryScan{ID: "scan-1", CIDR: "10.0.0.0/24", Status: domain.ScanRunning}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/discovery/scans", strings.NewReader(`{"cidr":"10.0.0.0/24","ports":[22]}`))
+	rr := httptest.NewRecorder()
+	h.StartScan(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"Running"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestStartScan_InvalidRange(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	mockSvc.On("StartScan", "10.0.0.0/8", []int(nil)).Return(nil, domain.ErrInvalidDiscoveryScan)
+
+	req := httptest.NewRequest(http.MethodPost, "/discovery/scans", strings.NewReader(`{"cidr":"10.0.0.0/8"}`))
+	rr := httptest.NewRecorder()
+	h.StartScan(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetScan_WithHosts(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	mockSvc.On("GetScan", "scan-1").Return(&domain.DiscoveryScan{ID: "scan-1", Status: domain.ScanCompleted}, nil)
+	mockSvc.On("GetHosts", "scan-1", domain.HostNew).Return([]domain.DiscoveredHost{{ScanID: "scan-1", Address: "10.0.0.2", Status: domain.HostNew}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/discovery/scans/scan-1?status=New", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "scan-1"})
+	rr := httptest.NewRecorder()
+	h.GetScan(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var body struct {
+		Hosts []domain.DiscoveredHost `json:"hosts"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Len(t, body.Hosts, 1)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetScan_InvalidStatus(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/discovery/scans/scan-1?status=Up", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "scan-1"})
+	rr := httptest.NewRecorder()
+	h.GetScan(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "GetScan", mock.Anything)
+}
+
+func TestGetScan_NotFound(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	mockSvc.On("GetScan", "missing").Return(nil, service.ErrScanNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/discovery/scans/missing", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.GetScan(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAcceptHosts_Success(t *testing.T) {
+	mockSvc := new(mockDiscoveryService)
+	h := handler.NewDiscoveryHandler(mockSvc)
+
+	acceptances := []dto.DiscoveryAcceptance{{Address: "10.0.0.2", ServerID: "web-2"}}
+	mockSvc.On("AcceptHosts", "scan-1", acceptances).Return([]domain.Server{{ServerID: "web-2"}}, []domain.Server{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/discovery/scans/scan-1/accept", strings.NewReader(`{"hosts":[{"address":"10.0.0.2","server_id":"web-2"}]}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "scan-1"})
+	rr := httptest.NewRecorder()
+	h.AcceptHosts(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"imported_servers"`)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+)
+
+type JobHandler interface {
+	StartImportJob(w http.ResponseWriter, r *http.Request)
+	StartExportJob(w http.ResponseWriter, r *http.Request)
+	GetJob(w http.ResponseWriter, r *http.Request)
+	GetJobResult(w http.ResponseWriter, r *http.Request)
+}
+
+type jobHandler struct {
+	service service.JobService
+}
+
+func NewJobHandler(service service.JobService) JobHandler {
+	return &jobHandler{
+		service: service,
+	}
+}
+
+// StartImportJob takes the same form as /import and answers 202 with the Pending job.
+func (h *jobHandler) StartImportJob(w http.ResponseWriter, r *http.Request) {
+	buf, options, ok := readImportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.StartImportJob(buf, options)
+	if err != nil {
+		writeJobError(w, "start import", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// StartExportJob takes the same query parameters as /export and answers 202 with the Pending job.
+func (h *jobHandler) StartExportJob(w http.ResponseWriter, r *http.Request) {
+	options, ok := readExportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.StartExportJob(options)
+	if err != nil {
+		writeJobError(w, "start export", err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (h *jobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetJob(mux.Vars(r)["id"])
+	if err != nil {
+		writeJobError(w, "get", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+// GetJobResult answers the report of a Completed import job or the workbook of a Completed export job.
+func (h *jobHandler) GetJobResult(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetJobResult(mux.Vars(r)["id"])
+	if err != nil {
+		writeJobError(w, "get result of", err)
+		return
+	}
+
+	if job.Kind == domain.JobExport {
+		// Read the start of the file before answering, so that a file that can't be read is still a 500
+		file := bufio.NewReader(h.service.OpenJobFile(job))
+		if _, err := file.Peek(1); err != nil && err != io.EOF {
+			writeJobError(w, "get result of", err)
+			return
+		}
+		setExportHeaders(w, job.Filename)
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, file); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to send the file of job "+job.ID+": "+err.Error(), "ERROR")
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(job.Result)
+}
+
+// writeJobError maps invalid formats, label selectors, filter expressions and sorts to 400, unknown jobs to 404 and jobs without a result yet to 409.
+func writeJobError(w http.ResponseWriter, action string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+action+" job: "+err.Error(), "ERROR")
+	switch {
+	case errors.Is(err, domain.ErrInvalidLabelSelector), errors.Is(err, domain.ErrInvalidFilterExpression), errors.Is(err, domain.ErrInvalidSort), errors.Is(err, service.ErrInvalidExport):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrJobNotFound):
+		http.Error(w, "Job not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrJobNotFinished):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Failed to "+action+" job", http.StatusInternalServerError)
+	}
+}
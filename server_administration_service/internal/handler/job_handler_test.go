@@ -0,0 +1,200 @@
+package handler_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockJobService implements service.JobService for testing
+type mockJobService struct {
+	mock.Mock
+}
+
+func (m *mockJobService) StartImportJob(buf []byte, options dto.ImportOptions) (*domain.Job, error) {
+	args := m.Called(buf, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobService) StartExportJob(options dto.ExportOptions) (*domain.Job, error) {
+	args := m.Called(options.Filter.ServerName, options.LabelSelector, options.From, options.To)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobService) GetJob(id string) (*domain.Job, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobService) GetJobResult(id string) (*domain.Job, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobService) OpenJobFile(job *domain.Job) io.Reader {
+	args := m.Called(job.FileOID)
+	return args.Get(0).(io.Reader)
+}
+
+func (m *mockJobService) PurgeFinishedJobs() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockJobService) ResumeJobs() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestStartImportJob_Accepted(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("StartImportJob", []byte("Server ID,Server Name,Addresses\n"), mock.MatchedBy(func(options dto.ImportOptions) bool {
+		return options.Mode == "upsert" && options.Filename == "servers.csv"
+	})).Return(&domain.Job{ID: "job-1", Kind: domain.JobImport, Status: domain.JobPending}, nil)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("servers_file", "servers.csv")
+	part.Write([]byte("Server ID,Server Name,Addresses\n"))
+	writer.WriteField("mode", "upsert")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/servers/import?async=true", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+	h.StartImportJob(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"status":"Pending"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestStartExportJob_Accepted(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("StartExportJob", "web", "env=prod", 0, 100).Return(&domain.Job{ID: "job-1", Kind: domain.JobExport, Status: domain.JobPending}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?async=true&from=0&to=100&server_name=web&label_selector=env%3Dprod", nil)
+	rr := httptest.NewRecorder()
+	h.StartExportJob(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestStartExportJob_InvalidRange(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?async=true&from=a&to=100", nil)
+	rr := httptest.NewRecorder()
+	h.StartExportJob(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "StartExportJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJob", "job-1").Return(nil, service.ErrJobNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/jobs/job-1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job-1"})
+	rr := httptest.NewRecorder()
+	h.GetJob(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetJobResult_Import(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobResult", "job-1").Return(&domain.Job{ID: "job-1", Kind: domain.JobImport, Status: domain.JobCompleted, Result: []byte(`{"mode":"insert"}`)}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/jobs/job-1/result", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job-1"})
+	rr := httptest.NewRecorder()
+	h.GetJobResult(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"mode":"insert"}`, rr.Body.String())
+}
+
+func TestGetJobResult_Export(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobResult", "job-1").Return(&domain.Job{ID: "job-1", Kind: domain.JobExport, Status: domain.JobCompleted, FileOID: 16400, Filename: "servers.xlsx"}, nil)
+	mockSvc.On("OpenJobFile", uint32(16400)).Return(bytes.NewReader([]byte("xlsx")))
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/jobs/job-1/result", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job-1"})
+	rr := httptest.NewRecorder()
+	h.GetJobResult(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "attachment; filename=servers.xlsx", rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "xlsx", rr.Body.String())
+}
+
+func TestGetJobResult_UnreadableFile(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobResult", "job-1").Return(&domain.Job{ID: "job-1", Kind: domain.JobExport, Status: domain.JobCompleted, FileOID: 16400, Filename: "servers.xlsx"}, nil)
+	mockSvc.On("OpenJobFile", uint32(16400)).Return(iotest.ErrReader(errors.New("large object 16400 does not exist")))
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/jobs/job-1/result", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job-1"})
+	rr := httptest.NewRecorder()
+	h.GetJobResult(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Empty(t, rr.Header().Get("Content-Disposition"))
+}
+
+func TestGetJobResult_NotFinished(t *testing.T) {
+	mockSvc := new(mockJobService)
+	h := handler.NewJobHandler(mockSvc)
+
+	mockSvc.On("GetJobResult", "job-1").Return(nil, service.ErrJobNotFinished)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/jobs/job-1/result", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "job-1"})
+	rr := httptest.NewRecorder()
+	h.GetJobResult(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
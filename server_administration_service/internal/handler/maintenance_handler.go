@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type MaintenanceHandler interface {
+	CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request)
+	GetMaintenanceWindows(w http.ResponseWriter, r *http.Request)
+	GetMaintenanceWindow(w http.ResponseWriter, r *http.Request)
+	UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request)
+	DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request)
+}
+
+type maintenanceHandler struct {
+	service service.MaintenanceService
+}
+
+func NewMaintenanceHandler(service service.MaintenanceService) MaintenanceHandler {
+	return &maintenanceHandler{
+		service: service,
+	}
+}
+
+type maintenanceWindowRequest struct {
+	Name            *string    `json:"name"`
+	ServerIDs       []string   `json:"server_ids"`
+	GroupIDs        []string   `json:"group_ids"`
+	StartTime       *time.Time `json:"start_time"`
+	EndTime         *time.Time `json:"end_time"`
+	CronExpression  *string    `json:"cron_expression"`
+	DurationMinutes *int       `json:"duration_minutes"`
+	ExcludeFromSLA  *bool      `json:"exclude_from_sla"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (h *maintenanceHandler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var req maintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request CreateMaintenanceWindow: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	window := &domain.MaintenanceWindow{ServerIDs: req.ServerIDs, GroupIDs: req.GroupIDs}
+	if req.Name != nil {
+		window.Name = *req.Name
+	}
+	if req.StartTime != nil {
+		window.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		window.EndTime = *req.EndTime
+	}
+	if req.CronExpression != nil {
+		window.CronExpression = *req.CronExpression
+	}
+	if req.DurationMinutes != nil {
+		window.DurationMinutes = *req.DurationMinutes
+	}
+	if req.ExcludeFromSLA != nil {
+		window.ExcludeFromSLA = *req.ExcludeFromSLA
+	}
+
+	id, err := h.service.CreateMaintenanceWindow(window)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create maintenance window: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Maintenance window created successfully",
+		"id":      id,
+	})
+}
+
+func (h *maintenanceHandler) GetMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	windows, err := h.service.GetMaintenanceWindows()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get maintenance windows: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get maintenance windows", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, windows)
+}
+
+func (h *maintenanceHandler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	window, err := h.service.GetMaintenanceWindow(id)
+	if err != nil {
+		writeMaintenanceError(w, "get", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, window)
+}
+
+func (h *maintenanceHandler) UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req maintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request UpdateMaintenanceWindow: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updatedData := make(map[string]interface{})
+	if req.Name != nil {
+		updatedData["name"] = *req.Name
+	}
+	if req.ServerIDs != nil {
+		updatedData["server_ids"] = req.ServerIDs
+	}
+	if req.GroupIDs != nil {
+		updatedData["group_ids"] = req.GroupIDs
+	}
+	if req.StartTime != nil {
+		updatedData["start_time"] = *req.StartTime
+	}
+	if req.EndTime != nil {
+		updatedData["end_time"] = *req.EndTime
+	}
+	if req.CronExpression != nil {
+		updatedData["cron_expression"] = *req.CronExpression
+	}
+	if req.DurationMinutes != nil {
+		updatedData["duration_minutes"] = *req.DurationMinutes
+	}
+	if req.ExcludeFromSLA != nil {
+		updatedData["exclude_from_sla"] = *req.ExcludeFromSLA
+	}
+
+	if err := h.service.UpdateMaintenanceWindow(id, updatedData); err != nil {
+		writeMaintenanceError(w, "update", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Maintenance window updated successfully",
+	})
+}
+
+func (h *maintenanceHandler) DeleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.DeleteMaintenanceWindow(id); err != nil {
+		writeMaintenanceError(w, "delete", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Maintenance window deleted successfully",
+	})
+}
+
+// writeMaintenanceError maps a missing window to 404 and everything else to a validation error.
+func writeMaintenanceError(w http.ResponseWriter, action, id string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+action+" maintenance window "+id+": "+err.Error(), "ERROR")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Maintenance window not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,165 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// mockMaintenanceService implements service.MaintenanceService for testing
+type mockMaintenanceService struct {
+	mock.Mock
+}
+
+func (m *mockMaintenanceService) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	args := m.Called(window)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMaintenanceService) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceService) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceService) UpdateMaintenanceWindow(id string, updatedData map[string]interface{}) error {
+	args := m.Called(id, updatedData)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceService) DeleteMaintenanceWindow(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceService) GetActiveMaintenanceWindow(serverID string, t time.Time) (*domain.MaintenanceWindow, error) {
+	args := m.Called(serverID, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
+func TestCreateMaintenanceWindow_Success(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	body := `{"name":"Weekly reboot","server_ids":["srv-1"],"start_time":"2025-01-01T00:00:00Z","cron_expression":"0 3 * * 0","duration_minutes":30,"exclude_from_sla":true}`
+	mockSvc.On("CreateMaintenanceWindow", mock.MatchedBy(func(w *domain.MaintenanceWindow) bool {
+		return w.Name == "Weekly reboot" && w.CronExpression == "0 3 * * 0" && w.DurationMinutes == 30 &&
+			w.ExcludeFromSLA && w.StartTime.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	})).Return("window-1", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/maintenance_windows", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	h.CreateMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), "window-1")
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCreateMaintenanceWindow_ValidationError(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	mockSvc.On("CreateMaintenanceWindow", mock.Anything).Return("", errors.New("maintenance window name is required"))
+
+	req := httptest.NewRequest(http.MethodPost, "/maintenance_windows", strings.NewReader(`{"server_ids":["srv-1"]}`))
+	rr := httptest.NewRecorder()
+	h.CreateMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "name is required")
+}
+
+func TestCreateMaintenanceWindow_InvalidBody(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPost, "/maintenance_windows", strings.NewReader(`{"start_time":"yesterday"}`))
+	rr := httptest.NewRecorder()
+	h.CreateMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "CreateMaintenanceWindow", mock.Anything)
+}
+
+func TestGetMaintenanceWindow_NotFound(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	mockSvc.On("GetMaintenanceWindow", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/maintenance_windows/missing", nil), map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.GetMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetMaintenanceWindows_Success(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	mockSvc.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{{ID: "window-1", Name: "Patching"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/maintenance_windows", nil)
+	rr := httptest.NewRecorder()
+	h.GetMaintenanceWindows(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Patching")
+}
+
+func TestUpdateMaintenanceWindow_Success(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	mockSvc.On("UpdateMaintenanceWindow", "window-1", map[string]interface{}{
+		"server_ids":       []string{"srv-1", "srv-2"},
+		"exclude_from_sla": false,
+	}).Return(nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/maintenance_windows/window-1",
+		strings.NewReader(`{"server_ids":["srv-1","srv-2"],"exclude_from_sla":false}`)), map[string]string{"id": "window-1"})
+	rr := httptest.NewRecorder()
+	h.UpdateMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteMaintenanceWindow_NotFound(t *testing.T) {
+	mockSvc := new(mockMaintenanceService)
+	h := handler.NewMaintenanceHandler(mockSvc)
+
+	mockSvc.On("DeleteMaintenanceWindow", "missing").Return(gorm.ErrRecordNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/maintenance_windows/missing", nil), map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.DeleteMaintenanceWindow(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"server_administration_service/internal/service"
+	"strconv"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+type SearchHandler interface {
+	SearchServers(w http.ResponseWriter, r *http.Request)
+	Reindex(w http.ResponseWriter, r *http.Request)
+}
+
+type searchHandler struct {
+	service service.ServerSearchService
+}
+
+func NewSearchHandler(service service.ServerSearchService) SearchHandler {
+	return &searchHandler{
+		service: service,
+	}
+}
+
+// SearchServers answers the servers matching the q query parameter, best first, at most limit of them.
+func (h *searchHandler) SearchServers(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 {
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result, err := h.service.SearchServers(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to search servers: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to search servers", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Reindex writes every server into the search index again.
+func (h *searchHandler) Reindex(w http.ResponseWriter, r *http.Request) {
+	indexed, err := h.service.Reindex()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to reindex servers: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to reindex servers", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Servers reindexed successfully",
+		"indexed": indexed,
+	})
+}
@@ -0,0 +1,111 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockServerSearchService implements service.ServerSearchService for testing
+type mockServerSearchService struct {
+	mock.Mock
+}
+
+func (m *mockServerSearchService) SearchServers(query string, limit int) (*dto.ServerSearchResult, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ServerSearchResult), args.Error(1)
+}
+
+func (m *mockServerSearchService) PrepareIndex() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *mockServerSearchService) Reindex() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestSearchServers_Success(t *testing.T) {
+	mockSvc := new(mockServerSearchService)
+	h := handler.NewSearchHandler(mockSvc)
+
+	mockSvc.On("SearchServers", "10.0.3", 5).Return(&dto.ServerSearchResult{
+		Hits: []dto.ServerSearchHit{{
+			ServerID:   "srv-1",
+			Server:     &domain.Server{ServerID: "srv-1", ServerName: "web-1"},
+			Score:      2.5,
+			Highlights: map[string][]string{"addresses": {"<em>10.0.3</em>.14"}},
+		}},
+		Total: 1,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=10.0.3&limit=5", nil)
+	rr := httptest.NewRecorder()
+	h.SearchServers(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var result dto.ServerSearchResult
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&result))
+	assert.Equal(t, int64(1), result.Total)
+	assert.Equal(t, "web-1", result.Hits[0].Server.ServerName)
+	assert.Equal(t, []string{"<em>10.0.3</em>.14"}, result.Hits[0].Highlights["addresses"])
+	mockSvc.AssertExpectations(t)
+}
+
+func TestSearchServers_Invalid(t *testing.T) {
+	mockSvc := new(mockServerSearchService)
+	h := handler.NewSearchHandler(mockSvc)
+
+	mockSvc.On("SearchServers", "", 0).Return(nil, fmt.Errorf("%w: the query must be 1 to 256 characters", service.ErrInvalidSearch))
+
+	for _, query := range []string{"", "?q=web&limit=0", "?q=web&limit=many"} {
+		req := httptest.NewRequest(http.MethodGet, "/search"+query, nil)
+		rr := httptest.NewRecorder()
+		h.SearchServers(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+	mockSvc.AssertNotCalled(t, "SearchServers", "web", mock.Anything)
+}
+
+func TestSearchServers_Error(t *testing.T) {
+	mockSvc := new(mockServerSearchService)
+	h := handler.NewSearchHandler(mockSvc)
+
+	mockSvc.On("SearchServers", "web", 0).Return(nil, errors.New("can't send search request to ES"))
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=web", nil)
+	rr := httptest.NewRecorder()
+	h.SearchServers(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestReindex_Success(t *testing.T) {
+	mockSvc := new(mockServerSearchService)
+	h := handler.NewSearchHandler(mockSvc)
+
+	mockSvc.On("Reindex").Return(42, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/search/reindex", nil)
+	rr := httptest.NewRecorder()
+	h.Reindex(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"indexed":42`)
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type ServerGroupHandler interface {
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	GetGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	UpdateGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+	GetGroupMembers(w http.ResponseWriter, r *http.Request)
+	AddGroupMembers(w http.ResponseWriter, r *http.Request)
+	RemoveGroupMember(w http.ResponseWriter, r *http.Request)
+	GetGroupSummaries(w http.ResponseWriter, r *http.Request)
+	GetGroupSummary(w http.ResponseWriter, r *http.Request)
+}
+
+type serverGroupHandler struct {
+	service service.ServerGroupService
+}
+
+func NewServerGroupHandler(service service.ServerGroupService) ServerGroupHandler {
+	return &serverGroupHandler{
+		service: service,
+	}
+}
+
+type serverGroupRequest struct {
+	Name      *string  `json:"name"`
+	ParentID  *string  `json:"parent_id"`
+	SLATarget *float64 `json:"sla_target"`
+}
+
+func (h *serverGroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var req serverGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request CreateGroup: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	group := &domain.ServerGroup{}
+	if req.Name != nil {
+		group.Name = *req.Name
+	}
+	if req.ParentID != nil && *req.ParentID != "" {
+		group.ParentID = req.ParentID
+	}
+	if req.SLATarget != nil {
+		group.SLATarget = *req.SLATarget
+	}
+
+	id, err := h.service.CreateGroup(group)
+	if err != nil {
+		writeGroupError(w, "create", "", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Server group created successfully",
+		"id":      id,
+	})
+}
+
+func (h *serverGroupHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.GetGroups()
+	if err != nil {
+		writeGroupError(w, "get", "", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, groups)
+}
+
+func (h *serverGroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	group, err := h.service.GetGroup(id)
+	if err != nil {
+		writeGroupError(w, "get", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, group)
+}
+
+func (h *serverGroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req serverGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request UpdateGroup: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	updatedData := make(map[string]interface{})
+	if req.Name != nil {
+		updatedData["name"] = *req.Name
+	}
+	if req.ParentID != nil {
+		updatedData["parent_id"] = *req.ParentID
+	}
+	if req.SLATarget != nil {
+		updatedData["sla_target"] = *req.SLATarget
+	}
+
+	if err := h.service.UpdateGroup(id, updatedData); err != nil {
+		writeGroupError(w, "update", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Server group updated successfully",
+	})
+}
+
+func (h *serverGroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.DeleteGroup(id); err != nil {
+		writeGroupError(w, "delete", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Server group deleted successfully",
+	})
+}
+
+func (h *serverGroupHandler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	serverIDs, err := h.service.GetGroupMembers(id)
+	if err != nil {
+		writeGroupError(w, "get the members of", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"server_ids": serverIDs,
+	})
+}
+
+func (h *serverGroupHandler) AddGroupMembers(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var req struct {
+		ServerIDs []string `json:"server_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request AddGroupMembers: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.AddMembers(id, req.ServerIDs); err != nil {
+		writeGroupError(w, "add servers to", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Servers added to the group successfully",
+	})
+}
+
+func (h *serverGroupHandler) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	serverID := mux.Vars(r)["server_id"]
+
+	if err := h.service.RemoveMember(id, serverID); err != nil {
+		writeGroupError(w, "remove server "+serverID+" from", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Server removed from the group successfully",
+	})
+}
+
+// GetGroupSummaries accepts the same period parameters as the SLA compliance report.
+func (h *serverGroupHandler) GetGroupSummaries(w http.ResponseWriter, r *http.Request) {
+	startTime, endTime, err := parseSLAPeriod(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to parse group summary period: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := h.service.GetGroupSummaries(startTime, endTime)
+	if err != nil {
+		writeGroupError(w, "summarize", "", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (h *serverGroupHandler) GetGroupSummary(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	startTime, endTime, err := parseSLAPeriod(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to parse group summary period: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.GetGroupSummary(id, startTime, endTime)
+	if err != nil {
+		writeGroupError(w, "summarize", id, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, summary)
+}
+
+// writeGroupError maps validation errors to 400, missing groups or servers to 404 and
+// deleting a group that still has subgroups to 409.
+func writeGroupError(w http.ResponseWriter, action, id string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+action+" server group "+id+": "+err.Error(), "ERROR")
+	switch {
+	case errors.Is(err, service.ErrInvalidGroup), errors.Is(err, domain.ErrGroupCycle), errors.Is(err, service.ErrInvalidSLAPeriod):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrServerNotFound):
+		http.Error(w, "Server not found", http.StatusNotFound)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		http.Error(w, "Server group not found", http.StatusNotFound)
+	case errors.Is(err, domain.ErrGroupHasChildren):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Failed to "+action+" server group", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,245 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+	"server_administration_service/proto"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// mockServerGroupService implements service.ServerGroupService for testing
+type mockServerGroupService struct {
+	mock.Mock
+}
+
+func (m *mockServerGroupService) CreateGroup(group *domain.ServerGroup) (string, error) {
+	args := m.Called(group)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockServerGroupService) GetGroup(id string) (*domain.ServerGroup, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ServerGroup), args.Error(1)
+}
+
+func (m *mockServerGroupService) GetGroups() ([]domain.ServerGroup, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServerGroup), args.Error(1)
+}
+
+func (m *mockServerGroupService) UpdateGroup(id string, updatedData map[string]interface{}) error {
+	args := m.Called(id, updatedData)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupService) DeleteGroup(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupService) GetGroupMembers(id string) ([]string, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerGroupService) AddMembers(id string, serverIDs []string) error {
+	args := m.Called(id, serverIDs)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupService) RemoveMember(id, serverID string) error {
+	args := m.Called(id, serverID)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupService) GetGroupSummaries(startTime, endTime time.Time) ([]domain.GroupSummary, error) {
+	args := m.Called(startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.GroupSummary), args.Error(1)
+}
+
+func (m *mockServerGroupService) GetGroupSummary(id string, startTime, endTime time.Time) (*domain.GroupSummary, error) {
+	args := m.Called(id, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.GroupSummary), args.Error(1)
+}
+
+func (m *mockServerGroupService) GetGroupCompliance(startTime, endTime time.Time) ([]domain.GroupSLACompliance, error) {
+	args := m.Called(startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.GroupSLACompliance), args.Error(1)
+}
+
+func TestCreateGroup_Success(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("CreateGroup", mock.MatchedBy(func(g *domain.ServerGroup) bool {
+		return g.Name == "rack-2" && g.ParentID != nil && *g.ParentID == "dc-1" && g.SLATarget == 99.5
+	})).Return("group-1", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/groups", strings.NewReader(`{"name":"rack-2","parent_id":"dc-1","sla_target":99.5}`))
+	rr := httptest.NewRecorder()
+	h.CreateGroup(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), "group-1")
+}
+
+func TestCreateGroup_ValidationError(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("CreateGroup", mock.Anything).Return("", service.ErrInvalidGroup)
+
+	req := httptest.NewRequest(http.MethodPost, "/groups", strings.NewReader(`{}`))
+	rr := httptest.NewRecorder()
+	h.CreateGroup(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateGroup_Cycle(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("UpdateGroup", "dc-1", map[string]interface{}{"parent_id": "rack-2"}).Return(domain.ErrGroupCycle)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/groups/dc-1", strings.NewReader(`{"parent_id":"rack-2"}`)), map[string]string{"id": "dc-1"})
+	rr := httptest.NewRecorder()
+	h.UpdateGroup(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestDeleteGroup_HasChildren(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("DeleteGroup", "dc-1").Return(domain.ErrGroupHasChildren)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/groups/dc-1", nil), map[string]string{"id": "dc-1"})
+	rr := httptest.NewRecorder()
+	h.DeleteGroup(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestGetGroup_NotFound(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("GetGroup", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/groups/missing", nil), map[string]string{"id": "missing"})
+	rr := httptest.NewRecorder()
+	h.GetGroup(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAddGroupMembers_UnknownServer(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("AddMembers", "rack-2", []string{"srv-1", "srv-9"}).Return(service.ErrServerNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/groups/rack-2/members",
+		strings.NewReader(`{"server_ids":["srv-1","srv-9"]}`)), map[string]string{"id": "rack-2"})
+	rr := httptest.NewRecorder()
+	h.AddGroupMembers(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Server not found")
+}
+
+func TestRemoveGroupMember_Success(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	mockSvc.On("RemoveMember", "rack-2", "srv-1").Return(nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/groups/rack-2/members/srv-1", nil),
+		map[string]string{"id": "rack-2", "server_id": "srv-1"})
+	rr := httptest.NewRecorder()
+	h.RemoveGroupMember(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetGroupSummaries_Month(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	start := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("GetGroupSummaries", start, start.AddDate(0, 1, 0)).Return([]domain.GroupSummary{
+		{GroupID: "dc-1", Path: "dc-1", Status: domain.GroupStatusPartiallyDown, Breached: true},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/groups/summary?month=2025-05", nil)
+	rr := httptest.NewRecorder()
+	h.GetGroupSummaries(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "PartiallyDown")
+}
+
+func TestGetGroupSummary_InvalidPeriod(t *testing.T) {
+	mockSvc := new(mockServerGroupService)
+	h := handler.NewServerGroupHandler(mockSvc)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/groups/dc-1/summary?period=weekly", nil), map[string]string{"id": "dc-1"})
+	rr := httptest.NewRecorder()
+	h.GetGroupSummary(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "GetGroupSummary", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetGroupsInformation_Success(t *testing.T) {
+	mockGroups := new(mockServerGroupService)
+	h := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), new(mockMaintenanceService), new(mockSLAService), mockGroups, new(mockDependencyService))
+
+	mockGroups.On("GetGroupSummaries", mock.Anything, mock.Anything).Return([]domain.GroupSummary{
+		{GroupID: "dc-1", Path: "dc-1", Status: domain.GroupStatusUp, NumServers: 2, NumUp: 2, UpTimeRatio: 100, SLATarget: 99.9},
+		{GroupID: "rack-2", Path: "dc-1 / rack-2", Status: domain.GroupStatusDown, NumServers: 1, NumDown: 1, UpTimeRatio: 50, SLATarget: 99.9, Breached: true},
+	}, nil)
+
+	resp, err := h.GetGroupsInformation(context.Background(), &proto.TimeRequest{
+		StartTime: "2025-06-01T00:00:00Z",
+		EndTime:   "2025-06-02T00:00:00Z",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, resp.GroupList, 2)
+	assert.Equal(t, "dc-1 / rack-2", resp.GroupList[1].Path)
+	assert.True(t, resp.GroupList[1].Breached)
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"context"
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/service"
 	"server_administration_service/proto"
+	"strings"
+	"time"
 
 	"github.com/flashhhhh/pkg/logging"
 )
@@ -11,20 +14,28 @@ import (
 type ServerGRPCHandler struct {
 	serverGRPCService service.ServerGRPCService
 	serverInfoService service.ServerInfoService
+	maintenanceService service.MaintenanceService
+	slaService service.SLAService
+	serverGroupService service.ServerGroupService
+	dependencyService service.DependencyService
 	proto.UnimplementedServerAdministrationServiceServer
 }
 
-func NewServerGRPCHandler(serverGRPCService service.ServerGRPCService, serverInfoService service.ServerInfoService) *ServerGRPCHandler {
+func NewServerGRPCHandler(serverGRPCService service.ServerGRPCService, serverInfoService service.ServerInfoService, maintenanceService service.MaintenanceService, slaService service.SLAService, serverGroupService service.ServerGroupService, dependencyService service.DependencyService) *ServerGRPCHandler {
 	return &ServerGRPCHandler{
 		serverGRPCService: serverGRPCService,
 		serverInfoService: serverInfoService,
+		maintenanceService: maintenanceService,
+		slaService: slaService,
+		serverGroupService: serverGroupService,
+		dependencyService: dependencyService,
 	}
 }
 
-func (h *ServerGRPCHandler) GetAddressAndStatus(ctx context.Context, req *proto.EmptyRequest) (*proto.IDAddressAndStatusList, error) {
-	logging.LogMessage("server_administration_service", "Get Address and current status list of all servers", "INFO")
+func (h *ServerGRPCHandler) GetAddressAndStatus(ctx context.Context, req *proto.AddressRequest) (*proto.IDAddressAndStatusList, error) {
+	logging.LogMessage("server_administration_service", "Get Address and current status list of servers matching label selector '" + req.LabelSelector + "' and filter '" + req.Filter + "'", "INFO")
 
-	serverAddresses, err := h.serverGRPCService.GetServerAddresses()
+	serverAddresses, err := h.serverGRPCService.GetServerAddresses(req.LabelSelector, req.Filter)
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to get address and current status list, err: " + err.Error(), "INFO")
 		return nil, err
@@ -34,16 +45,36 @@ func (h *ServerGRPCHandler) GetAddressAndStatus(ctx context.Context, req *proto.
 
 	idAddressAndStatusList := &proto.IDAddressAndStatusList{}
 	for _, serverAddress := range serverAddresses {
-		idAddressAndStatusList.ServerList = append(idAddressAndStatusList.ServerList, &proto.IDAddressAndStatus{
+		idAddressAndStatus := &proto.IDAddressAndStatus{
 			ServerId: serverAddress.ServerID,
-			Address: serverAddress.IPv4,
+			Address: serverAddress.PrimaryAddress,
 			Status: serverAddress.Status,
-		})
+		}
+
+		// The primary address goes first so that health checks try it before the others
+		if primary, ok := serverAddress.Addresses.Primary(); ok {
+			idAddressAndStatus.Addresses = append(idAddressAndStatus.Addresses, networkAddress(primary))
+		}
+		for _, address := range serverAddress.Addresses {
+			if !address.Primary {
+				idAddressAndStatus.Addresses = append(idAddressAndStatus.Addresses, networkAddress(address))
+			}
+		}
+
+		idAddressAndStatusList.ServerList = append(idAddressAndStatusList.ServerList, idAddressAndStatus)
 	}
 
 	return idAddressAndStatusList, nil
 }
 
+func networkAddress(address domain.Address) *proto.NetworkAddress {
+	return &proto.NetworkAddress{
+		Type: address.Type,
+		Address: address.Address,
+		Primary: address.Primary,
+	}
+}
+
 func (h *ServerGRPCHandler) GetServersInformation(ctx context.Context, req *proto.TimeRequest) (*proto.ServersInformationResponse, error) {
 	numServers, err := h.serverInfoService.GetNumServers()
 	if err != nil {
@@ -75,4 +106,108 @@ func (h *ServerGRPCHandler) GetServersInformation(ctx context.Context, req *prot
 		NumOffServers: int64(numOffServers),
 		MeanUpTimeRatio: meanUpTimeRatio,
 	}, nil
-}
\ No newline at end of file
+}
+
+func (h *ServerGRPCHandler) GetNotificationStatus(ctx context.Context, req *proto.ServerIDRequest) (*proto.NotificationStatus, error) {
+	window, err := h.maintenanceService.GetActiveMaintenanceWindow(req.ServerId, time.Now())
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the notification status of server " + req.ServerId + ", err: " + err.Error(), "ERROR")
+		return &proto.NotificationStatus{}, err
+	}
+
+	if window != nil {
+		return &proto.NotificationStatus{
+			Suppressed: true,
+			Reason: "Server is in maintenance window " + window.Name,
+			MaintenanceWindowId: window.ID,
+		}, nil
+	}
+
+	// Servers behind a down switch or gateway would only echo the parent's outage
+	parents, err := h.dependencyService.GetDownDependencies(req.ServerId)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the notification status of server " + req.ServerId + ", err: " + err.Error(), "ERROR")
+		return &proto.NotificationStatus{}, err
+	}
+
+	if len(parents) > 0 {
+		return &proto.NotificationStatus{
+			Suppressed: true,
+			Reason: "Server is unreachable, parent down: " + strings.Join(parents, ", "),
+		}, nil
+	}
+
+	return &proto.NotificationStatus{}, nil
+}
+
+func (h *ServerGRPCHandler) GetSLACompliance(ctx context.Context, req *proto.TimeRequest) (*proto.SLAComplianceList, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Start time is not valid", "ERROR")
+		return &proto.SLAComplianceList{}, err
+	}
+
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "End time is not valid", "ERROR")
+		return &proto.SLAComplianceList{}, err
+	}
+
+	compliances, err := h.slaService.GetCompliance("", startTime, endTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get SLA compliance, err: " + err.Error(), "ERROR")
+		return &proto.SLAComplianceList{}, err
+	}
+
+	complianceList := &proto.SLAComplianceList{}
+	for _, compliance := range compliances {
+		complianceList.ComplianceList = append(complianceList.ComplianceList, &proto.SLACompliance{
+			ServerId: compliance.ServerID,
+			ServerName: compliance.ServerName,
+			SlaTarget: compliance.SLATarget,
+			Achieved: compliance.Achieved,
+			ErrorBudgetRemaining: compliance.ErrorBudgetRemaining,
+			BurnRate: compliance.BurnRate,
+			Breached: compliance.Breached,
+		})
+	}
+
+	return complianceList, nil
+}
+func (h *ServerGRPCHandler) GetGroupsInformation(ctx context.Context, req *proto.TimeRequest) (*proto.GroupSummaryList, error) {
+	startTime, err := time.Parse(time.RFC3339, req.StartTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Start time is not valid", "ERROR")
+		return &proto.GroupSummaryList{}, err
+	}
+
+	endTime, err := time.Parse(time.RFC3339, req.EndTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "End time is not valid", "ERROR")
+		return &proto.GroupSummaryList{}, err
+	}
+
+	summaries, err := h.serverGroupService.GetGroupSummaries(startTime, endTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get group summaries, err: " + err.Error(), "ERROR")
+		return &proto.GroupSummaryList{}, err
+	}
+
+	groupList := &proto.GroupSummaryList{}
+	for _, summary := range summaries {
+		groupList.GroupList = append(groupList.GroupList, &proto.GroupSummary{
+			GroupId: summary.GroupID,
+			Name: summary.Name,
+			Path: summary.Path,
+			Status: summary.Status,
+			NumServers: int64(summary.NumServers),
+			NumUp: int64(summary.NumUp),
+			NumDown: int64(summary.NumDown),
+			UptimeRatio: summary.UpTimeRatio,
+			SlaTarget: summary.SLATarget,
+			Breached: summary.Breached,
+		})
+	}
+
+	return groupList, nil
+}
@@ -3,8 +3,11 @@ package handler_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/handler"
 	"server_administration_service/proto"
@@ -18,8 +21,8 @@ type mockServerGRPCService struct {
 	mock.Mock
 }
 
-func (m *mockServerGRPCService) GetServerAddresses() ([]dto.ServerAddress, error) {
-	args := m.Called()
+func (m *mockServerGRPCService) GetServerAddresses(labelSelector, filter string) ([]dto.ServerAddress, error) {
+	args := m.Called(labelSelector, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -51,18 +54,37 @@ func (m *mockServerInfoService) GetServerMeanUpTimeRatio(startTime, endTime stri
 	return args.Get(0).(float64), args.Error(1)
 }
 
+func (m *mockServerInfoService) GetUpTimeRatios(start, end time.Time) (map[string]float64, error) {
+	args := m.Called(start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]float64), args.Error(1)
+}
+
+func (m *mockServerInfoService) GetUpTimeTrend(start, end time.Time, points int) ([]dto.UpTimePoint, error) {
+	args := m.Called(start, end, points)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.UpTimePoint), args.Error(1)
+}
+
 func TestGetAddressAndStatus_Success(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	addresses := []dto.ServerAddress{
-		{ServerID: "1", IPv4: "10.0.0.1", Status: "On"},
-		{ServerID: "2", IPv4: "10.0.0.2", Status: "Off"},
+		{ServerID: "1", PrimaryAddress: "10.0.0.1", Status: "On", Addresses: domain.Addresses{
+			{Type: domain.AddressTypeIPv6, Address: "2001:db8::1"},
+			{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true},
+		}},
+		{ServerID: "2", PrimaryAddress: "10.0.0.2", Status: "Off"},
 	}
-	mockGRPC.On("GetServerAddresses").Return(addresses, nil)
+	mockGRPC.On("GetServerAddresses", "env=prod", "status = Up").Return(addresses, nil)
 
-	resp, err := handler.GetAddressAndStatus(context.Background(), &proto.EmptyRequest{})
+	resp, err := handler.GetAddressAndStatus(context.Background(), &proto.AddressRequest{LabelSelector: "env=prod", Filter: "status = Up"})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -72,16 +94,21 @@ func TestGetAddressAndStatus_Success(t *testing.T) {
 	if resp.ServerList[0].ServerId != "1" || resp.ServerList[1].ServerId != "2" {
 		t.Errorf("unexpected server IDs: %+v", resp.ServerList)
 	}
+	// The primary address is listed first
+	first := resp.ServerList[0]
+	if first.Address != "10.0.0.1" || len(first.Addresses) != 2 || !first.Addresses[0].Primary || first.Addresses[1].Address != "2001:db8::1" {
+		t.Errorf("unexpected addresses: %+v", first)
+	}
 }
 
 func TestGetAddressAndStatus_Error(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
-	mockGRPC.On("GetServerAddresses").Return(nil, errors.New("db error"))
+	mockGRPC.On("GetServerAddresses", "", "").Return(nil, errors.New("db error"))
 
-	resp, err := handler.GetAddressAndStatus(context.Background(), &proto.EmptyRequest{})
+	resp, err := handler.GetAddressAndStatus(context.Background(), &proto.AddressRequest{})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -93,7 +120,7 @@ func TestGetAddressAndStatus_Error(t *testing.T) {
 func TestGetServersInformation_Success(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	mockInfo.On("GetNumServers").Return(5, nil)
 	mockInfo.On("GetNumOnServers").Return(3, nil)
@@ -113,7 +140,7 @@ func TestGetServersInformation_Success(t *testing.T) {
 func TestGetServersInformation_NumServersError(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	mockInfo.On("GetNumServers").Return(0, errors.New("fail"))
 	req := &proto.TimeRequest{StartTime: "2025-06-24T00:00:00Z", EndTime: "2025-06-24T23:59:59Z"}
@@ -129,7 +156,7 @@ func TestGetServersInformation_NumServersError(t *testing.T) {
 func TestGetServersInformation_NumOnServersError(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	mockInfo.On("GetNumServers").Return(5, nil)
 	mockInfo.On("GetNumOnServers").Return(0, errors.New("fail"))
@@ -146,7 +173,7 @@ func TestGetServersInformation_NumOnServersError(t *testing.T) {
 func TestGetServersInformation_NumOffServersError(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	mockInfo.On("GetNumServers").Return(5, nil)
 	mockInfo.On("GetNumOnServers").Return(3, nil)
@@ -164,7 +191,7 @@ func TestGetServersInformation_NumOffServersError(t *testing.T) {
 func TestGetServersInformation_MeanUpTimeRatioError(t *testing.T) {
 	mockGRPC := new(mockServerGRPCService)
 	mockInfo := new(mockServerInfoService)
-	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo)
+	handler := handler.NewServerGRPCHandler(mockGRPC, mockInfo, new(mockMaintenanceService), new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
 
 	mockInfo.On("GetNumServers").Return(5, nil)
 	mockInfo.On("GetNumOnServers").Return(3, nil)
@@ -178,4 +205,97 @@ func TestGetServersInformation_MeanUpTimeRatioError(t *testing.T) {
 	if resp == nil {
 		t.Error("expected non-nil response")
 	}
-}
\ No newline at end of file
+}
+func TestGetNotificationStatus_Suppressed(t *testing.T) {
+	mockMaintenance := new(mockMaintenanceService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), mockMaintenance, new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
+
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-1", mock.Anything).
+		Return(&domain.MaintenanceWindow{ID: "window-1", Name: "Patching"}, nil)
+
+	resp, err := handler.GetNotificationStatus(context.Background(), &proto.ServerIDRequest{ServerId: "srv-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.Suppressed || resp.MaintenanceWindowId != "window-1" {
+		t.Errorf("expected suppression by window-1, got %+v", resp)
+	}
+}
+
+func TestGetNotificationStatus_NotSuppressed(t *testing.T) {
+	mockMaintenance := new(mockMaintenanceService)
+	mockDependencies := new(mockDependencyService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), mockMaintenance, new(mockSLAService), new(mockServerGroupService), mockDependencies)
+
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-1", mock.Anything).Return(nil, nil)
+	mockDependencies.On("GetDownDependencies", "srv-1").Return([]string{}, nil)
+
+	resp, err := handler.GetNotificationStatus(context.Background(), &proto.ServerIDRequest{ServerId: "srv-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.Suppressed {
+		t.Errorf("expected notifications not to be suppressed")
+	}
+}
+
+func TestGetNotificationStatus_Error(t *testing.T) {
+	mockMaintenance := new(mockMaintenanceService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), mockMaintenance, new(mockSLAService), new(mockServerGroupService), new(mockDependencyService))
+
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-1", mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := handler.GetNotificationStatus(context.Background(), &proto.ServerIDRequest{ServerId: "srv-1"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestGetNotificationStatus_ParentDown(t *testing.T) {
+	mockMaintenance := new(mockMaintenanceService)
+	mockDependencies := new(mockDependencyService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), mockMaintenance, new(mockSLAService), new(mockServerGroupService), mockDependencies)
+
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-1", mock.Anything).Return(nil, nil)
+	mockDependencies.On("GetDownDependencies", "srv-1").Return([]string{"switch-1"}, nil)
+
+	resp, err := handler.GetNotificationStatus(context.Background(), &proto.ServerIDRequest{ServerId: "srv-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !resp.Suppressed || !strings.Contains(resp.Reason, "switch-1") {
+		t.Errorf("expected suppression because switch-1 is down, got %+v", resp)
+	}
+}
+
+func TestGetSLACompliance_Success(t *testing.T) {
+	mockSLA := new(mockSLAService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), new(mockMaintenanceService), mockSLA, new(mockServerGroupService), new(mockDependencyService))
+
+	mockSLA.On("GetCompliance", "", mock.Anything, mock.Anything).Return([]domain.SLACompliance{
+		{ServerID: "srv-1", SLATarget: 99.9, Achieved: 99.95},
+		{ServerID: "srv-2", SLATarget: 99.9, Achieved: 98, Breached: true},
+	}, nil)
+
+	resp, err := handler.GetSLACompliance(context.Background(), &proto.TimeRequest{
+		StartTime: "2025-06-01T00:00:00Z",
+		EndTime:   "2025-07-01T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(resp.ComplianceList) != 2 || !resp.ComplianceList[1].Breached {
+		t.Errorf("unexpected compliance list: %+v", resp.ComplianceList)
+	}
+}
+
+func TestGetSLACompliance_InvalidTime(t *testing.T) {
+	mockSLA := new(mockSLAService)
+	handler := handler.NewServerGRPCHandler(new(mockServerGRPCService), new(mockServerInfoService), new(mockMaintenanceService), mockSLA, new(mockServerGroupService), new(mockDependencyService))
+
+	_, err := handler.GetSLACompliance(context.Background(), &proto.TimeRequest{StartTime: "yesterday", EndTime: "today"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	mockSLA.AssertNotCalled(t, "GetCompliance", mock.Anything, mock.Anything, mock.Anything)
+}
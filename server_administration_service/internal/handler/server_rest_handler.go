@@ -2,23 +2,32 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"path/filepath"
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/service"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
 )
 
 type ServerRestHandler interface {
 	CreateServer(w http.ResponseWriter, r *http.Request)
 	ViewServers(w http.ResponseWriter, r *http.Request)
+	GetServer(w http.ResponseWriter, r *http.Request)
 	UpdateServer(w http.ResponseWriter, r *http.Request)
 	DeleteServer(w http.ResponseWriter, r *http.Request)
 	ImportServers(w http.ResponseWriter, r *http.Request)
+	ImportProgress(w http.ResponseWriter, r *http.Request)
 	ExportServers(w http.ResponseWriter, r *http.Request)
+	BulkUpdateServers(w http.ResponseWriter, r *http.Request)
+	BulkDeleteServers(w http.ResponseWriter, r *http.Request)
 }
 
 type serverRestHandler struct {
@@ -43,11 +52,34 @@ func (h *serverRestHandler) CreateServer(w http.ResponseWriter, r *http.Request)
 
 	serverID, _ := requestBody["server_id"].(string)
 	serverName, _ := requestBody["server_name"].(string)
-	ipAddress, _ := requestBody["ipv4"].(string)
+	slaTarget, _ := requestBody["sla_target"].(float64)
+	notes, _ := requestBody["notes"].(string)
+
+	addresses, _, err := addressesFromBody(requestBody)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid addresses for request CreateServer: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	labels, err := labelsFromBody(requestBody["labels"])
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid labels for request CreateServer: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	
-	server_id, err := h.service.CreateServer(serverID, serverName, ipAddress)
+	server_id, err := h.service.CreateServer(serverID, serverName, addresses, slaTarget, labels, notes, actorFromRequest(r))
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to create server: "+err.Error(), "ERROR")
+		if errors.Is(err, domain.ErrInvalidLabel) || errors.Is(err, domain.ErrInvalidAddress) || errors.Is(err, domain.ErrInvalidServer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, service.ErrServerConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Failed to create server", http.StatusInternalServerError)
 		return
 	}
@@ -62,7 +94,22 @@ func (h *serverRestHandler) CreateServer(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// ViewServers lists the matching servers. Given from and to it answers the bare array of that offset
+// range; otherwise it pages through them by cursor and answers a page with the total count.
 func (h *serverRestHandler) ViewServers(w http.ResponseWriter, r *http.Request) {
+	serverFilter, ok := readServerFilter(w, r)
+	if !ok {
+		return
+	}
+
+	sortedColumn := r.URL.Query().Get("sort_column")
+	order := r.URL.Query().Get("sort_order")
+
+	if !r.URL.Query().Has("from") && !r.URL.Query().Has("to") {
+		h.viewServerPage(w, r, &serverFilter)
+		return
+	}
+
 	fromStr := r.URL.Query().Get("from")
 	from, err := strconv.Atoi(fromStr)
 	if err != nil {
@@ -81,13 +128,97 @@ func (h *serverRestHandler) ViewServers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	sortedColumn := r.URL.Query().Get("sort_column")
-	order := r.URL.Query().Get("sort_order")
+	sort, err := domain.ParseServerSort(sortedColumn, order)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid sort query parameters: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	servers, err := h.service.ViewServers(&serverFilter, from, to, sort)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidFilterExpression) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to view servers: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to view servers", http.StatusInternalServerError)
+		return
+	}
+
+	logging.LogMessage("server_administration_service", "Servers retrieved successfully", "INFO")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	response, _ := json.Marshal(servers)
+	w.Write(response)
+}
+
+func (h *serverRestHandler) viewServerPage(w http.ResponseWriter, r *http.Request, serverFilter *dto.ServerFilter) {
+	sort, err := parsePageSort(r)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid sort query parameters: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request := dto.ServerPageRequest{
+		Sort:   sort,
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			http.Error(w, "Invalid 'limit' query parameter", http.StatusBadRequest)
+			return
+		}
+		request.Limit = limit
+	}
+
+	page, err := h.service.ViewServerPage(serverFilter, request)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPage) || errors.Is(err, domain.ErrInvalidFilterExpression) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to view servers: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to view servers", http.StatusInternalServerError)
+		return
+	}
+
+	logging.LogMessage("server_administration_service", "Servers retrieved successfully", "INFO")
+	writeJSON(w, http.StatusOK, page)
+}
+
+// parsePageSort reads the sort of a page of servers. Without sort_column and sort_order it's nil, so
+// that a cursor carries on in its own sort.
+func parsePageSort(r *http.Request) (domain.ServerSort, error) {
+	if r.URL.Query().Get("sort_column") == "" && r.URL.Query().Get("sort_order") == "" {
+		return nil, nil
+	}
+	return domain.ParseServerSort(r.URL.Query().Get("sort_column"), r.URL.Query().Get("sort_order"))
+}
+
+// readServerFilter reads the server filter of a listing or export, answering 400 when it's invalid.
+func readServerFilter(w http.ResponseWriter, r *http.Request) (dto.ServerFilter, bool) {
+	serverFilter, err := parseServerFilter(r)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid server filter: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return dto.ServerFilter{}, false
+	}
+	return serverFilter, true
+}
 
+// parseServerFilter reads the server filter of the query parameters.
+func parseServerFilter(r *http.Request) (dto.ServerFilter, error) {
 	serverID := r.URL.Query().Get("server_id")
 	serverName := r.URL.Query().Get("server_name")
 	status := r.URL.Query().Get("status")
-	ipv4 := r.URL.Query().Get("ipv4")
+	// ipv4 is the filter's name from before servers had several addresses
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		address = r.URL.Query().Get("ipv4")
+	}
 
 	serverFilter := dto.ServerFilter{}
 
@@ -100,23 +231,45 @@ func (h *serverRestHandler) ViewServers(w http.ResponseWriter, r *http.Request)
 	if status != "" {
 		serverFilter.Status = status
 	}
-	if ipv4 != "" {
-		serverFilter.IPv4 = ipv4
+	if address != "" {
+		serverFilter.Address = address
 	}
 
-	servers, err := h.service.ViewServers(&serverFilter, from, to, sortedColumn, order)
+	labelSelector, err := domain.ParseLabelSelector(r.URL.Query().Get("label_selector"))
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to view servers: "+err.Error(), "ERROR")
-		http.Error(w, "Failed to view servers", http.StatusInternalServerError)
-		return
+		return dto.ServerFilter{}, err
 	}
+	serverFilter.LabelSelector = labelSelector
 
-	logging.LogMessage("server_administration_service", "Servers retrieved successfully", "INFO")
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	expression, err := domain.ParseFilterExpression(r.URL.Query().Get("filter"))
+	if err != nil {
+		return dto.ServerFilter{}, err
+	}
+	serverFilter.Expression = expression
 
-	response, _ := json.Marshal(servers)
-	w.Write(response)
+	return serverFilter, nil
+}
+
+// GetServer returns a server with its version as ETag, for an If-Match on its update or deletion.
+func (h *serverRestHandler) GetServer(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	server, err := h.service.GetServer(serverID)
+	if err != nil {
+		if errors.Is(err, service.ErrServerNotFound) {
+			http.Error(w, "Server "+serverID+" not found", http.StatusNotFound)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to get server "+serverID+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get server", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", serverETag(server.Version))
+	if notModified(r, server.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, server)
 }
 
 func (h *serverRestHandler) UpdateServer(w http.ResponseWriter, r *http.Request) {
@@ -127,6 +280,12 @@ func (h *serverRestHandler) UpdateServer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	version, ok := versionFromIfMatch(r)
+	if !ok {
+		http.Error(w, "If-Match is not an ETag of the server", http.StatusPreconditionFailed)
+		return
+	}
+
 	var requestBody map[string]interface{}
 	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
@@ -141,14 +300,55 @@ func (h *serverRestHandler) UpdateServer(w http.ResponseWriter, r *http.Request)
 		updatedData["server_name"] = serverName
 	}
 
-	ipAddress, existed := requestBody["ipv4"].(string)
+	addresses, existed, err := addressesFromBody(requestBody)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Invalid addresses for request UpdateServer: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if existed {
+		updatedData["addresses"] = addresses
+	}
+
+	slaTarget, existed := requestBody["sla_target"].(float64)
 	if existed {
-		updatedData["ipv4"] = ipAddress
+		updatedData["sla_target"] = slaTarget
+	}
+
+	notes, existed := requestBody["notes"].(string)
+	if existed {
+		updatedData["notes"] = notes
+	}
+
+	if rawLabels, existed := requestBody["labels"]; existed {
+		labels, err := labelsFromBody(rawLabels)
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Invalid labels for request UpdateServer: "+err.Error(), "ERROR")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updatedData["labels"] = labels
 	}
 
-	err = h.service.UpdateServer(serverID, updatedData)
+	err = h.service.UpdateServer(serverID, updatedData, version, actorFromRequest(r))
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to update server: "+err.Error(), "ERROR")
+		if errors.Is(err, service.ErrVersionConflict) {
+			http.Error(w, "Server "+serverID+" changed since it was read", http.StatusPreconditionFailed)
+			return
+		}
+		if errors.Is(err, service.ErrServerNotFound) {
+			http.Error(w, "Server "+serverID+" not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidLabel) || errors.Is(err, domain.ErrInvalidAddress) || errors.Is(err, domain.ErrInvalidServer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, service.ErrServerConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Failed to update server", http.StatusInternalServerError)
 		return
 	}
@@ -166,8 +366,19 @@ func (h *serverRestHandler) DeleteServer(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.service.DeleteServer(serverID)
+	version, ok := versionFromIfMatch(r)
+	if !ok {
+		http.Error(w, "If-Match is not an ETag of the server", http.StatusPreconditionFailed)
+		return
+	}
+
+	err := h.service.DeleteServer(serverID, version, actorFromRequest(r))
 	if err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			logging.LogMessage("server_administration_service", "Server "+serverID+" not deleted, it changed since it was read", "ERROR")
+			http.Error(w, "Server "+serverID+" changed since it was read", http.StatusPreconditionFailed)
+			return
+		}
 		logging.LogMessage("server_administration_service", "Invalid server ID: "+serverID+" - "+err.Error(), "ERROR")
 		http.Error(w, "Invalid server ID", http.StatusNotFound)
 		return
@@ -178,52 +389,281 @@ func (h *serverRestHandler) DeleteServer(w http.ResponseWriter, r *http.Request)
 	w.Write([]byte("Server deleted successfully"))
 }
 
-func (h *serverRestHandler) ImportServers(w http.ResponseWriter, r *http.Request) {
-	serversFile, _, err := r.FormFile("servers_file")
+// serverETag is the ETag of a version of a server.
+func serverETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// versionFromIfMatch reads the version of the server an If-Match header asks for, 0 when there's none or
+// it's *. It reports false when the header is no ETag of a server, which can never match.
+func versionFromIfMatch(r *http.Request) (int64, bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, true
+	}
+	return versionFromETag(ifMatch)
+}
+
+// notModified reports whether the If-None-Match header, * or a list of ETags, matches the version of
+// the server. The comparison is weak, as for any If-None-Match, and ETags of no server never match.
+func notModified(r *http.Request, version int64) bool {
+	ifNoneMatch := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if ifNoneMatch == "*" {
+		return true
+	}
+	for _, etag := range strings.Split(ifNoneMatch, ",") {
+		etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+		if tagged, ok := versionFromETag(etag); ok && tagged == version {
+			return true
+		}
+	}
+	return false
+}
+
+// versionFromETag reads the version of a server ETag, reporting false when etag is none.
+func versionFromETag(etag string) (int64, bool) {
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// bulkRequestBody is the JSON body of a bulk update or delete: the servers by ID or by filter, the changes
+// of an update, and whether to only preview them.
+type bulkRequestBody struct {
+	ServerIDs []string               `json:"server_ids"`
+	Filter    *bulkFilterBody        `json:"filter"`
+	Changes   map[string]interface{} `json:"changes"`
+	Preview   bool                   `json:"preview"`
+}
+
+// bulkFilterBody is the server filter of /view as a JSON object.
+type bulkFilterBody struct {
+	ServerID      string `json:"server_id"`
+	ServerName    string `json:"server_name"`
+	Status        string `json:"status"`
+	Address       string `json:"address"`
+	LabelSelector string `json:"label_selector"`
+	Filter        string `json:"filter"`
+}
+
+func (h *serverRestHandler) BulkUpdateServers(w http.ResponseWriter, r *http.Request) {
+	body, request, ok := readBulkRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updatedData := make(map[string]interface{}, len(body.Changes))
+	for field, value := range body.Changes {
+		switch field {
+		case "sla_target":
+			slaTarget, ok := value.(float64)
+			if !ok {
+				http.Error(w, "sla_target must be a number", http.StatusBadRequest)
+				return
+			}
+			updatedData[field] = slaTarget
+		case "notes":
+			notes, ok := value.(string)
+			if !ok {
+				http.Error(w, "notes must be a string", http.StatusBadRequest)
+				return
+			}
+			updatedData[field] = notes
+		case "labels":
+			labels, err := labelsFromBody(value)
+			if err != nil {
+				logging.LogMessage("server_administration_service", "Invalid labels for request BulkUpdateServers: "+err.Error(), "ERROR")
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			updatedData[field] = labels
+		default:
+			// Rejected by the service with the reason
+			updatedData[field] = value
+		}
+	}
 
+	result, err := h.service.BulkUpdateServers(request, updatedData, actorFromRequest(r))
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to get file from request: "+err.Error(), "ERROR")
-		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+		writeBulkError(w, "update", err)
 		return
 	}
-	defer serversFile.Close()
 
-	var buf []byte
-	buf, err = io.ReadAll(serversFile)
+	if !result.Preview {
+		logging.LogMessage("server_administration_service", strconv.Itoa(result.Affected)+" servers updated in bulk", "INFO")
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *serverRestHandler) BulkDeleteServers(w http.ResponseWriter, r *http.Request) {
+	_, request, ok := readBulkRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.BulkDeleteServers(request, actorFromRequest(r))
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to read file: "+err.Error(), "ERROR")
-		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		writeBulkError(w, "delete", err)
+		return
+	}
+
+	if !result.Preview {
+		logging.LogMessage("server_administration_service", strconv.Itoa(result.Affected)+" servers deleted in bulk", "INFO")
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// readBulkRequest reads the body of a bulk update or delete, answering 400 when it's invalid.
+func readBulkRequest(w http.ResponseWriter, r *http.Request) (bulkRequestBody, dto.BulkRequest, bool) {
+	var body bulkRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for a bulk change: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return body, dto.BulkRequest{}, false
+	}
+
+	request := dto.BulkRequest{ServerIDs: body.ServerIDs, Preview: body.Preview}
+	if body.Filter != nil {
+		labelSelector, err := domain.ParseLabelSelector(body.Filter.LabelSelector)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return body, dto.BulkRequest{}, false
+		}
+		expression, err := domain.ParseFilterExpression(body.Filter.Filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return body, dto.BulkRequest{}, false
+		}
+		request.Filter = &dto.ServerFilter{
+			ServerID:      body.Filter.ServerID,
+			ServerName:    body.Filter.ServerName,
+			Status:        body.Filter.Status,
+			Address:       body.Filter.Address,
+			LabelSelector: labelSelector,
+			Expression:    expression,
+		}
+	}
+	return body, request, true
+}
+
+func writeBulkError(w http.ResponseWriter, change string, err error) {
+	logging.LogMessage("server_administration_service", "Failed to "+change+" servers in bulk: "+err.Error(), "ERROR")
+	if errors.Is(err, service.ErrInvalidBulk) || errors.Is(err, domain.ErrInvalidFilterExpression) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	http.Error(w, "Failed to "+change+" servers", http.StatusInternalServerError)
+}
 
-	importedServer, nonImportedServer, err := h.service.ImportServers(buf)
+// ImportServers reads the servers_file form file. The optional format field forces its format, otherwise it's
+// detected, and the optional mapping field is a JSON object mapping server fields to the file's column names.
+// With dry_run=true, as a form field or query parameter, the file is only validated.
+// The mode field picks insert, upsert or sync; a sync also takes removal and the confirmation_token of its dry run.
+// The optional progress_id field lets the client poll /import/progress/{id} while a large file is imported.
+func (h *serverRestHandler) ImportServers(w http.ResponseWriter, r *http.Request) {
+	buf, options, ok := readImportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ImportServers(buf, options)
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to import servers: "+err.Error(), "ERROR")
+		if errors.Is(err, service.ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, service.ErrImportNotConfirmed) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Failed to import servers", http.StatusInternalServerError)
 		return
 	}
 
 	logging.LogMessage("server_administration_service", "Servers imported successfully", "INFO")
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, http.StatusOK, report)
+}
 
-	response := map[string]interface{}{
-		"imported_servers":    importedServer,
-		"non_imported_servers": nonImportedServer,
+func (h *serverRestHandler) ImportProgress(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.service.GetImportProgress(mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, service.ErrImportProgressNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to get import progress: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get import progress", http.StatusInternalServerError)
+		return
 	}
-	
-	responseJSON, _ := json.Marshal(response)
-	w.Write(responseJSON)
+
+	writeJSON(w, http.StatusOK, progress)
 }
 
-func (h *serverRestHandler) ExportServers(w http.ResponseWriter, r *http.Request) {
+// readImportRequest reads the file and options of an import, answering 400 when they're invalid.
+func readImportRequest(w http.ResponseWriter, r *http.Request) ([]byte, dto.ImportOptions, bool) {
+	var options dto.ImportOptions
+	serversFile, fileHeader, err := r.FormFile("servers_file")
+
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get file from request: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get file from request", http.StatusBadRequest)
+		return nil, options, false
+	}
+	defer serversFile.Close()
+
+	var buf []byte
+	buf, err = io.ReadAll(serversFile)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to read file: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		return nil, options, false
+	}
+
+	options = dto.ImportOptions{
+		Format:            r.FormValue("format"),
+		Filename:          fileHeader.Filename,
+		Mode:              r.FormValue("mode"),
+		Removal:           r.FormValue("removal"),
+		ConfirmationToken: r.FormValue("confirmation_token"),
+		ProgressID:        r.FormValue("progress_id"),
+		Actor:             actorFromRequest(r),
+	}
+	if len(options.ProgressID) > 128 {
+		http.Error(w, "Invalid 'progress_id' field, expected at most 128 characters", http.StatusBadRequest)
+		return nil, options, false
+	}
+	if dryRun := r.FormValue("dry_run"); dryRun != "" {
+		if options.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			http.Error(w, "Invalid 'dry_run' field, expected true or false", http.StatusBadRequest)
+			return nil, options, false
+		}
+	}
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &options.Mapping); err != nil {
+			logging.LogMessage("server_administration_service", "Invalid import mapping: "+err.Error(), "ERROR")
+			http.Error(w, "Invalid 'mapping' field, expected a JSON object of field to column names", http.StatusBadRequest)
+			return nil, options, false
+		}
+	}
+
+	return buf, options, true
+}
+
+// readExportRequest reads the filter, range and order of an export, answering 400 when they're invalid.
+func readExportRequest(w http.ResponseWriter, r *http.Request) (dto.ExportOptions, bool) {
 	fromStr := r.URL.Query().Get("from")
 	from, err := strconv.Atoi(fromStr)
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Invalid 'from' query parameter: "+err.Error(), "ERROR")
 		logging.LogMessage("server_administration_service", "from: "+fromStr, "DEBUG")
 		http.Error(w, "Invalid 'from' query parameter", http.StatusBadRequest)
-		return
+		return dto.ExportOptions{}, false
 	}
 
 	toStr := r.URL.Query().Get("to")
@@ -232,45 +672,164 @@ func (h *serverRestHandler) ExportServers(w http.ResponseWriter, r *http.Request
 		logging.LogMessage("server_administration_service", "Invalid 'to' query parameter: "+err.Error(), "ERROR")
 		logging.LogMessage("server_administration_service", "to: "+toStr, "DEBUG")
 		http.Error(w, "Invalid 'to' query parameter", http.StatusBadRequest)
-		return
+		return dto.ExportOptions{}, false
 	}
 
 	sortedColumn := r.URL.Query().Get("sort_column")
 	order := r.URL.Query().Get("sort_order")
 
-	serverID := r.URL.Query().Get("server_id")
-	serverName := r.URL.Query().Get("server_name")
-	status := r.URL.Query().Get("status")
-	ipv4 := r.URL.Query().Get("ipv4")
+	serverFilter, ok := readServerFilter(w, r)
+	if !ok {
+		return dto.ExportOptions{}, false
+	}
 
-	serverFilter := dto.ServerFilter{}
+	options := dto.ExportOptions{
+		Filter:           serverFilter,
+		LabelSelector:    r.URL.Query().Get("label_selector"),
+		FilterExpression: r.URL.Query().Get("filter"),
+		From:             from,
+		To:               to,
+		SortColumn:       sortedColumn,
+		Order:            order,
+		Format:           negotiateExportFormat(r),
+	}
+	if _, ok := exportContentTypes[options.Format]; !ok {
+		http.Error(w, "Invalid 'format' query parameter, expected xlsx, csv, json, ndjson or pdf", http.StatusBadRequest)
+		return dto.ExportOptions{}, false
+	}
 
-	if serverID != "" {
-		serverFilter.ServerID = serverID
+	for name, at := range map[string]**time.Time{"uptime_from": &options.UpTimeFrom, "uptime_to": &options.UpTimeTo} {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Invalid '"+name+"' query parameter, expected an RFC 3339 time", http.StatusBadRequest)
+			return dto.ExportOptions{}, false
+		}
+		*at = &parsed
 	}
-	if serverName != "" {
-		serverFilter.ServerName = serverName
+
+	if report := r.URL.Query().Get("report"); report != "" {
+		if options.Report, err = strconv.ParseBool(report); err != nil {
+			http.Error(w, "Invalid 'report' query parameter, expected true or false", http.StatusBadRequest)
+			return dto.ExportOptions{}, false
+		}
 	}
-	if status != "" {
-		serverFilter.Status = status
+
+	return options, true
+}
+
+// exportContentTypes are the media types of the export formats.
+var exportContentTypes = map[string]string{
+	service.ExportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	service.ExportFormatCSV:    "text/csv",
+	service.ExportFormatJSON:   "application/json",
+	service.ExportFormatNDJSON: "application/x-ndjson",
+	service.ExportFormatPDF:    "application/pdf",
+}
+
+// negotiateExportFormat takes the format query parameter, else the first media type of the Accept header
+// that is an export format, else xlsx.
+func negotiateExportFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format)
 	}
-	if ipv4 != "" {
-		serverFilter.IPv4 = ipv4
+
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.Split(accepted, ";")[0])
+		for format, contentType := range exportContentTypes {
+			if strings.EqualFold(mediaType, contentType) {
+				return format
+			}
+		}
 	}
+	return service.ExportFormatXLSX
+}
 
-	serverBuf, err := h.service.ExportServers(&serverFilter, from, to, sortedColumn, order)
-	if err != nil {
+// ExportServers streams the servers in the negotiated format. Failures before the first byte is written
+// are answered with an error status; later ones can only cut the file short.
+func (h *serverRestHandler) ExportServers(w http.ResponseWriter, r *http.Request) {
+	options, ok := readExportRequest(w, r)
+	if !ok {
+		return
+	}
+
+	setExportHeaders(w, "servers_"+time.Now().Format("2006-01-02_15-04-05")+"."+options.Format)
+	export := &exportResponseWriter{ResponseWriter: w}
+	if err := h.service.ExportServers(export, options); err != nil {
 		logging.LogMessage("server_administration_service", "Failed to export servers: "+err.Error(), "ERROR")
+		if export.started {
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		w.Header().Del("File-Name")
+		if errors.Is(err, service.ErrInvalidExport) || errors.Is(err, domain.ErrInvalidLabelSelector) || errors.Is(err, domain.ErrInvalidFilterExpression) || errors.Is(err, domain.ErrInvalidSort) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "Failed to export servers", http.StatusInternalServerError)
-		return
 	}
+}
 
-	filename := "servers_" + time.Now().Format("2006-01-02_15-04-05") + ".xlsx"
+// exportResponseWriter tells whether the export has started writing its file.
+type exportResponseWriter struct {
+	http.ResponseWriter
+	started bool
+}
 
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+func (e *exportResponseWriter) Write(b []byte) (int, error) {
+	e.started = true
+	return e.ResponseWriter.Write(b)
+}
+
+// setExportHeaders sets the headers of an exported file, its content type taken from its extension.
+func setExportHeaders(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", exportContentTypes[strings.TrimPrefix(filepath.Ext(filename), ".")])
 	w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
 	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
 	w.Header().Set("File-Name", filename)
-	w.WriteHeader(http.StatusOK)
-	w.Write(serverBuf)
-}
\ No newline at end of file
+}
+
+// labelsFromBody reads the "labels" object of a request body; a missing object means no labels.
+func labelsFromBody(raw interface{}) (domain.Labels, error) {
+	labels := domain.Labels{}
+	if raw == nil {
+		return labels, nil
+	}
+
+	object, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("labels must be an object of string values")
+	}
+	for key, value := range object {
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("label " + key + " must have a string value")
+		}
+		labels[key] = str
+	}
+
+	return labels, labels.Validate()
+}
+
+// addressesFromBody reads the "addresses" array of a request body, or the single "ipv4" address older clients send.
+// It reports false when the body has neither.
+func addressesFromBody(body map[string]interface{}) (domain.Addresses, bool, error) {
+	if raw, existed := body["addresses"]; existed {
+		// Round-trip through JSON to reuse the field names of domain.Address
+		data, _ := json.Marshal(raw)
+		var addresses domain.Addresses
+		if err := json.Unmarshal(data, &addresses); err != nil {
+			return nil, true, errors.New("addresses must be an array of {type, address, primary} objects")
+		}
+		return addresses, true, nil
+	}
+
+	if ipv4, existed := body["ipv4"].(string); existed {
+		return domain.Addresses{{Type: domain.AddressTypeIPv4, Address: ipv4, Primary: true}}, true, nil
+	}
+
+	return nil, false, nil
+}
@@ -4,57 +4,122 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
 	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/mock"
 )
 
 // Mock implementation of ServerCRUDService
 type mockServerCRUDService struct {
 	mock.Mock
+	// exportOptions are the options of the last export
+	exportOptions dto.ExportOptions
+	// pageRequest is the last page of servers asked for
+	pageRequest dto.ServerPageRequest
+	// viewFilter and viewSort are the filter and sort of the last listing
+	viewFilter dto.ServerFilter
+	viewSort   domain.ServerSort
+	// actor is who made the last change
+	actor dto.Actor
+	// version is the version the last change was made against
+	version int64
 }
 
-func (m *mockServerCRUDService) CreateServer(serverID, serverName, ipv4 string) (string, error) {
+func (m *mockServerCRUDService) CreateServer(serverID, serverName string, addresses domain.Addresses, slaTarget float64, labels domain.Labels, notes string, actor dto.Actor) (string, error) {
+	m.actor = actor
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
-func (m *mockServerCRUDService) ViewServers(filter *dto.ServerFilter, from, to int, sortColumn, order string) ([]domain.Server, error) {
+func (m *mockServerCRUDService) ViewServers(filter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error) {
+	m.viewFilter = *filter
+	m.viewSort = sort
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Server), args.Error(1)
 }
-func (m *mockServerCRUDService) UpdateServer(serverID string, updatedData map[string]interface{}) error {
+func (m *mockServerCRUDService) ViewServerPage(filter *dto.ServerFilter, request dto.ServerPageRequest) (*dto.ServerPage, error) {
+	m.pageRequest = request
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ServerPage), args.Error(1)
+}
+func (m *mockServerCRUDService) GetServer(serverID string) (*domain.Server, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Server), args.Error(1)
+}
+func (m *mockServerCRUDService) UpdateServer(serverID string, updatedData map[string]interface{}, version int64, actor dto.Actor) error {
+	m.actor = actor
+	m.version = version
 	args := m.Called()
 	return args.Error(0)
 }
-func (m *mockServerCRUDService) DeleteServer(serverID string) error {
+func (m *mockServerCRUDService) DeleteServer(serverID string, version int64, actor dto.Actor) error {
+	m.actor = actor
+	m.version = version
 	args := m.Called()
 	return args.Error(0)
 }
-func (m *mockServerCRUDService) ImportServers(buf []byte) ([]domain.Server, []domain.Server, error) {
+func (m *mockServerCRUDService) ImportServers(buf []byte, options dto.ImportOptions) (*dto.ImportReport, error) {
 	args := m.Called()
-	if (args.Get(0) == nil || args.Get(1) == nil) {
-		return nil, nil, args.Error(2)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
-	return args.Get(0).([]domain.Server), args.Get(1).([]domain.Server), args.Error(2)
+	return args.Get(0).(*dto.ImportReport), args.Error(1)
 }
-func (m *mockServerCRUDService) ExportServers(filter *dto.ServerFilter, from, to int, sortColumn, order string) ([]byte, error) {
+func (m *mockServerCRUDService) ExportServers(w io.Writer, options dto.ExportOptions) error {
+	m.exportOptions = options
 	args := m.Called()
+	if data, ok := args.Get(0).([]byte); ok {
+		w.Write(data)
+	}
+	return args.Error(1)
+}
+
+func (m *mockServerCRUDService) GetImportProgress(progressID string) (*dto.ImportProgress, error) {
+	args := m.Called(progressID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ImportProgress), args.Error(1)
+}
+
+func (m *mockServerCRUDService) BulkUpdateServers(request dto.BulkRequest, updatedData map[string]interface{}, actor dto.Actor) (*dto.BulkResult, error) {
+	m.actor = actor
+	args := m.Called(request, updatedData)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]byte), args.Error(1)
+	return args.Get(0).(*dto.BulkResult), args.Error(1)
+}
+
+func (m *mockServerCRUDService) BulkDeleteServers(request dto.BulkRequest, actor dto.Actor) (*dto.BulkResult, error) {
+	m.actor = actor
+	args := m.Called(request)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BulkResult), args.Error(1)
 }
 
 func TestCreateServer_Success(t *testing.T) {
@@ -102,6 +167,72 @@ func TestCreateServer_InvalidBody(t *testing.T) {
 	}
 }
 
+func TestCreateServer_InvalidLabels(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"server_id":"srv-1","server_name":"Server1","ipv4":"192.168.1.1","labels":{"env":1}}`
+	req := httptest.NewRequest(http.MethodPost, "/servers", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateServer(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "CreateServer")
+}
+
+func TestCreateServer_Addresses(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"server_id":"srv-1","server_name":"Server1","addresses":[{"address":"10.0.0.1"},{"type":"ipv6","address":"2001:db8::1","primary":true}]}`
+	req := httptest.NewRequest(http.MethodPost, "/servers", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreateServer").Return("srv-1", nil)
+
+	handler.CreateServer(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestCreateServer_InvalidAddresses(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"server_id":"srv-1","server_name":"Server1","addresses":"10.0.0.1"}`
+	req := httptest.NewRequest(http.MethodPost, "/servers", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateServer(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "CreateServer")
+}
+
+func TestCreateServer_AddressValidationError(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"server_id":"srv-1","server_name":"Server1","ipv4":"10.0.0.300"}`
+	req := httptest.NewRequest(http.MethodPost, "/servers", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreateServer").Return("", domain.ErrInvalidAddress)
+
+	handler.CreateServer(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestCreateServer_ServiceError(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -131,8 +262,8 @@ func TestViewServers_Success(t *testing.T) {
 	handler := handler.NewServerRestHandler(mockService)
 
 	servers := []domain.Server{
-		{ServerID: "srv-1", ServerName: "Server1", IPv4: "192.168.1.1"},
-		{ServerID: "srv-2", ServerName: "Server2", IPv4: "192.168.1.2"},
+		{ServerID: "srv-1", ServerName: "Server1", PrimaryAddress: "192.168.1.1"},
+		{ServerID: "srv-2", ServerName: "Server2", PrimaryAddress: "192.168.1.2"},
 	}
 	mockService.On("ViewServers").Return(servers, nil)
 
@@ -156,6 +287,55 @@ func TestViewServers_Success(t *testing.T) {
 	}
 }
 
+func TestViewServers_Page(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ViewServerPage").Return(&dto.ServerPage{
+		Items:      []domain.Server{{ServerID: "srv-1"}},
+		NextCursor: "next",
+		Total:      7,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers?limit=1&cursor=abc&sort_column=server_name&status=Up", nil)
+	w := httptest.NewRecorder()
+
+	handler.ViewServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var page dto.ServerPage
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if page.Total != 7 || page.NextCursor != "next" || len(page.Items) != 1 {
+		t.Errorf("unexpected page %+v", page)
+	}
+	expected := dto.ServerPageRequest{Sort: domain.ServerSort{{Column: "server_name"}, {Column: "server_id"}}, Limit: 1, Cursor: "abc"}
+	if !reflect.DeepEqual(mockService.pageRequest, expected) {
+		t.Errorf("expected request %+v, got %+v", expected, mockService.pageRequest)
+	}
+}
+
+func TestViewServers_InvalidPage(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ViewServerPage").Return(nil, fmt.Errorf("%w: malformed cursor", service.ErrInvalidPage))
+
+	for _, query := range []string{"limit=ten", "limit=0", "cursor=abc", "sort_column=password", "sort_order=sideways"} {
+		req := httptest.NewRequest(http.MethodGet, "/servers?"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.ViewServers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
 func TestViewServers_InvalidFrom(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -188,6 +368,140 @@ func TestViewServers_InvalidTo(t *testing.T) {
 	}
 }
 
+func TestViewServers_InvalidLabelSelector(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers?from=0&to=10&label_selector="+url.QueryEscape("role in (db"), nil)
+	w := httptest.NewRecorder()
+
+	handler.ViewServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "ViewServers")
+}
+
+func TestViewServers_InvalidFilterExpression(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	for _, filter := range []string{
+		`password = x`,
+		`status < Up`,
+		`sla_target = high`,
+		`created_time > yesterday`,
+		`address = 10.0.0.0/33`,
+		`server_name ~ "("`,
+		`(status = Up`,
+		`status = Up AND`,
+		`status = Up labels.env`,
+		`labels.-bad = x`,
+		`server_name = "unterminated`,
+		strings.Repeat("NOT ", 40) + "status = Up",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/servers?filter="+url.QueryEscape(filter), nil)
+		w := httptest.NewRecorder()
+
+		handler.ViewServers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", filter, http.StatusBadRequest, w.Code)
+		}
+	}
+	mockService.AssertNotCalled(t, "ViewServerPage")
+}
+
+func TestViewServers_RegularExpressionRejectedByDatabase(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	// Go's regexp takes \pL, Postgres doesn't
+	mockService.On("ViewServers").Return(nil, fmt.Errorf("%w: invalid regular expression: invalid escape \\ sequence", domain.ErrInvalidFilterExpression))
+
+	req := httptest.NewRequest(http.MethodGet, "/servers?from=0&to=10&filter="+url.QueryEscape(`server_name ~ "\\pL"`), nil)
+	w := httptest.NewRecorder()
+
+	handler.ViewServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestViewServers_FilterExpression(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ViewServers").Return([]domain.Server{}, nil)
+
+	filter := `(status = Up OR status = Degraded) AND labels.team AND last_updated >= 2024-01-01T00:00:00Z`
+	req := httptest.NewRequest(http.MethodGet, "/servers?from=0&to=10&filter="+url.QueryEscape(filter), nil)
+	w := httptest.NewRecorder()
+
+	handler.ViewServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	expression := mockService.viewFilter.Expression
+	if expression == nil || expression.Op != domain.FilterAnd || len(expression.Operands) != 3 || expression.Operands[0].Op != domain.FilterOr {
+		t.Errorf("unexpected expression %+v", expression)
+	}
+}
+
+func TestViewServers_Sort(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ViewServers").Return([]domain.Server{}, nil)
+
+	for query, expected := range map[string]domain.ServerSort{
+		"": {{Column: "server_id"}},
+		"&sort_column=server_name&sort_order=DESC":             {{Column: "server_name", Desc: true}, {Column: "server_id"}},
+		"&sort_column=status,last_updated&sort_order=asc,desc": {{Column: "status"}, {Column: "last_updated", Desc: true}, {Column: "server_id"}},
+		"&sort_column=server_id,status&sort_order=desc":        {{Column: "server_id", Desc: true}, {Column: "status", Desc: true}},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/servers?from=0&to=10"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.ViewServers(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusOK, w.Code)
+		}
+		if !reflect.DeepEqual(mockService.viewSort, expected) {
+			t.Errorf("%s: expected sort %+v, got %+v", query, expected, mockService.viewSort)
+		}
+	}
+}
+
+func TestViewServers_InvalidSort(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	for _, query := range []string{
+		"sort_column=password",
+		"sort_column=labels",
+		"sort_column=" + url.QueryEscape("server_id; DROP TABLE servers"),
+		"sort_column=status,status",
+		"sort_column=status,",
+		"sort_column=status&sort_order=up",
+		"sort_column=status,server_name&sort_order=asc,desc,asc",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/servers?from=0&to=10&"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.ViewServers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+	mockService.AssertNotCalled(t, "ViewServers")
+}
+
 func TestViewServers_ServiceError(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -206,6 +520,86 @@ func TestViewServers_ServiceError(t *testing.T) {
 	}
 }
 
+func TestGetServer_ETag(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("GetServer", "srv-1").Return(&domain.Server{ServerID: "srv-1", Version: 7}, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/servers/srv-1", nil), map[string]string{"server_id": "srv-1"})
+	w := httptest.NewRecorder()
+	handler.GetServer(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"7"` {
+		t.Errorf(`expected ETag "7", got %s`, etag)
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/servers/srv-1", nil), map[string]string{"server_id": "srv-1"})
+	req.Header.Set("If-None-Match", `"7"`)
+	w = httptest.NewRecorder()
+	handler.GetServer(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("expected status 304, got %d", w.Code)
+	}
+}
+
+func TestGetServer_NotFound(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("GetServer", "srv-9").Return(nil, service.ErrServerNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/servers/srv-9", nil), map[string]string{"server_id": "srv-9"})
+	w := httptest.NewRecorder()
+	handler.GetServer(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestUpdateServer_IfMatch(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPut, "/servers/update?server_id=srv-1", strings.NewReader(`{"notes":"Rack 5"}`))
+	req.Header.Set("If-Match", `"7"`)
+	w := httptest.NewRecorder()
+
+	mockService.On("UpdateServer").Return(service.ErrVersionConflict)
+
+	handler.UpdateServer(w, req)
+
+	if mockService.version != 7 {
+		t.Errorf("expected version 7, got %d", mockService.version)
+	}
+	if w.Code != http.StatusPreconditionFailed {
+		t.Errorf("expected status 412, got %d", w.Code)
+	}
+}
+
+func TestDeleteServer_InvalidIfMatch(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	for _, ifMatch := range []string{`W/"7"`, `7`, `"seven"`} {
+		req := httptest.NewRequest(http.MethodDelete, "/servers/delete?server_id=srv-1", nil)
+		req.Header.Set("If-Match", ifMatch)
+		w := httptest.NewRecorder()
+
+		handler.DeleteServer(w, req)
+
+		if w.Code != http.StatusPreconditionFailed {
+			t.Errorf("If-Match %s: expected status 412, got %d", ifMatch, w.Code)
+		}
+	}
+	mockService.AssertNotCalled(t, "DeleteServer")
+}
+
 func TestUpdateServer_Success(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -317,6 +711,25 @@ func TestDeleteServer_Success(t *testing.T) {
 	}
 }
 
+func TestDeleteServer_PassesActor(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/servers/delete?server_id=srv-1", nil)
+	req.RemoteAddr = "192.0.2.10:51234"
+	req.Header.Set("userID", "user-1")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w := httptest.NewRecorder()
+
+	mockService.On("DeleteServer").Return(nil)
+
+	handler.DeleteServer(w, req)
+
+	if want := (dto.Actor{UserID: "user-1", SourceIP: "192.0.2.10"}); mockService.actor != want {
+		t.Errorf("expected actor %+v, got %+v", want, mockService.actor)
+	}
+}
+
 func TestDeleteServer_MissingServerID(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -357,7 +770,7 @@ func TestImportServers_Success(t *testing.T) {
 
 	imported := []domain.Server{{ServerID: "srv-1"}}
 	nonImported := []domain.Server{{ServerID: "srv-2"}}
-	mockService.On("ImportServers").Return(imported, nonImported, nil)
+	mockService.On("ImportServers").Return(&dto.ImportReport{ImportedServers: imported, NonImportedServers: nonImported}, nil)
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -419,7 +832,7 @@ func TestImportServers_ReadError(t *testing.T) {
 	writer.Close()
 
 	// Mock ImportServers to avoid "unexpected call" error
-	mockService.On("ImportServers").Return(nil, nil, errors.New("read error"))
+	mockService.On("ImportServers").Return(nil, errors.New("read error"))
 
 	req := httptest.NewRequest(http.MethodPost, "/servers/import", body)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
@@ -439,7 +852,7 @@ func TestImportServers_ServiceError(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
 
-	mockService.On("ImportServers").Return(nil, nil, errors.New("import error"))
+	mockService.On("ImportServers").Return(nil, errors.New("import error"))
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -460,6 +873,137 @@ func TestImportServers_ServiceError(t *testing.T) {
 	}
 }
 
+func TestImportServers_InvalidMapping(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("servers_file", "servers.csv")
+	part.Write([]byte("hostname,ip\nsrv-1,10.0.0.1\n"))
+	writer.WriteField("mapping", "server_id=hostname")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/servers/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ImportServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "ImportServers")
+}
+
+func TestImportServers_InvalidDryRun(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("servers_file", "servers.csv")
+	part.Write([]byte("Server ID,Server Name,Addresses\nsrv-1,Server 1,10.0.0.1\n"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/servers/import?dry_run=maybe", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ImportServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	mockService.AssertNotCalled(t, "ImportServers")
+}
+
+func TestImportServers_SyncNotConfirmed(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ImportServers").Return(nil, service.ErrImportNotConfirmed)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("servers_file", "servers.csv")
+	part.Write([]byte("Server ID,Server Name,Addresses\nsrv-1,Server 1,10.0.0.1\n"))
+	writer.WriteField("mode", "sync")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/servers/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ImportServers(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestImportServers_InvalidFile(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ImportServers").Return(nil, service.ErrInvalidImport)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("servers_file", "servers.json")
+	part.Write([]byte("{}"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/servers/import", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	handler.ImportServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestImportProgress_Success(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("GetImportProgress", "upload-1").Return(&dto.ImportProgress{ID: "upload-1", Phase: service.ImportPhaseWriting, Processed: 1000, Total: 25000}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/import/progress/upload-1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "upload-1"})
+	w := httptest.NewRecorder()
+
+	handler.ImportProgress(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var progress dto.ImportProgress
+	json.Unmarshal(w.Body.Bytes(), &progress)
+	if progress.Processed != 1000 || progress.Total != 25000 {
+		t.Errorf("expected 1000 of 25000 processed, got %d of %d", progress.Processed, progress.Total)
+	}
+}
+
+func TestImportProgress_NotFound(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("GetImportProgress", "unknown").Return(nil, service.ErrImportProgressNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/import/progress/unknown", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "unknown"})
+	w := httptest.NewRecorder()
+
+	handler.ImportProgress(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
 func TestExportServers_Success(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -485,6 +1029,105 @@ func TestExportServers_Success(t *testing.T) {
 	}
 }
 
+func TestExportServers_AcceptHeader(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ExportServers").Return([]byte("Server ID\n"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?from=0&to=10&uptime_from=2024-01-01T00:00:00Z&uptime_to=2024-02-01T00:00:00Z", nil)
+	req.Header.Set("Accept", "application/pdf;q=0.5, text/csv")
+	w := httptest.NewRecorder()
+
+	handler.ExportServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	// The first export format listed wins
+	if mockService.exportOptions.Format != "pdf" {
+		t.Errorf("expected pdf format, got %s", mockService.exportOptions.Format)
+	}
+	if mockService.exportOptions.UpTimeFrom == nil || mockService.exportOptions.UpTimeTo == nil {
+		t.Errorf("expected the uptime range to be passed")
+	}
+}
+
+func TestExportServers_FormatParameter(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ExportServers").Return([]byte("{}\n"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?from=0&to=10&format=ndjson", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+
+	handler.ExportServers(w, req)
+
+	if w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("expected ndjson content type, got %s", w.Header().Get("Content-Type"))
+	}
+	if !strings.HasSuffix(w.Header().Get("File-Name"), ".ndjson") {
+		t.Errorf("expected an ndjson file name, got %s", w.Header().Get("File-Name"))
+	}
+}
+
+func TestExportServers_Report(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ExportServers").Return([]byte("exceldata"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?from=0&to=10&report=true", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !mockService.exportOptions.Report {
+		t.Error("expected a report")
+	}
+}
+
+func TestExportServers_InvalidFormat(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	for _, query := range []string{"format=docx", "uptime_from=yesterday", "report=maybe"} {
+		req := httptest.NewRequest(http.MethodGet, "/servers/export?from=0&to=10&"+query, nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportServers(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+	mockService.AssertNotCalled(t, "ExportServers")
+}
+
+func TestExportServers_InvalidExport(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	mockService.On("ExportServers").Return(nil, service.ErrInvalidExport)
+
+	req := httptest.NewRequest(http.MethodGet, "/servers/export?from=0&to=10&uptime_from=2024-01-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w.Header().Get("Content-Disposition") != "" {
+		t.Errorf("expected no attachment on errors")
+	}
+}
+
 func TestExportServers_InvalidFrom(t *testing.T) {
 	mockService := new(mockServerCRUDService)
 	handler := handler.NewServerRestHandler(mockService)
@@ -533,4 +1176,91 @@ func TestExportServers_ServiceError(t *testing.T) {
 	if resp.StatusCode != http.StatusInternalServerError {
 		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, resp.StatusCode)
 	}
-}
\ No newline at end of file
+}
+func TestBulkUpdateServers_ByFilter(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"filter":{"status":"On","label_selector":"env=prod"},"changes":{"sla_target":99.95,"labels":{"tier":"web"}},"preview":true}`
+	req := httptest.NewRequest(http.MethodPost, "/bulk/update", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	wantRequest := dto.BulkRequest{
+		Filter: &dto.ServerFilter{
+			Status:        "On",
+			LabelSelector: domain.LabelSelector{{Key: "env", Operator: domain.SelectorEquals, Values: []string{"prod"}}},
+		},
+		Preview: true,
+	}
+	wantData := map[string]interface{}{"sla_target": 99.95, "labels": domain.Labels{"tier": "web"}}
+	result := &dto.BulkResult{Preview: true, Matched: 1, Affected: 1, Outcomes: []dto.BulkOutcome{{ServerID: "srv-1", Outcome: service.BulkOutcomeUpdated}}}
+	mockService.On("BulkUpdateServers", wantRequest, wantData).Return(result, nil)
+
+	handler.BulkUpdateServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var got dto.BulkResult
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(&got, result) {
+		t.Errorf("expected result %+v, got %+v", result, got)
+	}
+}
+
+func TestBulkUpdateServers_InvalidChange(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"server_ids":["srv-1"],"changes":{"server_name":"web"}}`
+	req := httptest.NewRequest(http.MethodPost, "/bulk/update", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	mockService.On("BulkUpdateServers", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: server_name can't be changed on several servers at once", service.ErrInvalidBulk))
+
+	handler.BulkUpdateServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestBulkUpdateServers_InvalidFilter(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	body := `{"filter":{"filter":"status =="},"changes":{"notes":"Rack 5"}}`
+	req := httptest.NewRequest(http.MethodPost, "/bulk/update", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.BulkUpdateServers(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	mockService.AssertNotCalled(t, "BulkUpdateServers", mock.Anything, mock.Anything)
+}
+
+func TestBulkDeleteServers_ByIDs(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	handler := handler.NewServerRestHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/bulk/delete", strings.NewReader(`{"server_ids":["srv-1","srv-2"]}`))
+	req.Header.Set("userID", "user-1")
+	w := httptest.NewRecorder()
+
+	mockService.On("BulkDeleteServers", dto.BulkRequest{ServerIDs: []string{"srv-1", "srv-2"}}).
+		Return(&dto.BulkResult{Matched: 2, Affected: 2}, nil)
+
+	handler.BulkDeleteServers(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+	if mockService.actor.UserID != "user-1" {
+		t.Errorf("expected actor user-1, got %+v", mockService.actor)
+	}
+}
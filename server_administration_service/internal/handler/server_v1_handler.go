@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+	"strconv"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+)
+
+// ServerV1Handler serves the servers as the /api/v1/servers resource. Unlike the older routes, every
+// error is answered with an apiError body and a status telling what went wrong.
+type ServerV1Handler interface {
+	ListServers(w http.ResponseWriter, r *http.Request)
+	CreateServer(w http.ResponseWriter, r *http.Request)
+	GetServer(w http.ResponseWriter, r *http.Request)
+	PatchServer(w http.ResponseWriter, r *http.Request)
+	DeleteServer(w http.ResponseWriter, r *http.Request)
+}
+
+type serverV1Handler struct {
+	service service.ServerCRUDService
+}
+
+func NewServerV1Handler(service service.ServerCRUDService) ServerV1Handler {
+	return &serverV1Handler{
+		service: service,
+	}
+}
+
+// Codes of the errors of the v1 API
+const (
+	apiErrorBadRequest         = "bad_request"
+	apiErrorValidation         = "validation_failed"
+	apiErrorNotFound           = "not_found"
+	apiErrorConflict           = "conflict"
+	apiErrorPreconditionFailed = "precondition_failed"
+	apiErrorInternal           = "internal_error"
+)
+
+// apiError is the body of every error of the v1 API.
+type apiError struct {
+	Error apiErrorDetail `json:"error"`
+}
+
+type apiErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, apiError{Error: apiErrorDetail{Code: code, Message: message}})
+}
+
+// writeServiceError answers an error of the service with the status it stands for. Anything unexpected
+// is logged and answered 500 without its details.
+func writeServiceError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrServerNotFound):
+		writeAPIError(w, http.StatusNotFound, apiErrorNotFound, "server not found")
+	case errors.Is(err, service.ErrServerConflict):
+		writeAPIError(w, http.StatusConflict, apiErrorConflict, err.Error())
+	case errors.Is(err, service.ErrVersionConflict):
+		writeAPIError(w, http.StatusPreconditionFailed, apiErrorPreconditionFailed, "the server changed since the version of If-Match")
+	case errors.Is(err, domain.ErrInvalidServer), errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, domain.ErrInvalidLabel):
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorValidation, err.Error())
+	case errors.Is(err, service.ErrInvalidPage), errors.Is(err, domain.ErrInvalidSort), errors.Is(err, domain.ErrInvalidFilterExpression):
+		writeAPIError(w, http.StatusBadRequest, apiErrorBadRequest, err.Error())
+	default:
+		logging.LogMessage("server_administration_service", "Failed to "+action+": "+err.Error(), "ERROR")
+		writeAPIError(w, http.StatusInternalServerError, apiErrorInternal, "failed to "+action)
+	}
+}
+
+// ListServers pages through the servers matching the filters of /view by cursor.
+func (h *serverV1Handler) ListServers(w http.ResponseWriter, r *http.Request) {
+	serverFilter, err := parseServerFilter(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrorBadRequest, err.Error())
+		return
+	}
+
+	sort, err := parsePageSort(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrorBadRequest, err.Error())
+		return
+	}
+	request := dto.ServerPageRequest{
+		Sort:   sort,
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			writeAPIError(w, http.StatusBadRequest, apiErrorBadRequest, "limit must be a positive integer")
+			return
+		}
+		request.Limit = limit
+	}
+
+	page, err := h.service.ViewServerPage(&serverFilter, request)
+	if err != nil {
+		writeServiceError(w, "list servers", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// CreateServer creates the server of the body and answers it with its location and ETag.
+func (h *serverV1Handler) CreateServer(w http.ResponseWriter, r *http.Request) {
+	body, ok := readServerBody(w, r)
+	if !ok {
+		return
+	}
+	fields, err := serverFieldsFromBody(body)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorValidation, err.Error())
+		return
+	}
+
+	serverID, _ := fields["server_id"].(string)
+	serverName, _ := fields["server_name"].(string)
+	addresses, _ := fields["addresses"].(domain.Addresses)
+	slaTarget, _ := fields["sla_target"].(float64)
+	labels, _ := fields["labels"].(domain.Labels)
+	notes, _ := fields["notes"].(string)
+
+	serverID, err = h.service.CreateServer(serverID, serverName, addresses, slaTarget, labels, notes, actorFromRequest(r))
+	if err != nil {
+		writeServiceError(w, "create server", err)
+		return
+	}
+	logging.LogMessage("server_administration_service", "Server created successfully with ID: "+serverID, "INFO")
+
+	w.Header().Set("Location", "/api/v1/servers/"+serverID)
+	server, err := h.service.GetServer(serverID)
+	if err != nil {
+		// The server is created all the same, the client can read it from its location
+		logging.LogMessage("server_administration_service", "Server "+serverID+" created but not read back: "+err.Error(), "ERROR")
+		writeJSON(w, http.StatusCreated, map[string]string{"server_id": serverID})
+		return
+	}
+	w.Header().Set("ETag", serverETag(server.Version))
+	writeJSON(w, http.StatusCreated, server)
+}
+
+// GetServer answers a server with its version as ETag.
+func (h *serverV1Handler) GetServer(w http.ResponseWriter, r *http.Request) {
+	server, err := h.service.GetServer(mux.Vars(r)["server_id"])
+	if err != nil {
+		writeServiceError(w, "get server", err)
+		return
+	}
+
+	w.Header().Set("ETag", serverETag(server.Version))
+	if notModified(r, server.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, server)
+}
+
+// PatchServer changes the fields of the body, the others are left as they are, and answers the server
+// as it is afterwards. An If-Match makes the change only if the server is still at that version.
+func (h *serverV1Handler) PatchServer(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	version, ok := versionFromIfMatch(r)
+	if !ok {
+		writeAPIError(w, http.StatusPreconditionFailed, apiErrorPreconditionFailed, "If-Match is not an ETag of the server")
+		return
+	}
+
+	body, ok := readServerBody(w, r)
+	if !ok {
+		return
+	}
+	updatedData, err := serverFieldsFromBody(body)
+	if err != nil {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorValidation, err.Error())
+		return
+	}
+	if _, ok := updatedData["server_id"]; ok {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorValidation, "server_id can't be changed")
+		return
+	}
+	if len(updatedData) == 0 {
+		writeAPIError(w, http.StatusUnprocessableEntity, apiErrorValidation, "no fields to change")
+		return
+	}
+
+	if err := h.service.UpdateServer(serverID, updatedData, version, actorFromRequest(r)); err != nil {
+		writeServiceError(w, "update server", err)
+		return
+	}
+	logging.LogMessage("server_administration_service", "Server updated successfully with ID: "+serverID, "INFO")
+
+	server, err := h.service.GetServer(serverID)
+	if err != nil {
+		writeServiceError(w, "get server", err)
+		return
+	}
+	w.Header().Set("ETag", serverETag(server.Version))
+	writeJSON(w, http.StatusOK, server)
+}
+
+// DeleteServer moves a server to the trash. An If-Match deletes it only if it's still at that version.
+func (h *serverV1Handler) DeleteServer(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	version, ok := versionFromIfMatch(r)
+	if !ok {
+		writeAPIError(w, http.StatusPreconditionFailed, apiErrorPreconditionFailed, "If-Match is not an ETag of the server")
+		return
+	}
+
+	if err := h.service.DeleteServer(serverID, version, actorFromRequest(r)); err != nil {
+		writeServiceError(w, "delete server", err)
+		return
+	}
+
+	logging.LogMessage("server_administration_service", "Server deleted successfully with ID: "+serverID, "INFO")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readServerBody reads the JSON object of a request, answering 400 when it isn't one.
+func readServerBody(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var body map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body == nil {
+		writeAPIError(w, http.StatusBadRequest, apiErrorBadRequest, "the body must be a JSON object")
+		return nil, false
+	}
+	return body, true
+}
+
+// serverFieldsFromBody reads the server fields of a body, refusing values of the wrong type rather than
+// leaving them out. Fields it doesn't know are ignored.
+func serverFieldsFromBody(body map[string]interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, field := range []string{"server_id", "server_name", "notes"} {
+		if raw, existed := body[field]; existed {
+			value, ok := raw.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a string", field)
+			}
+			fields[field] = value
+		}
+	}
+
+	if raw, existed := body["sla_target"]; existed {
+		slaTarget, ok := raw.(float64)
+		if !ok {
+			return nil, errors.New("sla_target must be a number")
+		}
+		fields["sla_target"] = slaTarget
+	}
+
+	if _, existed := body["addresses"]; existed {
+		addresses, _, err := addressesFromBody(map[string]interface{}{"addresses": body["addresses"]})
+		if err != nil {
+			return nil, err
+		}
+		fields["addresses"] = addresses
+	}
+
+	if raw, existed := body["labels"]; existed {
+		labels, err := labelsFromBody(raw)
+		if err != nil {
+			return nil, err
+		}
+		fields["labels"] = labels
+	}
+
+	return fields, nil
+}
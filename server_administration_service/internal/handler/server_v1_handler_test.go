@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// apiErrorCode returns the code of the JSON error body of a v1 response.
+func apiErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.NotEmpty(t, body.Error.Message)
+	return body.Error.Code
+}
+
+func withServerID(r *http.Request, serverID string) *http.Request {
+	return mux.SetURLVars(r, map[string]string{"server_id": serverID})
+}
+
+func TestV1CreateServer_Created(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	mockService.On("CreateServer").Return("srv-1", nil)
+	mockService.On("GetServer", "srv-1").Return(&domain.Server{ServerID: "srv-1", ServerName: "web-1", Version: 1}, nil)
+
+	body := `{"server_id":"srv-1","server_name":"web-1","addresses":[{"address":"10.0.0.1"}]}`
+	w := httptest.NewRecorder()
+	h.CreateServer(w, httptest.NewRequest(http.MethodPost, "/api/v1/servers", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/api/v1/servers/srv-1", w.Header().Get("Location"))
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var server domain.Server
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &server))
+	assert.Equal(t, "web-1", server.ServerName)
+}
+
+func TestV1CreateServer_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		err    error
+		status int
+		code   string
+	}{
+		{"malformed body", `{"server_id":`, nil, http.StatusBadRequest, "bad_request"},
+		{"wrong type", `{"server_id":"srv-1","sla_target":"high"}`, nil, http.StatusUnprocessableEntity, "validation_failed"},
+		{"invalid server", `{"server_id":"srv 1"}`, fmt.Errorf("%w: server_id", domain.ErrInvalidServer), http.StatusUnprocessableEntity, "validation_failed"},
+		{"duplicate name", `{"server_id":"srv-1"}`, fmt.Errorf("%w: server_name taken", service.ErrServerConflict), http.StatusConflict, "conflict"},
+		{"database down", `{"server_id":"srv-1"}`, errors.New("connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockServerCRUDService)
+			h := handler.NewServerV1Handler(mockService)
+			mockService.On("CreateServer").Return("", tt.err)
+
+			w := httptest.NewRecorder()
+			h.CreateServer(w, httptest.NewRequest(http.MethodPost, "/api/v1/servers", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.code, apiErrorCode(t, w))
+			if tt.err == nil {
+				mockService.AssertNotCalled(t, "CreateServer")
+			}
+		})
+	}
+}
+
+func TestV1GetServer_IfNoneMatch(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	mockService.On("GetServer", "srv-1").Return(&domain.Server{ServerID: "srv-1", Version: 7}, nil)
+
+	for ifNoneMatch, expected := range map[string]int{
+		`"7"`:            http.StatusNotModified,
+		`*`:              http.StatusNotModified,
+		`"5", "7"`:       http.StatusNotModified,
+		`W/"7"`:          http.StatusNotModified,
+		` "6" ,"7" `:     http.StatusNotModified,
+		`"5", "6"`:       http.StatusOK,
+		`"7`:             http.StatusOK,
+		`"abc", "seven"`: http.StatusOK,
+	} {
+		req := withServerID(httptest.NewRequest(http.MethodGet, "/api/v1/servers/srv-1", nil), "srv-1")
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		h.GetServer(w, req)
+
+		assert.Equal(t, expected, w.Code, ifNoneMatch)
+		assert.Equal(t, `"7"`, w.Header().Get("ETag"), ifNoneMatch)
+	}
+}
+
+func TestV1GetServer_NotFound(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	mockService.On("GetServer", "srv-9").Return(nil, service.ErrServerNotFound)
+
+	w := httptest.NewRecorder()
+	h.GetServer(w, withServerID(httptest.NewRequest(http.MethodGet, "/api/v1/servers/srv-9", nil), "srv-9"))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "not_found", apiErrorCode(t, w))
+}
+
+func TestV1PatchServer_Success(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	mockService.On("UpdateServer").Return(nil)
+	mockService.On("GetServer", "srv-1").Return(&domain.Server{ServerID: "srv-1", Notes: "Rack 5", Version: 4}, nil)
+
+	req := withServerID(httptest.NewRequest(http.MethodPatch, "/api/v1/servers/srv-1", strings.NewReader(`{"notes":"Rack 5"}`)), "srv-1")
+	req.Header.Set("If-Match", `"3"`)
+	w := httptest.NewRecorder()
+	h.PatchServer(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int64(3), mockService.version)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+}
+
+func TestV1PatchServer_Invalid(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	for _, body := range []string{`{"server_id":"srv-2"}`, `{}`, `{"labels":"env=prod"}`} {
+		w := httptest.NewRecorder()
+		h.PatchServer(w, withServerID(httptest.NewRequest(http.MethodPatch, "/api/v1/servers/srv-1", strings.NewReader(body)), "srv-1"))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+		assert.Equal(t, "validation_failed", apiErrorCode(t, w))
+	}
+	mockService.AssertNotCalled(t, "UpdateServer")
+}
+
+func TestV1DeleteServer(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	mockService.On("DeleteServer").Return(nil).Once()
+	w := httptest.NewRecorder()
+	h.DeleteServer(w, withServerID(httptest.NewRequest(http.MethodDelete, "/api/v1/servers/srv-1", nil), "srv-1"))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockService.On("DeleteServer").Return(service.ErrVersionConflict)
+	req := withServerID(httptest.NewRequest(http.MethodDelete, "/api/v1/servers/srv-1", nil), "srv-1")
+	req.Header.Set("If-Match", `"2"`)
+	w = httptest.NewRecorder()
+	h.DeleteServer(w, req)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	assert.Equal(t, "precondition_failed", apiErrorCode(t, w))
+}
+
+func TestV1ListServers_InvalidQuery(t *testing.T) {
+	mockService := new(mockServerCRUDService)
+	h := handler.NewServerV1Handler(mockService)
+
+	for _, query := range []string{"limit=0", "label_selector=env%20in", "filter=status%20==", "sort_column=password", "sort_order=sideways"} {
+		w := httptest.NewRecorder()
+		h.ListServers(w, httptest.NewRequest(http.MethodGet, "/api/v1/servers?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, "bad_request", apiErrorCode(t, w))
+	}
+	mockService.AssertNotCalled(t, "ViewServerPage")
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"server_administration_service/internal/service"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+type SLAHandler interface {
+	GetCompliance(w http.ResponseWriter, r *http.Request)
+}
+
+type slaHandler struct {
+	service      service.SLAService
+	groupService service.ServerGroupService
+}
+
+func NewSLAHandler(service service.SLAService, groupService service.ServerGroupService) SLAHandler {
+	return &slaHandler{
+		service:      service,
+		groupService: groupService,
+	}
+}
+
+// parseSLAPeriod turns the period query parameters into a time range:
+// period=month (default) with month=YYYY-MM (default the current month), or
+// period=rolling with window=<N>d or a Go duration such as 12h (default 30d) ending now.
+func parseSLAPeriod(query url.Values, now time.Time) (time.Time, time.Time, error) {
+	switch query.Get("period") {
+	case "", "month":
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if month := query.Get("month"); month != "" {
+			parsed, err := time.Parse("2006-01", month)
+			if err != nil {
+				return time.Time{}, time.Time{}, errors.New("invalid 'month' query parameter, expected YYYY-MM")
+			}
+			start = parsed
+		}
+		return start, start.AddDate(0, 1, 0), nil
+	case "rolling":
+		window := 30 * 24 * time.Hour
+		if value := query.Get("window"); value != "" {
+			var err error
+			if days, found := strings.CutSuffix(value, "d"); found {
+				var n int
+				n, err = strconv.Atoi(days)
+				window = time.Duration(n) * 24 * time.Hour
+			} else {
+				window, err = time.ParseDuration(value)
+			}
+			if err != nil || window <= 0 {
+				return time.Time{}, time.Time{}, errors.New("invalid 'window' query parameter")
+			}
+		}
+		return now.Add(-window), now, nil
+	default:
+		return time.Time{}, time.Time{}, errors.New("invalid 'period' query parameter, expected month or rolling")
+	}
+}
+
+func (h *slaHandler) GetCompliance(w http.ResponseWriter, r *http.Request) {
+	startTime, endTime, err := parseSLAPeriod(r.URL.Query(), time.Now().UTC())
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to parse SLA period: "+err.Error(), "ERROR")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Query().Get("scope") {
+	case "", "servers":
+	case "groups":
+		h.getGroupCompliance(w, startTime, endTime)
+		return
+	default:
+		http.Error(w, "invalid 'scope' query parameter, expected servers or groups", http.StatusBadRequest)
+		return
+	}
+
+	serverID := r.URL.Query().Get("server_id")
+	compliances, err := h.service.GetCompliance(serverID, startTime, endTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get SLA compliance: "+err.Error(), "ERROR")
+		if errors.Is(err, service.ErrServerNotFound) {
+			http.Error(w, "Server not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSLAPeriod) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to get SLA compliance", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, compliances)
+}
+
+// getGroupCompliance answers the compliance of every group, rolled up from the servers below it.
+func (h *slaHandler) getGroupCompliance(w http.ResponseWriter, startTime, endTime time.Time) {
+	compliances, err := h.groupService.GetGroupCompliance(startTime, endTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get group SLA compliance: "+err.Error(), "ERROR")
+		if errors.Is(err, service.ErrInvalidSLAPeriod) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to get SLA compliance", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, compliances)
+}
@@ -0,0 +1,148 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockSLAService implements service.SLAService for testing
+type mockSLAService struct {
+	mock.Mock
+}
+
+func (m *mockSLAService) GetCompliance(serverID string, startTime, endTime time.Time) ([]domain.SLACompliance, error) {
+	args := m.Called(serverID, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SLACompliance), args.Error(1)
+}
+
+func TestGetCompliance_CalendarMonth(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	h := handler.NewSLAHandler(mockSvc, new(mockServerGroupService))
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	mockSvc.On("GetCompliance", "srv-1", start, end).
+		Return([]domain.SLACompliance{{ServerID: "srv-1", SLATarget: 99.9, Achieved: 99.5, Breached: true}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance?server_id=srv-1&period=month&month=2025-06", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []domain.SLACompliance
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if assert.Len(t, resp, 1) {
+		assert.True(t, resp[0].Breached)
+	}
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetCompliance_RollingWindow(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	h := handler.NewSLAHandler(mockSvc, new(mockServerGroupService))
+
+	mockSvc.On("GetCompliance", "", mock.Anything, mock.Anything).Return([]domain.SLACompliance{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance?period=rolling&window=7d", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	start := mockSvc.Calls[0].Arguments.Get(1).(time.Time)
+	end := mockSvc.Calls[0].Arguments.Get(2).(time.Time)
+	assert.Equal(t, 7*24*time.Hour, end.Sub(start))
+}
+
+func TestGetCompliance_InvalidPeriod(t *testing.T) {
+	for _, query := range []string{"period=yearly", "period=month&month=June", "period=rolling&window=-3d", "period=rolling&window=soon"} {
+		mockSvc := new(mockSLAService)
+		h := handler.NewSLAHandler(mockSvc, new(mockServerGroupService))
+
+		req := httptest.NewRequest(http.MethodGet, "/sla/compliance?"+query, nil)
+		rr := httptest.NewRecorder()
+		h.GetCompliance(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+		mockSvc.AssertNotCalled(t, "GetCompliance", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestGetCompliance_ServerNotFound(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	h := handler.NewSLAHandler(mockSvc, new(mockServerGroupService))
+
+	mockSvc.On("GetCompliance", "missing", mock.Anything, mock.Anything).Return(nil, service.ErrServerNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance?server_id=missing", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetCompliance_ServiceError(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	h := handler.NewSLAHandler(mockSvc, new(mockServerGroupService))
+
+	mockSvc.On("GetCompliance", "", mock.Anything, mock.Anything).Return(nil, errors.New("es error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestGetCompliance_Groups(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	mockGroups := new(mockServerGroupService)
+	h := handler.NewSLAHandler(mockSvc, mockGroups)
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	mockGroups.On("GetGroupCompliance", start, end).Return([]domain.GroupSLACompliance{
+		{GroupID: "g-1", SLATarget: 99.9, Achieved: 99.8, ErrorBudgetRemaining: -100, BurnRate: 2, Breached: true},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance?scope=groups&month=2025-06", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp []domain.GroupSLACompliance
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if assert.Len(t, resp, 1) {
+		assert.Equal(t, "g-1", resp[0].GroupID)
+		assert.Equal(t, 2.0, resp[0].BurnRate)
+		assert.True(t, resp[0].Breached)
+	}
+	mockSvc.AssertNotCalled(t, "GetCompliance", mock.Anything, mock.Anything, mock.Anything)
+	mockGroups.AssertExpectations(t)
+}
+
+func TestGetCompliance_InvalidScope(t *testing.T) {
+	mockSvc := new(mockSLAService)
+	mockGroups := new(mockServerGroupService)
+	h := handler.NewSLAHandler(mockSvc, mockGroups)
+
+	req := httptest.NewRequest(http.MethodGet, "/sla/compliance?scope=racks", nil)
+	rr := httptest.NewRecorder()
+	h.GetCompliance(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "GetCompliance", mock.Anything, mock.Anything, mock.Anything)
+}
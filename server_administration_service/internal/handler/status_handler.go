@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+
+	"github.com/flashhhhh/pkg/logging"
+	"gorm.io/gorm"
+)
+
+type StatusHandler interface {
+	ChangeStatus(w http.ResponseWriter, r *http.Request)
+}
+
+type statusHandler struct {
+	service service.ServerStatusService
+}
+
+func NewStatusHandler(service service.ServerStatusService) StatusHandler {
+	return &statusHandler{
+		service: service,
+	}
+}
+
+func (h *statusHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	serverID := r.URL.Query().Get("server_id")
+	if serverID == "" {
+		http.Error(w, "Missing 'server_id' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode request body for request ChangeStatus: "+err.Error(), "ERROR")
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ChangeStatus(serverID, req.Status); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to change the status of server "+serverID+": "+err.Error(), "ERROR")
+		switch {
+		case errors.Is(err, domain.ErrInvalidStatus):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrInvalidTransition):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			http.Error(w, "Server not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to change server status", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Server status changed successfully",
+		"status":  req.Status,
+	})
+}
@@ -0,0 +1,81 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/handler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// mockServerStatusService implements service.ServerStatusService for testing
+type mockServerStatusService struct {
+	mock.Mock
+}
+
+func (m *mockServerStatusService) ChangeStatus(serverID, status string) error {
+	args := m.Called(serverID, status)
+	return args.Error(0)
+}
+
+func (m *mockServerStatusService) RecordStatusChanges(serverIDs []string, status string) error {
+	args := m.Called(serverIDs, status)
+	return args.Error(0)
+}
+
+func TestChangeStatus_Success(t *testing.T) {
+	mockSvc := new(mockServerStatusService)
+	h := handler.NewStatusHandler(mockSvc)
+
+	mockSvc.On("ChangeStatus", "srv-1", domain.StatusMaintenance).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/status?server_id=srv-1", strings.NewReader(`{"status":"Maintenance"}`))
+	rr := httptest.NewRecorder()
+	h.ChangeStatus(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestChangeStatus_MissingServerID(t *testing.T) {
+	mockSvc := new(mockServerStatusService)
+	h := handler.NewStatusHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPut, "/status", strings.NewReader(`{"status":"Maintenance"}`))
+	rr := httptest.NewRecorder()
+	h.ChangeStatus(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockSvc.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestChangeStatus_Errors(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{fmt.Errorf("%w: %q", domain.ErrInvalidStatus, "On"), http.StatusBadRequest},
+		{fmt.Errorf("%w: Decommissioned -> Up", domain.ErrInvalidTransition), http.StatusConflict},
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+		{fmt.Errorf("db error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		mockSvc := new(mockServerStatusService)
+		h := handler.NewStatusHandler(mockSvc)
+		mockSvc.On("ChangeStatus", "srv-1", "Up").Return(tt.err)
+
+		req := httptest.NewRequest(http.MethodPut, "/status?server_id=srv-1", strings.NewReader(`{"status":"Up"}`))
+		rr := httptest.NewRecorder()
+		h.ChangeStatus(rr, req)
+
+		assert.Equal(t, tt.expected, rr.Code, tt.err.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"server_administration_service/internal/service"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/gorilla/mux"
+)
+
+type TrashHandler interface {
+	GetTrash(w http.ResponseWriter, r *http.Request)
+	RestoreServer(w http.ResponseWriter, r *http.Request)
+}
+
+type trashHandler struct {
+	service service.TrashService
+}
+
+func NewTrashHandler(service service.TrashService) TrashHandler {
+	return &trashHandler{
+		service: service,
+	}
+}
+
+func (h *trashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	trash, err := h.service.GetTrash()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the trash: "+err.Error(), "ERROR")
+		http.Error(w, "Failed to get the trash", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, trash)
+}
+
+func (h *trashHandler) RestoreServer(w http.ResponseWriter, r *http.Request) {
+	serverID := mux.Vars(r)["server_id"]
+	server, err := h.service.RestoreServer(serverID, actorFromRequest(r))
+	if err != nil {
+		if errors.Is(err, service.ErrServerNotInTrash) {
+			http.Error(w, "Server "+serverID+" is not in the trash", http.StatusNotFound)
+			return
+		}
+		logging.LogMessage("server_administration_service", "Failed to restore server "+serverID+": "+err.Error(), "ERROR")
+		http.Error(w, "Failed to restore server", http.StatusInternalServerError)
+		return
+	}
+
+	logging.LogMessage("server_administration_service", "Server restored from the trash with ID: "+serverID, "INFO")
+	writeJSON(w, http.StatusOK, server)
+}
@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/handler"
+	"server_administration_service/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockTrashService implements service.TrashService for testing
+type mockTrashService struct {
+	mock.Mock
+}
+
+func (m *mockTrashService) GetTrash() ([]dto.TrashedServer, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.TrashedServer), args.Error(1)
+}
+
+func (m *mockTrashService) RestoreServer(serverID string, actor dto.Actor) (*domain.Server, error) {
+	args := m.Called(serverID, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Server), args.Error(1)
+}
+
+func (m *mockTrashService) PurgeExpired() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestGetTrash_Success(t *testing.T) {
+	mockSvc := new(mockTrashService)
+	h := handler.NewTrashHandler(mockSvc)
+
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockSvc.On("GetTrash").Return([]dto.TrashedServer{{
+		Server:    domain.Server{ServerID: "srv-1", ServerName: "web-1"},
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(service.DefaultTrashRetention),
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	rr := httptest.NewRecorder()
+	h.GetTrash(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var trash []map[string]interface{}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&trash))
+	assert.Equal(t, "web-1", trash[0]["server_name"])
+	assert.Equal(t, "2026-10-01T12:00:00Z", trash[0]["deleted_at"])
+	assert.Equal(t, "2026-10-31T12:00:00Z", trash[0]["purge_at"])
+}
+
+func TestRestoreServer_Success(t *testing.T) {
+	mockSvc := new(mockTrashService)
+	h := handler.NewTrashHandler(mockSvc)
+
+	mockSvc.On("RestoreServer", "srv-1", dto.Actor{UserID: "user-1", SourceIP: "192.0.2.10"}).Return(&domain.Server{ServerID: "srv-1"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/trash/srv-1/restore", nil)
+	req = mux.SetURLVars(req, map[string]string{"server_id": "srv-1"})
+	req.RemoteAddr = "192.0.2.10:51234"
+	req.Header.Set("userID", "user-1")
+	rr := httptest.NewRecorder()
+	h.RestoreServer(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestRestoreServer_Errors(t *testing.T) {
+	for _, c := range []struct {
+		err    error
+		status int
+	}{
+		{service.ErrServerNotInTrash, http.StatusNotFound},
+		{errors.New("connection refused"), http.StatusInternalServerError},
+	} {
+		mockSvc := new(mockTrashService)
+		h := handler.NewTrashHandler(mockSvc)
+		mockSvc.On("RestoreServer", "srv-1", mock.Anything).Return(nil, c.err)
+
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/trash/srv-1/restore", nil), map[string]string{"server_id": "srv-1"})
+		rr := httptest.NewRecorder()
+		h.RestoreServer(rr, req)
+
+		assert.Equal(t, c.status, rr.Code)
+	}
+}
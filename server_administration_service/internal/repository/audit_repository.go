@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	CreateAuditEntries(entries []domain.AuditEntry) error
+	GetAuditEntries(filter dto.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+// Rows per INSERT of CreateAuditEntries, an import can change thousands of servers at once
+const createAuditEntriesBatchSize = 1000
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) CreateAuditEntries(entries []domain.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(entries, createAuditEntriesBatchSize).Error
+}
+
+// GetAuditEntries returns the entries matching the filter, newest first, at most filter.Limit of them.
+func (r *auditRepository) GetAuditEntries(filter dto.AuditFilter) ([]domain.AuditEntry, error) {
+	query := r.db.Model(&domain.AuditEntry{})
+	if filter.ServerID != "" {
+		query = query.Where("server_id = ?", filter.ServerID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_time < ?", *filter.To)
+	}
+
+	var entries []domain.AuditEntry
+	if err := query.Order("created_time DESC").Order("id").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+)
+
+func TestCreateAuditEntries_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewAuditRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "audit_entries" ("id","server_id","user_id","action","changes","source_ip","created_time") VALUES ($1,$2,$3,$4,$5,$6,$7)`)).
+		WithArgs("entry-1", "srv-1", "user-1", domain.AuditUpdate, `{"notes":{"before":"Rack 4","after":"Rack 5"}}`, "192.0.2.10", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.CreateAuditEntries([]domain.AuditEntry{{
+		ID:       "entry-1",
+		ServerID: "srv-1",
+		UserID:   "user-1",
+		Action:   domain.AuditUpdate,
+		Changes:  domain.AuditChanges{"notes": {Before: "Rack 4", After: "Rack 5"}},
+		SourceIP: "192.0.2.10",
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.CreateAuditEntries(nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditEntries_Filter(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewAuditRepository(gdb)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_entries" WHERE server_id = $1 AND user_id = $2 AND created_time >= $3 AND created_time < $4 ORDER BY created_time DESC,id LIMIT $5`)).
+		WithArgs("srv-1", "user-1", from, to, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "server_id", "user_id", "action", "changes"}).
+			AddRow("entry-1", "srv-1", "user-1", domain.AuditDelete, `{"server_name":{"before":"web-1","after":null}}`))
+
+	entries, err := repo.GetAuditEntries(dto.AuditFilter{ServerID: "srv-1", UserID: "user-1", From: &from, To: &to, Limit: 50})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, domain.AuditChanges{"server_name": {Before: "web-1", After: nil}}, entries[0].Changes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DependencyRepository interface {
+	AddDependency(dependency *domain.ServerDependency) error
+	RemoveDependency(serverID, dependsOnID string) error
+	GetDependencies() ([]domain.ServerDependency, error)
+	GetServers(serverIDs []string) ([]domain.Server, error)
+	GetDownDependencies(serverID string) ([]string, error)
+}
+
+type dependencyRepository struct {
+	db *gorm.DB
+}
+
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepository{
+		db: db,
+	}
+}
+
+// AddDependency stores the edge, ignoring one that already exists.
+// It fails with gorm.ErrRecordNotFound when one of the servers doesn't exist.
+func (r *dependencyRepository) AddDependency(dependency *domain.ServerDependency) error {
+	var numServers int64
+	if err := r.db.Model(&domain.Server{}).
+		Where("server_id IN ?", []string{dependency.ServerID, dependency.DependsOnID}).
+		Count(&numServers).Error; err != nil {
+		return err
+	}
+	if numServers != 2 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency).Error
+}
+
+func (r *dependencyRepository) RemoveDependency(serverID, dependsOnID string) error {
+	result := r.db.Where("server_id = ? AND depends_on_id = ?", serverID, dependsOnID).Delete(&domain.ServerDependency{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *dependencyRepository) GetDependencies() ([]domain.ServerDependency, error) {
+	var dependencies []domain.ServerDependency
+	if err := r.db.Find(&dependencies).Error; err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+func (r *dependencyRepository) GetServers(serverIDs []string) ([]domain.Server, error) {
+	var servers []domain.Server
+	if len(serverIDs) == 0 {
+		return servers, nil
+	}
+
+	if err := r.db.Select("server_id", "server_name", "status").Where("server_id IN ?", serverIDs).Find(&servers).Error; err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+func (r *dependencyRepository) GetDownDependencies(serverID string) ([]string, error) {
+	return downDependencies(r.db, serverID)
+}
+
+// downDependencies returns the servers serverID depends on directly that are Down or themselves Unreachable.
+func downDependencies(db *gorm.DB, serverID string) ([]string, error) {
+	var serverIDs []string
+	err := db.Table("server_dependencies").
+		Joins("JOIN servers ON servers.server_id = server_dependencies.depends_on_id").
+		Where("server_dependencies.server_id = ? AND servers.status IN ? AND servers.deleted_at IS NULL", serverID, []string{domain.StatusDown, domain.StatusUnreachable}).
+		Order("servers.server_id").
+		Pluck("servers.server_id", &serverIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIDs, nil
+}
+
+// unreachableDependents returns the Unreachable servers that depend on serverID directly.
+func unreachableDependents(db *gorm.DB, serverID string) ([]string, error) {
+	var serverIDs []string
+	err := db.Table("server_dependencies").
+		Joins("JOIN servers ON servers.server_id = server_dependencies.server_id").
+		Where("server_dependencies.depends_on_id = ? AND servers.status = ? AND servers.deleted_at IS NULL", serverID, domain.StatusUnreachable).
+		Order("servers.server_id").
+		Pluck("servers.server_id", &serverIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIDs, nil
+}
+
+// downDependents returns the Down servers that depend on serverID, directly or through servers that are
+// Down or Unreachable themselves.
+func downDependents(db *gorm.DB, serverID string) ([]string, error) {
+	unavailable := []string{domain.StatusDown, domain.StatusUnreachable}
+
+	var serverIDs []string
+	err := db.Raw(`WITH RECURSIVE dependents AS (
+		SELECT d.server_id FROM server_dependencies d JOIN servers s ON s.server_id = d.server_id
+		WHERE d.depends_on_id = ? AND s.status IN ? AND s.deleted_at IS NULL
+		UNION
+		SELECT d.server_id FROM server_dependencies d JOIN dependents ON d.depends_on_id = dependents.server_id
+		JOIN servers s ON s.server_id = d.server_id
+		WHERE s.status IN ? AND s.deleted_at IS NULL
+	)
+	SELECT servers.server_id FROM dependents JOIN servers ON servers.server_id = dependents.server_id
+	WHERE servers.status = ?
+	ORDER BY servers.server_id`, serverID, unavailable, unavailable, domain.StatusDown).
+		Scan(&serverIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIDs, nil
+}
@@ -0,0 +1,80 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+)
+
+func TestAddDependency_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDependencyRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "servers" WHERE server_id IN ($1,$2)`)).
+		WithArgs("srv-1", "switch-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "server_dependencies" .* ON CONFLICT DO NOTHING`).
+		WithArgs("srv-1", "switch-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := repo.AddDependency(&domain.ServerDependency{ServerID: "srv-1", DependsOnID: "switch-1"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddDependency_UnknownServer(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDependencyRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "servers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := repo.AddDependency(&domain.ServerDependency{ServerID: "srv-1", DependsOnID: "missing"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveDependency_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDependencyRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "server_dependencies" WHERE server_id = \$1 AND depends_on_id = \$2`).
+		WithArgs("srv-1", "switch-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.RemoveDependency("srv-1", "switch-1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDownDependencies_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDependencyRepository(gdb)
+
+	mock.ExpectQuery(`SELECT "servers"."server_id" FROM "server_dependencies" JOIN servers ON .* WHERE server_dependencies.server_id = \$1 AND servers.status IN \(\$2,\$3\)`).
+		WithArgs("srv-1", domain.StatusDown, domain.StatusUnreachable).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("switch-1"))
+
+	parents, err := repo.GetDownDependencies("srv-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"switch-1"}, parents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+
+	"gorm.io/gorm"
+)
+
+type DiscoveryRepository interface {
+	CreateScan(scan *domain.DiscoveryScan) (string, error)
+	UpdateScan(scan *domain.DiscoveryScan) error
+	GetScan(id string) (*domain.DiscoveryScan, error)
+	GetScans() ([]domain.DiscoveryScan, error)
+	SaveHosts(hosts []domain.DiscoveredHost) error
+	GetHosts(scanID, status string) ([]domain.DiscoveredHost, error)
+	AcceptHosts(scanID string, serverIDs map[string]string) error
+	GetInventoryAddresses() ([]dto.ServerAddress, error)
+}
+
+type discoveryRepository struct {
+	db *gorm.DB
+}
+
+func NewDiscoveryRepository(db *gorm.DB) DiscoveryRepository {
+	return &discoveryRepository{
+		db: db,
+	}
+}
+
+func (r *discoveryRepository) CreateScan(scan *domain.DiscoveryScan) (string, error) {
+	if err := r.db.Create(scan).Error; err != nil {
+		return "", err
+	}
+
+	return scan.ID, nil
+}
+
+func (r *discoveryRepository) UpdateScan(scan *domain.DiscoveryScan) error {
+	return r.db.Save(scan).Error
+}
+
+func (r *discoveryRepository) GetScan(id string) (*domain.DiscoveryScan, error) {
+	var scan domain.DiscoveryScan
+	if err := r.db.Where("id = ?", id).First(&scan).Error; err != nil {
+		return nil, err
+	}
+
+	return &scan, nil
+}
+
+func (r *discoveryRepository) GetScans() ([]domain.DiscoveryScan, error) {
+	var scans []domain.DiscoveryScan
+	if err := r.db.Order("started_at DESC").Find(&scans).Error; err != nil {
+		return nil, err
+	}
+
+	return scans, nil
+}
+
+func (r *discoveryRepository) SaveHosts(hosts []domain.DiscoveredHost) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	return r.db.CreateInBatches(hosts, 500).Error
+}
+
+// GetHosts returns the hosts found by a scan, only those in the given status unless it's empty.
+func (r *discoveryRepository) GetHosts(scanID, status string) ([]domain.DiscoveredHost, error) {
+	var hosts []domain.DiscoveredHost
+	query := r.db.Where("scan_id = ?", scanID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("address").Find(&hosts).Error; err != nil {
+		return nil, err
+	}
+
+	return hosts, nil
+}
+
+// AcceptHosts marks the hosts of a scan as accepted into the inventory, serverIDs maps their address to the new server.
+func (r *discoveryRepository) AcceptHosts(scanID string, serverIDs map[string]string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for address, serverID := range serverIDs {
+			if err := tx.Model(&domain.DiscoveredHost{}).
+				Where("scan_id = ? AND address = ?", scanID, address).
+				Updates(map[string]interface{}{"status": domain.HostAccepted, "server_id": serverID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetInventoryAddresses returns the addresses of every server, whatever its status, to diff scan results against.
+func (r *discoveryRepository) GetInventoryAddresses() ([]dto.ServerAddress, error) {
+	var serverAddresses []dto.ServerAddress
+	if err := r.db.Model(&domain.Server{}).
+		Select("server_id", "primary_address", "addresses", "status").
+		Find(&serverAddresses).Error; err != nil {
+		return nil, err
+	}
+
+	return serverAddresses, nil
+}
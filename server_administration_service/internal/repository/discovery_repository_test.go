@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+)
+
+func TestGetHosts_ByStatus(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDiscoveryRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "discovered_hosts" WHERE scan_id = $1 AND status = $2 ORDER BY address`)).
+		WithArgs("scan-1", domain.HostNew).
+		WillReturnRows(sqlmock.NewRows([]string{"scan_id", "address", "hostname", "method", "status", "server_id"}).
+			AddRow("scan-1", "10.0.0.2", "web-2.example.com", "icmp", domain.HostNew, ""))
+
+	hosts, err := repo.GetHosts("scan-1", domain.HostNew)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.DiscoveredHost{{ScanID: "scan-1", Address: "10.0.0.2", Hostname: "web-2.example.com", Method: "icmp", Status: domain.HostNew}}, hosts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcceptDiscoveredHosts_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDiscoveryRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "discovered_hosts" SET "server_id"=$1,"status"=$2 WHERE scan_id = $3 AND address = $4`)).
+		WithArgs("web-2", domain.HostAccepted, "scan-1", "10.0.0.2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.AcceptHosts("scan-1", map[string]string{"10.0.0.2": "web-2"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetInventoryAddresses_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewDiscoveryRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "server_id","primary_address","addresses","status" FROM "servers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "primary_address", "addresses", "status"}).
+			AddRow("gw-1", "10.0.0.1", `[{"type":"ipv4","address":"10.0.0.1","primary":true}]`, domain.StatusUp))
+
+	addresses, err := repo.GetInventoryAddresses()
+	assert.NoError(t, err)
+	assert.Len(t, addresses, 1)
+	assert.Equal(t, "10.0.0.1", addresses[0].Addresses[0].Address)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// pgInvalidRegularExpression is the SQLSTATE Postgres fails a query with when a ~ operand doesn't compile.
+const pgInvalidRegularExpression = "2201B"
+
+// applyFilterExpression narrows the query down to the servers matching the expression. Field names
+// come from the parsed expression's fixed set and every value is a parameter, so nothing the client
+// wrote ends up in the SQL itself.
+func applyFilterExpression(query *gorm.DB, expression *domain.FilterExpression) *gorm.DB {
+	if expression == nil {
+		return query
+	}
+	sql, args := compileFilterExpression(expression)
+	return query.Where(sql, args...)
+}
+
+// filterQueryError turns the error of a query run with a filter expression into
+// domain.ErrInvalidFilterExpression when Postgres rejected one of its regular expressions. Parsing checks
+// them with Go's regexp, whose syntax isn't quite Postgres's.
+func filterQueryError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgInvalidRegularExpression {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidFilterExpression, pgErr.Message)
+	}
+	return err
+}
+
+func compileFilterExpression(expression *domain.FilterExpression) (string, []interface{}) {
+	if expression.Field != "" {
+		return compileFilterComparison(expression)
+	}
+
+	var (
+		parts []string
+		args  []interface{}
+	)
+	for _, operand := range expression.Operands {
+		sql, operandArgs := compileFilterExpression(operand)
+		parts = append(parts, "("+sql+")")
+		args = append(args, operandArgs...)
+	}
+
+	switch expression.Op {
+	case domain.FilterNot:
+		// A comparison on a missing label is NULL, which NOT would leave NULL rather than make true
+		return "NOT COALESCE(" + parts[0] + ", false)", args
+	case domain.FilterOr:
+		return strings.Join(parts, " OR "), args
+	}
+	return strings.Join(parts, " AND "), args
+}
+
+// filterColumns are the columns of the fields compared as they are.
+var filterColumns = map[string]string{
+	domain.FilterFieldServerID:       "server_id",
+	domain.FilterFieldServerName:     "server_name",
+	domain.FilterFieldStatus:         "status",
+	domain.FilterFieldPrimaryAddress: "primary_address",
+	domain.FilterFieldSLATarget:      "sla_target",
+	domain.FilterFieldCreatedTime:    "created_time",
+	domain.FilterFieldLastUpdated:    "last_updated",
+}
+
+// filterSQLOperators are the SQL operators of the comparisons that have one.
+var filterSQLOperators = map[string]string{
+	domain.FilterEquals:       "=",
+	domain.FilterNotEquals:    "<>",
+	domain.FilterLess:         "<",
+	domain.FilterLessEqual:    "<=",
+	domain.FilterGreater:      ">",
+	domain.FilterGreaterEqual: ">=",
+	domain.FilterIn:           "IN",
+	domain.FilterNotIn:        "NOT IN",
+	domain.FilterMatches:      "~",
+	domain.FilterNotMatches:   "!~",
+	domain.FilterPrefix:       "LIKE",
+}
+
+func compileFilterComparison(comparison *domain.FilterExpression) (string, []interface{}) {
+	switch comparison.Field {
+	case domain.FilterFieldLabels:
+		key := comparison.Label
+		switch comparison.Operator {
+		case domain.FilterExists:
+			return "labels ->> ? IS NOT NULL", []interface{}{key}
+		case domain.FilterNotEquals, domain.FilterNotIn, domain.FilterNotMatches:
+			// As with label selectors, servers without the label match too
+			sql, args := compileFilterValue("labels ->> ?", comparison)
+			return "labels ->> ? IS NULL OR " + sql, append([]interface{}{key, key}, args...)
+		}
+		sql, args := compileFilterValue("labels ->> ?", comparison)
+		return sql, append([]interface{}{key}, args...)
+
+	case domain.FilterFieldAddress:
+		value := comparison.Values[0]
+		switch comparison.Operator {
+		case domain.FilterWithin:
+			// Only IP addresses can be cast to inet; CASE keeps the cast off FQDNs
+			return "EXISTS (SELECT 1 FROM jsonb_array_elements(addresses) AS a WHERE CASE WHEN a ->> 'type' IN ('ipv4', 'ipv6') " +
+				"THEN (a ->> 'address')::inet <<= ?::cidr ELSE false END)", []interface{}{value}
+		case domain.FilterEquals, domain.FilterNotEquals:
+			contains, _ := json.Marshal([]map[string]interface{}{{"address": value}})
+			sql := "primary_address = ? OR addresses @> ?::jsonb"
+			if comparison.Operator == domain.FilterNotEquals {
+				sql = "NOT (" + sql + ")"
+			}
+			return sql, []interface{}{value, string(contains)}
+		}
+		// Matches when any of the addresses does, and !~ when none of them does
+		exists := "EXISTS"
+		if comparison.Operator == domain.FilterNotMatches {
+			exists = "NOT EXISTS"
+			comparison = &domain.FilterExpression{Field: comparison.Field, Operator: domain.FilterMatches, Values: comparison.Values}
+		}
+		sql, args := compileFilterValue("a ->> 'address'", comparison)
+		return exists + " (SELECT 1 FROM jsonb_array_elements(addresses) AS a WHERE " + sql + ")", args
+	}
+
+	return compileFilterValue(filterColumns[comparison.Field], comparison)
+}
+
+// compileFilterValue compares column, a column or an expression, with the comparison's values.
+func compileFilterValue(column string, comparison *domain.FilterExpression) (string, []interface{}) {
+	operator := filterSQLOperators[comparison.Operator]
+	switch comparison.Operator {
+	case domain.FilterIn, domain.FilterNotIn:
+		return column + " " + operator + " ?", []interface{}{comparison.Values}
+	case domain.FilterPrefix:
+		return column + " " + operator + " ?", []interface{}{escapeLike(comparison.Values[0].(string)) + "%"}
+	}
+	return column + " " + operator + " ?", []interface{}{comparison.Values[0]}
+}
+
+// escapeLike escapes the LIKE wildcards of s, so that it only matches itself.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+// indexedServerCRUDRepository keeps the search index in step with the servers it writes. A write
+// that reached Postgres is not undone when the index fails; the failure is logged, and a reindex
+// brings the index back in line.
+type indexedServerCRUDRepository struct {
+	ServerCRUDRepository
+	search ServerSearchRepository
+}
+
+// NewIndexedServerCRUDRepository wraps servers so that every server created, updated or deleted
+// through it is mirrored into the search index.
+func NewIndexedServerCRUDRepository(servers ServerCRUDRepository, search ServerSearchRepository) ServerCRUDRepository {
+	return &indexedServerCRUDRepository{
+		ServerCRUDRepository: servers,
+		search:               search,
+	}
+}
+
+func (r *indexedServerCRUDRepository) CreateServer(server *domain.Server) (string, error) {
+	id, err := r.ServerCRUDRepository.CreateServer(server)
+	if err != nil {
+		return "", err
+	}
+	r.index([]domain.Server{*server})
+	return id, nil
+}
+
+func (r *indexedServerCRUDRepository) CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error) {
+	inserted, skipped, err := r.ServerCRUDRepository.CreateServers(servers, onBatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.index(inserted)
+	return inserted, skipped, nil
+}
+
+func (r *indexedServerCRUDRepository) UpdateServers(servers []domain.Server) error {
+	if err := r.ServerCRUDRepository.UpdateServers(servers); err != nil {
+		return err
+	}
+	serverIDs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		serverIDs = append(serverIDs, server.ServerID)
+	}
+	r.reindex(serverIDs)
+	return nil
+}
+
+func (r *indexedServerCRUDRepository) UpdateServer(serverID string, updatedData map[string]interface{}, version int64) error {
+	if err := r.ServerCRUDRepository.UpdateServer(serverID, updatedData, version); err != nil {
+		return err
+	}
+	r.reindex([]string{serverID})
+	return nil
+}
+
+func (r *indexedServerCRUDRepository) DeleteServer(serverID string, version int64) error {
+	if err := r.ServerCRUDRepository.DeleteServer(serverID, version); err != nil {
+		return err
+	}
+	if err := r.search.DeleteServers([]string{serverID}); err != nil {
+		logging.LogMessage("server_administration_service", "Server "+serverID+" deleted but left in the search index: "+err.Error(), "ERROR")
+	}
+	return nil
+}
+
+func (r *indexedServerCRUDRepository) UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error) {
+	updated, err := r.ServerCRUDRepository.UpdateServersByID(serverIDs, updatedData)
+	if err != nil {
+		return nil, err
+	}
+	r.reindex(updated)
+	return updated, nil
+}
+
+func (r *indexedServerCRUDRepository) DeleteServersByID(serverIDs []string) ([]string, error) {
+	deleted, err := r.ServerCRUDRepository.DeleteServersByID(serverIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) > 0 {
+		if err := r.search.DeleteServers(deleted); err != nil {
+			logging.LogMessage("server_administration_service", "Servers deleted but left in the search index: "+err.Error(), "ERROR")
+		}
+	}
+	return deleted, nil
+}
+
+// Transaction indexes the servers written in the transaction once it's committed, a rolled back
+// transaction leaves the index alone.
+func (r *indexedServerCRUDRepository) Transaction(fn func(tx ServerCRUDRepository) error) error {
+	var written *indexedServerCRUDTx
+	err := r.ServerCRUDRepository.Transaction(func(tx ServerCRUDRepository) error {
+		written = &indexedServerCRUDTx{ServerCRUDRepository: tx}
+		return fn(written)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.reindex(written.changed)
+	if len(written.deleted) > 0 {
+		if err := r.search.DeleteServers(written.deleted); err != nil {
+			logging.LogMessage("server_administration_service", "Servers deleted but left in the search index: "+err.Error(), "ERROR")
+		}
+	}
+	return nil
+}
+
+func (r *indexedServerCRUDRepository) index(servers []domain.Server) {
+	if len(servers) == 0 {
+		return
+	}
+	if err := r.search.IndexServers(servers); err != nil {
+		logging.LogMessage("server_administration_service", "Servers written but not indexed for search: "+err.Error(), "ERROR")
+	}
+}
+
+// reindex reads the servers back after an update, which only has the fields that changed, and
+// indexes them whole.
+func (r *indexedServerCRUDRepository) reindex(serverIDs []string) {
+	for start := 0; start < len(serverIDs); start += serverIndexBatchSize {
+		end := start + serverIndexBatchSize
+		if end > len(serverIDs) {
+			end = len(serverIDs)
+		}
+
+		values := make([]interface{}, 0, end-start)
+		for _, serverID := range serverIDs[start:end] {
+			values = append(values, serverID)
+		}
+		filter := &dto.ServerFilter{Expression: &domain.FilterExpression{
+			Field:    domain.FilterFieldServerID,
+			Operator: domain.FilterIn,
+			Values:   values,
+		}}
+		servers, err := r.ServerCRUDRepository.ViewServers(filter, 0, len(values), nil)
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Servers updated but not indexed for search: "+err.Error(), "ERROR")
+			return
+		}
+		r.index(servers)
+	}
+}
+
+// indexedServerCRUDTx writes through a transaction and notes the servers it changed or deleted, to be
+// indexed once the transaction is committed.
+type indexedServerCRUDTx struct {
+	ServerCRUDRepository
+	changed []string
+	deleted []string
+}
+
+func (t *indexedServerCRUDTx) CreateServer(server *domain.Server) (string, error) {
+	id, err := t.ServerCRUDRepository.CreateServer(server)
+	if err != nil {
+		return "", err
+	}
+	t.changed = append(t.changed, id)
+	return id, nil
+}
+
+func (t *indexedServerCRUDTx) CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error) {
+	inserted, skipped, err := t.ServerCRUDRepository.CreateServers(servers, onBatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, server := range inserted {
+		t.changed = append(t.changed, server.ServerID)
+	}
+	return inserted, skipped, nil
+}
+
+func (t *indexedServerCRUDTx) UpdateServers(servers []domain.Server) error {
+	if err := t.ServerCRUDRepository.UpdateServers(servers); err != nil {
+		return err
+	}
+	for _, server := range servers {
+		t.changed = append(t.changed, server.ServerID)
+	}
+	return nil
+}
+
+func (t *indexedServerCRUDTx) UpdateServer(serverID string, updatedData map[string]interface{}, version int64) error {
+	if err := t.ServerCRUDRepository.UpdateServer(serverID, updatedData, version); err != nil {
+		return err
+	}
+	t.changed = append(t.changed, serverID)
+	return nil
+}
+
+func (t *indexedServerCRUDTx) DeleteServer(serverID string, version int64) error {
+	if err := t.ServerCRUDRepository.DeleteServer(serverID, version); err != nil {
+		return err
+	}
+	t.deleted = append(t.deleted, serverID)
+	return nil
+}
+
+func (t *indexedServerCRUDTx) UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error) {
+	updated, err := t.ServerCRUDRepository.UpdateServersByID(serverIDs, updatedData)
+	if err != nil {
+		return nil, err
+	}
+	t.changed = append(t.changed, updated...)
+	return updated, nil
+}
+
+func (t *indexedServerCRUDTx) DeleteServersByID(serverIDs []string) ([]string, error) {
+	deleted, err := t.ServerCRUDRepository.DeleteServersByID(serverIDs)
+	if err != nil {
+		return nil, err
+	}
+	t.deleted = append(t.deleted, deleted...)
+	return deleted, nil
+}
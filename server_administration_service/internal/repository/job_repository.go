@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"bufio"
+	"io"
+	"server_administration_service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	CreateJob(job *domain.Job) (string, error)
+	UpdateJob(job *domain.Job) error
+	StartJob(id string, startedAt time.Time) error
+	UpdateJobProgress(id, phase string, processed, total int) error
+	GetJob(id string) (*domain.Job, error)
+	GetJobResult(id string) (*domain.Job, error)
+	GetUnfinishedJobs() ([]domain.Job, error)
+	WriteJobFile(write func(w io.Writer) error) (uint32, error)
+	OpenJobFile(oid uint32) io.Reader
+	DeleteFinishedJobs(finishedBefore time.Time) (int, error)
+}
+
+// jobFileChunkSize is how much of a job file goes to or comes from Postgres in one statement.
+const jobFileChunkSize = 1 << 20
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{
+		db: db,
+	}
+}
+
+func (r *jobRepository) CreateJob(job *domain.Job) (string, error) {
+	if err := r.db.Create(job).Error; err != nil {
+		return "", err
+	}
+
+	return job.ID, nil
+}
+
+func (r *jobRepository) UpdateJob(job *domain.Job) error {
+	return r.db.Save(job).Error
+}
+
+// StartJob marks a job Running from scratch, without rewriting its input.
+func (r *jobRepository) StartJob(id string, startedAt time.Time) error {
+	return r.db.Model(&domain.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     domain.JobRunning,
+		"phase":      "",
+		"processed":  0,
+		"total":      0,
+		"started_at": startedAt,
+	}).Error
+}
+
+func (r *jobRepository) UpdateJobProgress(id, phase string, processed, total int) error {
+	return r.db.Model(&domain.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"phase":     phase,
+		"processed": processed,
+		"total":     total,
+	}).Error
+}
+
+// GetJob returns a job without its input and result, which polling doesn't need.
+func (r *jobRepository) GetJob(id string) (*domain.Job, error) {
+	var job domain.Job
+	if err := r.db.Omit("options", "input", "result").Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetJobResult returns a job with its result and the reference to its file.
+func (r *jobRepository) GetJobResult(id string) (*domain.Job, error) {
+	var job domain.Job
+	if err := r.db.Omit("options", "input").Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetUnfinishedJobs returns the jobs still pending or running, oldest first, with what they need to run.
+func (r *jobRepository) GetUnfinishedJobs() ([]domain.Job, error) {
+	var jobs []domain.Job
+	if err := r.db.Where("status IN ?", []string{domain.JobPending, domain.JobRunning}).Order("created_time").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// WriteJobFile stores what write writes in a new large object and returns its OID. The file goes to
+// Postgres a chunk at a time, so it's never in memory in full, and all in one transaction: a failed
+// write leaves no object behind.
+func (r *jobRepository) WriteJobFile(write func(w io.Writer) error) (uint32, error) {
+	var oid uint32
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw("SELECT lo_create(0)").Row().Scan(&oid); err != nil {
+			return err
+		}
+
+		file := bufio.NewWriterSize(&largeObjectWriter{db: tx, oid: oid}, jobFileChunkSize)
+		if err := write(file); err != nil {
+			return err
+		}
+		return file.Flush()
+	})
+	if err != nil {
+		return 0, err
+	}
+	return oid, nil
+}
+
+// OpenJobFile reads back a file of WriteJobFile a chunk at a time.
+func (r *jobRepository) OpenJobFile(oid uint32) io.Reader {
+	return &largeObjectReader{db: r.db, oid: oid}
+}
+
+// DeleteFinishedJobs deletes the jobs that finished before finishedBefore together with the large objects of
+// their files, in one transaction so that no file outlives its job, and returns how many it deleted.
+func (r *jobRepository) DeleteFinishedJobs(finishedBefore time.Time) (int, error) {
+	var jobs []domain.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "file_oid"}}}).
+			Where("status IN ? AND finished_at < ?", []string{domain.JobCompleted, domain.JobFailed}, finishedBefore).
+			Delete(&jobs).Error; err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			// Imports and failed exports have no file
+			if job.FileOID == 0 {
+				continue
+			}
+			if err := tx.Exec("SELECT lo_unlink(?)", job.FileOID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(jobs), nil
+}
+
+type largeObjectWriter struct {
+	db     *gorm.DB
+	oid    uint32
+	offset int64
+}
+
+func (w *largeObjectWriter) Write(p []byte) (int, error) {
+	if err := w.db.Exec("SELECT lo_put(?, ?, ?)", w.oid, w.offset, p).Error; err != nil {
+		return 0, err
+	}
+	w.offset += int64(len(p))
+	return len(p), nil
+}
+
+type largeObjectReader struct {
+	db     *gorm.DB
+	oid    uint32
+	offset int64
+	chunk  []byte
+}
+
+func (r *largeObjectReader) Read(p []byte) (int, error) {
+	if len(r.chunk) == 0 {
+		// lo_get answers nothing at all past the end of the object
+		if err := r.db.Raw("SELECT lo_get(?, ?, ?)", r.oid, r.offset, jobFileChunkSize).Row().Scan(&r.chunk); err != nil {
+			return 0, err
+		}
+		if len(r.chunk) == 0 {
+			return 0, io.EOF
+		}
+		r.offset += int64(len(r.chunk))
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
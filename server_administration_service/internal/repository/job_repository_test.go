@@ -0,0 +1,173 @@
+package repository_test
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+)
+
+func TestGetJob_OmitsPayloads(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "jobs"."id","jobs"."kind","jobs"."status","jobs"."phase","jobs"."processed","jobs"."total","jobs"."error","jobs"."file_oid","jobs"."filename","jobs"."started_at","jobs"."finished_at","jobs"."created_time" FROM "jobs" WHERE id = $1`)).
+		WithArgs("job-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status", "processed", "total"}).
+			AddRow("job-1", domain.JobImport, domain.JobRunning, 1000, 40000))
+
+	job, err := repo.GetJob("job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobRunning, job.Status)
+	assert.Equal(t, 1000, job.Processed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJobProgress_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "jobs" SET "phase"=$1,"processed"=$2,"total"=$3 WHERE id = $4`)).
+		WithArgs("writing", 1000, 40000, "job-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.UpdateJobProgress("job-1", "writing", 1000, 40000)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUnfinishedJobs_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs" WHERE status IN ($1,$2) ORDER BY created_time`)).
+		WithArgs(domain.JobPending, domain.JobRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status", "input"}).
+			AddRow("job-1", domain.JobImport, domain.JobRunning, []byte("csv")))
+
+	jobs, err := repo.GetUnfinishedJobs()
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, []byte("csv"), jobs[0].Input)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteJobFile_Chunks(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+	file := strings.Repeat("x", 1<<20) + "tail"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT lo_create(0)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"lo_create"}).AddRow(16400))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT lo_put($1, $2, $3)`)).
+		WithArgs(16400, 0, []byte(file[:1<<20])).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT lo_put($1, $2, $3)`)).
+		WithArgs(16400, 1<<20, []byte("tail")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	oid, err := repo.WriteJobFile(func(w io.Writer) error {
+		_, err := io.WriteString(w, file)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(16400), oid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWriteJobFile_FailedWriteRollsBack(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT lo_create(0)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"lo_create"}).AddRow(16400))
+	mock.ExpectRollback()
+
+	_, err := repo.WriteJobFile(func(w io.Writer) error {
+		return errors.New("db error")
+	})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpenJobFile_ReadsToTheEnd(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT lo_get($1, $2, $3)`)).
+		WithArgs(16400, 0, 1<<20).
+		WillReturnRows(sqlmock.NewRows([]string{"lo_get"}).AddRow([]byte("Server ID\n")))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT lo_get($1, $2, $3)`)).
+		WithArgs(16400, 10, 1<<20).
+		WillReturnRows(sqlmock.NewRows([]string{"lo_get"}).AddRow([]byte{}))
+
+	file, err := io.ReadAll(repo.OpenJobFile(16400))
+	assert.NoError(t, err)
+	assert.Equal(t, "Server ID\n", string(file))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteFinishedJobs_UnlinksFiles(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+	finishedBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM "jobs" WHERE status IN ($1,$2) AND finished_at < $3 RETURNING "id","file_oid"`)).
+		WithArgs(domain.JobCompleted, domain.JobFailed, finishedBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_oid"}).AddRow("job-1", 16400).AddRow("job-2", 0))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT lo_unlink($1)`)).
+		WithArgs(16400).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteFinishedJobs(finishedBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteFinishedJobs_UnlinkFailureKeepsJobs(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewJobRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM "jobs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_oid"}).AddRow("job-1", 16400))
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT lo_unlink($1)`)).
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	_, err := repo.DeleteFinishedJobs(time.Now())
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// applyLabelSelector narrows the query down to the servers whose labels match every requirement.
+// As in Kubernetes, != and notin also match servers that don't have the label at all.
+func applyLabelSelector(query *gorm.DB, selector domain.LabelSelector) *gorm.DB {
+	for _, requirement := range selector {
+		key := requirement.Key
+		switch requirement.Operator {
+		case domain.SelectorEquals:
+			query = query.Where("labels ->> ? = ?", key, requirement.Values[0])
+		case domain.SelectorNotEquals:
+			query = query.Where("labels ->> ? IS NULL OR labels ->> ? <> ?", key, key, requirement.Values[0])
+		case domain.SelectorIn:
+			query = query.Where("labels ->> ? IN ?", key, requirement.Values)
+		case domain.SelectorNotIn:
+			query = query.Where("labels ->> ? IS NULL OR labels ->> ? NOT IN ?", key, key, requirement.Values)
+		case domain.SelectorExists:
+			query = query.Where("labels ->> ? IS NOT NULL", key)
+		case domain.SelectorDoesNotExist:
+			query = query.Where("labels ->> ? IS NULL", key)
+		}
+	}
+	return query
+}
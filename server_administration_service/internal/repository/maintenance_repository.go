@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MaintenanceRepository interface {
+	CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error)
+	GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error)
+	GetMaintenanceWindows() ([]domain.MaintenanceWindow, error)
+	GetServerMaintenanceWindows(serverID string, t time.Time) ([]domain.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(window *domain.MaintenanceWindow) error
+	DeleteMaintenanceWindow(id string) error
+}
+
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) MaintenanceRepository {
+	return &maintenanceRepository{
+		db: db,
+	}
+}
+
+func (r *maintenanceRepository) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	if err := r.db.Create(window).Error; err != nil {
+		return "", err
+	}
+
+	return window.ID, nil
+}
+
+func (r *maintenanceRepository) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	var window domain.MaintenanceWindow
+	if err := r.db.Where("id = ?", id).First(&window).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.resolveGroupServers(&window); err != nil {
+		return nil, err
+	}
+
+	return &window, nil
+}
+
+func (r *maintenanceRepository) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	var windows []domain.MaintenanceWindow
+	if err := r.db.Order("start_time").Find(&windows).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range windows {
+		if err := r.resolveGroupServers(&windows[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return windows, nil
+}
+
+// GetServerMaintenanceWindows returns the windows that may be active for a server at t: those targeting it
+// directly or through one of its groups or their ancestors, started by t and, unless recurring, not over yet.
+// The group servers of the windows aren't resolved.
+func (r *maintenanceRepository) GetServerMaintenanceWindows(serverID string, t time.Time) ([]domain.MaintenanceWindow, error) {
+	var windows []domain.MaintenanceWindow
+	err := r.db.Raw(`WITH RECURSIVE ancestors AS (
+		SELECT group_id AS id FROM server_group_members WHERE server_id = ?
+		UNION
+		SELECT g.parent_id FROM server_groups g JOIN ancestors a ON g.id = a.id WHERE g.parent_id IS NOT NULL
+	)
+	SELECT * FROM maintenance_windows
+	WHERE start_time <= ? AND (cron_expression <> '' OR end_time > ?)
+	AND (server_ids::jsonb @> jsonb_build_array(?::text)
+		OR EXISTS (SELECT 1 FROM ancestors a WHERE group_ids::jsonb @> jsonb_build_array(a.id::text)))
+	ORDER BY start_time`, serverID, t, t, serverID).
+		Scan(&windows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return windows, nil
+}
+
+// resolveGroupServers loads the servers of the window's groups and of all their subgroups.
+func (r *maintenanceRepository) resolveGroupServers(window *domain.MaintenanceWindow) error {
+	if len(window.GroupIDs) == 0 {
+		return nil
+	}
+
+	serverIDs, err := subtreeServerIDs(r.db, window.GroupIDs)
+	if err != nil {
+		return err
+	}
+
+	window.GroupServerIDs = serverIDs
+	return nil
+}
+
+func (r *maintenanceRepository) UpdateMaintenanceWindow(window *domain.MaintenanceWindow) error {
+	return r.db.Save(window).Error
+}
+
+func (r *maintenanceRepository) DeleteMaintenanceWindow(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&domain.MaintenanceWindow{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+)
+
+func TestCreateMaintenanceWindow_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+	window := &domain.MaintenanceWindow{
+		ID:        "window-1",
+		Name:      "Patching",
+		ServerIDs: []string{"srv-1"},
+		StartTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "maintenance_windows"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateMaintenanceWindow(window)
+	assert.NoError(t, err)
+	assert.Equal(t, "window-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMaintenanceWindow_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+
+	mock.ExpectQuery(`SELECT \* FROM "maintenance_windows" WHERE id = \$1`).
+		WithArgs("missing", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	window, err := repo.GetMaintenanceWindow("missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, window)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMaintenanceWindows_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "server_ids", "cron_expression", "duration_minutes"}).
+		AddRow("window-1", "Weekly reboot", `["srv-1","srv-2"]`, "0 3 * * 0", 30)
+	mock.ExpectQuery(`SELECT \* FROM "maintenance_windows" ORDER BY start_time`).WillReturnRows(rows)
+
+	windows, err := repo.GetMaintenanceWindows()
+	assert.NoError(t, err)
+	assert.Len(t, windows, 1)
+	assert.Equal(t, []string{"srv-1", "srv-2"}, windows[0].ServerIDs)
+	assert.True(t, windows[0].IsRecurring())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMaintenanceWindows_ResolvesGroups(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "server_ids", "group_ids"}).
+		AddRow("window-1", "Rack move", `["srv-1"]`, `["rack-1"]`)
+	mock.ExpectQuery(`SELECT \* FROM "maintenance_windows" ORDER BY start_time`).WillReturnRows(rows)
+	mock.ExpectQuery(`WITH RECURSIVE subtree AS`).
+		WithArgs("rack-1").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+
+	windows, err := repo.GetMaintenanceWindows()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1", "srv-2"}, windows[0].TargetServerIDs())
+	assert.True(t, windows[0].AppliesTo("srv-2"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetServerMaintenanceWindows_FiltersByServerAndGroups(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+	at := time.Date(2025, 3, 10, 2, 15, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "server_ids", "group_ids"}).
+		AddRow("window-1", "Rack move", `[]`, `["rack-1"]`)
+	mock.ExpectQuery(`WITH RECURSIVE ancestors AS .* FROM maintenance_windows WHERE start_time <= \$2 AND \(cron_expression <> '' OR end_time > \$3\)`).
+		WithArgs("srv-2", at, at, "srv-2").
+		WillReturnRows(rows)
+
+	windows, err := repo.GetServerMaintenanceWindows("srv-2", at)
+	assert.NoError(t, err)
+	assert.Len(t, windows, 1)
+	assert.Equal(t, []string{"rack-1"}, windows[0].GroupIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteMaintenanceWindow_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewMaintenanceRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "maintenance_windows" WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.DeleteMaintenanceWindow("missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,22 +1,37 @@
 package repository
 
 import (
-	"fmt"
+	"encoding/json"
 	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
+	"strings"
+	"time"
 
 	"github.com/flashhhhh/pkg/logging"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ServerCRUDRepository interface {
 	CreateServer(server *domain.Server) (string, error)
-	CreateServers(servers []domain.Server) ([]domain.Server, []domain.Server, error)
-	ViewServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]domain.Server, error)
-	UpdateServer(server_id string, updatedData map[string]interface{}) error
-	DeleteServer(serverID string) error
+	CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error)
+	GetConflictingServers(serverIDs, serverNames, addresses []string) ([]domain.Server, error)
+	UpdateServers(servers []domain.Server) error
+	GetAllServers() ([]domain.Server, error)
+	ViewServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error)
+	StreamServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort, fn func(server domain.Server) error) error
+	ViewServersByKey(serverFilter *dto.ServerFilter, keyset dto.ServerKeyset) ([]domain.Server, error)
+	CountServers(serverFilter *dto.ServerFilter) (int64, error)
+	UpdateServer(server_id string, updatedData map[string]interface{}, version int64) error
+	DeleteServer(serverID string, version int64) error
+	UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error)
+	DeleteServersByID(serverIDs []string) ([]string, error)
+	Transaction(fn func(tx ServerCRUDRepository) error) error
 }
 
+// Rows per INSERT of CreateServers, 10 parameters each stay well below the 65535 Postgres allows in a statement
+const createServersBatchSize = 1000
+
 type serverCRUDRepository struct {
 	db *gorm.DB
 }
@@ -53,24 +68,44 @@ func (r *serverCRUDRepository) CreateServer(server *domain.Server) (string, erro
 	return server.ServerID, nil
 }
 
-func (r *serverCRUDRepository) CreateServers(servers []domain.Server) ([]domain.Server, []domain.Server, error) {
-	query := `
-		INSERT INTO servers (server_id, server_name, status, ipv4) VALUES 
-	`
+// CreateServers inserts the servers in parameterized batches of createServersBatchSize rows, all in one
+// transaction, skipping the ones whose ID, name or primary address is taken. onBatch, when not nil, is
+// told how many servers were written so far after every batch.
+// It returns the servers inserted and the ones skipped.
+func (r *serverCRUDRepository) CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error) {
+	var result []domain.Server
+	now := time.Now()
 
-	for i, server := range servers {
-		query += fmt.Sprintf("('%s', '%s', '%s', '%s')",
-			server.ServerID, server.ServerName, server.Status, server.IPv4)
-		
-		if i < len(servers)-1 {
-			query += ", "
-		}
-	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(servers); start += createServersBatchSize {
+			end := start + createServersBatchSize
+			if end > len(servers) {
+				end = len(servers)
+			}
 
-	query += " ON CONFLICT DO NOTHING RETURNING *"
+			values := make([]string, 0, end-start)
+			args := make([]interface{}, 0, (end-start)*10)
+			for _, server := range servers[start:end] {
+				values = append(values, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+				args = append(args, server.ServerID, server.ServerName, server.Status, server.PrimaryAddress,
+					server.Addresses.JSON(), server.SLATarget, server.Labels.JSON(), server.Notes, now, now)
+			}
 
-	var result []domain.Server
-	err := r.db.Raw(query).Scan(&result).Error
+			query := "INSERT INTO servers (server_id, server_name, status, primary_address, addresses, sla_target, labels, notes, created_time, last_updated) VALUES " +
+				strings.Join(values, ", ") + " ON CONFLICT DO NOTHING RETURNING *"
+
+			var batch []domain.Server
+			if err := tx.Raw(query, args...).Scan(&batch).Error; err != nil {
+				return err
+			}
+			result = append(result, batch...)
+
+			if onBatch != nil {
+				onBatch(end)
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Error inserting servers: " + err.Error(), "ERROR")
 		return nil, nil, err
@@ -94,8 +129,174 @@ func (r *serverCRUDRepository) CreateServers(servers []domain.Server) ([]domain.
 	return insertedServer, nonInsertedServer, nil
 }
 
-func (r *serverCRUDRepository) ViewServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]domain.Server, error) {
+// GetConflictingServers returns the servers that have one of the IDs, names or addresses, the addresses
+// being compared in lower case with every address of the servers. Servers in the trash are included,
+// they keep their ID, name and addresses until they're purged.
+func (r *serverCRUDRepository) GetConflictingServers(serverIDs, serverNames, addresses []string) ([]domain.Server, error) {
+	var servers []domain.Server
+	err := r.db.Unscoped().Where("server_id IN ? OR server_name IN ? OR lower(primary_address) IN ? OR "+
+		"EXISTS (SELECT 1 FROM jsonb_array_elements(servers.addresses) AS a WHERE lower(a->>'address') IN ?)",
+		serverIDs, serverNames, addresses, addresses).Find(&servers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+// UpdateServers overwrites the name, addresses, SLA target and labels of existing servers in one transaction.
+func (r *serverCRUDRepository) UpdateServers(servers []domain.Server) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, server := range servers {
+			if err := tx.Model(&domain.Server{}).
+				Where("server_id = ?", server.ServerID).
+				Updates(nextVersion(map[string]interface{}{
+					"server_name":     server.ServerName,
+					"primary_address": server.PrimaryAddress,
+					"addresses":       server.Addresses,
+					"sla_target":      server.SLATarget,
+					"labels":          server.Labels,
+					"notes":           server.Notes,
+				})).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *serverCRUDRepository) GetAllServers() ([]domain.Server, error) {
+	var servers []domain.Server
+	if err := r.db.Order("server_id").Find(&servers).Error; err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+func (r *serverCRUDRepository) ViewServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error) {
 	var servers []domain.Server
+
+	err := orderServers(r.filterServers(serverFilter), sort).Offset(from).Limit(to - from).Find(&servers).Error
+	if err != nil {
+		return nil, filterQueryError(err)
+	}
+
+	return servers, nil
+}
+
+// StreamServers hands the servers ViewServers would return to fn one at a time, read through a cursor
+// so that they never all sit in memory. It stops at the first error of fn.
+func (r *serverCRUDRepository) StreamServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort, fn func(server domain.Server) error) error {
+	rows, err := orderServers(r.filterServers(serverFilter), sort).Offset(from).Limit(to - from).Rows()
+	if err != nil {
+		return filterQueryError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var server domain.Server
+		if err := r.db.ScanRows(rows, &server); err != nil {
+			return err
+		}
+		if err := fn(server); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// orderServers sorts the query on the keys of sort, by server ID when it has none. Columns are quoted
+// as identifiers rather than written into the SQL as they are.
+func orderServers(query *gorm.DB, sort domain.ServerSort) *gorm.DB {
+	if len(sort) == 0 {
+		sort = domain.ServerSort{{Column: "server_id"}}
+	}
+	columns := make([]clause.OrderByColumn, 0, len(sort))
+	for _, key := range sort {
+		columns = append(columns, clause.OrderByColumn{Column: clause.Column{Name: key.Column}, Desc: key.Desc})
+	}
+	return query.Order(clause.OrderBy{Columns: columns})
+}
+
+// ViewServersByKey returns the servers of a keyset page in the order of its sort, which ends on server
+// ID. Seeking on the sort columns costs the same at any depth, unlike an offset. Columns are quoted as
+// identifiers, the sort must still have been parsed so that they exist.
+func (r *serverCRUDRepository) ViewServersByKey(serverFilter *dto.ServerFilter, keyset dto.ServerKeyset) ([]domain.Server, error) {
+	sort := make(domain.ServerSort, len(keyset.Sort))
+	for i, key := range keyset.Sort {
+		// Read back from the key, then turn the page around
+		sort[i] = domain.SortKey{Column: key.Column, Desc: key.Desc != keyset.Backward}
+	}
+
+	query := r.filterServers(serverFilter)
+	if keyset.After != nil {
+		query = query.Where(afterServerKey(sort, keyset.After.Values))
+	}
+
+	var servers []domain.Server
+	if err := orderServers(query, sort).Limit(keyset.Limit).Find(&servers).Error; err != nil {
+		return nil, filterQueryError(err)
+	}
+
+	if keyset.Backward {
+		for i, j := 0, len(servers)-1; i < j; i, j = i+1, j-1 {
+			servers[i], servers[j] = servers[j], servers[i]
+		}
+	}
+	return servers, nil
+}
+
+// afterServerKey matches the servers that come after values in the sort. When every key goes the
+// same way that is a single row comparison, which an index on the columns can serve; otherwise each
+// key in turn decides between servers equal on the keys before it.
+func afterServerKey(sort domain.ServerSort, values []interface{}) clause.Expression {
+	columns := make([]interface{}, len(sort))
+	for i, key := range sort {
+		columns[i] = clause.Column{Name: key.Column}
+	}
+
+	comparison := func(key domain.SortKey) string {
+		if key.Desc {
+			return " < "
+		}
+		return " > "
+	}
+
+	mixed := false
+	for _, key := range sort {
+		mixed = mixed || key.Desc != sort[0].Desc
+	}
+	if !mixed {
+		return clause.Expr{SQL: "?" + comparison(sort[0]) + "?", Vars: []interface{}{columns, values}}
+	}
+
+	terms := make([]string, 0, len(sort))
+	var vars []interface{}
+	for i, key := range sort {
+		var term strings.Builder
+		for j := 0; j < i; j++ {
+			term.WriteString("? = ? AND ")
+			vars = append(vars, columns[j], values[j])
+		}
+		term.WriteString("?" + comparison(key) + "?")
+		vars = append(vars, columns[i], values[i])
+		terms = append(terms, "("+term.String()+")")
+	}
+	return clause.Expr{SQL: strings.Join(terms, " OR "), Vars: vars}
+}
+
+// CountServers returns how many servers match the filter.
+func (r *serverCRUDRepository) CountServers(serverFilter *dto.ServerFilter) (int64, error) {
+	var count int64
+	if err := r.filterServers(serverFilter).Count(&count).Error; err != nil {
+		return 0, filterQueryError(err)
+	}
+	return count, nil
+}
+
+func (r *serverCRUDRepository) filterServers(serverFilter *dto.ServerFilter) *gorm.DB {
 	query := r.db.Model(&domain.Server{})
 
 	if serverFilter.ServerID != "" {
@@ -107,36 +308,107 @@ func (r *serverCRUDRepository) ViewServers(serverFilter *dto.ServerFilter, from,
 	}
 
 	if serverFilter.Status != "" {
-		query = query.Where("status = ?", serverFilter.Status)
+		query = query.Where("status = ?", domain.NormalizeStatus(serverFilter.Status))
 	}
 
-	if serverFilter.IPv4 != "" {
-		query = query.Where("ipv4 = ?", serverFilter.IPv4)
+	if serverFilter.Address != "" {
+		contains, _ := json.Marshal([]map[string]string{{"address": serverFilter.Address}})
+		query = query.Where("primary_address = ? OR addresses @> ?::jsonb", serverFilter.Address, string(contains))
 	}
 
-	// sortedColumn is mandatory
-	err := query.Order(sortedColumn + " " + order).Offset(from).Limit(to - from).Find(&servers).Error
-	if err != nil {
-		return nil, err
+	return applyFilterExpression(applyLabelSelector(query, serverFilter.LabelSelector), serverFilter.Expression)
+}
+
+// UpdateServer writes the changes to the server and moves it to its next version. A version other than 0
+// is the one the changes were made against. gorm.ErrRecordNotFound is returned when nothing was written,
+// because there's no such server or it's no longer at that version.
+func (r *serverCRUDRepository) UpdateServer(serverID string, updatedData map[string]interface{}, version int64) error {
+	query := r.db.Model(&domain.Server{}).Where("server_id = ?", serverID)
+	if version != 0 {
+		query = query.Where("version = ?", version)
 	}
 
-	return servers, nil
+	result := query.Updates(nextVersion(updatedData))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
 
-func (r *serverCRUDRepository) UpdateServer(serverID string, updatedData map[string]interface{}) error {
-	if err := r.db.Model(&domain.Server{}).
-		Where("server_id = ?", serverID).
-		Updates(updatedData).Error; err != nil {
-			return err
-		}
+// DeleteServer moves the server to the trash. A version other than 0 is the one the deletion was decided
+// on, as in UpdateServer.
+func (r *serverCRUDRepository) DeleteServer(serverID string, version int64) error {
+	query := r.db.Where("server_id = ?", serverID)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&domain.Server{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
 	return nil
 }
 
-func (r *serverCRUDRepository) DeleteServer(serverID string) error {
-	if err := r.db.Where("server_id = ?", serverID).Delete(&domain.Server{}).Error; err != nil {
-		return err
+func (r *serverCRUDRepository) UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error) {
+	var servers []domain.Server
+	if err := r.db.Model(&servers).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "server_id"}}}).
+		Where("server_id IN ?", serverIDs).
+		Updates(nextVersion(updatedData)).Error; err != nil {
+		return nil, err
 	}
 
-	return nil
-}
\ No newline at end of file
+	return serverIDsOf(servers), nil
+}
+
+// DeleteServersByID moves every server of serverIDs to the trash in a single statement. It returns the
+// IDs of the servers that were deleted.
+func (r *serverCRUDRepository) DeleteServersByID(serverIDs []string) ([]string, error) {
+	var servers []domain.Server
+	if err := r.db.Clauses(clause.Returning{Columns: []clause.Column{{Name: "server_id"}}}).
+		Where("server_id IN ?", serverIDs).
+		Delete(&servers).Error; err != nil {
+		return nil, err
+	}
+
+	return serverIDsOf(servers), nil
+}
+
+// Transaction runs fn with a repository whose writes all go to one transaction, committed when fn
+// returns nil and rolled back otherwise.
+func (r *serverCRUDRepository) Transaction(fn func(tx ServerCRUDRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&serverCRUDRepository{db: tx})
+	})
+}
+
+// nextVersion adds the version bump every edit of a server makes to its changes. A change of the status
+// alone, such as a decommission, isn't an edit and leaves the version as it is.
+func nextVersion(updatedData map[string]interface{}) map[string]interface{} {
+	if _, ok := updatedData["status"]; ok && len(updatedData) == 1 {
+		return updatedData
+	}
+	updates := make(map[string]interface{}, len(updatedData)+1)
+	for field, value := range updatedData {
+		updates[field] = value
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	return updates
+}
+
+func serverIDsOf(servers []domain.Server) []string {
+	serverIDs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		serverIDs = append(serverIDs, server.ServerID)
+	}
+	return serverIDs
+}
@@ -1,10 +1,16 @@
 package repository_test
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 
 	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
@@ -20,7 +26,7 @@ func TestCreateServer_Success(t *testing.T) {
 		ServerID:   "srv-1",
 		ServerName: "TestServer",
 		Status:     "On",
-		IPv4:       "192.168.1.1",
+		PrimaryAddress: "192.168.1.1",
 	}
 
 	mock.ExpectBegin()
@@ -31,7 +37,13 @@ func TestCreateServer_Success(t *testing.T) {
 			server.Status,
 			sqlmock.AnyArg(), // created_time
 			sqlmock.AnyArg(), // last_updated
-			server.IPv4,
+			server.PrimaryAddress,
+			sqlmock.AnyArg(), // addresses
+			sqlmock.AnyArg(), // sla_target
+			sqlmock.AnyArg(), // labels
+			"",               // notes
+			nil,              // deleted_at
+			int64(1),         // version
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
@@ -51,7 +63,7 @@ func TestCreateServer_FailDB(t *testing.T) {
 		ServerID: "server-1",
 		ServerName: "Server 1",
 		Status: "Off",
-		IPv4: "192.168.1.1",
+		PrimaryAddress: "192.168.1.1",
 	}
 
 	mock.ExpectBegin()
@@ -62,7 +74,13 @@ func TestCreateServer_FailDB(t *testing.T) {
 			server.Status,
 			sqlmock.AnyArg(),
 			sqlmock.AnyArg(),
-			server.IPv4,
+			server.PrimaryAddress,
+			sqlmock.AnyArg(), // addresses
+			sqlmock.AnyArg(), // sla_target
+			sqlmock.AnyArg(), // labels
+			"",               // notes
+			nil,              // deleted_at
+			int64(1),         // version
 		).
 		WillReturnError(assert.AnError)
 	mock.ExpectRollback()
@@ -82,25 +100,30 @@ func TestCreateServers_Success(t *testing.T) {
 			ServerID:   "srv-1",
 			ServerName: "Server1",
 			Status:     "On",
-			IPv4:       "192.168.1.1",
+			PrimaryAddress: "192.168.1.1",
 		},
 		{
 			ServerID:   "srv-2",
 			ServerName: "Server2",
 			Status:     "Off",
-			IPv4:       "192.168.1.2",
+			PrimaryAddress: "192.168.1.2",
+			Notes:      "Rack 5",
 		},
 	}
 
-	// Build expected SQL
-	expectedSQL := `INSERT INTO servers \(server_id, server_name, status, ipv4\) VALUES \('srv-1', 'Server1', 'On', '192.168.1.1'\), \('srv-2', 'Server2', 'Off', '192.168.1.2'\) ON CONFLICT DO NOTHING RETURNING \*`
+	expectedSQL := `INSERT INTO servers \(server_id, server_name, status, primary_address, addresses, sla_target, labels, notes, created_time, last_updated\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10\), \(\$11, \$12, \$13, \$14, \$15, \$16, \$17, \$18, \$19, \$20\) ON CONFLICT DO NOTHING RETURNING \*`
 
-	rows := sqlmock.NewRows([]string{"server_id", "server_name", "status", "ipv4"}).
+	rows := sqlmock.NewRows([]string{"server_id", "server_name", "status", "primary_address"}).
 		AddRow("srv-1", "Server1", "On", "192.168.1.1")
 
-	mock.ExpectQuery(expectedSQL).WillReturnRows(rows)
+	mock.ExpectBegin()
+	mock.ExpectQuery(expectedSQL).
+		WithArgs("srv-1", "Server1", "On", "192.168.1.1", "[]", float64(0), "{}", "", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			"srv-2", "Server2", "Off", "192.168.1.2", "[]", float64(0), "{}", "Rack 5", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(rows)
+	mock.ExpectCommit()
 
-	inserted, nonInserted, err := repo.CreateServers(servers)
+	inserted, nonInserted, err := repo.CreateServers(servers, nil)
 	assert.NoError(t, err)
 	assert.Len(t, inserted, 1)
 	assert.Len(t, nonInserted, 1)
@@ -119,20 +142,83 @@ func TestCreateServers_FailDB(t *testing.T) {
 			ServerID:   "srv-1",
 			ServerName: "Server1",
 			Status:     "On",
-			IPv4:       "192.168.1.1",
+			PrimaryAddress: "192.168.1.1",
 		},
 	}
 
-	expectedSQL := `INSERT INTO servers \(server_id, server_name, status, ipv4\) VALUES \('srv-1', 'Server1', 'On', '192.168.1.1'\) ON CONFLICT DO NOTHING RETURNING \*`
-	mock.ExpectQuery(expectedSQL).WillReturnError(assert.AnError)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO servers`).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
 
-	inserted, nonInserted, err := repo.CreateServers(servers)
+	inserted, nonInserted, err := repo.CreateServers(servers, nil)
 	assert.Error(t, err)
 	assert.Nil(t, inserted)
 	assert.Nil(t, nonInserted)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateServers_QuotesAreParameters(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+	servers := []domain.Server{
+		{
+			ServerID:   "srv-1",
+			ServerName: "O'Brien's box",
+			Status:     "On",
+			PrimaryAddress: "192.168.1.1",
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7, \$8, \$9, \$10\) ON CONFLICT DO NOTHING`).
+		WithArgs("srv-1", "O'Brien's box", "On", "192.168.1.1", "[]", float64(0), "{}", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name"}).AddRow("srv-1", "O'Brien's box"))
+	mock.ExpectCommit()
+
+	inserted, nonInserted, err := repo.CreateServers(servers, nil)
+	assert.NoError(t, err)
+	assert.Len(t, inserted, 1)
+	assert.Empty(t, nonInserted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateServers_Batches(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+	servers := make([]domain.Server, 2500)
+	for i := range servers {
+		servers[i] = domain.Server{
+			ServerID:   fmt.Sprintf("srv-%d", i),
+			ServerName: fmt.Sprintf("Server%d", i),
+			Status:     "On",
+			PrimaryAddress: fmt.Sprintf("10.0.%d.%d", i/256, i%256),
+		}
+	}
+
+	mock.ExpectBegin()
+	for _, size := range []int{1000, 1000, 500} {
+		rows := sqlmock.NewRows([]string{"server_id"})
+		for i := 0; i < size; i++ {
+			rows.AddRow("inserted")
+		}
+		mock.ExpectQuery(`INSERT INTO servers`).WillReturnRows(rows)
+	}
+	mock.ExpectCommit()
+
+	var progress []int
+	inserted, _, err := repo.CreateServers(servers, func(processed int) {
+		progress = append(progress, processed)
+	})
+	assert.NoError(t, err)
+	assert.Len(t, inserted, 2500)
+	assert.Equal(t, []int{1000, 2000, 2500}, progress)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestViewServers_Success(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
@@ -144,37 +230,231 @@ func TestViewServers_Success(t *testing.T) {
 		ServerID:   "srv-1",
 		ServerName: "Test",
 		Status:     "On",
-		IPv4:       "192.168.1.1",
+		Address:    "192.168.1.1",
 	}
 	from := 0
 	to := 10
-	sortedColumn := "server_id"
-	order := "asc"
+	sort := domain.ServerSort{{Column: "server_id"}}
 
 	// Build expected SQL with LIKE and WHEREs
-	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id = \$1 AND server_name LIKE \$2 AND status = \$3 AND ipv4 = \$4 ORDER BY server_id asc LIMIT \$5`).
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id = \$1 AND server_name LIKE \$2 AND status = \$3 AND \(primary_address = \$4 OR addresses @> \$5::jsonb\) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT \$6`).
 		WithArgs(
 			filter.ServerID,
 			"%"+filter.ServerName+"%",
-			filter.Status,
-			filter.IPv4,
+			"Up", // the legacy On filter maps onto Up
+
+			filter.Address,
+			`[{"address":"192.168.1.1"}]`,
 			to - from,
 		).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"server_id", "server_name", "status", "ipv4"}).
-				AddRow("srv-1", "TestServer", "On", "192.168.1.1"),
+			sqlmock.NewRows([]string{"server_id", "server_name", "status", "primary_address"}).
+				AddRow("srv-1", "TestServer", "Up", "192.168.1.1"),
 		)
 
-	servers, err := repo.ViewServers(filter, from, to, sortedColumn, order)
+	servers, err := repo.ViewServers(filter, from, to, sort)
 	assert.NoError(t, err)
 	assert.Len(t, servers, 1)
 	assert.Equal(t, "srv-1", servers[0].ServerID)
 	assert.Equal(t, "TestServer", servers[0].ServerName)
-	assert.Equal(t, "On", servers[0].Status)
-	assert.Equal(t, "192.168.1.1", servers[0].IPv4)
+	assert.Equal(t, "Up", servers[0].Status)
+	assert.Equal(t, "192.168.1.1", servers[0].PrimaryAddress)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServersByKey_After(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE status = \$1 AND \("server_name","server_id"\) > \(\$2,\$3\) AND "servers"."deleted_at" IS NULL ORDER BY "server_name","server_id" LIMIT \$4`).
+		WithArgs("Up", "web-1", "srv-1", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name"}).
+			AddRow("srv-2", "web-2").
+			AddRow("srv-3", "web-3"))
+
+	servers, err := repo.ViewServersByKey(&dto.ServerFilter{Status: "Up"}, dto.ServerKeyset{
+		Sort:  domain.ServerSort{{Column: "server_name"}, {Column: "server_id"}},
+		After: &dto.ServerKey{Values: []interface{}{"web-1", "srv-1"}}, Limit: 3,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, servers, 2)
+	assert.Equal(t, "srv-2", servers[0].ServerID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServersByKey_Backward(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	// Reads back from the key in the opposite order and returns the page in the sort order
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE \("server_id"\) > \(\$1\) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT \$2`).
+		WithArgs("srv-5", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).
+			AddRow("srv-6").
+			AddRow("srv-7"))
+
+	servers, err := repo.ViewServersByKey(&dto.ServerFilter{}, dto.ServerKeyset{
+		Sort:  domain.ServerSort{{Column: "server_id", Desc: true}},
+		After: &dto.ServerKey{Values: []interface{}{"srv-5"}}, Backward: true, Limit: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "srv-7", servers[0].ServerID)
+	assert.Equal(t, "srv-6", servers[1].ServerID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServersByKey_MixedOrders(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// No row comparison goes both ways: each key decides between servers equal on the keys before it
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE \(\("status" > \$1\) OR \("status" = \$2 AND "created_time" < \$3\) OR \("status" = \$4 AND "created_time" = \$5 AND "server_id" > \$6\)\) AND "servers"."deleted_at" IS NULL ORDER BY "status","created_time" DESC,"server_id" LIMIT \$7`).
+		WithArgs("Down", "Down", created, "Down", created, "srv-1", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-2"))
+
+	servers, err := repo.ViewServersByKey(&dto.ServerFilter{}, dto.ServerKeyset{
+		Sort:  domain.ServerSort{{Column: "status"}, {Column: "created_time", Desc: true}, {Column: "server_id"}},
+		After: &dto.ServerKey{Values: []interface{}{"Down", created, "srv-1"}}, Limit: 2,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, servers, 1)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCountServers(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "servers" WHERE server_name LIKE \$1`).
+		WithArgs("%web%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	count, err := repo.CountServers(&dto.ServerFilter{ServerName: "web"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServers_FilterExpression(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	expression, err := domain.ParseFilterExpression(`status in (Down, Off) AND (address = 10.1.0.0/16 OR labels.env = prod) AND NOT server_name ^= "tmp_" AND created_time <= 2024-01-31`)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE ((status IN ($1,$2)) AND ((EXISTS (SELECT 1 FROM jsonb_array_elements(addresses) AS a `+
+		`WHERE CASE WHEN a ->> 'type' IN ('ipv4', 'ipv6') THEN (a ->> 'address')::inet <<= $3::cidr ELSE false END)) OR (labels ->> $4 = $5)) `+
+		`AND (NOT COALESCE((server_name LIKE $6), false)) AND (created_time < $7)) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT $8`)).
+		// Legacy statuses are normalized, LIKE wildcards escaped and a date taken as the whole day
+		WithArgs("Down", "Down", "10.1.0.0/16", "env", "prod", `tmp\_%`, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+
+	servers, err := repo.ViewServers(&dto.ServerFilter{Expression: expression}, 0, 10, nil)
+	assert.NoError(t, err)
+	assert.Len(t, servers, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServers_RegularExpressionRejectedByDatabase(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	// Go's regexp takes \pL, Postgres doesn't
+	expression, err := domain.ParseFilterExpression(`server_name ~ "\\pL"`)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE server_name ~ $1`)).
+		WillReturnError(&pgconn.PgError{Code: "2201B", Message: `invalid regular expression: invalid escape \ sequence`})
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "servers" WHERE server_name ~ $1`)).
+		WillReturnError(&pgconn.PgError{Code: "2201B", Message: `invalid regular expression: invalid escape \ sequence`})
+
+	_, err = repo.ViewServers(&dto.ServerFilter{Expression: expression}, 0, 10, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidFilterExpression)
+	_, err = repo.CountServers(&dto.ServerFilter{Expression: expression})
+	assert.ErrorIs(t, err, domain.ErrInvalidFilterExpression)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestViewServers_FilterExpressionNegations(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	expression, err := domain.ParseFilterExpression(`address !~ "^10\\." OR labels.team != ops OR address not in (10.0.0.1, 192.168.0.0/16) OR sla_target >= 99.5`)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE ((NOT EXISTS (SELECT 1 FROM jsonb_array_elements(addresses) AS a WHERE a ->> 'address' ~ $1)) `+
+		`OR (labels ->> $2 IS NULL OR labels ->> $3 <> $4) `+
+		`OR (NOT COALESCE(((primary_address = $5 OR addresses @> $6::jsonb) OR (EXISTS (SELECT 1 FROM jsonb_array_elements(addresses) AS a `+
+		`WHERE CASE WHEN a ->> 'type' IN ('ipv4', 'ipv6') THEN (a ->> 'address')::inet <<= $7::cidr ELSE false END))), false)) `+
+		`OR (sla_target >= $8)) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT $9`)).
+		WithArgs(`^10\.`, "team", "team", "ops", "10.0.0.1", `[{"address":"10.0.0.1"}]`, "192.168.0.0/16", 99.5, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}))
+
+	_, err = repo.ViewServers(&dto.ServerFilter{Expression: expression}, 0, 10, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamServers_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE status = \$1 AND "servers"."deleted_at" IS NULL ORDER BY "server_name" DESC,"server_id" LIMIT \$2 OFFSET \$3`).
+		WithArgs("Up", 10, 10).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"server_id", "server_name", "status", "addresses", "labels"}).
+				AddRow("srv-2", "Server2", "Up", `[{"type":"ipv4","address":"10.0.0.2","primary":true}]`, `{"env":"prod"}`).
+				AddRow("srv-1", "Server1", "Up", `[]`, `{}`),
+		)
+
+	var servers []domain.Server
+	err := repo.StreamServers(&dto.ServerFilter{Status: "Up"}, 10, 20, domain.ServerSort{{Column: "server_name", Desc: true}, {Column: "server_id"}}, func(server domain.Server) error {
+		servers = append(servers, server)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, servers, 2)
+	assert.Equal(t, "srv-2", servers[0].ServerID)
+	assert.Equal(t, "10.0.0.2", servers[0].Addresses[0].Address)
+	assert.Equal(t, "prod", servers[0].Labels["env"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamServers_StopsOnError(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectQuery(`SELECT \* FROM "servers"`).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+
+	calls := 0
+	err := repo.StreamServers(&dto.ServerFilter{}, 0, 10, nil, func(server domain.Server) error {
+		calls++
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+}
+
 func TestViewServers_FailDB(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
@@ -185,26 +465,26 @@ func TestViewServers_FailDB(t *testing.T) {
 	filter := &dto.ServerFilter{
 		ServerID:   "srv-1",
 		ServerName: "Test",
-		Status:     "On",
-		IPv4:       "192.168.1.1",
+		Status:     "Up",
+		Address:    "192.168.1.1",
 	}
 	from := 0
 	to := 10
-	sortedColumn := "server_id"
-	order := "asc"
+	sort := domain.ServerSort{{Column: "server_id"}}
 
 	// Build expected SQL with LIKE and WHEREs
-	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id = \$1 AND server_name LIKE \$2 AND status = \$3 AND ipv4 = \$4 ORDER BY server_id asc LIMIT \$5`).
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id = \$1 AND server_name LIKE \$2 AND status = \$3 AND \(primary_address = \$4 OR addresses @> \$5::jsonb\) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT \$6`).
 		WithArgs(
 			filter.ServerID,
 			"%"+filter.ServerName+"%",
 			filter.Status,
-			filter.IPv4,
+			filter.Address,
+			`[{"address":"192.168.1.1"}]`,
 			to - from,
 		).
 		WillReturnError(assert.AnError)
 
-	_, err := repo.ViewServers(filter, from, to, sortedColumn, order)
+	_, err := repo.ViewServers(filter, from, to, sort)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -232,7 +512,7 @@ func TestUpdateServer_Success(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err := repo.UpdateServer(serverID, updatedData)
+	err := repo.UpdateServer(serverID, updatedData, 0)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -257,11 +537,28 @@ func TestUpdateServer_FailDB(t *testing.T) {
 		WillReturnError(assert.AnError)
 	mock.ExpectRollback()
 
-	err := repo.UpdateServer(serverID, updatedData)
+	err := repo.UpdateServer(serverID, updatedData, 0)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateServer_VersionConflict(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "server_name"=$1,"version"=version + 1,"last_updated"=$2 WHERE server_id = $3 AND version = $4 AND "servers"."deleted_at" IS NULL`)).
+		WithArgs("web-01", sqlmock.AnyArg(), "srv-1", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.UpdateServer("srv-1", map[string]interface{}{"server_name": "web-01"}, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteServer_Success(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
@@ -269,20 +566,37 @@ func TestDeleteServer_Success(t *testing.T) {
 	repo := repository.NewServerCRUDRepository(gdb)
 	serverID := "srv-1"
 
-	// Expect update
 	mock.ExpectBegin()
-	mock.ExpectExec(`DELETE FROM "servers"`).
+	// Deleting moves the server to the trash
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1 WHERE server_id = $2 AND "servers"."deleted_at" IS NULL`)).
 		WithArgs(
+			sqlmock.AnyArg(),
 			serverID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err := repo.DeleteServer(serverID)
+	err := repo.DeleteServer(serverID, 0)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDeleteServer_Versioned(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1 WHERE server_id = $2 AND version = $3 AND "servers"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), "srv-1", int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.DeleteServer("srv-1", 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteServer_FailDB(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
@@ -290,16 +604,177 @@ func TestDeleteServer_FailDB(t *testing.T) {
 	repo := repository.NewServerCRUDRepository(gdb)
 	serverID := "srv-1"
 
-	// Expect update
 	mock.ExpectBegin()
-	mock.ExpectExec(`DELETE FROM "servers"`).
+	// Deleting moves the server to the trash
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1 WHERE server_id = $2 AND "servers"."deleted_at" IS NULL`)).
 		WithArgs(
+			sqlmock.AnyArg(),
 			serverID,
 		).
 		WillReturnError(assert.AnError)
 	mock.ExpectRollback()
 
-	err := repo.DeleteServer(serverID)
+	err := repo.DeleteServer(serverID, 0)
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+func TestViewServers_LabelSelector(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	selector, err := domain.ParseLabelSelector("env=prod,role in (db,cache),!deprecated,tier!=edge")
+	assert.NoError(t, err)
+	filter := &dto.ServerFilter{LabelSelector: selector}
+
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE labels ->> \$1 = \$2 AND labels ->> \$3 IN \(\$4,\$5\) AND labels ->> \$6 IS NULL AND \(labels ->> \$7 IS NULL OR labels ->> \$8 <> \$9\) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT \$10`).
+		WithArgs("env", "prod", "role", "db", "cache", "deprecated", "tier", "tier", "edge", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "labels"}).AddRow("srv-1", `{"env":"prod","role":"db"}`))
+
+	servers, err := repo.ViewServers(filter, 0, 10, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, servers, 1) {
+		assert.Equal(t, domain.Labels{"env": "prod", "role": "db"}, servers[0].Labels)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetConflictingServers_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id IN \(\$1\) OR server_name IN \(\$2\) OR lower\(primary_address\) IN \(\$3,\$4\) OR EXISTS \(SELECT 1 FROM jsonb_array_elements\(servers.addresses\) AS a WHERE lower\(a->>'address'\) IN \(\$5,\$6\)\)`).
+		WithArgs("srv-1", "Server1", "10.0.0.1", "srv-1.example.com", "10.0.0.1", "srv-1.example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name", "primary_address"}).AddRow("old-1", "Old", "10.0.0.1"))
+
+	servers, err := repo.GetConflictingServers([]string{"srv-1"}, []string{"Server1"}, []string{"10.0.0.1", "srv-1.example.com"})
+	assert.NoError(t, err)
+	if assert.Len(t, servers, 1) {
+		assert.Equal(t, "old-1", servers[0].ServerID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateServers_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "addresses"=$1,"labels"=$2,"notes"=$3,"primary_address"=$4,"server_name"=$5,"sla_target"=$6,"version"=version + 1,"last_updated"=$7 WHERE server_id = $8`)).
+		WithArgs(`[{"type":"ipv4","address":"10.0.0.1","primary":true}]`, `{}`, "Rack 5", "10.0.0.1", "Server1", 99.9, sqlmock.AnyArg(), "srv-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.UpdateServers([]domain.Server{{
+		ServerID:       "srv-1",
+		ServerName:     "Server1",
+		PrimaryAddress: "10.0.0.1",
+		Addresses:      domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}},
+		SLATarget:      99.9,
+		Labels:         domain.Labels{},
+		Notes:          "Rack 5",
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransaction_RollsBack(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	// Both writes go to the same transaction, the failed second one takes back the first
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "servers" SET "status"=\$1,"last_updated"=\$2 WHERE server_id IN \(\$3\)`).
+		WithArgs(domain.StatusDecommissioned, sqlmock.AnyArg(), "srv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+	mock.ExpectQuery(`UPDATE "servers" SET "deleted_at"=\$1 WHERE server_id IN \(\$2\)`).
+		WithArgs(sqlmock.AnyArg(), "srv-2").
+		WillReturnError(errors.New("db error"))
+	mock.ExpectRollback()
+
+	err := repo.Transaction(func(tx repository.ServerCRUDRepository) error {
+		if _, err := tx.UpdateServersByID([]string{"srv-1"}, map[string]interface{}{"status": domain.StatusDecommissioned}); err != nil {
+			return err
+		}
+		_, err := tx.DeleteServersByID([]string{"srv-2"})
+		return err
+	})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateServersByID_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "servers" SET "notes"=$1,"version"=version + 1,"last_updated"=$2 WHERE server_id IN ($3,$4) AND "servers"."deleted_at" IS NULL RETURNING "server_id"`)).
+		WithArgs("racked in B2", sqlmock.AnyArg(), "srv-1", "srv-2").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+	mock.ExpectCommit()
+
+	updated, err := repo.UpdateServersByID([]string{"srv-1", "srv-2"}, map[string]interface{}{"notes": "racked in B2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1"}, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateServersByID_StatusOnlyKeepsVersion(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "servers" SET "status"=$1,"last_updated"=$2 WHERE server_id IN ($3) AND "servers"."deleted_at" IS NULL RETURNING "server_id"`)).
+		WithArgs(domain.StatusDecommissioned, sqlmock.AnyArg(), "srv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+	mock.ExpectCommit()
+
+	updated, err := repo.UpdateServersByID([]string{"srv-1"}, map[string]interface{}{"status": domain.StatusDecommissioned})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1"}, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteServersByID_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1 WHERE server_id IN ($2,$3) AND "servers"."deleted_at" IS NULL RETURNING "server_id"`)).
+		WithArgs(sqlmock.AnyArg(), "srv-1", "srv-2").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+	mock.ExpectCommit()
+
+	deleted, err := repo.DeleteServersByID([]string{"srv-1", "srv-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1", "srv-2"}, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteServersByID_FailDB(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerCRUDRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE "servers"`).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	_, err := repo.DeleteServersByID([]string{"srv-1"})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"server_administration_service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ServerGroupRepository interface {
+	CreateGroup(group *domain.ServerGroup) (string, error)
+	GetGroup(id string) (*domain.ServerGroup, error)
+	GetGroups() ([]domain.ServerGroup, error)
+	UpdateGroup(group *domain.ServerGroup) error
+	DeleteGroup(id string) error
+	GetMembers() ([]domain.ServerGroupMember, error)
+	GetGroupMembers(groupID string) ([]string, error)
+	AddMembers(groupID string, serverIDs []string) error
+	RemoveMember(groupID, serverID string) error
+}
+
+type serverGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewServerGroupRepository(db *gorm.DB) ServerGroupRepository {
+	return &serverGroupRepository{
+		db: db,
+	}
+}
+
+func (r *serverGroupRepository) CreateGroup(group *domain.ServerGroup) (string, error) {
+	if err := r.db.Create(group).Error; err != nil {
+		return "", err
+	}
+
+	return group.ID, nil
+}
+
+func (r *serverGroupRepository) GetGroup(id string) (*domain.ServerGroup, error) {
+	var group domain.ServerGroup
+	if err := r.db.Where("id = ?", id).First(&group).Error; err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+func (r *serverGroupRepository) GetGroups() ([]domain.ServerGroup, error) {
+	var groups []domain.ServerGroup
+	if err := r.db.Order("name").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (r *serverGroupRepository) UpdateGroup(group *domain.ServerGroup) error {
+	return r.db.Save(group).Error
+}
+
+// DeleteGroup removes the group together with its memberships.
+func (r *serverGroupRepository) DeleteGroup(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", id).Delete(&domain.ServerGroupMember{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&domain.ServerGroup{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *serverGroupRepository) GetMembers() ([]domain.ServerGroupMember, error) {
+	var members []domain.ServerGroupMember
+	if err := r.db.Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *serverGroupRepository) GetGroupMembers(groupID string) ([]string, error) {
+	var serverIDs []string
+	if err := r.db.Model(&domain.ServerGroupMember{}).
+		Where("group_id = ?", groupID).
+		Order("server_id").
+		Pluck("server_id", &serverIDs).Error; err != nil {
+		return nil, err
+	}
+
+	return serverIDs, nil
+}
+
+// AddMembers puts the servers in the group; servers already in it are left alone.
+// It fails with gorm.ErrRecordNotFound when one of the servers doesn't exist.
+func (r *serverGroupRepository) AddMembers(groupID string, serverIDs []string) error {
+	var numServers int64
+	if err := r.db.Model(&domain.Server{}).Where("server_id IN ?", serverIDs).Count(&numServers).Error; err != nil {
+		return err
+	}
+	if int(numServers) != len(serverIDs) {
+		return gorm.ErrRecordNotFound
+	}
+
+	members := make([]domain.ServerGroupMember, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		members = append(members, domain.ServerGroupMember{GroupID: groupID, ServerID: serverID})
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error
+}
+
+func (r *serverGroupRepository) RemoveMember(groupID, serverID string) error {
+	result := r.db.Where("group_id = ? AND server_id = ?", groupID, serverID).Delete(&domain.ServerGroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// subtreeServerIDs returns the servers in the given groups or in any of their subgroups.
+func subtreeServerIDs(db *gorm.DB, groupIDs []string) ([]string, error) {
+	var serverIDs []string
+	err := db.Raw(`WITH RECURSIVE subtree AS (
+		SELECT id FROM server_groups WHERE id IN ?
+		UNION
+		SELECT g.id FROM server_groups g JOIN subtree s ON g.parent_id = s.id
+	)
+	SELECT DISTINCT server_id FROM server_group_members WHERE group_id IN (SELECT id FROM subtree) ORDER BY server_id`, groupIDs).
+		Scan(&serverIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIDs, nil
+}
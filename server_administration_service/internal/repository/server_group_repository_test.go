@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+)
+
+func TestCreateGroup_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "server_groups"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	id, err := repo.CreateGroup(&domain.ServerGroup{ID: "group-1", Name: "dc-1", SLATarget: 99.9})
+	assert.NoError(t, err)
+	assert.Equal(t, "group-1", id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteGroup_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "server_group_members" WHERE group_id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "server_groups" WHERE id = \$1`).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.DeleteGroup("missing")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMembers_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "servers" WHERE server_id IN ($1,$2)`)).
+		WithArgs("srv-1", "srv-2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "server_group_members" ("group_id","server_id") VALUES ($1,$2),($3,$4) ON CONFLICT DO NOTHING`)).
+		WithArgs("group-1", "srv-1", "group-1", "srv-2").
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	err := repo.AddMembers("group-1", []string{"srv-1", "srv-2"})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddMembers_UnknownServer(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "servers" WHERE server_id IN ($1,$2)`)).
+		WithArgs("srv-1", "srv-9").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	err := repo.AddMembers("group-1", []string{"srv-1", "srv-9"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetGroupMembers_Success(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "server_id" FROM "server_group_members" WHERE group_id = $1 ORDER BY server_id`)).
+		WithArgs("group-1").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+
+	serverIDs, err := repo.GetGroupMembers("group-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1", "srv-2"}, serverIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveMember_NotFound(t *testing.T) {
+	gdb, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerGroupRepository(gdb)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "server_group_members" WHERE group_id = \$1 AND server_id = \$2`).
+		WithArgs("group-1", "srv-9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.RemoveMember("group-1", "srv-9")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
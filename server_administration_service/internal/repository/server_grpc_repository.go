@@ -8,7 +8,7 @@ import (
 )
 
 type ServerGRPCRepository interface {
-	GetServerAddresses() ([]dto.ServerAddress, error)
+	GetServerAddresses(selector domain.LabelSelector, expression *domain.FilterExpression) ([]dto.ServerAddress, error)
 }
 
 type serverGRPCRepository struct {
@@ -21,12 +21,15 @@ func NewServerGRPCRepository(db *gorm.DB) ServerGRPCRepository {
 	}
 }
 
-func (r *serverGRPCRepository) GetServerAddresses() ([]dto.ServerAddress, error) {
+func (r *serverGRPCRepository) GetServerAddresses(selector domain.LabelSelector, expression *domain.FilterExpression) ([]dto.ServerAddress, error) {
 	var serverAddresses []dto.ServerAddress
-	if err := r.db.Model(&domain.Server{}).
-		Select("server_id", "ipv4", "status").
+	query := r.db.Model(&domain.Server{}).
+		Select("server_id", "primary_address", "addresses", "status").
+		// Servers in maintenance or decommissioned aren't probed
+		Where("status NOT IN ?", []string{domain.StatusMaintenance, domain.StatusDecommissioned})
+	if err := applyFilterExpression(applyLabelSelector(query, selector), expression).
 		Find(&serverAddresses).Error; err != nil {
-			return nil, err
+			return nil, filterQueryError(err)
 		}
 	
 	return serverAddresses, nil
@@ -14,20 +14,22 @@ func TestGetServerAddresses_Success(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"server_id", "ipv4", "status"}).
-		AddRow("srv1", "192.168.1.1", "active").
-		AddRow("srv2", "192.168.1.2", "inactive")
+	rows := sqlmock.NewRows([]string{"server_id", "primary_address", "addresses", "status"}).
+		AddRow("srv1", "192.168.1.1", `[{"type":"ipv4","address":"192.168.1.1","primary":true}]`, "Up").
+		AddRow("srv2", "192.168.1.2", `[{"type":"ipv4","address":"192.168.1.2","primary":true},{"type":"fqdn","address":"srv2.example.com","primary":false}]`, "Down")
 
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT "server_id","ipv4","status" FROM "servers"`)).
+		`SELECT "server_id","primary_address","addresses","status" FROM "servers" WHERE status NOT IN ($1,$2)`)).
+		WithArgs("Maintenance", "Decommissioned").
 		WillReturnRows(rows)
 
 	repo := repository.NewServerGRPCRepository(gdb)
-	addresses, err := repo.GetServerAddresses()
+	addresses, err := repo.GetServerAddresses(nil, nil)
 	assert.NoError(t, err)
 	assert.Len(t, addresses, 2)
 	assert.Equal(t, "srv1", addresses[0].ServerID)
-	assert.Equal(t, "192.168.1.2", addresses[1].IPv4)
+	assert.Equal(t, "192.168.1.2", addresses[1].PrimaryAddress)
+	assert.Equal(t, "srv2.example.com", addresses[1].Addresses[1].Address)
 }
 
 func TestGetServerAddresses_Error(t *testing.T) {
@@ -35,11 +37,11 @@ func TestGetServerAddresses_Error(t *testing.T) {
 	defer cleanup()
 
 	mock.ExpectQuery(regexp.QuoteMeta(
-		`SELECT server_id,ipv4,status FROM "servers"`)).
+		`SELECT server_id,primary_address,addresses,status FROM "servers"`)).
 		WillReturnError(errors.New("db error"))
 
 	repo := repository.NewServerGRPCRepository(gdb)
-	addresses, err := repo.GetServerAddresses()
+	addresses, err := repo.GetServerAddresses(nil, nil)
 	assert.Error(t, err)
 	assert.Nil(t, addresses)
 }
\ No newline at end of file
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
 	"server_administration_service/infrastructure/elasticsearch"
 	"server_administration_service/internal/domain"
 	"time"
@@ -15,9 +14,11 @@ import (
 
 type ServerInfoRepository interface {
 	GetNumServers() (int, error)
+	GetServers(serverID string) ([]domain.Server, error)
 	GetNumOnServers() (int, error)
 	GetNumOffServers() (int, error)
-	GetServerSumUpTimeRatio(startTime, endTime string) (float64, error)
+	GetStatusHistories(startTime, endTime time.Time) (map[string][]domain.StatusChange, error)
+	GetStatusHistory(serverID string, startTime, endTime time.Time) ([]domain.StatusChange, error)
 }
 
 type serverInfoRepository struct {
@@ -34,7 +35,7 @@ func NewServerInfoRepository(db *gorm.DB, esc elasticsearch.ElasticsearchClient)
 
 func (r *serverInfoRepository) GetNumServers() (int, error) {
 	var numServers int64
-	if err := r.db.Model(&domain.Server{}).Count(&numServers).Error; err != nil {
+	if err := r.db.Model(&domain.Server{}).Where("status <> ?", domain.StatusDecommissioned).Count(&numServers).Error; err != nil {
 		logging.LogMessage("server_administration_service", "Failed to count the number of servers, err: " + err.Error(), "ERROR")
 		return 0, err
 	}
@@ -42,9 +43,27 @@ func (r *serverInfoRepository) GetNumServers() (int, error) {
 	return int(numServers), nil
 }
 
+// GetServers returns the server with the given ID, or every server still in service when serverID is empty.
+func (r *serverInfoRepository) GetServers(serverID string) ([]domain.Server, error) {
+	var servers []domain.Server
+	query := r.db.Model(&domain.Server{})
+	if serverID != "" {
+		query = query.Where("server_id = ?", serverID)
+	} else {
+		query = query.Where("status <> ?", domain.StatusDecommissioned)
+	}
+
+	if err := query.Order("server_id").Find(&servers).Error; err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get servers, err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+
+	return servers, nil
+}
+
 func (r *serverInfoRepository) GetNumOnServers() (int, error) {
 	var numOnServers int64
-	if err := r.db.Model(&domain.Server{}).Where("status = ?", "On").Count(&numOnServers).Error; err != nil {
+	if err := r.db.Model(&domain.Server{}).Where("status IN ?", []string{domain.StatusUp, domain.StatusDegraded}).Count(&numOnServers).Error; err != nil {
 		logging.LogMessage("server_administration_service", "Failed to count the number of ON servers, err: " + err.Error(), "ERROR")
 		return 0, err
 	}
@@ -54,7 +73,7 @@ func (r *serverInfoRepository) GetNumOnServers() (int, error) {
 
 func (r *serverInfoRepository) GetNumOffServers() (int, error) {
 	var numOffServers int64
-	if err := r.db.Model(&domain.Server{}).Where("status = ?", "Off").Count(&numOffServers).Error; err != nil {
+	if err := r.db.Model(&domain.Server{}).Where("status = ?", domain.StatusDown).Count(&numOffServers).Error; err != nil {
 		logging.LogMessage("server_administration_service", "Failed to count the number of OFF servers, err: " + err.Error(), "ERROR")
 		return 0, err
 	}
@@ -62,146 +81,257 @@ func (r *serverInfoRepository) GetNumOffServers() (int, error) {
 	return int(numOffServers), nil
 }
 
-func (r *serverInfoRepository) GetServerSumUpTimeRatio(startTime, endTime string) (float64, error) {
-	startTimeInt64, err := time.Parse(time.RFC3339, startTime)
-	if (err != nil) {
-		logging.LogMessage("server_administration_service", "Start time is not valid", "ERROR")
-		return 0, err
-	}
+// A status search pages through its hits, or the servers of its aggregation, this many at a time
+const statusPageSize = 1000
 
-	endTimeInt64, err := time.Parse(time.RFC3339, endTime)
-	if (err != nil) {
-		logging.LogMessage("server_administration_service", "End time is not valid", "ERROR")
-		return 0, err
+// statusPITKeepAlive is how long the point in time of a status search is kept between two of its pages
+const statusPITKeepAlive = "1m"
+
+// GetStatusHistories returns, per server, the status changes in [startTime, endTime] in chronological order,
+// each preceded by the server's last change before startTime.
+func (r *serverInfoRepository) GetStatusHistories(startTime, endTime time.Time) (map[string][]domain.StatusChange, error) {
+	histories, err := r.lastStatusChanges(startTime)
+	if err != nil {
+		return nil, err
 	}
 
-	query := map[string]interface{}{
-		"size": 0,
-		"query": map[string]interface{}{
-			"range": map[string]interface{}{
-				"Timestamp": map[string]interface{}{
-					"gte": startTime,
-					"lte": endTime,
-				},
+	inRange, err := r.searchAllStatusChanges(map[string]interface{}{
+		"range": map[string]interface{}{
+			"Timestamp": map[string]interface{}{
+				"gte": startTime.Format(time.RFC3339Nano),
+				"lte": endTime.Format(time.RFC3339Nano),
 			},
 		},
-		"aggs": map[string]interface{}{
-			"id_bucket": map[string]interface{}{
-				"terms": map[string]interface{}{
-					"field": "ID.keyword",
-					"size": 10000,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range inRange {
+		histories[change.ID] = append(histories[change.ID], change)
+	}
+
+	return histories, nil
+}
+
+// lastStatusChanges returns the last status change of every server before the given time, paging through
+// the servers with a composite aggregation.
+func (r *serverInfoRepository) lastStatusChanges(before time.Time) (map[string][]domain.StatusChange, error) {
+	histories := make(map[string][]domain.StatusChange)
+	var after map[string]interface{}
+	for {
+		composite := map[string]interface{}{
+			"size": statusPageSize,
+			"sources": []map[string]interface{}{
+				{"id": map[string]interface{}{"terms": map[string]interface{}{"field": "ID.keyword"}}},
+			},
+		}
+		if after != nil {
+			composite["after"] = after
+		}
+
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(map[string]interface{}{
+			"size": 0,
+			"query": map[string]interface{}{
+				"range": map[string]interface{}{
+					"Timestamp": map[string]interface{}{"lt": before.Format(time.RFC3339Nano)},
 				},
-				"aggs": map[string]interface{}{
-					"on_ping": map[string]interface{}{
-						"filter": map[string]interface{}{
-							"term": map[string]interface{}{
-								"Status.keyword": "On",
-							},
-						},
-						"aggs": map[string]interface{}{
-							"total_on_ping_time": map[string]interface{}{
-								"sum": map[string]interface{}{
-									"field": "Timestamp",
-								},
-							},
-						},
-					},
-					"off_ping": map[string]interface{}{
-						"filter": map[string]interface{}{
-							"term": map[string]interface{}{
-								"Status.keyword": "Off",
-							},
-						},
-						"aggs": map[string]interface{}{
-							"total_off_ping_time": map[string]interface{}{
-								"sum": map[string]interface{}{
-									"field": "Timestamp",
-								},
-							},
-						},
-					},
-					"last_ping": map[string]interface{}{
-						"top_hits": map[string]interface{}{
-							"size": 1,
-							"sort": []map[string]interface{}{
-								{
-									"Timestamp": map[string]interface{}{
-										"order": "desc",
-									},
+			},
+			"aggs": map[string]interface{}{
+				"id_bucket": map[string]interface{}{
+					"composite": composite,
+					"aggs": map[string]interface{}{
+						"last_change": map[string]interface{}{
+							"top_hits": map[string]interface{}{
+								"size": 1,
+								"sort": []map[string]interface{}{
+									{"Timestamp": map[string]interface{}{"order": "desc"}},
 								},
 							},
-							"_source": map[string]interface{}{
-								"includes": []string{"Status"},
-							},
 						},
 					},
 				},
 			},
+		})
+
+		resp, err := r.esc.Search(context.Background(), "ping_status", buf)
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Failed to get Elasticsearch response. Err: " + err.Error(), "ERROR")
+			return nil, err
+		}
+
+		var answer struct {
+			Aggregations struct {
+				IDBucket struct {
+					AfterKey map[string]interface{} `json:"after_key"`
+					Buckets  []struct {
+						LastChange struct {
+							Hits struct {
+								Hits []struct {
+									Source domain.StatusChange `json:"_source"`
+								} `json:"hits"`
+							} `json:"hits"`
+						} `json:"last_change"`
+					} `json:"buckets"`
+				} `json:"id_bucket"`
+			} `json:"aggregations"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&answer)
+		resp.Body.Close()
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Failed to decode Elasticsearch query's result, err: " + err.Error(), "ERROR")
+			return nil, err
+		}
+
+		for _, bucket := range answer.Aggregations.IDBucket.Buckets {
+			for _, hit := range bucket.LastChange.Hits.Hits {
+				histories[hit.Source.ID] = append(histories[hit.Source.ID], hit.Source)
+			}
+		}
+
+		if len(answer.Aggregations.IDBucket.Buckets) < statusPageSize || answer.Aggregations.IDBucket.AfterKey == nil {
+			return histories, nil
+		}
+		after = answer.Aggregations.IDBucket.AfterKey
+	}
+}
+
+// GetStatusHistory returns the status changes of a server in [startTime, endTime] in chronological order,
+// preceded by the last change before startTime so callers know the status the range starts with.
+func (r *serverInfoRepository) GetStatusHistory(serverID string, startTime, endTime time.Time) ([]domain.StatusChange, error) {
+	previous, err := r.searchStatusChanges(map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"ID.keyword": serverID}},
+					{"range": map[string]interface{}{"Timestamp": map[string]interface{}{"lt": startTime.Format(time.RFC3339Nano)}}},
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"Timestamp": map[string]interface{}{"order": "desc"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inRange, err := r.searchAllStatusChanges(map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []map[string]interface{}{
+				{"term": map[string]interface{}{"ID.keyword": serverID}},
+				{"range": map[string]interface{}{"Timestamp": map[string]interface{}{
+					"gte": startTime.Format(time.RFC3339Nano),
+					"lte": endTime.Format(time.RFC3339Nano),
+				}}},
+			},
 		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	json.NewEncoder(&buf).Encode(query)
+	return append(previous, inRange...), nil
+}
 
-	resp, err := r.esc.Search(context.Background(), "ping_status", buf)
+// statusHits is the answer of a search of status changes.
+type statusHits struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Hits []struct {
+			Source domain.StatusChange `json:"_source"`
+			Sort   []interface{}       `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
 
+func (r *serverInfoRepository) searchStatusChanges(query map[string]interface{}) ([]domain.StatusChange, error) {
+	answer, err := r.searchStatusHits("ping_status", query)
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to get Elasticsearch response. Err: " + err.Error(), "ERROR")
-		return 0, err
+		return nil, err
 	}
 
-	defer resp.Body.Close()
+	changes := make([]domain.StatusChange, 0, len(answer.Hits.Hits))
+	for _, hit := range answer.Hits.Hits {
+		changes = append(changes, hit.Source)
+	}
+	return changes, nil
+}
 
-	var answer map[string]interface{}
-	json.NewDecoder(resp.Body).Decode(&answer)
-
-	if answer["error"] != nil {
-		logging.LogMessage("server_administration_service", "Elasticsearch query returned an error with status: " + fmt.Sprintf("%v", answer["status"].(float64)), "ERROR")
-		return 0, nil
-	}
-
-	buckets := answer["aggregations"].(map[string]interface{})["id_bucket"].(map[string]interface{})["buckets"].([]interface{})
-	meanOnTimeRatio := 0.0
-	
-	for _, s := range buckets {
-		server := s.(map[string]interface{})
-		
-		last_ping := server["last_ping"].(map[string]interface{})["hits"].(map[string]interface{})["hits"].([]interface{})[0].(map[string]interface{})
-		last_status := last_ping["_source"].(map[string]interface{})["Status"].(string)
-		
-		doc_count := int(server["doc_count"].(float64))
-		first_status := last_status
-
-		if (doc_count % 2 == 0) {
-			switch last_status {
-			case "On":
-				first_status = "Off"
-			case "Off":
-				first_status = "On"
-			}
+// searchAllStatusChanges returns every status change matching query in chronological order. It pages
+// through them with search_after on a point in time, so changes indexed meanwhile are neither skipped nor
+// counted twice, however many there are.
+func (r *serverInfoRepository) searchAllStatusChanges(query map[string]interface{}) ([]domain.StatusChange, error) {
+	ctx := context.Background()
+	pitID, err := r.esc.OpenPointInTime(ctx, "ping_status", statusPITKeepAlive)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to open an Elasticsearch point in time. Err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+	defer func() {
+		if err := r.esc.ClosePointInTime(ctx, pitID); err != nil {
+			// It expires on its own after the keep alive
+			logging.LogMessage("server_administration_service", "Failed to close an Elasticsearch point in time. Err: " + err.Error(), "ERROR")
 		}
+	}()
 
-		off_ping := server["off_ping"].(map[string]interface{})
-		total_off_ping_time := int64(off_ping["total_off_ping_time"].(map[string]interface{})["value"].(float64))
-		
-		on_ping := server["on_ping"].(map[string]interface{})
-		total_on_ping_time := int64(on_ping["total_on_ping_time"].(map[string]interface{})["value"].(float64))
-		
-		if last_status == "On" {
-			total_off_ping_time += endTimeInt64.UnixNano() / int64(time.Millisecond)
+	var changes []domain.StatusChange
+	var after []interface{}
+	for {
+		search := map[string]interface{}{
+			"size":  statusPageSize,
+			"query": query,
+			"pit":   map[string]interface{}{"id": pitID, "keep_alive": statusPITKeepAlive},
+			// _shard_doc breaks the ties between changes of the same time
+			"sort": []map[string]interface{}{
+				{"Timestamp": map[string]interface{}{"order": "asc"}},
+				{"_shard_doc": map[string]interface{}{"order": "asc"}},
+			},
+		}
+		if after != nil {
+			search["search_after"] = after
 		}
 
-		if first_status == "Off" {
-			total_on_ping_time += startTimeInt64.UnixNano() / int64(time.Millisecond)
+		// A point in time names its index, the search mustn't
+		answer, err := r.searchStatusHits("", search)
+		if err != nil {
+			return nil, err
 		}
 
-		total_time := (endTimeInt64.UnixNano() - startTimeInt64.UnixNano()) / int64(time.Millisecond)
+		for _, hit := range answer.Hits.Hits {
+			changes = append(changes, hit.Source)
+		}
+		if len(answer.Hits.Hits) < statusPageSize {
+			return changes, nil
+		}
 
-		on_time_ratio := float64(total_off_ping_time-total_on_ping_time) / float64(total_time) * 100
-		meanOnTimeRatio += on_time_ratio
+		after = answer.Hits.Hits[len(answer.Hits.Hits)-1].Sort
+		if answer.PitID != "" {
+			pitID = answer.PitID
+		}
 	}
+}
 
-	// meanOnTimeRatio /= float64(len(buckets))
+func (r *serverInfoRepository) searchStatusHits(index string, query map[string]interface{}) (*statusHits, error) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(query)
 
-	return float64(meanOnTimeRatio), nil
-}
\ No newline at end of file
+	resp, err := r.esc.Search(context.Background(), index, buf)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get Elasticsearch response. Err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var answer statusHits
+	decoder := json.NewDecoder(resp.Body)
+	// Sort values are sent back as they came, without rounding them through float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&answer); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode Elasticsearch query's result, err: " + err.Error(), "ERROR")
+		return nil, err
+	}
+	return &answer, nil
+}
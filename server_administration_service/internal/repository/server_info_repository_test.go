@@ -25,6 +25,31 @@ func (m *MockESClient) Index(ctx context.Context, index string, body []byte) err
 	return args.Error(0)
 }
 
+func (m *MockESClient) Bulk(ctx context.Context, index string, body []byte) error {
+	args := m.Called(ctx, index, body)
+	return args.Error(0)
+}
+
+func (m *MockESClient) CreateIndex(ctx context.Context, index string, body []byte) (bool, error) {
+	args := m.Called(ctx, index, body)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockESClient) DeleteByQuery(ctx context.Context, index string, body []byte) (int64, error) {
+	args := m.Called(ctx, index, body)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockESClient) OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error) {
+	args := m.Called(ctx, index, keepAlive)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockESClient) ClosePointInTime(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockESClient) Search(ctx context.Context, index string, buf bytes.Buffer) (*esapi.Response, error) {
 	args := m.Called(ctx, index, buf)
 	if (args.Get(0) == nil) {
@@ -39,7 +64,8 @@ func TestGetNumServers_Success(t *testing.T) {
 
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
-	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\"").
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status <> ?").
+		WithArgs("Decommissioned").
 		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(5))
 
 	num, err := repo.GetNumServers()
@@ -57,7 +83,8 @@ func TestGetNumServers_Error(t *testing.T) {
 
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
-	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\"").
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status <> ?").
+		WithArgs("Decommissioned").
 		WillReturnError(fmt.Errorf("db error"))
 
 	num, err := repo.GetNumServers()
@@ -75,8 +102,8 @@ func TestGetNumOnServers_Success(t *testing.T) {
 
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
-	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status = ?").
-		WithArgs("On").
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status IN ").
+		WithArgs("Up", "Degraded").
 		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(3))
 
 	num, err := repo.GetNumOnServers()
@@ -94,8 +121,8 @@ func TestGetNumOnServers_Error(t *testing.T) {
 
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
-	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status = ?").
-		WithArgs("On").
+	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status IN ").
+		WithArgs("Up", "Degraded").
 		WillReturnError(fmt.Errorf("db error"))
 
 	num, err := repo.GetNumOnServers()
@@ -114,7 +141,7 @@ func TestGetNumOffServers_Success(t *testing.T) {
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
 	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status = ?").
-		WithArgs("Off").
+		WithArgs("Down").
 		WillReturnRows(mock.NewRows([]string{"count"}).AddRow(2))
 
 	num, err := repo.GetNumOffServers()
@@ -133,7 +160,7 @@ func TestGetNumOffServers_Error(t *testing.T) {
 	repo := repository.NewServerInfoRepository(gdb, nil)
 
 	mock.ExpectQuery("SELECT count\\(\\*\\) FROM \"servers\" WHERE status = ?").
-		WithArgs("Off").
+		WithArgs("Down").
 		WillReturnError(fmt.Errorf("db error"))
 
 	num, err := repo.GetNumOffServers()
@@ -145,120 +172,196 @@ func TestGetNumOffServers_Error(t *testing.T) {
 	}
 }
 
-func TestGetServerSumUpTimeRatio_Success(t *testing.T) {
-	// Mock Elasticsearch client
+func TestGetStatusHistories_Success(t *testing.T) {
 	mockESC := new(MockESClient)
 
-	// Prepare mock response body
-	mockBuckets := []interface{}{
-		map[string]interface{}{
-			"doc_count": float64(4),
-			"last_ping": map[string]interface{}{
-				"hits": map[string]interface{}{
-					"hits": []interface{}{
-						map[string]interface{}{
-							"_source": map[string]interface{}{
-								"Status": "On",
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := func(id, status string, at time.Time) map[string]interface{} {
+		return map[string]interface{}{"ID": id, "Status": status, "Timestamp": at.Format(time.RFC3339)}
+	}
+	newResponse := func(answer map[string]interface{}) *esapi.Response {
+		body, _ := json.Marshal(answer)
+		return &esapi.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+
+	// First the last change of every server before the range, then the changes inside it
+	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(newResponse(map[string]interface{}{
+		"aggregations": map[string]interface{}{
+			"id_bucket": map[string]interface{}{
+				"buckets": []interface{}{
+					map[string]interface{}{
+						"last_change": map[string]interface{}{
+							"hits": map[string]interface{}{
+								"hits": []interface{}{
+									map[string]interface{}{"_source": source("srv-1", "Up", start.Add(-time.Hour))},
+								},
 							},
 						},
 					},
 				},
 			},
-			"on_ping": map[string]interface{}{
-				"total_on_ping_time": map[string]interface{}{
-					"value": float64(10000),
-				},
-			},
-			"off_ping": map[string]interface{}{
-				"total_off_ping_time": map[string]interface{}{
-					"value": float64(20000),
-				},
+		},
+	}), nil).Once()
+	mockESC.On("OpenPointInTime", mock.Anything, "ping_status", "1m").Return("pit-1", nil).Once()
+	mockESC.On("Search", mock.Anything, "", mock.Anything).Return(newResponse(map[string]interface{}{
+		"hits": map[string]interface{}{
+			"hits": []interface{}{
+				map[string]interface{}{"_source": source("srv-2", "Degraded", start.Add(time.Hour))},
+				map[string]interface{}{"_source": source("srv-1", "Down", start.Add(2*time.Hour))},
 			},
 		},
+	}), nil).Once()
+	mockESC.On("ClosePointInTime", mock.Anything, "pit-1").Return(nil).Once()
+
+	repo := repository.NewServerInfoRepository(nil, mockESC)
+
+	histories, err := repo.GetStatusHistories(start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
 	}
-	mockAggs := map[string]interface{}{
-		"id_bucket": map[string]interface{}{
-			"buckets": mockBuckets,
-		},
+	if len(histories["srv-1"]) != 2 || histories["srv-1"][0].Status != "Up" || histories["srv-1"][1].Status != "Down" {
+		t.Errorf("unexpected history for srv-1: %+v", histories["srv-1"])
 	}
-	mockAnswer := map[string]interface{}{
-		"aggregations": mockAggs,
+	if len(histories["srv-2"]) != 1 || histories["srv-2"][0].Status != "Degraded" {
+		t.Errorf("unexpected history for srv-2: %+v", histories["srv-2"])
 	}
+	mockESC.AssertExpectations(t)
+}
+
+func TestGetStatusHistories_ESError(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(nil, assert.AnError)
+	repo := repository.NewServerInfoRepository(nil, mockESC)
 
-	respBody, _ := json.Marshal(mockAnswer)
-	resp := &esapi.Response{
-		StatusCode: 200,
-		Body:       io.NopCloser(bytes.NewReader(respBody)),
+	_, err := repo.GetStatusHistories(time.Now().Add(-time.Hour), time.Now())
+	if err == nil {
+		t.Fatal("expected error for ES, got nil")
 	}
+}
 
-	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(resp, nil)
+func TestGetStatusHistory_Success(t *testing.T) {
+	mockESC := new(MockESClient)
 
-	repo := repository.NewServerInfoRepository(nil, mockESC)
+	newResponse := func(statuses ...string) *esapi.Response {
+		hits := []interface{}{}
+		for i, status := range statuses {
+			hits = append(hits, map[string]interface{}{
+				"_source": map[string]interface{}{
+					"ID":        "srv-1",
+					"Status":    status,
+					"Timestamp": time.Date(2024, 1, 1, i, 0, 0, 0, time.UTC).Format(time.RFC3339),
+				},
+			})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"hits": map[string]interface{}{"hits": hits}})
+		return &esapi.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+
+	// First the last change before the range, then the changes inside it
+	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(newResponse("Off"), nil).Once()
+	mockESC.On("OpenPointInTime", mock.Anything, "ping_status", "1m").Return("pit-1", nil).Once()
+	mockESC.On("Search", mock.Anything, "", mock.Anything).Return(newResponse("On", "Off"), nil).Once()
+	mockESC.On("ClosePointInTime", mock.Anything, "pit-1").Return(nil).Once()
 
-	start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
-	end := time.Now().UTC().Format(time.RFC3339)
+	repo := repository.NewServerInfoRepository(nil, mockESC)
 
-	ratio, err := repo.GetServerSumUpTimeRatio(start, end)
+	history, err := repo.GetStatusHistory("srv-1", time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if ratio == 0 {
-		t.Errorf("expected non-zero ratio, got %v", ratio)
+	if len(history) != 3 {
+		t.Fatalf("expected 3 status changes, got %d", len(history))
 	}
-}
-
-func TestGetServerSumUpTimeRatio_InvalidStartTime(t *testing.T) {
-	repo := repository.NewServerInfoRepository(nil, nil)
-	_, err := repo.GetServerSumUpTimeRatio("invalid", time.Now().UTC().Format(time.RFC3339))
-	if err == nil {
-		t.Fatal("expected error for invalid start time, got nil")
+	if history[0].Status != "Off" || history[1].Status != "On" || history[2].Status != "Off" {
+		t.Errorf("unexpected history: %+v", history)
 	}
+	mockESC.AssertExpectations(t)
 }
 
-func TestGetServerSumUpTimeRatio_InvalidEndTime(t *testing.T) {
-	repo := repository.NewServerInfoRepository(nil, nil)
-	_, err := repo.GetServerSumUpTimeRatio(time.Now().UTC().Format(time.RFC3339), "invalid")
-	if err == nil {
-		t.Fatal("expected error for invalid end time, got nil")
+func TestGetStatusHistory_PagesThroughEveryChange(t *testing.T) {
+	mockESC := new(MockESClient)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newResponse := func(pitID string, from, count int) *esapi.Response {
+		hits := []interface{}{}
+		for i := from; i < from+count; i++ {
+			at := start.Add(time.Duration(i) * time.Second)
+			hits = append(hits, map[string]interface{}{
+				"_source": map[string]interface{}{"ID": "srv-1", "Status": "Up", "Timestamp": at.Format(time.RFC3339)},
+				"sort":    []interface{}{at.UnixMilli(), i},
+			})
+		}
+		body, _ := json.Marshal(map[string]interface{}{"pit_id": pitID, "hits": map[string]interface{}{"hits": hits}})
+		return &esapi.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	searchAfter := func(buf bytes.Buffer) []interface{} {
+		var search struct {
+			SearchAfter []interface{} `json:"search_after"`
+			Pit         struct {
+				ID string `json:"id"`
+			} `json:"pit"`
+		}
+		json.Unmarshal(buf.Bytes(), &search)
+		return append(search.SearchAfter, search.Pit.ID)
 	}
-}
 
-func TestGetServerSumUpTimeRatio_ESError(t *testing.T) {
-	mockESC := new(MockESClient)
-	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(nil, assert.AnError)
-	repo := repository.NewServerInfoRepository(nil, mockESC)
+	var pages [][]interface{}
+	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(newResponse("", 0, 0), nil).Once()
+	mockESC.On("OpenPointInTime", mock.Anything, "ping_status", "1m").Return("pit-1", nil).Once()
+	mockESC.On("Search", mock.Anything, "", mock.Anything).Run(func(args mock.Arguments) {
+		pages = append(pages, searchAfter(args.Get(2).(bytes.Buffer)))
+	}).Return(newResponse("pit-2", 0, 1000), nil).Once()
+	mockESC.On("Search", mock.Anything, "", mock.Anything).Run(func(args mock.Arguments) {
+		pages = append(pages, searchAfter(args.Get(2).(bytes.Buffer)))
+	}).Return(newResponse("pit-2", 1000, 500), nil).Once()
+	mockESC.On("ClosePointInTime", mock.Anything, "pit-2").Return(nil).Once()
 
-	start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
-	end := time.Now().UTC().Format(time.RFC3339)
+	repo := repository.NewServerInfoRepository(nil, mockESC)
 
-	_, err := repo.GetServerSumUpTimeRatio(start, end)
-	if err == nil {
-		t.Fatal("expected error for ES, got " + err.Error())
+	history, err := repo.GetStatusHistory("srv-1", start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(history) != 1500 {
+		t.Fatalf("expected 1500 status changes, got %d", len(history))
 	}
+	lastOfFirstPage := float64(start.Add(999 * time.Second).UnixMilli())
+	assert.Equal(t, [][]interface{}{{"pit-1"}, {lastOfFirstPage, float64(999), "pit-2"}}, pages)
+	mockESC.AssertExpectations(t)
 }
 
-func TestGetServerSumUpTimeRatio_ESReturnsErrorField(t *testing.T) {
-	mockESC := new(MockESClient)
-	mockAnswer := map[string]interface{}{
-		"error":  "some error",
-		"status": float64(500),
+func TestGetServers_ByID(t *testing.T) {
+	db, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE server_id = \$1 AND "servers"."deleted_at" IS NULL ORDER BY server_id`).
+		WithArgs("srv-1").
+		WillReturnRows(mock.NewRows([]string{"server_id", "server_name", "sla_target"}).AddRow("srv-1", "db", 99.5))
+
+	repo := repository.NewServerInfoRepository(db, nil)
+	servers, err := repo.GetServers("srv-1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
 	}
-	respBody, _ := json.Marshal(mockAnswer)
-	resp := &esapi.Response{
-		StatusCode: 500,
-		Body:       io.NopCloser(bytes.NewReader(respBody)),
+	if len(servers) != 1 || servers[0].SLATarget != 99.5 {
+		t.Errorf("unexpected servers: %+v", servers)
 	}
-	mockESC.On("Search", mock.Anything, "ping_status", mock.Anything).Return(resp, nil)
-	repo := repository.NewServerInfoRepository(nil, mockESC)
+}
+
+func TestGetServers_All(t *testing.T) {
+	db, mock, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
 
-	start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
-	end := time.Now().UTC().Format(time.RFC3339)
+	mock.ExpectQuery(`SELECT \* FROM "servers" WHERE status <> \$1 AND "servers"."deleted_at" IS NULL ORDER BY server_id`).
+		WithArgs("Decommissioned").
+		WillReturnRows(mock.NewRows([]string{"server_id", "server_name"}).AddRow("srv-1", "db").AddRow("srv-2", "web"))
 
-	ratio, err := repo.GetServerSumUpTimeRatio(start, end)
+	repo := repository.NewServerInfoRepository(db, nil)
+	servers, err := repo.GetServers("")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if ratio != 0 {
-		t.Errorf("expected 0 ratio on ES error, got %v", ratio)
+	if len(servers) != 2 {
+		t.Errorf("expected 2 servers, got %d", len(servers))
 	}
 }
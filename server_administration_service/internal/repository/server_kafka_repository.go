@@ -12,7 +12,12 @@ import (
 )
 
 type ServerKafkaRepository interface {
-	UpdateStatus(server_id, status string) (error)
+	GetStatus(server_id string) (string, error)
+	GetDownDependencies(server_id string) ([]string, error)
+	GetDownDependents(server_id string) ([]string, error)
+	GetUnreachableDependents(server_id string) ([]string, error)
+	UpdateStatus(server_id, status, maintenanceWindowID string) (error)
+	RecordStatus(server_id, status, maintenanceWindowID string) (error)
 }
 
 type serverKafkaRepository struct {
@@ -27,19 +32,53 @@ func NewServerKafkaRepository(db *gorm.DB, esc elasticsearch.ElasticsearchClient
 	}
 }
 
-func (r *serverKafkaRepository) UpdateStatus(server_id, status string) (error) {
+func (r *serverKafkaRepository) GetStatus(server_id string) (string, error) {
+	var server domain.Server
+	if err := r.db.Select("status").Where("server_id = ?", server_id).First(&server).Error; err != nil {
+		return "", err
+	}
+
+	return server.Status, nil
+}
+
+func (r *serverKafkaRepository) GetDownDependencies(server_id string) ([]string, error) {
+	return downDependencies(r.db, server_id)
+}
+
+func (r *serverKafkaRepository) GetDownDependents(server_id string) ([]string, error) {
+	return downDependents(r.db, server_id)
+}
+
+func (r *serverKafkaRepository) GetUnreachableDependents(server_id string) ([]string, error) {
+	return unreachableDependents(r.db, server_id)
+}
+
+func (r *serverKafkaRepository) UpdateStatus(server_id, status, maintenanceWindowID string) (error) {
 	if err := r.db.Model(&domain.Server{}).Where("server_id = ?", server_id).Update("status", status).Error; err != nil {
 		return err
 	}
 
+	return r.RecordStatus(server_id, status, maintenanceWindowID)
+}
+
+// RecordStatus adds a status change to the status history without touching the server, whose status
+// was already written.
+func (r *serverKafkaRepository) RecordStatus(server_id, status, maintenanceWindowID string) (error) {
 	docs := map[string]any {
 		"ID": server_id,
 		"Status": status,
 		"Timestamp": time.Now(),
 	}
 
+	if maintenanceWindowID != "" {
+		docs["Maintenance"] = true
+		docs["MaintenanceWindowID"] = maintenanceWindowID
+	}
+
 	data, err := json.Marshal(docs)
+	if err != nil {
+		return err
+	}
 
-	err = r.esc.Index(context.Background(), env.GetEnv("ES_NAME", "ping_status"), data)
-	return err
+	return r.esc.Index(context.Background(), env.GetEnv("ES_NAME", "ping_status"), data)
 }
\ No newline at end of file
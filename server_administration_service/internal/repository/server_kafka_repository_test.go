@@ -3,6 +3,7 @@ package repository_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -29,6 +30,31 @@ func (m *mockESC) Search(ctx context.Context, index string, buf bytes.Buffer) (*
 	return args.Get(0).(*esapi.Response), args.Error(1)
 }
 
+func (m *mockESC) Bulk(ctx context.Context, index string, body []byte) error {
+	args := m.Called(ctx, index, body)
+	return args.Error(0)
+}
+
+func (m *mockESC) CreateIndex(ctx context.Context, index string, body []byte) (bool, error) {
+	args := m.Called(ctx, index, body)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockESC) DeleteByQuery(ctx context.Context, index string, body []byte) (int64, error) {
+	args := m.Called(ctx, index, body)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockESC) OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error) {
+	args := m.Called(ctx, index, keepAlive)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockESC) ClosePointInTime(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestServerKafkaRepository_UpdateStatus_DBError(t *testing.T) {
 	gdb, mock, cleanup := repository.SetupMockDB(t)
 	defer cleanup()
@@ -45,7 +71,7 @@ func TestServerKafkaRepository_UpdateStatus_DBError(t *testing.T) {
 		WithArgs(status, serverID).
 		WillReturnError(errors.New("db error"))
 
-	err := repo.UpdateStatus(serverID, status)
+	err := repo.UpdateStatus(serverID, status, "")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "db error")
 }
@@ -68,7 +94,7 @@ func TestServerKafkaRepository_UpdateStatus_ESIndexError(t *testing.T) {
 
 	mockESC.On("Index", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("es error"))
 
-	err := repo.UpdateStatus(serverID, status)
+	err := repo.UpdateStatus(serverID, status, "")
 	assert.Error(t, err)
 }
 
@@ -90,6 +116,78 @@ func TestServerKafkaRepository_UpdateStatus_Success(t *testing.T) {
 
 	mockESC.On("Index", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := repo.UpdateStatus(serverID, status)
+	err := repo.UpdateStatus(serverID, status, "")
+	assert.NoError(t, err)
+}
+func TestServerKafkaRepository_UpdateStatus_TagsMaintenance(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	mockESC := new(mockESC)
+	repo := repository.NewServerKafkaRepository(gdb, mockESC)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec("UPDATE \"servers\" SET \"status\"").
+		WithArgs("Off", sqlmock.AnyArg(), "server-3").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+
+	mockESC.On("Index", mock.Anything, mock.Anything, mock.MatchedBy(func(data []byte) bool {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return false
+		}
+		return doc["Maintenance"] == true && doc["MaintenanceWindowID"] == "window-1"
+	})).Return(nil)
+
+	err := repo.UpdateStatus("server-3", "Off", "window-1")
 	assert.NoError(t, err)
-}
\ No newline at end of file
+	mockESC.AssertExpectations(t)
+}
+
+func TestServerKafkaRepository_GetStatus(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerKafkaRepository(gdb, nil)
+
+	mockDB.ExpectQuery(`SELECT "status" FROM "servers" WHERE server_id = \$1`).
+		WithArgs("server-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("Maintenance"))
+
+	status, err := repo.GetStatus("server-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Maintenance", status)
+}
+
+func TestServerKafkaRepository_GetUnreachableDependents(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerKafkaRepository(gdb, nil)
+
+	mockDB.ExpectQuery(`SELECT "servers"."server_id" FROM "server_dependencies" JOIN servers ON servers.server_id = server_dependencies.server_id WHERE server_dependencies.depends_on_id = \$1 AND servers.status = \$2 AND servers.deleted_at IS NULL ORDER BY servers.server_id`).
+		WithArgs("switch-1", "Unreachable").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+
+	dependents, err := repo.GetUnreachableDependents("switch-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1"}, dependents)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestServerKafkaRepository_GetDownDependents(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewServerKafkaRepository(gdb, nil)
+
+	mockDB.ExpectQuery(`WITH RECURSIVE dependents AS .* WHERE d.depends_on_id = \$1 AND s.status IN \(\$2,\$3\) .* WHERE s.status IN \(\$4,\$5\) .* WHERE servers.status = \$6`).
+		WithArgs("switch-1", "Down", "Unreachable", "Down", "Unreachable", "Down").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+
+	dependents, err := repo.GetDownDependents("switch-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv-1", "srv-2"}, dependents)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
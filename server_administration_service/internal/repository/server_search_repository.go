@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"server_administration_service/infrastructure/elasticsearch"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"sort"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+// ServerSearchRepository mirrors the server inventory into an Elasticsearch index for full-text
+// search. Postgres stays the source of truth: the index only holds the searchable fields, and
+// searches return server IDs for the caller to load.
+type ServerSearchRepository interface {
+	CreateIndex() (bool, error)
+	IndexServers(servers []domain.Server) error
+	DeleteServers(serverIDs []string) error
+	SearchServers(query string, limit int) (*dto.ServerSearchResult, error)
+}
+
+// Servers per bulk request of IndexServers
+const serverIndexBatchSize = 1000
+
+// serverIndexDefinition maps names and IDs for search-as-you-type, and cuts addresses into 2 and 3
+// character grams so that a fragment from anywhere in an IP address finds it.
+var serverIndexDefinition = map[string]interface{}{
+	"settings": map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"tokenizer": map[string]interface{}{
+				"address_gram": map[string]interface{}{
+					"type":        "ngram",
+					"min_gram":    2,
+					"max_gram":    3,
+					"token_chars": []string{"letter", "digit", "punctuation", "symbol"},
+				},
+			},
+			"analyzer": map[string]interface{}{
+				"address_gram": map[string]interface{}{
+					"type":      "custom",
+					"tokenizer": "address_gram",
+					"filter":    []string{"lowercase"},
+				},
+			},
+		},
+	},
+	"mappings": map[string]interface{}{
+		"dynamic": "strict",
+		"properties": map[string]interface{}{
+			"server_id":   map[string]interface{}{"type": "search_as_you_type"},
+			"server_name": map[string]interface{}{"type": "search_as_you_type"},
+			"addresses":   map[string]interface{}{"type": "text", "analyzer": "address_gram"},
+			"labels":      map[string]interface{}{"type": "text"},
+			"notes":       map[string]interface{}{"type": "text"},
+		},
+	},
+}
+
+// serverDocument is what the index holds of a server, its labels written key=value.
+type serverDocument struct {
+	ServerID   string   `json:"server_id"`
+	ServerName string   `json:"server_name"`
+	Addresses  []string `json:"addresses"`
+	Labels     []string `json:"labels"`
+	Notes      string   `json:"notes"`
+}
+
+type serverSearchRepository struct {
+	esc   elasticsearch.ElasticsearchClient
+	index string
+}
+
+func NewServerSearchRepository(esc elasticsearch.ElasticsearchClient, index string) ServerSearchRepository {
+	return &serverSearchRepository{
+		esc:   esc,
+		index: index,
+	}
+}
+
+// CreateIndex creates the index unless it exists, and reports whether it did.
+func (r *serverSearchRepository) CreateIndex() (bool, error) {
+	body, _ := json.Marshal(serverIndexDefinition)
+	return r.esc.CreateIndex(context.Background(), r.index, body)
+}
+
+// IndexServers adds the servers to the index, or replaces their documents.
+func (r *serverSearchRepository) IndexServers(servers []domain.Server) error {
+	for start := 0; start < len(servers); start += serverIndexBatchSize {
+		end := start + serverIndexBatchSize
+		if end > len(servers) {
+			end = len(servers)
+		}
+
+		var body bytes.Buffer
+		encoder := json.NewEncoder(&body)
+		for _, server := range servers[start:end] {
+			document := serverDocument{
+				ServerID:   server.ServerID,
+				ServerName: server.ServerName,
+				Addresses:  []string{},
+				Labels:     []string{},
+				Notes:      server.Notes,
+			}
+			for _, address := range server.Addresses {
+				document.Addresses = append(document.Addresses, address.Address)
+			}
+			for key, value := range server.Labels {
+				document.Labels = append(document.Labels, key+"="+value)
+			}
+			sort.Strings(document.Labels)
+			encoder.Encode(map[string]interface{}{"index": map[string]string{"_id": server.ServerID}})
+			encoder.Encode(document)
+		}
+
+		if err := r.esc.Bulk(context.Background(), r.index, body.Bytes()); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to index servers: "+err.Error(), "ERROR")
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteServers removes the servers from the index; the ones it doesn't have are skipped.
+func (r *serverSearchRepository) DeleteServers(serverIDs []string) error {
+	if len(serverIDs) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, serverID := range serverIDs {
+		encoder.Encode(map[string]interface{}{"delete": map[string]string{"_id": serverID}})
+	}
+
+	if err := r.esc.Bulk(context.Background(), r.index, body.Bytes()); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to remove servers from the index: "+err.Error(), "ERROR")
+		return err
+	}
+	return nil
+}
+
+// SearchServers returns the limit best matches of query: names and IDs as typed so far, names, IDs,
+// labels and notes with typos, and addresses containing the query.
+func (r *serverSearchRepository) SearchServers(query string, limit int) (*dto.ServerSearchResult, error) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(map[string]interface{}{
+		"size":             limit,
+		"track_total_hits": true,
+		"_source":          false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					{"multi_match": map[string]interface{}{
+						"query": query,
+						"type":  "bool_prefix",
+						"fields": []string{
+							"server_name^3", "server_name._2gram^3", "server_name._3gram^3",
+							"server_id^2", "server_id._2gram^2", "server_id._3gram^2",
+						},
+					}},
+					{"multi_match": map[string]interface{}{
+						"query":     query,
+						"fields":    []string{"server_name^2", "server_id", "labels", "notes"},
+						"fuzziness": "AUTO",
+					}},
+					{"match": map[string]interface{}{
+						"addresses": map[string]interface{}{"query": query, "operator": "and"},
+					}},
+				},
+				"minimum_should_match": 1,
+			},
+		},
+		"highlight": map[string]interface{}{
+			"fields": map[string]interface{}{
+				"server_name": map[string]interface{}{},
+				"server_id":   map[string]interface{}{},
+				"addresses":   map[string]interface{}{},
+				"labels":      map[string]interface{}{},
+				"notes":       map[string]interface{}{},
+			},
+		},
+	})
+
+	resp, err := r.esc.Search(context.Background(), r.index, buf)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get Elasticsearch response. Err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var answer struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID        string              `json:"_id"`
+				Score     float64             `json:"_score"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to decode Elasticsearch query's result, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	result := &dto.ServerSearchResult{Hits: []dto.ServerSearchHit{}, Total: answer.Hits.Total.Value}
+	for _, hit := range answer.Hits.Hits {
+		result.Hits = append(result.Hits, dto.ServerSearchHit{ServerID: hit.ID, Score: hit.Score, Highlights: hit.Highlight})
+	}
+	return result, nil
+}
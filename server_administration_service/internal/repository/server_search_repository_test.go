@@ -0,0 +1,166 @@
+package repository_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestServerSearch_CreateIndex(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("CreateIndex", mock.Anything, "servers", mock.MatchedBy(func(body []byte) bool {
+		var definition map[string]interface{}
+		json.Unmarshal(body, &definition)
+		properties := definition["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+		return properties["server_name"].(map[string]interface{})["type"] == "search_as_you_type" &&
+			properties["addresses"].(map[string]interface{})["analyzer"] == "address_gram"
+	})).Return(true, nil)
+
+	created, err := repository.NewServerSearchRepository(mockESC, "servers").CreateIndex()
+	assert.NoError(t, err)
+	assert.True(t, created)
+	mockESC.AssertExpectations(t)
+}
+
+func TestServerSearch_IndexServers(t *testing.T) {
+	mockESC := new(MockESClient)
+	var body []byte
+	mockESC.On("Bulk", mock.Anything, "servers", mock.Anything).Run(func(args mock.Arguments) {
+		body = args.Get(2).([]byte)
+	}).Return(nil)
+
+	err := repository.NewServerSearchRepository(mockESC, "servers").IndexServers([]domain.Server{{
+		ServerID:   "srv-1",
+		ServerName: "web-1",
+		Addresses:  domain.Addresses{{Address: "10.0.3.14"}, {Address: "web-1.example.com"}},
+		Labels:     domain.Labels{"role": "web", "env": "prod"},
+		Notes:      "Rack 4",
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"index":{"_id":"srv-1"}}`+"\n"+
+		`{"server_id":"srv-1","server_name":"web-1","addresses":["10.0.3.14","web-1.example.com"],"labels":["env=prod","role=web"],"notes":"Rack 4"}`+"\n",
+		string(body))
+}
+
+func TestServerSearch_IndexServersBatches(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("Bulk", mock.Anything, "servers", mock.Anything).Return(nil)
+
+	servers := make([]domain.Server, 2500)
+	assert.NoError(t, repository.NewServerSearchRepository(mockESC, "servers").IndexServers(servers))
+	mockESC.AssertNumberOfCalls(t, "Bulk", 3)
+}
+
+func TestServerSearch_DeleteServers(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("Bulk", mock.Anything, "servers", []byte(`{"delete":{"_id":"srv-1"}}`+"\n"+`{"delete":{"_id":"srv-2"}}`+"\n")).Return(nil)
+
+	repo := repository.NewServerSearchRepository(mockESC, "servers")
+	assert.NoError(t, repo.DeleteServers([]string{"srv-1", "srv-2"}))
+	assert.NoError(t, repo.DeleteServers(nil))
+	mockESC.AssertNumberOfCalls(t, "Bulk", 1)
+}
+
+func TestServerSearch_SearchServers(t *testing.T) {
+	mockESC := new(MockESClient)
+	answer, _ := json.Marshal(map[string]interface{}{
+		"hits": map[string]interface{}{
+			"total": map[string]interface{}{"value": 2},
+			"hits": []interface{}{
+				map[string]interface{}{"_id": "srv-1", "_score": 4.2, "highlight": map[string]interface{}{"addresses": []string{"<em>10.0.3</em>.14"}}},
+				map[string]interface{}{"_id": "srv-7", "_score": 1.5},
+			},
+		},
+	})
+	mockESC.On("Search", mock.Anything, "servers", mock.MatchedBy(func(buf bytes.Buffer) bool {
+		return strings.Contains(buf.String(), `"query":"10.0.3"`) && strings.Contains(buf.String(), `"size":10`)
+	})).Return(&esapi.Response{StatusCode: 200, Body: io.NopCloser(bytes.NewReader(answer))}, nil)
+
+	result, err := repository.NewServerSearchRepository(mockESC, "servers").SearchServers("10.0.3", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ServerSearchResult{
+		Hits: []dto.ServerSearchHit{
+			{ServerID: "srv-1", Score: 4.2, Highlights: map[string][]string{"addresses": {"<em>10.0.3</em>.14"}}},
+			{ServerID: "srv-7", Score: 1.5},
+		},
+		Total: 2,
+	}, result)
+}
+
+func TestServerSearch_SearchServersError(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("Search", mock.Anything, "servers", mock.Anything).Return(nil, assert.AnError)
+
+	_, err := repository.NewServerSearchRepository(mockESC, "servers").SearchServers("web", 10)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestIndexedServerCRUD_UpdateServerReindexes(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	mockESC := new(MockESClient)
+	repo := repository.NewIndexedServerCRUDRepository(repository.NewServerCRUDRepository(gdb), repository.NewServerSearchRepository(mockESC, "servers"))
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "notes"=$1,"version"=version + 1,"last_updated"=$2 WHERE server_id = $3`)).
+		WithArgs("Rack 5", sqlmock.AnyArg(), "srv-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE server_id IN ($1) AND "servers"."deleted_at" IS NULL ORDER BY "server_id" LIMIT $2`)).
+		WithArgs("srv-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name", "notes"}).AddRow("srv-1", "web-1", "Rack 5"))
+	mockESC.On("Bulk", mock.Anything, "servers", mock.MatchedBy(func(body []byte) bool {
+		return strings.Contains(string(body), `"notes":"Rack 5"`)
+	})).Return(nil)
+
+	assert.NoError(t, repo.UpdateServer("srv-1", map[string]interface{}{"notes": "Rack 5"}, 0))
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+	mockESC.AssertExpectations(t)
+}
+
+func TestIndexedServerCRUD_IndexFailureKeepsWrite(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	mockESC := new(MockESClient)
+	repo := repository.NewIndexedServerCRUDRepository(repository.NewServerCRUDRepository(gdb), repository.NewServerSearchRepository(mockESC, "servers"))
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`INSERT INTO "servers"`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mockDB.ExpectCommit()
+	mockESC.On("Bulk", mock.Anything, "servers", mock.Anything).Return(assert.AnError)
+
+	id, err := repo.CreateServer(&domain.Server{ServerID: "srv-1", ServerName: "web-1", PrimaryAddress: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "srv-1", id)
+	mockESC.AssertExpectations(t)
+}
+
+func TestIndexedServerCRUD_DeleteServer(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	mockESC := new(MockESClient)
+	repo := repository.NewIndexedServerCRUDRepository(repository.NewServerCRUDRepository(gdb), repository.NewServerSearchRepository(mockESC, "servers"))
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(`UPDATE "servers" SET "deleted_at"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mockDB.ExpectCommit()
+	mockESC.On("Bulk", mock.Anything, "servers", []byte(`{"delete":{"_id":"srv-1"}}`+"\n")).Return(nil)
+
+	assert.NoError(t, repo.DeleteServer("srv-1", 0))
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+	mockESC.AssertExpectations(t)
+}
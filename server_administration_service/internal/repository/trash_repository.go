@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"server_administration_service/infrastructure/elasticsearch"
+	"server_administration_service/internal/domain"
+	"time"
+
+	"github.com/flashhhhh/pkg/env"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TrashRepository reaches the servers DeleteServer put in the trash, which every other repository leaves out.
+type TrashRepository interface {
+	GetTrashedServers() ([]domain.Server, error)
+	RestoreServer(serverID string) (*domain.Server, error)
+	PurgeServers(deletedBefore time.Time) ([]domain.Server, error)
+	DeleteStatusHistory(serverIDs []string) (int64, error)
+}
+
+type trashRepository struct {
+	db  *gorm.DB
+	esc elasticsearch.ElasticsearchClient
+}
+
+func NewTrashRepository(db *gorm.DB, esc elasticsearch.ElasticsearchClient) TrashRepository {
+	return &trashRepository{
+		db:  db,
+		esc: esc,
+	}
+}
+
+// GetTrashedServers returns the servers in the trash, most recently deleted first.
+func (r *trashRepository) GetTrashedServers() ([]domain.Server, error) {
+	var servers []domain.Server
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Order("server_id").Find(&servers).Error; err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+// RestoreServer takes a server out of the trash and returns it, gorm.ErrRecordNotFound when it isn't in the trash.
+func (r *trashRepository) RestoreServer(serverID string) (*domain.Server, error) {
+	var servers []domain.Server
+	result := r.db.Unscoped().Model(&servers).Clauses(clause.Returning{}).
+		Where("server_id = ? AND deleted_at IS NOT NULL", serverID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 || len(servers) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &servers[0], nil
+}
+
+// PurgeServers deletes for good the servers put in the trash before deletedBefore, together with their
+// group memberships and dependencies, and returns them.
+func (r *trashRepository) PurgeServers(deletedBefore time.Time) ([]domain.Server, error) {
+	var servers []domain.Server
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Returning{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Delete(&servers).Error; err != nil {
+			return err
+		}
+		if len(servers) == 0 {
+			return nil
+		}
+
+		serverIDs := make([]string, len(servers))
+		for i, server := range servers {
+			serverIDs[i] = server.ServerID
+		}
+
+		if err := tx.Where("server_id IN ?", serverIDs).Delete(&domain.ServerGroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("server_id IN ? OR depends_on_id IN ?", serverIDs, serverIDs).Delete(&domain.ServerDependency{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return servers, nil
+}
+
+// DeleteStatusHistory deletes the status documents of the servers from Elasticsearch and returns how many it deleted.
+func (r *trashRepository) DeleteStatusHistory(serverIDs []string) (int64, error) {
+	if len(serverIDs) == 0 {
+		return 0, nil
+	}
+
+	query, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"terms": map[string]interface{}{"ID.keyword": serverIDs},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return r.esc.DeleteByQuery(context.Background(), env.GetEnv("ES_NAME", "ping_status"), query)
+}
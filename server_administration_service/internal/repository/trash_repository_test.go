@@ -0,0 +1,145 @@
+package repository_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+
+	"server_administration_service/internal/repository"
+)
+
+func TestGetTrashedServers_Success(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC,server_id`)).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name", "deleted_at"}).AddRow("srv-1", "web-1", deletedAt))
+
+	servers, err := repo.GetTrashedServers()
+	assert.NoError(t, err)
+	assert.Len(t, servers, 1)
+	assert.Equal(t, deletedAt, servers[0].DeletedAt.Time)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestRestoreServer_Success(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1,"last_updated"=$2 WHERE server_id = $3 AND deleted_at IS NOT NULL RETURNING *`)).
+		WithArgs(nil, sqlmock.AnyArg(), "srv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "server_name"}).AddRow("srv-1", "web-1"))
+	mockDB.ExpectCommit()
+
+	server, err := repo.RestoreServer("srv-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "web-1", server.ServerName)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestRestoreServer_NotInTrash(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`UPDATE "servers"`).WillReturnRows(sqlmock.NewRows([]string{"server_id"}))
+	mockDB.ExpectCommit()
+
+	_, err := repo.RestoreServer("srv-1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestPurgeServers_Success(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	cutoff := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(regexp.QuoteMeta(`DELETE FROM "servers" WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING *`)).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1").AddRow("srv-2"))
+	mockDB.ExpectExec(regexp.QuoteMeta(`DELETE FROM "server_group_members" WHERE server_id IN ($1,$2)`)).
+		WithArgs("srv-1", "srv-2").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mockDB.ExpectExec(regexp.QuoteMeta(`DELETE FROM "server_dependencies" WHERE server_id IN ($1,$2) OR depends_on_id IN ($3,$4)`)).
+		WithArgs("srv-1", "srv-2", "srv-1", "srv-2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mockDB.ExpectCommit()
+
+	servers, err := repo.PurgeServers(cutoff)
+	assert.NoError(t, err)
+	assert.Len(t, servers, 2)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestPurgeServers_Nothing(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	cutoff := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`DELETE FROM "servers"`).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}))
+	mockDB.ExpectCommit()
+
+	servers, err := repo.PurgeServers(cutoff)
+	assert.NoError(t, err)
+	assert.Empty(t, servers)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestPurgeServers_RollsBack(t *testing.T) {
+	gdb, mockDB, cleanup := repository.SetupMockDB(t)
+	defer cleanup()
+
+	repo := repository.NewTrashRepository(gdb, nil)
+
+	// The servers stay in the trash when their memberships can't be removed
+	cutoff := time.Date(2026, 9, 19, 0, 0, 0, 0, time.UTC)
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`DELETE FROM "servers"`).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id"}).AddRow("srv-1"))
+	mockDB.ExpectExec(`DELETE FROM "server_group_members"`).
+		WillReturnError(errors.New("db error"))
+	mockDB.ExpectRollback()
+
+	servers, err := repo.PurgeServers(cutoff)
+	assert.Error(t, err)
+	assert.Nil(t, servers)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+func TestDeleteStatusHistory_Success(t *testing.T) {
+	mockESC := new(MockESClient)
+	mockESC.On("DeleteByQuery", mock.Anything, "ping_status", []byte(`{"query":{"terms":{"ID.keyword":["srv-1","srv-2"]}}}`)).Return(int64(420), nil)
+
+	repo := repository.NewTrashRepository(nil, mockESC)
+	deleted, err := repo.DeleteStatusHistory([]string{"srv-1", "srv-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(420), deleted)
+
+	deleted, err = repo.DeleteStatusHistory(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+	mockESC.AssertNumberOfCalls(t, "DeleteByQuery", 1)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditService interface {
+	GetAuditLog(filter dto.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+type auditService struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewAuditService(auditRepository repository.AuditRepository) AuditService {
+	return &auditService{
+		auditRepository: auditRepository,
+	}
+}
+
+// GetAuditLog returns the changes matching the filter, newest first; a zero limit means the default.
+func (s *auditService) GetAuditLog(filter dto.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditFilter)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditFilter, maxAuditLimit)
+	}
+
+	entries, err := s.auditRepository.GetAuditEntries(filter)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []domain.AuditEntry{}
+	}
+	return entries, nil
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock for AuditRepository
+type mockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *mockAuditRepository) CreateAuditEntries(entries []domain.AuditEntry) error {
+	args := m.Called(entries)
+	return args.Error(0)
+}
+
+func (m *mockAuditRepository) GetAuditEntries(filter dto.AuditFilter) ([]domain.AuditEntry, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+var auditActor = dto.Actor{UserID: "3f6c1a9e-user", SourceIP: "192.0.2.10"}
+
+// oneServer matches the lookup of a single server by its ID
+func oneServer(serverID string) interface{} {
+	return mock.MatchedBy(func(filter *dto.ServerFilter) bool {
+		return filter.Expression != nil && filter.Expression.Field == domain.FilterFieldServerID &&
+			assert.ObjectsAreEqual([]interface{}{serverID}, filter.Expression.Values)
+	})
+}
+
+func TestUpdateServer_RecordsAudit(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	before := domain.Server{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", SLATarget: 99.9,
+		Addresses: domain.Addresses{{Address: "10.0.0.1", Primary: true}}, Labels: domain.Labels{"env": "prod"}}
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{before}, nil)
+	mockRepo.On("GetConflictingServers", []string(nil), []string{"Server Uno"}, []string(nil)).Return([]domain.Server{}, nil)
+	mockRepo.On("UpdateServer", "srv1", mock.Anything, int64(0)).Return(nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		entry := entries[0]
+		return len(entries) == 1 && entry.ID != "" && entry.ServerID == "srv1" && entry.Action == domain.AuditUpdate &&
+			entry.UserID == auditActor.UserID && entry.SourceIP == auditActor.SourceIP &&
+			assert.ObjectsAreEqual(domain.AuditChanges{
+				"server_name": {Before: "Server One", After: "Server Uno"},
+				"sla_target":  {Before: 99.9, After: 99.5},
+			}, entry.Changes)
+	})).Return(nil)
+
+	err := svc.UpdateServer("srv1", map[string]interface{}{"server_name": "Server Uno", "sla_target": 99.5, "labels": domain.Labels{"env": "prod"}}, 0, auditActor)
+	assert.NoError(t, err)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestUpdateServer_FailedUpdateNotAudited(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{}, nil)
+	mockRepo.On("UpdateServer", "srv1", mock.Anything, int64(0)).Return(assert.AnError)
+
+	err := svc.UpdateServer("srv1", map[string]interface{}{"notes": "Rack 5"}, 0, auditActor)
+	assert.ErrorIs(t, err, assert.AnError)
+	mockAudit.AssertNotCalled(t, "CreateAuditEntries", mock.Anything)
+}
+
+func TestDeleteServer_RecordsAudit(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{{ServerID: "srv1", ServerName: "Server One"}}, nil)
+	mockRepo.On("DeleteServer", "srv1", int64(0)).Return(nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		change := entries[0].Changes["server_name"]
+		return entries[0].Action == domain.AuditDelete && change.Before == "Server One" && change.After == nil
+	})).Return(nil)
+
+	assert.NoError(t, svc.DeleteServer("srv1", 0, auditActor))
+	mockAudit.AssertExpectations(t)
+}
+
+func TestCreateServer_AuditFailureKeepsServer(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServer", mock.Anything).Return("srv1", nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		change := entries[0].Changes["server_name"]
+		return entries[0].Action == domain.AuditCreate && change.Before == nil && change.After == "Server One"
+	})).Return(assert.AnError)
+
+	id, err := svc.CreateServer("srv1", "Server One", domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", auditActor)
+	assert.NoError(t, err)
+	assert.Equal(t, "srv1", id)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestImportServers_RecordsAudit(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockStatus := new(mockServerStatusService)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, mockStatus, nil, mockAudit)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One Renamed,10.0.0.1\nsrv2,Server Two,10.0.0.2\n")
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", SLATarget: domain.DefaultSLATarget, Status: domain.StatusUp,
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}}, Labels: domain.Labels{}},
+	}, nil)
+	mockRepo.On("GetAllServers").Return([]domain.Server{{ServerID: "srv1"}, {ServerID: "old1", Status: domain.StatusUp}}, nil)
+	mockRepo.On("CreateServers", mock.Anything).Return([]domain.Server{{ServerID: "srv2", ServerName: "Server Two"}}, []domain.Server{}, nil)
+	mockRepo.On("UpdateServers", mock.Anything).Return(nil)
+	mockRepo.On("UpdateServersByID", []string{"old1"}, map[string]interface{}{"status": domain.StatusDecommissioned}).Return([]string{"old1"}, nil)
+	mockStatus.On("RecordStatusChanges", []string{"old1"}, domain.StatusDecommissioned).Return(nil)
+
+	var entries []domain.AuditEntry
+	mockAudit.On("CreateAuditEntries", mock.Anything).Run(func(args mock.Arguments) {
+		entries = append(entries, args.Get(0).([]domain.AuditEntry)...)
+	}).Return(nil)
+
+	options := dto.ImportOptions{Mode: service.ImportModeSync, DryRun: true, Actor: auditActor}
+	dryRun, err := svc.ImportServers(csvFile, options)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	options.DryRun = false
+	options.ConfirmationToken = dryRun.ConfirmationToken
+	_, err = svc.ImportServers(csvFile, options)
+	assert.NoError(t, err)
+
+	assert.Len(t, entries, 3)
+	actions := map[string]domain.AuditEntry{}
+	for _, entry := range entries {
+		assert.Equal(t, auditActor.UserID, entry.UserID)
+		actions[entry.Action] = entry
+	}
+	assert.Equal(t, "srv2", actions[domain.AuditCreate].ServerID)
+	// Only the renamed field of the updated server is recorded
+	assert.Equal(t, domain.AuditChanges{"server_name": {Before: "Server One", After: "Server One Renamed"}}, actions[domain.AuditUpdate].Changes)
+	assert.Equal(t, domain.AuditChanges{"status": {Before: domain.StatusUp, After: domain.StatusDecommissioned}}, actions[domain.AuditDecommission].Changes)
+}
+
+func TestGetAuditLog(t *testing.T) {
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewAuditService(mockAudit)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mockAudit.On("GetAuditEntries", dto.AuditFilter{ServerID: "srv1", From: &from, Limit: 100}).Return(nil, nil)
+
+	entries, err := svc.GetAuditLog(dto.AuditFilter{ServerID: "srv1", From: &from})
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.AuditEntry{}, entries)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestGetAuditLog_Invalid(t *testing.T) {
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewAuditService(mockAudit)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	for _, filter := range []dto.AuditFilter{{From: &from, To: &to}, {Limit: 1001}, {Limit: -1}} {
+		_, err := svc.GetAuditLog(filter)
+		assert.ErrorIs(t, err, service.ErrInvalidAuditFilter)
+	}
+	mockAudit.AssertNotCalled(t, "GetAuditEntries", mock.Anything)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+
+	"github.com/flashhhhh/pkg/logging"
+	"gorm.io/gorm"
+)
+
+type DependencyService interface {
+	AddDependency(serverID, dependsOnID string) error
+	RemoveDependency(serverID, dependsOnID string) error
+	GetGraph() (*domain.DependencyGraph, error)
+	GetDownDependencies(serverID string) ([]string, error)
+}
+
+type dependencyService struct {
+	dependencyRepository repository.DependencyRepository
+}
+
+func NewDependencyService(dependencyRepository repository.DependencyRepository) DependencyService {
+	return &dependencyService{
+		dependencyRepository: dependencyRepository,
+	}
+}
+
+// AddDependency declares that serverID is only reachable through dependsOnID.
+// Edges that would make a server depend on itself, directly or through other servers, are rejected.
+func (s *dependencyService) AddDependency(serverID, dependsOnID string) error {
+	if serverID == "" || dependsOnID == "" {
+		return fmt.Errorf("%w: server_id and depends_on are required", domain.ErrInvalidDependency)
+	}
+	if serverID == dependsOnID {
+		return fmt.Errorf("%w: a server can't depend on itself", domain.ErrInvalidDependency)
+	}
+
+	dependencies, err := s.dependencyRepository.GetDependencies()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get server dependencies, err: "+err.Error(), "ERROR")
+		return err
+	}
+	if domain.DependsOn(dependencies, dependsOnID, serverID) {
+		return fmt.Errorf("%w: %s already depends on %s", domain.ErrDependencyCycle, dependsOnID, serverID)
+	}
+
+	err = s.dependencyRepository.AddDependency(&domain.ServerDependency{
+		ServerID:    serverID,
+		DependsOnID: dependsOnID,
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrServerNotFound
+	}
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to add dependency "+serverID+" -> "+dependsOnID+", err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	logging.LogMessage("server_administration_service", "Server "+serverID+" now depends on "+dependsOnID, "INFO")
+	return nil
+}
+
+func (s *dependencyService) RemoveDependency(serverID, dependsOnID string) error {
+	if err := s.dependencyRepository.RemoveDependency(serverID, dependsOnID); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.LogMessage("server_administration_service", "Failed to remove dependency "+serverID+" -> "+dependsOnID+", err: "+err.Error(), "ERROR")
+		}
+		return err
+	}
+
+	logging.LogMessage("server_administration_service", "Server "+serverID+" no longer depends on "+dependsOnID, "INFO")
+	return nil
+}
+
+// GetGraph returns the dependency topology with the current status of every server in it.
+func (s *dependencyService) GetGraph() (*domain.DependencyGraph, error) {
+	dependencies, err := s.dependencyRepository.GetDependencies()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get server dependencies, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	serverIDs := []string{}
+	for _, dependency := range dependencies {
+		for _, id := range []string{dependency.ServerID, dependency.DependsOnID} {
+			if !seen[id] {
+				seen[id] = true
+				serverIDs = append(serverIDs, id)
+			}
+		}
+	}
+
+	servers, err := s.dependencyRepository.GetServers(serverIDs)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the servers of the dependency graph, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	graph := domain.NewDependencyGraph(servers, dependencies)
+	return &graph, nil
+}
+
+// GetDownDependencies returns the servers serverID depends on that are currently down or unreachable themselves.
+func (s *dependencyService) GetDownDependencies(serverID string) ([]string, error) {
+	return s.dependencyRepository.GetDownDependencies(serverID)
+}
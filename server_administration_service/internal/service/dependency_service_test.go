@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock implementation of DependencyRepository
+type mockDependencyRepository struct {
+	mock.Mock
+}
+
+func (m *mockDependencyRepository) AddDependency(dependency *domain.ServerDependency) error {
+	args := m.Called(dependency)
+	return args.Error(0)
+}
+
+func (m *mockDependencyRepository) RemoveDependency(serverID, dependsOnID string) error {
+	args := m.Called(serverID, dependsOnID)
+	return args.Error(0)
+}
+
+func (m *mockDependencyRepository) GetDependencies() ([]domain.ServerDependency, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServerDependency), args.Error(1)
+}
+
+func (m *mockDependencyRepository) GetServers(serverIDs []string) ([]domain.Server, error) {
+	args := m.Called(serverIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockDependencyRepository) GetDownDependencies(serverID string) ([]string, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestAddDependency_Success(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	mockRepo.On("GetDependencies").Return([]domain.ServerDependency{{ServerID: "switch-1", DependsOnID: "gateway-1"}}, nil)
+	mockRepo.On("AddDependency", &domain.ServerDependency{ServerID: "srv-1", DependsOnID: "switch-1"}).Return(nil)
+
+	assert.NoError(t, svc.AddDependency("srv-1", "switch-1"))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddDependency_Invalid(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	assert.ErrorIs(t, svc.AddDependency("srv-1", ""), domain.ErrInvalidDependency)
+	assert.ErrorIs(t, svc.AddDependency("srv-1", "srv-1"), domain.ErrInvalidDependency)
+	mockRepo.AssertNotCalled(t, "GetDependencies")
+}
+
+func TestAddDependency_Cycle(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	// gateway-1 -> switch-1 would close srv-1 -> switch-1 -> gateway-1
+	mockRepo.On("GetDependencies").Return([]domain.ServerDependency{
+		{ServerID: "srv-1", DependsOnID: "switch-1"},
+		{ServerID: "switch-1", DependsOnID: "gateway-1"},
+	}, nil)
+
+	err := svc.AddDependency("gateway-1", "srv-1")
+	assert.ErrorIs(t, err, domain.ErrDependencyCycle)
+	mockRepo.AssertNotCalled(t, "AddDependency", mock.Anything)
+}
+
+func TestAddDependency_UnknownServer(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	mockRepo.On("GetDependencies").Return([]domain.ServerDependency{}, nil)
+	mockRepo.On("AddDependency", mock.Anything).Return(gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, svc.AddDependency("srv-1", "missing"), ErrServerNotFound)
+}
+
+func TestRemoveDependency_NotFound(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	mockRepo.On("RemoveDependency", "srv-1", "switch-1").Return(gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, svc.RemoveDependency("srv-1", "switch-1"), gorm.ErrRecordNotFound)
+}
+
+func TestGetGraph_Success(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	mockRepo.On("GetDependencies").Return([]domain.ServerDependency{
+		{ServerID: "srv-2", DependsOnID: "switch-1"},
+		{ServerID: "srv-1", DependsOnID: "switch-1"},
+		{ServerID: "srv-3", DependsOnID: "deleted"},
+	}, nil)
+	mockRepo.On("GetServers", []string{"srv-2", "switch-1", "srv-1", "srv-3", "deleted"}).Return([]domain.Server{
+		{ServerID: "srv-1", ServerName: "web-1", Status: domain.StatusUnreachable},
+		{ServerID: "srv-2", ServerName: "web-2", Status: "Off"},
+		{ServerID: "srv-3", ServerName: "web-3", Status: domain.StatusUp},
+		{ServerID: "switch-1", ServerName: "core switch", Status: domain.StatusDown},
+	}, nil)
+
+	graph, err := svc.GetGraph()
+	assert.NoError(t, err)
+	// Edges to servers that no longer exist are dropped
+	assert.Equal(t, []domain.ServerDependency{
+		{ServerID: "srv-1", DependsOnID: "switch-1"},
+		{ServerID: "srv-2", DependsOnID: "switch-1"},
+	}, graph.Edges)
+	assert.Equal(t, []domain.DependencyNode{
+		{ServerID: "srv-1", ServerName: "web-1", Status: domain.StatusUnreachable},
+		{ServerID: "srv-2", ServerName: "web-2", Status: domain.StatusDown},
+		{ServerID: "switch-1", ServerName: "core switch", Status: domain.StatusDown},
+	}, graph.Nodes)
+
+	dot := graph.DOT()
+	assert.Contains(t, dot, "digraph dependencies {")
+	assert.Contains(t, dot, `"srv-1" [label="web-1 (srv-1)\nUnreachable", color=gray];`)
+	assert.Contains(t, dot, `"srv-2" -> "switch-1";`)
+}
+
+func TestGetGraph_Error(t *testing.T) {
+	mockRepo := new(mockDependencyRepository)
+	svc := NewDependencyService(mockRepo)
+
+	mockRepo.On("GetDependencies").Return(nil, errors.New("db error"))
+
+	graph, err := svc.GetGraph()
+	assert.Error(t, err)
+	assert.Nil(t, graph)
+}
@@ -0,0 +1,315 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/infrastructure/discovery"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrScanNotFound = errors.New("discovery scan not found")
+
+// DefaultDiscoveryPorts are tried over TCP when a scan doesn't name its ports, for hosts that drop ICMP.
+var DefaultDiscoveryPorts = []int{22, 80, 443}
+
+// Number of hosts probed at the same time by a scan
+const discoveryWorkers = 64
+
+type DiscoveryService interface {
+	StartScan(cidr string, ports []int) (*domain.DiscoveryScan, error)
+	GetScans() ([]domain.DiscoveryScan, error)
+	GetScan(id string) (*domain.DiscoveryScan, error)
+	GetHosts(scanID, status string) ([]domain.DiscoveredHost, error)
+	AcceptHosts(scanID string, acceptances []dto.DiscoveryAcceptance) ([]domain.Server, []domain.Server, error)
+}
+
+type discoveryService struct {
+	discoveryRepository  repository.DiscoveryRepository
+	serverCRUDRepository repository.ServerCRUDRepository
+	prober               discovery.Prober
+}
+
+func NewDiscoveryService(discoveryRepository repository.DiscoveryRepository, serverCRUDRepository repository.ServerCRUDRepository, prober discovery.Prober) DiscoveryService {
+	return &discoveryService{
+		discoveryRepository:  discoveryRepository,
+		serverCRUDRepository: serverCRUDRepository,
+		prober:               prober,
+	}
+}
+
+// StartScan validates the range and ports, records a Running scan and probes it in the background.
+func (s *discoveryService) StartScan(cidr string, ports []int) (*domain.DiscoveryScan, error) {
+	hosts, err := domain.DiscoveryHosts(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ports) == 0 {
+		ports = DefaultDiscoveryPorts
+	}
+	seen := make(map[int]bool, len(ports))
+	uniquePorts := make([]int, 0, len(ports))
+	for _, port := range ports {
+		if !domain.ValidDiscoveryPort(port) {
+			return nil, fmt.Errorf("%w: invalid port %d", domain.ErrInvalidDiscoveryScan, port)
+		}
+		if !seen[port] {
+			seen[port] = true
+			uniquePorts = append(uniquePorts, port)
+		}
+	}
+
+	scan := &domain.DiscoveryScan{
+		ID:        uuid.New().String(),
+		CIDR:      strings.TrimSpace(cidr),
+		Ports:     uniquePorts,
+		Status:    domain.ScanRunning,
+		StartedAt: time.Now(),
+	}
+	if _, err := s.discoveryRepository.CreateScan(scan); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create discovery scan of "+scan.CIDR+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	logging.LogMessage("server_administration_service", "Started discovery scan "+scan.ID+" of "+scan.CIDR, "INFO")
+	go s.runScan(*scan, hosts)
+	return scan, nil
+}
+
+// runScan probes every host of the scan, diffs the ones that answered against the inventory and records the result.
+func (s *discoveryService) runScan(scan domain.DiscoveryScan, hosts []string) {
+	found := s.probeHosts(hosts, scan.Ports)
+
+	err := s.classifyHosts(scan.ID, found)
+	if err == nil {
+		err = s.discoveryRepository.SaveHosts(found)
+	}
+
+	scan.NumProbed = len(hosts)
+	scan.NumFound = len(found)
+	scan.FinishedAt = time.Now()
+	scan.Status = domain.ScanCompleted
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Discovery scan "+scan.ID+" failed, err: "+err.Error(), "ERROR")
+		scan.Status = domain.ScanFailed
+		scan.Error = err.Error()
+	}
+
+	if err := s.discoveryRepository.UpdateScan(&scan); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to update discovery scan "+scan.ID+", err: "+err.Error(), "ERROR")
+		return
+	}
+	logging.LogMessage("server_administration_service", "Discovery scan "+scan.ID+" finished, "+strconv.Itoa(scan.NumFound)+" of "+strconv.Itoa(scan.NumProbed)+" hosts answered", "INFO")
+}
+
+// probeHosts returns the hosts that answered an ICMP echo or accepted a TCP connection on one of the ports.
+func (s *discoveryService) probeHosts(hosts []string, ports []int) []domain.DiscoveredHost {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		found = []domain.DiscoveredHost{}
+	)
+	slots := make(chan struct{}, discoveryWorkers)
+
+	for _, address := range hosts {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(address string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			method := s.probeHost(address, ports)
+			if method == "" {
+				return
+			}
+			host := domain.DiscoveredHost{
+				Address:  address,
+				Hostname: s.prober.LookupHostname(address),
+				Method:   method,
+			}
+
+			mu.Lock()
+			found = append(found, host)
+			mu.Unlock()
+		}(address)
+	}
+	wg.Wait()
+
+	return found
+}
+
+// probeHost returns the first probe the host answered, "" when it answered none.
+func (s *discoveryService) probeHost(address string, ports []int) string {
+	if s.prober.Ping(address) {
+		return "icmp"
+	}
+	for _, port := range ports {
+		if s.prober.ConnectTCP(address, port) {
+			return "tcp/" + strconv.Itoa(port)
+		}
+	}
+	return ""
+}
+
+// classifyHosts marks the hosts whose address or hostname a server already has as Known, and the others as New.
+func (s *discoveryService) classifyHosts(scanID string, hosts []domain.DiscoveredHost) error {
+	inventory, err := s.discoveryRepository.GetInventoryAddresses()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]string)
+	for _, server := range inventory {
+		known[strings.ToLower(server.PrimaryAddress)] = server.ServerID
+		for _, address := range server.Addresses {
+			known[strings.ToLower(address.Address)] = server.ServerID
+		}
+	}
+
+	for i := range hosts {
+		hosts[i].ScanID = scanID
+		hosts[i].Status = domain.HostNew
+
+		serverID, ok := known[strings.ToLower(hosts[i].Address)]
+		if !ok && hosts[i].Hostname != "" {
+			serverID, ok = known[strings.ToLower(hosts[i].Hostname)]
+		}
+		if ok {
+			hosts[i].Status = domain.HostKnown
+			hosts[i].ServerID = serverID
+		}
+	}
+	return nil
+}
+
+func (s *discoveryService) GetScans() ([]domain.DiscoveryScan, error) {
+	scans, err := s.discoveryRepository.GetScans()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get discovery scans, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	return scans, nil
+}
+
+func (s *discoveryService) GetScan(id string) (*domain.DiscoveryScan, error) {
+	scan, err := s.discoveryRepository.GetScan(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScanNotFound
+	}
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get discovery scan "+id+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	return scan, nil
+}
+
+// GetHosts returns the hosts found by a scan, only those in the given status unless it's empty.
+func (s *discoveryService) GetHosts(scanID, status string) ([]domain.DiscoveredHost, error) {
+	if _, err := s.GetScan(scanID); err != nil {
+		return nil, err
+	}
+
+	hosts, err := s.discoveryRepository.GetHosts(scanID, status)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get hosts of discovery scan "+scanID+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// AcceptHosts creates a server for every accepted New host of a finished scan, in one bulk insert like an import.
+// The host's address becomes the primary address and its reverse DNS name, if any, a second address.
+// It returns the servers created and the ones that weren't, because their ID or address is already taken.
+func (s *discoveryService) AcceptHosts(scanID string, acceptances []dto.DiscoveryAcceptance) ([]domain.Server, []domain.Server, error) {
+	scan, err := s.GetScan(scanID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scan.Status != domain.ScanCompleted {
+		return nil, nil, fmt.Errorf("%w: scan %s is %s", domain.ErrInvalidDiscoveryScan, scanID, scan.Status)
+	}
+	if len(acceptances) == 0 {
+		return nil, nil, fmt.Errorf("%w: no host to accept", domain.ErrInvalidDiscoveryScan)
+	}
+
+	hosts, err := s.discoveryRepository.GetHosts(scanID, domain.HostNew)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get hosts of discovery scan "+scanID+", err: "+err.Error(), "ERROR")
+		return nil, nil, err
+	}
+	newHosts := make(map[string]domain.DiscoveredHost, len(hosts))
+	for _, host := range hosts {
+		newHosts[host.Address] = host
+	}
+
+	servers := make([]domain.Server, 0, len(acceptances))
+	addressOf := make(map[string]string, len(acceptances))
+	for _, acceptance := range acceptances {
+		host, ok := newHosts[acceptance.Address]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s isn't a new host of scan %s", domain.ErrInvalidDiscoveryScan, acceptance.Address, scanID)
+		}
+		// Accepting a host twice in the same request would create two servers with the same address
+		delete(newHosts, acceptance.Address)
+
+		name := host.Hostname
+		if name == "" {
+			name = host.Address
+		}
+		if acceptance.ServerID == "" {
+			acceptance.ServerID = name
+		}
+		if acceptance.ServerName == "" {
+			acceptance.ServerName = name
+		}
+
+		addresses := domain.Addresses{{Address: host.Address, Primary: true}}
+		if host.Hostname != "" {
+			addresses = append(addresses, domain.Address{Type: domain.AddressTypeFQDN, Address: host.Hostname})
+		}
+		if err := addresses.Normalize(); err != nil {
+			// A reverse DNS name that isn't a valid host name is left out rather than failing the host
+			addresses = domain.Addresses{{Address: host.Address, Primary: true}}
+			addresses.Normalize()
+		}
+
+		servers = append(servers, domain.Server{
+			ServerID:       acceptance.ServerID,
+			ServerName:     acceptance.ServerName,
+			Status:         domain.StatusUnknown,
+			PrimaryAddress: host.Address,
+			Addresses:      addresses,
+			SLATarget:      domain.DefaultSLATarget,
+			Labels:         domain.Labels{},
+		})
+		addressOf[acceptance.ServerID] = host.Address
+	}
+
+	insertedServers, nonInsertedServers, err := s.serverCRUDRepository.CreateServers(servers, nil)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create servers from discovery scan "+scanID+", err: "+err.Error(), "ERROR")
+		return nil, nil, err
+	}
+
+	accepted := make(map[string]string, len(insertedServers))
+	for _, server := range insertedServers {
+		accepted[addressOf[server.ServerID]] = server.ServerID
+	}
+	if err := s.discoveryRepository.AcceptHosts(scanID, accepted); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to mark hosts of discovery scan "+scanID+" as accepted, err: "+err.Error(), "ERROR")
+		return nil, nil, err
+	}
+
+	logging.LogMessage("server_administration_service", strconv.Itoa(len(insertedServers))+" servers accepted from discovery scan "+scanID, "INFO")
+	return insertedServers, nonInsertedServers, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock implementation of DiscoveryRepository
+type mockDiscoveryRepository struct {
+	mock.Mock
+}
+
+func (m *mockDiscoveryRepository) CreateScan(scan *domain.DiscoveryScan) (string, error) {
+	args := m.Called(scan)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockDiscoveryRepository) UpdateScan(scan *domain.DiscoveryScan) error {
+	args := m.Called(scan)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryRepository) GetScan(id string) (*domain.DiscoveryScan, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DiscoveryScan), args.Error(1)
+}
+
+func (m *mockDiscoveryRepository) GetScans() ([]domain.DiscoveryScan, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DiscoveryScan), args.Error(1)
+}
+
+func (m *mockDiscoveryRepository) SaveHosts(hosts []domain.DiscoveredHost) error {
+	args := m.Called(hosts)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryRepository) GetHosts(scanID, status string) ([]domain.DiscoveredHost, error) {
+	args := m.Called(scanID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DiscoveredHost), args.Error(1)
+}
+
+func (m *mockDiscoveryRepository) AcceptHosts(scanID string, serverIDs map[string]string) error {
+	args := m.Called(scanID, serverIDs)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryRepository) GetInventoryAddresses() ([]dto.ServerAddress, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.ServerAddress), args.Error(1)
+}
+
+// Mock implementation of ServerCRUDRepository, only CreateServers is used by discovery
+type mockDiscoveryServerRepository struct {
+	mock.Mock
+}
+
+func (m *mockDiscoveryServerRepository) CreateServer(server *domain.Server) (string, error) {
+	args := m.Called(server)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) GetConflictingServers(serverIDs, serverNames, addresses []string) ([]domain.Server, error) {
+	args := m.Called(serverIDs, serverNames, addresses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) UpdateServers(servers []domain.Server) error {
+	args := m.Called(servers)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryServerRepository) GetAllServers() ([]domain.Server, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error) {
+	args := m.Called(servers)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]domain.Server), args.Get(1).([]domain.Server), args.Error(2)
+}
+
+func (m *mockDiscoveryServerRepository) ViewServers(filter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error) {
+	args := m.Called(filter, from, to, sort)
+	return nil, args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) ViewServersByKey(filter *dto.ServerFilter, keyset dto.ServerKeyset) ([]domain.Server, error) {
+	args := m.Called(filter, keyset)
+	return nil, args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) CountServers(filter *dto.ServerFilter) (int64, error) {
+	args := m.Called(filter)
+	return 0, args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) StreamServers(filter *dto.ServerFilter, from, to int, sort domain.ServerSort, fn func(server domain.Server) error) error {
+	args := m.Called(filter, from, to, sort)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryServerRepository) UpdateServer(serverID string, updatedData map[string]interface{}, version int64) error {
+	args := m.Called(serverID, updatedData, version)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryServerRepository) DeleteServer(serverID string, version int64) error {
+	args := m.Called(serverID, version)
+	return args.Error(0)
+}
+
+func (m *mockDiscoveryServerRepository) UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error) {
+	args := m.Called(serverIDs, updatedData)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) DeleteServersByID(serverIDs []string) ([]string, error) {
+	args := m.Called(serverIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockDiscoveryServerRepository) Transaction(fn func(tx repository.ServerCRUDRepository) error) error {
+	return fn(m)
+}
+
+// fakeProber answers from fixed tables instead of the network
+type fakeProber struct {
+	pings     map[string]bool
+	ports     map[string]int
+	hostnames map[string]string
+}
+
+func (p *fakeProber) Ping(address string) bool {
+	return p.pings[address]
+}
+
+func (p *fakeProber) ConnectTCP(address string, port int) bool {
+	return p.ports[address] == port
+}
+
+func (p *fakeProber) LookupHostname(address string) string {
+	return p.hostnames[address]
+}
+
+func TestStartScan_Invalid(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	svc := NewDiscoveryService(mockRepo, new(mockDiscoveryServerRepository), &fakeProber{})
+
+	_, err := svc.StartScan("10.0.0.0/8", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+	_, err = svc.StartScan("not a range", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+	_, err = svc.StartScan("10.0.0.0/24", []int{22, 70000})
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+	mockRepo.AssertNotCalled(t, "CreateScan", mock.Anything)
+}
+
+func TestRunScan_ClassifiesHosts(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	prober := &fakeProber{
+		pings:     map[string]bool{"10.0.0.1": true},
+		ports:     map[string]int{"10.0.0.2": 443},
+		hostnames: map[string]string{"10.0.0.2": "web-2.example.com"},
+	}
+	svc := &discoveryService{discoveryRepository: mockRepo, prober: prober}
+
+	mockRepo.On("GetInventoryAddresses").Return([]dto.ServerAddress{
+		{ServerID: "gw-1", PrimaryAddress: "10.0.0.1", Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}}},
+	}, nil)
+	mockRepo.On("SaveHosts", mock.MatchedBy(func(hosts []domain.DiscoveredHost) bool {
+		byAddress := map[string]domain.DiscoveredHost{}
+		for _, host := range hosts {
+			byAddress[host.Address] = host
+		}
+		return len(hosts) == 2 &&
+			byAddress["10.0.0.1"] == domain.DiscoveredHost{ScanID: "scan-1", Address: "10.0.0.1", Method: "icmp", Status: domain.HostKnown, ServerID: "gw-1"} &&
+			byAddress["10.0.0.2"] == domain.DiscoveredHost{ScanID: "scan-1", Address: "10.0.0.2", Hostname: "web-2.example.com", Method: "tcp/443", Status: domain.HostNew}
+	})).Return(nil)
+	mockRepo.On("UpdateScan", mock.MatchedBy(func(scan *domain.DiscoveryScan) bool {
+		return scan.Status == domain.ScanCompleted && scan.NumProbed == 6 && scan.NumFound == 2
+	})).Return(nil)
+
+	hosts, _ := domain.DiscoveryHosts("10.0.0.0/29")
+	svc.runScan(domain.DiscoveryScan{ID: "scan-1", CIDR: "10.0.0.0/29", Ports: []int{22, 443}}, hosts)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRunScan_Failed(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	svc := &discoveryService{discoveryRepository: mockRepo, prober: &fakeProber{pings: map[string]bool{"10.0.0.1": true}}}
+
+	mockRepo.On("GetInventoryAddresses").Return(nil, gorm.ErrInvalidDB)
+	mockRepo.On("UpdateScan", mock.MatchedBy(func(scan *domain.DiscoveryScan) bool {
+		return scan.Status == domain.ScanFailed && scan.Error != ""
+	})).Return(nil)
+
+	svc.runScan(domain.DiscoveryScan{ID: "scan-1"}, []string{"10.0.0.1"})
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SaveHosts", mock.Anything)
+}
+
+func TestAcceptHosts_Success(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	mockServerRepo := new(mockDiscoveryServerRepository)
+	svc := NewDiscoveryService(mockRepo, mockServerRepo, &fakeProber{})
+
+	mockRepo.On("GetScan", "scan-1").Return(&domain.DiscoveryScan{ID: "scan-1", Status: domain.ScanCompleted}, nil)
+	mockRepo.On("GetHosts", "scan-1", domain.HostNew).Return([]domain.DiscoveredHost{
+		{ScanID: "scan-1", Address: "10.0.0.2", Hostname: "web-2.example.com", Status: domain.HostNew},
+		{ScanID: "scan-1", Address: "10.0.0.3", Status: domain.HostNew},
+	}, nil)
+
+	expected := []domain.Server{
+		{
+			ServerID:       "web-2.example.com",
+			ServerName:     "web-2.example.com",
+			Status:         domain.StatusUnknown,
+			PrimaryAddress: "10.0.0.2",
+			Addresses: domain.Addresses{
+				{Type: domain.AddressTypeIPv4, Address: "10.0.0.2", Primary: true},
+				{Type: domain.AddressTypeFQDN, Address: "web-2.example.com"},
+			},
+			SLATarget: domain.DefaultSLATarget,
+			Labels:    domain.Labels{},
+		},
+		{
+			ServerID:       "db-3",
+			ServerName:     "Database",
+			Status:         domain.StatusUnknown,
+			PrimaryAddress: "10.0.0.3",
+			Addresses:      domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.3", Primary: true}},
+			SLATarget:      domain.DefaultSLATarget,
+			Labels:         domain.Labels{},
+		},
+	}
+	mockServerRepo.On("CreateServers", expected).Return(expected[:1], expected[1:], nil)
+	mockRepo.On("AcceptHosts", "scan-1", map[string]string{"10.0.0.2": "web-2.example.com"}).Return(nil)
+
+	inserted, nonInserted, err := svc.AcceptHosts("scan-1", []dto.DiscoveryAcceptance{
+		{Address: "10.0.0.2"},
+		{Address: "10.0.0.3", ServerID: "db-3", ServerName: "Database"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, inserted, 1)
+	assert.Len(t, nonInserted, 1)
+	mockRepo.AssertExpectations(t)
+	mockServerRepo.AssertExpectations(t)
+}
+
+func TestAcceptHosts_NotNewHost(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	mockServerRepo := new(mockDiscoveryServerRepository)
+	svc := NewDiscoveryService(mockRepo, mockServerRepo, &fakeProber{})
+
+	mockRepo.On("GetScan", "scan-1").Return(&domain.DiscoveryScan{ID: "scan-1", Status: domain.ScanCompleted}, nil)
+	mockRepo.On("GetHosts", "scan-1", domain.HostNew).Return([]domain.DiscoveredHost{{ScanID: "scan-1", Address: "10.0.0.2", Status: domain.HostNew}}, nil)
+
+	_, _, err := svc.AcceptHosts("scan-1", []dto.DiscoveryAcceptance{{Address: "10.0.0.2"}, {Address: "10.0.0.2"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+	_, _, err = svc.AcceptHosts("scan-1", []dto.DiscoveryAcceptance{{Address: "10.0.0.9"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+	mockServerRepo.AssertNotCalled(t, "CreateServers", mock.Anything)
+}
+
+func TestAcceptHosts_ScanRunning(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	svc := NewDiscoveryService(mockRepo, new(mockDiscoveryServerRepository), &fakeProber{})
+
+	mockRepo.On("GetScan", "scan-1").Return(&domain.DiscoveryScan{ID: "scan-1", Status: domain.ScanRunning}, nil)
+
+	_, _, err := svc.AcceptHosts("scan-1", []dto.DiscoveryAcceptance{{Address: "10.0.0.2"}})
+	assert.ErrorIs(t, err, domain.ErrInvalidDiscoveryScan)
+}
+
+func TestGetScan_NotFound(t *testing.T) {
+	mockRepo := new(mockDiscoveryRepository)
+	svc := NewDiscoveryService(mockRepo, new(mockDiscoveryServerRepository), &fakeProber{})
+
+	mockRepo.On("GetScan", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.GetScan("missing")
+	assert.ErrorIs(t, err, ErrScanNotFound)
+}
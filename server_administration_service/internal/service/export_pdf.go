@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 landscape, in points
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 30.0
+	pdfFontSize   = 7.0
+	pdfRowHeight  = 11.0
+)
+
+// Objects written before the pages: the catalog, the page tree written last, and the two fonts.
+const (
+	pdfCatalogObject = 1
+	pdfPagesObject   = 2
+	pdfFontObject    = 3
+	pdfBoldObject    = 4
+)
+
+// pdfTableWriter writes rows as a table to a PDF document as they come: each page goes out as soon as
+// it's full, so only the current one is kept in memory. Text uses the standard Helvetica fonts with
+// WinAnsi encoding, characters outside Latin-1 show as '?', and cells too long for their column are cut.
+type pdfTableWriter struct {
+	w       io.Writer
+	written int
+	offsets map[int]int
+	next    int
+	pages   []int
+	title   string
+	headers []string
+	widths  []float64
+	page    bytes.Buffer
+	y       float64
+	err     error
+}
+
+func newPDFTableWriter(w io.Writer, title string, headers []string, widths []float64) (*pdfTableWriter, error) {
+	p := &pdfTableWriter{
+		w:       w,
+		offsets: make(map[int]int),
+		next:    pdfBoldObject + 1,
+		title:   title,
+		headers: headers,
+		widths:  widths,
+	}
+
+	p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	p.object(pdfCatalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObject))
+	p.object(pdfFontObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	p.object(pdfBoldObject, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return p, p.err
+}
+
+func (p *pdfTableWriter) writeRow(cells []string) error {
+	if p.page.Len() > 0 && p.y-pdfRowHeight < pdfMargin+pdfRowHeight {
+		p.endPage()
+	}
+	if p.page.Len() == 0 {
+		p.startPage()
+	}
+	p.row("F1", cells)
+	return p.err
+}
+
+// close writes the last page, the page tree and the cross-reference table.
+func (p *pdfTableWriter) close() error {
+	if p.page.Len() == 0 {
+		p.startPage()
+	}
+	p.endPage()
+
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	p.object(pdfPagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+
+	xref := p.written
+	p.write(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", p.next))
+	for object := 1; object < p.next; object++ {
+		p.write(fmt.Sprintf("%010d 00000 n \n", p.offsets[object]))
+	}
+	p.write(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.next, pdfCatalogObject, xref))
+	return p.err
+}
+
+// startPage writes the title and the header row at the top of a new page.
+func (p *pdfTableWriter) startPage() {
+	p.y = pdfPageHeight - pdfMargin
+	fmt.Fprintf(&p.page, "BT /F2 10 Tf %.2f %.2f Td (%s) Tj ET\n", pdfMargin, p.y, pdfText(p.title))
+	p.y -= 2 * pdfRowHeight
+
+	p.row("F2", p.headers)
+	line := p.y + pdfRowHeight - 3
+	fmt.Fprintf(&p.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, line, pdfPageWidth-pdfMargin, line)
+}
+
+// endPage writes the current page out with its number at the bottom.
+func (p *pdfTableWriter) endPage() {
+	fmt.Fprintf(&p.page, "BT /F1 %.1f Tf %.2f %.2f Td (Page %d) Tj ET\n", pdfFontSize, pdfPageWidth-pdfMargin-40, pdfMargin/2, len(p.pages)+1)
+
+	contents := p.next
+	page := p.next + 1
+	p.next += 2
+
+	p.object(contents, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.page.Len(), p.page.String()))
+	p.object(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObject, pdfPageWidth, pdfPageHeight, pdfFontObject, pdfBoldObject, contents))
+	p.pages = append(p.pages, page)
+	p.page.Reset()
+}
+
+func (p *pdfTableWriter) row(font string, cells []string) {
+	x := pdfMargin
+	for i, cell := range cells {
+		if i >= len(p.widths) {
+			break
+		}
+		fmt.Fprintf(&p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, pdfFontSize, x, p.y, pdfText(fitPDFCell(cell, p.widths[i])))
+		x += p.widths[i]
+	}
+	p.y -= pdfRowHeight
+}
+
+func (p *pdfTableWriter) object(number int, body string) {
+	p.offsets[number] = p.written
+	p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", number, body))
+}
+
+func (p *pdfTableWriter) write(s string) {
+	if p.err != nil {
+		return
+	}
+	n, err := io.WriteString(p.w, s)
+	p.written += n
+	p.err = err
+}
+
+// fitPDFCell cuts a cell to what fits its column, counting Helvetica characters as 0.55 em wide on average.
+func fitPDFCell(cell string, width float64) string {
+	runes := []rune(cell)
+	fits := int((width - 4) / (pdfFontSize * 0.55))
+	if len(runes) <= fits || fits < 3 {
+		return cell
+	}
+	return string(runes[:fits-3]) + "..."
+}
+
+// pdfText escapes a string literal and encodes it in Latin-1.
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r > 0xff:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
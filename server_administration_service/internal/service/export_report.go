@@ -0,0 +1,283 @@
+package service
+
+import (
+	"math"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// reportTrendLimit caps the points of the uptime trend; a range spans a point per day, or per hour
+// when it's two days or less.
+const reportTrendLimit = 60
+
+// reportStatuses are the statuses the Summary sheet always lists, in order, with their fill and font
+// colors on the Servers sheet.
+var reportStatuses = []struct {
+	status string
+	fill   string
+	font   string
+}{
+	{domain.StatusUp, "C6EFCE", "006100"},
+	{domain.StatusDegraded, "FFEB9C", "9C5700"},
+	{domain.StatusDown, "FFC7CE", "9C0006"},
+	{domain.StatusUnreachable, "F4B084", "833C0B"},
+	{domain.StatusMaintenance, "DDEBF7", "1F4E78"},
+	{domain.StatusUnknown, "EDEDED", "595959"},
+	{domain.StatusDecommissioned, "D9D9D9", "3A3A3A"},
+}
+
+// exportReport makes an xlsx export a report: the Servers sheet gets a styled header, an autofilter,
+// a frozen header row and colored statuses, and a Summary sheet counts the servers by status and,
+// for an uptime range, sums up their uptime with charts of both.
+type exportReport struct {
+	title      string
+	upTimeFrom *time.Time
+	upTimeTo   *time.Time
+	trend      []dto.UpTimePoint
+
+	statuses    map[string]int
+	total       int
+	upTimeSum   float64
+	upTimeCount int
+	belowSLA    int
+}
+
+func newExportReport(title string, upTimeFrom, upTimeTo *time.Time, trend []dto.UpTimePoint) *exportReport {
+	return &exportReport{
+		title:      title,
+		upTimeFrom: upTimeFrom,
+		upTimeTo:   upTimeTo,
+		trend:      trend,
+		statuses:   make(map[string]int),
+	}
+}
+
+// reportTrendPoints returns how many points the uptime trend over [from, to] has.
+func reportTrendPoints(from, to time.Time) int {
+	step := 24 * time.Hour
+	if to.Sub(from) <= 2*24*time.Hour {
+		step = time.Hour
+	}
+	points := int(math.Ceil(float64(to.Sub(from)) / float64(step)))
+	if points > reportTrendLimit {
+		points = reportTrendLimit
+	}
+	if points < 1 {
+		points = 1
+	}
+	return points
+}
+
+func (r *exportReport) add(server exportedServer) {
+	r.total++
+	r.statuses[server.Status]++
+	if server.UpTime == nil {
+		return
+	}
+	r.upTimeSum += *server.UpTime
+	r.upTimeCount++
+	if *server.UpTime < server.SLATarget {
+		r.belowSLA++
+	}
+}
+
+// headerStyle is the style of the header rows on both sheets.
+func (r *exportReport) headerStyle(f *excelize.File) (int, error) {
+	return f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+		Border:    []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+	})
+}
+
+// startServers sets up the Servers sheet before its first row; the stream writer only takes column
+// widths and panes then. It returns the header row to write.
+func (r *exportReport) startServers(f *excelize.File, stream *excelize.StreamWriter, columns []exportColumn) ([]interface{}, error) {
+	style, err := r.headerStyle(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range columns {
+		width := column.pdfWidth / 5
+		if width < 18 {
+			width = 18
+		}
+		if err := stream.SetColWidth(i+1, i+1, width); err != nil {
+			return nil, err
+		}
+	}
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return nil, err
+	}
+
+	headers := make([]interface{}, len(columns))
+	for i, column := range columns {
+		headers[i] = excelize.Cell{StyleID: style, Value: column.header}
+	}
+	return headers, nil
+}
+
+// finishServers adds the autofilter and the status colors once the last of the rows is written.
+func (r *exportReport) finishServers(f *excelize.File, sheet string, columns []exportColumn, rows int) error {
+	last, _ := excelize.CoordinatesToCellName(len(columns), rows)
+	if err := f.AutoFilter(sheet, "A1:"+last, nil); err != nil {
+		return err
+	}
+	if rows < 2 {
+		return nil
+	}
+
+	for i, column := range columns {
+		if column.header != "Status" {
+			continue
+		}
+		first, _ := excelize.CoordinatesToCellName(i+1, 2)
+		last, _ := excelize.CoordinatesToCellName(i+1, rows)
+
+		formats := make([]excelize.ConditionalFormatOptions, 0, len(reportStatuses))
+		for _, status := range reportStatuses {
+			style, err := f.NewConditionalStyle(&excelize.Style{
+				Font: &excelize.Font{Color: status.font},
+				Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{status.fill}},
+			})
+			if err != nil {
+				return err
+			}
+			formats = append(formats, excelize.ConditionalFormatOptions{
+				Type: "cell", Criteria: "==", Format: &style, Value: `"` + status.status + `"`,
+			})
+		}
+		return f.SetConditionalFormat(sheet, first+":"+last, formats)
+	}
+	return nil
+}
+
+// writeSummary adds the Summary sheet: the counts by status with a pie chart of them and, for an
+// uptime range, the mean uptime, the servers below their SLA target and a line chart of the trend.
+func (r *exportReport) writeSummary(f *excelize.File) error {
+	const sheet = "Summary"
+	if _, err := f.NewSheet(sheet); err != nil {
+		return err
+	}
+	header, err := r.headerStyle(f)
+	if err != nil {
+		return err
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	title, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14}})
+	if err != nil {
+		return err
+	}
+	f.SetColWidth(sheet, "A", "A", 22)
+	f.SetColWidth(sheet, "B", "B", 16)
+
+	f.SetCellValue(sheet, "A1", r.title)
+	f.SetCellStyle(sheet, "A1", "A1", title)
+
+	f.SetSheetRow(sheet, "A3", &[]interface{}{"Status", "Servers"})
+	f.SetCellStyle(sheet, "A3", "B3", header)
+	row := 4
+	for _, status := range r.statusOrder() {
+		f.SetSheetRow(sheet, cellName(1, row), &[]interface{}{status, r.statuses[status]})
+		row++
+	}
+	f.SetSheetRow(sheet, cellName(1, row), &[]interface{}{"Total", r.total})
+	f.SetCellStyle(sheet, cellName(1, row), cellName(2, row), bold)
+
+	if err := f.AddChart(sheet, "D3", &excelize.Chart{
+		Type: excelize.Pie,
+		Series: []excelize.ChartSeries{{
+			Name:       sheet + "!$B$3",
+			Categories: sheet + "!$A$4:$A$" + strconv.Itoa(row-1),
+			Values:     sheet + "!$B$4:$B$" + strconv.Itoa(row-1),
+		}},
+		Title:  []excelize.RichTextRun{{Text: "Status distribution"}},
+		Legend: excelize.ChartLegend{Position: "right"},
+		PlotArea: excelize.ChartPlotArea{ShowPercent: true},
+	}); err != nil {
+		return err
+	}
+
+	if r.upTimeFrom == nil || r.upTimeTo == nil {
+		return nil
+	}
+
+	row += 2
+	meanUpTime := interface{}("")
+	if r.upTimeCount > 0 {
+		meanUpTime = math.Round(r.upTimeSum/float64(r.upTimeCount)*100) / 100
+	}
+	for _, values := range [][]interface{}{
+		{"Uptime from", r.upTimeFrom.Format(time.RFC3339)},
+		{"Uptime to", r.upTimeTo.Format(time.RFC3339)},
+		{"Mean uptime (%)", meanUpTime},
+		{"Below SLA target", r.belowSLA},
+	} {
+		f.SetSheetRow(sheet, cellName(1, row), &values)
+		f.SetCellStyle(sheet, cellName(1, row), cellName(1, row), bold)
+		row++
+	}
+
+	if len(r.trend) == 0 {
+		return nil
+	}
+	row++
+	f.SetSheetRow(sheet, cellName(1, row), &[]interface{}{"Period starting", "Mean uptime (%)"})
+	f.SetCellStyle(sheet, cellName(1, row), cellName(2, row), header)
+	first := row + 1
+	layout := "2006-01-02"
+	if r.upTimeTo.Sub(*r.upTimeFrom) <= 2*24*time.Hour {
+		layout = "2006-01-02 15:04"
+	}
+	for _, point := range r.trend {
+		row++
+		f.SetSheetRow(sheet, cellName(1, row), &[]interface{}{point.Time.Format(layout), math.Round(point.UpTime*100) / 100})
+	}
+
+	minimum := 0.0
+	maximum := 100.0
+	return f.AddChart(sheet, "D20", &excelize.Chart{
+		Type: excelize.Line,
+		Series: []excelize.ChartSeries{{
+			Name:       sheet + "!$B$" + strconv.Itoa(first-1),
+			Categories: sheet + "!$A$" + strconv.Itoa(first) + ":$A$" + strconv.Itoa(row),
+			Values:     sheet + "!$B$" + strconv.Itoa(first) + ":$B$" + strconv.Itoa(row),
+		}},
+		Title:  []excelize.RichTextRun{{Text: "Uptime trend"}},
+		Legend: excelize.ChartLegend{Position: "none"},
+		YAxis:  excelize.ChartAxis{Minimum: &minimum, Maximum: &maximum},
+		Dimension: excelize.ChartDimension{Width: 720, Height: 290},
+	})
+}
+
+// statusOrder lists the known statuses, then any other status the servers had, by name.
+func (r *exportReport) statusOrder() []string {
+	order := make([]string, 0, len(reportStatuses))
+	known := make(map[string]bool, len(reportStatuses))
+	for _, status := range reportStatuses {
+		order = append(order, status.status)
+		known[status.status] = true
+	}
+	var others []string
+	for status := range r.statuses {
+		if !known[status] {
+			others = append(others, status)
+		}
+	}
+	sort.Strings(others)
+	return append(order, others...)
+}
+
+func cellName(col, row int) string {
+	cell, _ := excelize.CoordinatesToCellName(col, row)
+	return cell
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/dto"
+	"sync"
+	"time"
+)
+
+var ErrImportProgressNotFound = errors.New("import progress not found")
+
+const (
+	ImportPhaseValidating = "validating"
+	ImportPhaseWriting    = "writing"
+	ImportPhaseDone       = "done"
+	ImportPhaseFailed     = "failed"
+)
+
+// How long the progress of a finished import can still be read
+const importProgressRetention = 10 * time.Minute
+
+// How many validated rows between two progress updates
+const importProgressInterval = 1000
+
+// importProgressTracker keeps the progress of the running imports in memory, for clients to poll
+// while their upload request is still being processed.
+type importProgressTracker struct {
+	mu      sync.Mutex
+	imports map[string]*dto.ImportProgress
+}
+
+func newImportProgressTracker() *importProgressTracker {
+	return &importProgressTracker{
+		imports: make(map[string]*dto.ImportProgress),
+	}
+}
+
+// start registers an import, false when an import with the same ID is still running.
+// Finished imports past their retention are forgotten on the way.
+func (t *importProgressTracker) start(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for key, progress := range t.imports {
+		if progress.FinishedAt != nil && now.Sub(*progress.FinishedAt) > importProgressRetention {
+			delete(t.imports, key)
+		}
+	}
+
+	if progress, ok := t.imports[id]; ok && progress.FinishedAt == nil {
+		return false
+	}
+	t.imports[id] = &dto.ImportProgress{
+		ID:        id,
+		Phase:     ImportPhaseValidating,
+		StartedAt: now,
+	}
+	return true
+}
+
+// update records the phase and counts of an import; imports without an ID aren't tracked.
+func (t *importProgressTracker) update(id, phase string, processed, total int) {
+	if id == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if progress, ok := t.imports[id]; ok {
+		progress.Phase = phase
+		progress.Processed = processed
+		progress.Total = total
+	}
+}
+
+func (t *importProgressTracker) finish(id string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress, ok := t.imports[id]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	progress.FinishedAt = &now
+	progress.Phase = ImportPhaseDone
+	if err != nil {
+		progress.Phase = ImportPhaseFailed
+		progress.Error = err.Error()
+	}
+}
+
+func (t *importProgressTracker) get(id string) (*dto.ImportProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress, ok := t.imports[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := *progress
+	return &snapshot, true
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"strconv"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultJobRetention is how long a finished job and its result are kept before they're deleted.
+const DefaultJobRetention = 7 * 24 * time.Hour
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrJobNotFinished = errors.New("job not finished")
+)
+
+type JobService interface {
+	StartImportJob(buf []byte, options dto.ImportOptions) (*domain.Job, error)
+	StartExportJob(options dto.ExportOptions) (*domain.Job, error)
+	GetJob(id string) (*domain.Job, error)
+	GetJobResult(id string) (*domain.Job, error)
+	OpenJobFile(job *domain.Job) io.Reader
+	ResumeJobs() error
+	PurgeFinishedJobs() (int, error)
+}
+
+type jobService struct {
+	jobRepository repository.JobRepository
+	serverService ServerCRUDService
+	retention     time.Duration
+}
+
+func NewJobService(jobRepository repository.JobRepository, serverService ServerCRUDService, retention time.Duration) JobService {
+	return &jobService{
+		jobRepository: jobRepository,
+		serverService: serverService,
+		retention:     retention,
+	}
+}
+
+// StartImportJob records a Pending import of the file and runs it in the background.
+// The job tracks its own progress, a progress ID isn't needed.
+func (s *jobService) StartImportJob(buf []byte, options dto.ImportOptions) (*domain.Job, error) {
+	options.ProgressID = ""
+	return s.startJob(domain.JobImport, buf, options)
+}
+
+// StartExportJob checks the format, label selector, filter expression and sort, records a Pending export and runs it in the background.
+func (s *jobService) StartExportJob(options dto.ExportOptions) (*domain.Job, error) {
+	if _, err := exportFormat(options.Format); err != nil {
+		return nil, err
+	}
+	if _, err := domain.ParseLabelSelector(options.LabelSelector); err != nil {
+		return nil, err
+	}
+	if _, err := domain.ParseFilterExpression(options.FilterExpression); err != nil {
+		return nil, err
+	}
+	if _, err := domain.ParseServerSort(options.SortColumn, options.Order); err != nil {
+		return nil, err
+	}
+	return s.startJob(domain.JobExport, nil, options)
+}
+
+func (s *jobService) startJob(kind string, input []byte, options interface{}) (*domain.Job, error) {
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &domain.Job{
+		ID:      uuid.New().String(),
+		Kind:    kind,
+		Status:  domain.JobPending,
+		Options: encodedOptions,
+		Input:   input,
+	}
+	if _, err := s.jobRepository.CreateJob(job); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create "+kind+" job, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	logging.LogMessage("server_administration_service", "Created "+kind+" job "+job.ID, "INFO")
+	go s.runJob(*job)
+	return job, nil
+}
+
+// ResumeJobs runs again the jobs a restart interrupted. They start over; an import writes everything in
+// one transaction, so an interrupted one left nothing behind to conflict with.
+func (s *jobService) ResumeJobs() error {
+	jobs, err := s.jobRepository.GetUnfinishedJobs()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get unfinished jobs, err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	for _, job := range jobs {
+		logging.LogMessage("server_administration_service", "Resuming "+job.Kind+" job "+job.ID, "INFO")
+		go s.runJob(job)
+	}
+	return nil
+}
+
+// runJob runs an import or export and records its result, or why it failed.
+func (s *jobService) runJob(job domain.Job) {
+	startedAt := time.Now()
+	if err := s.jobRepository.StartJob(job.ID, startedAt); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to start job "+job.ID+", err: "+err.Error(), "ERROR")
+		return
+	}
+	job.Status = domain.JobRunning
+	job.StartedAt = &startedAt
+
+	var err error
+	switch job.Kind {
+	case domain.JobImport:
+		err = s.runImport(&job)
+	case domain.JobExport:
+		err = s.runExport(&job)
+	default:
+		err = fmt.Errorf("unknown job kind %q", job.Kind)
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Input = nil
+	job.Status = domain.JobCompleted
+	if err != nil {
+		logging.LogMessage("server_administration_service", job.Kind+" job "+job.ID+" failed, err: "+err.Error(), "ERROR")
+		job.Status = domain.JobFailed
+		job.Error = err.Error()
+	}
+
+	if err := s.jobRepository.UpdateJob(&job); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to update job "+job.ID+", err: "+err.Error(), "ERROR")
+		return
+	}
+	logging.LogMessage("server_administration_service", job.Kind+" job "+job.ID+" finished as "+job.Status, "INFO")
+}
+
+func (s *jobService) runImport(job *domain.Job) error {
+	var options dto.ImportOptions
+	if err := json.Unmarshal(job.Options, &options); err != nil {
+		return err
+	}
+	options.OnProgress = func(phase string, processed, total int) {
+		job.Phase, job.Processed, job.Total = phase, processed, total
+		if err := s.jobRepository.UpdateJobProgress(job.ID, phase, processed, total); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to update progress of job "+job.ID+", err: "+err.Error(), "ERROR")
+		}
+	}
+
+	report, err := s.serverService.ImportServers(job.Input, options)
+	if err != nil {
+		return err
+	}
+	job.Result, err = json.Marshal(report)
+	return err
+}
+
+func (s *jobService) runExport(job *domain.Job) error {
+	var options dto.ExportOptions
+	if err := json.Unmarshal(job.Options, &options); err != nil {
+		return err
+	}
+	format, err := exportFormat(options.Format)
+	if err != nil {
+		return err
+	}
+
+	// The file can be far bigger than a job row should hold, it goes to a large object as it's written
+	job.FileOID, err = s.jobRepository.WriteJobFile(func(w io.Writer) error {
+		return s.serverService.ExportServers(w, options)
+	})
+	if err != nil {
+		return err
+	}
+	job.Filename = "servers_" + job.StartedAt.Format("2006-01-02_15-04-05") + "." + format
+	job.Processed, job.Total = 1, 1
+	return nil
+}
+
+// GetJob returns the status and progress of a job.
+func (s *jobService) GetJob(id string) (*domain.Job, error) {
+	job, err := s.jobRepository.GetJob(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get job "+id+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJobResult returns a Completed job with its import report or the reference to its exported file.
+func (s *jobService) GetJobResult(id string) (*domain.Job, error) {
+	job, err := s.jobRepository.GetJobResult(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get result of job "+id+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	if job.Status != domain.JobCompleted {
+		return nil, fmt.Errorf("%w: job %s is %s", ErrJobNotFinished, id, job.Status)
+	}
+	return job, nil
+}
+
+// OpenJobFile reads the exported file of a job returned by GetJobResult.
+func (s *jobService) OpenJobFile(job *domain.Job) io.Reader {
+	return s.jobRepository.OpenJobFile(job.FileOID)
+}
+
+// PurgeFinishedJobs deletes the jobs finished longer than the retention ago, with their exported files,
+// and returns how many it deleted.
+func (s *jobService) PurgeFinishedJobs() (int, error) {
+	deleted, err := s.jobRepository.DeleteFinishedJobs(time.Now().Add(-s.retention))
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to purge finished jobs: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	if deleted > 0 {
+		logging.LogMessage("server_administration_service", strconv.Itoa(deleted)+" finished jobs purged", "INFO")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock implementation of JobRepository
+type mockJobRepository struct {
+	mock.Mock
+}
+
+func (m *mockJobRepository) CreateJob(job *domain.Job) (string, error) {
+	args := m.Called(job)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockJobRepository) UpdateJob(job *domain.Job) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *mockJobRepository) StartJob(id string, startedAt time.Time) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockJobRepository) UpdateJobProgress(id, phase string, processed, total int) error {
+	args := m.Called(id, phase, processed, total)
+	return args.Error(0)
+}
+
+func (m *mockJobRepository) GetJob(id string) (*domain.Job, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) GetJobResult(id string) (*domain.Job, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Job), args.Error(1)
+}
+
+func (m *mockJobRepository) GetUnfinishedJobs() ([]domain.Job, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Job), args.Error(1)
+}
+
+// WriteJobFile runs write into a buffer and matches the call on what was written
+func (m *mockJobRepository) WriteJobFile(write func(w io.Writer) error) (uint32, error) {
+	var file bytes.Buffer
+	if err := write(&file); err != nil {
+		return 0, err
+	}
+	args := m.Called(file.String())
+	return args.Get(0).(uint32), args.Error(1)
+}
+
+func (m *mockJobRepository) OpenJobFile(oid uint32) io.Reader {
+	args := m.Called(oid)
+	return args.Get(0).(io.Reader)
+}
+
+func (m *mockJobRepository) DeleteFinishedJobs(finishedBefore time.Time) (int, error) {
+	args := m.Called(finishedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+// Mock implementation of ServerCRUDService, only the import and export jobs run
+type mockJobServerService struct {
+	ServerCRUDService
+	mock.Mock
+}
+
+func (m *mockJobServerService) ImportServers(buf []byte, options dto.ImportOptions) (*dto.ImportReport, error) {
+	args := m.Called(buf, options.Mode)
+	if options.OnProgress != nil {
+		options.OnProgress(ImportPhaseWriting, 1, 1)
+	}
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ImportReport), args.Error(1)
+}
+
+func (m *mockJobServerService) ExportServers(w io.Writer, options dto.ExportOptions) error {
+	args := m.Called(options.Filter.ServerName, options.LabelSelector, options.Format)
+	if data, ok := args.Get(0).([]byte); ok {
+		w.Write(data)
+	}
+	return args.Error(1)
+}
+
+func TestRunJob_Import(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	mockServers := new(mockJobServerService)
+	svc := &jobService{jobRepository: mockRepo, serverService: mockServers}
+
+	options, _ := json.Marshal(dto.ImportOptions{Mode: ImportModeUpsert})
+	report := &dto.ImportReport{Mode: ImportModeUpsert, Summary: dto.ImportSummary{Created: []string{"srv1"}}}
+
+	mockRepo.On("StartJob", "job-1").Return(nil)
+	mockServers.On("ImportServers", []byte("csv"), ImportModeUpsert).Return(report, nil)
+	mockRepo.On("UpdateJobProgress", "job-1", ImportPhaseWriting, 1, 1).Return(nil)
+	mockRepo.On("UpdateJob", mock.MatchedBy(func(job *domain.Job) bool {
+		var result dto.ImportReport
+		json.Unmarshal(job.Result, &result)
+		return job.Status == domain.JobCompleted && job.Input == nil && job.FinishedAt != nil &&
+			job.Processed == 1 && result.Summary.Created[0] == "srv1"
+	})).Return(nil)
+
+	svc.runJob(domain.Job{ID: "job-1", Kind: domain.JobImport, Status: domain.JobPending, Options: options, Input: []byte("csv")})
+	mockRepo.AssertExpectations(t)
+	mockServers.AssertExpectations(t)
+}
+
+func TestRunJob_Export(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	mockServers := new(mockJobServerService)
+	svc := &jobService{jobRepository: mockRepo, serverService: mockServers}
+
+	options, _ := json.Marshal(dto.ExportOptions{Filter: dto.ServerFilter{ServerName: "web"}, LabelSelector: "env=prod", From: 0, To: 100, Format: "csv"})
+
+	mockRepo.On("StartJob", "job-1").Return(nil)
+	mockServers.On("ExportServers", "web", "env=prod", "csv").Return([]byte("Server ID\n"), nil)
+	// The file goes to a large object, the job only keeps its OID
+	mockRepo.On("WriteJobFile", "Server ID\n").Return(uint32(16400), nil)
+	mockRepo.On("UpdateJob", mock.MatchedBy(func(job *domain.Job) bool {
+		return job.Status == domain.JobCompleted && job.FileOID == 16400 && strings.HasSuffix(job.Filename, ".csv")
+	})).Return(nil)
+
+	svc.runJob(domain.Job{ID: "job-1", Kind: domain.JobExport, Status: domain.JobPending, Options: options})
+	mockRepo.AssertExpectations(t)
+	mockServers.AssertExpectations(t)
+}
+
+func TestRunJob_Failed(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	mockServers := new(mockJobServerService)
+	svc := &jobService{jobRepository: mockRepo, serverService: mockServers}
+
+	options, _ := json.Marshal(dto.ImportOptions{})
+
+	mockRepo.On("StartJob", "job-1").Return(nil)
+	mockServers.On("ImportServers", []byte("csv"), "").Return(nil, errors.New("db error"))
+	mockRepo.On("UpdateJobProgress", "job-1", ImportPhaseWriting, 1, 1).Return(nil)
+	mockRepo.On("UpdateJob", mock.MatchedBy(func(job *domain.Job) bool {
+		return job.Status == domain.JobFailed && job.Error == "db error" && job.Result == nil
+	})).Return(nil)
+
+	svc.runJob(domain.Job{ID: "job-1", Kind: domain.JobImport, Options: options, Input: []byte("csv")})
+	mockRepo.AssertExpectations(t)
+}
+
+func TestStartExportJob_Invalid(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	svc := NewJobService(mockRepo, new(mockJobServerService), DefaultJobRetention)
+
+	_, err := svc.StartExportJob(dto.ExportOptions{LabelSelector: "env in prod"})
+	assert.ErrorIs(t, err, domain.ErrInvalidLabelSelector)
+	_, err = svc.StartExportJob(dto.ExportOptions{Format: "docx"})
+	assert.ErrorIs(t, err, ErrInvalidExport)
+	_, err = svc.StartExportJob(dto.ExportOptions{SortColumn: "server_id desc"})
+	assert.ErrorIs(t, err, domain.ErrInvalidSort)
+	mockRepo.AssertNotCalled(t, "CreateJob", mock.Anything)
+}
+
+func TestGetJob_NotFound(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	svc := NewJobService(mockRepo, nil, DefaultJobRetention)
+
+	mockRepo.On("GetJob", "job-1").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.GetJob("job-1")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestGetJobResult_NotFinished(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	svc := NewJobService(mockRepo, nil, DefaultJobRetention)
+
+	mockRepo.On("GetJobResult", "job-1").Return(&domain.Job{ID: "job-1", Status: domain.JobRunning}, nil)
+
+	_, err := svc.GetJobResult("job-1")
+	assert.ErrorIs(t, err, ErrJobNotFinished)
+}
+
+func TestPurgeFinishedJobs(t *testing.T) {
+	mockRepo := new(mockJobRepository)
+	svc := NewJobService(mockRepo, nil, 24*time.Hour)
+
+	mockRepo.On("DeleteFinishedJobs", mock.MatchedBy(func(finishedBefore time.Time) bool {
+		return time.Since(finishedBefore) > 23*time.Hour && time.Since(finishedBefore) < 25*time.Hour
+	})).Return(3, nil).Once()
+	deleted, err := svc.PurgeFinishedJobs()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+
+	mockRepo.On("DeleteFinishedJobs", mock.Anything).Return(0, errors.New("db error"))
+	_, err = svc.PurgeFinishedJobs()
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+type MaintenanceService interface {
+	CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error)
+	GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error)
+	GetMaintenanceWindows() ([]domain.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(id string, updatedData map[string]interface{}) error
+	DeleteMaintenanceWindow(id string) error
+	GetActiveMaintenanceWindow(serverID string, t time.Time) (*domain.MaintenanceWindow, error)
+}
+
+type maintenanceService struct {
+	maintenanceRepository repository.MaintenanceRepository
+}
+
+func NewMaintenanceService(maintenanceRepository repository.MaintenanceRepository) MaintenanceService {
+	return &maintenanceService{
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+func validateMaintenanceWindow(window *domain.MaintenanceWindow) error {
+	if window.Name == "" {
+		return errors.New("maintenance window name is required")
+	}
+	if len(window.ServerIDs) == 0 && len(window.GroupIDs) == 0 {
+		return errors.New("maintenance window must target at least one server or group")
+	}
+	if window.StartTime.IsZero() {
+		return errors.New("start_time is required")
+	}
+
+	if !window.IsRecurring() {
+		if !window.EndTime.After(window.StartTime) {
+			return errors.New("end_time must be after start_time")
+		}
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(window.CronExpression); err != nil {
+		return errors.New("invalid cron expression: " + err.Error())
+	}
+	if window.DurationMinutes <= 0 {
+		return errors.New("duration_minutes must be positive for recurring windows")
+	}
+	if !window.EndTime.IsZero() && !window.EndTime.After(window.StartTime) {
+		return errors.New("end_time must be after start_time")
+	}
+	return nil
+}
+
+func (s *maintenanceService) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	if err := validateMaintenanceWindow(window); err != nil {
+		return "", err
+	}
+
+	window.ID = uuid.New().String()
+	id, err := s.maintenanceRepository.CreateMaintenanceWindow(window)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create maintenance window, err: "+err.Error(), "ERROR")
+		return "", err
+	}
+
+	logging.LogMessage("server_administration_service", "Maintenance window "+id+" created", "INFO")
+	return id, nil
+}
+
+func (s *maintenanceService) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	return s.maintenanceRepository.GetMaintenanceWindow(id)
+}
+
+func (s *maintenanceService) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	return s.maintenanceRepository.GetMaintenanceWindows()
+}
+
+func (s *maintenanceService) UpdateMaintenanceWindow(id string, updatedData map[string]interface{}) error {
+	window, err := s.maintenanceRepository.GetMaintenanceWindow(id)
+	if err != nil {
+		return err
+	}
+
+	if name, ok := updatedData["name"].(string); ok {
+		window.Name = name
+	}
+	if serverIDs, ok := updatedData["server_ids"].([]string); ok {
+		window.ServerIDs = serverIDs
+	}
+	if groupIDs, ok := updatedData["group_ids"].([]string); ok {
+		window.GroupIDs = groupIDs
+	}
+	if startTime, ok := updatedData["start_time"].(time.Time); ok {
+		window.StartTime = startTime
+	}
+	if endTime, ok := updatedData["end_time"].(time.Time); ok {
+		window.EndTime = endTime
+	}
+	if cronExpression, ok := updatedData["cron_expression"].(string); ok {
+		window.CronExpression = cronExpression
+	}
+	if durationMinutes, ok := updatedData["duration_minutes"].(int); ok {
+		window.DurationMinutes = durationMinutes
+	}
+	if excludeFromSLA, ok := updatedData["exclude_from_sla"].(bool); ok {
+		window.ExcludeFromSLA = excludeFromSLA
+	}
+
+	if err := validateMaintenanceWindow(window); err != nil {
+		return err
+	}
+
+	return s.maintenanceRepository.UpdateMaintenanceWindow(window)
+}
+
+func (s *maintenanceService) DeleteMaintenanceWindow(id string) error {
+	return s.maintenanceRepository.DeleteMaintenanceWindow(id)
+}
+
+// GetActiveMaintenanceWindow returns the first window covering the server at t, or nil. It runs for every
+// status change and notification check, so only the windows that may apply to the server are loaded.
+func (s *maintenanceService) GetActiveMaintenanceWindow(serverID string, t time.Time) (*domain.MaintenanceWindow, error) {
+	windows, err := s.maintenanceRepository.GetServerMaintenanceWindows(serverID, t)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get maintenance windows of server "+serverID+", err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	for i := range windows {
+		if windows[i].ActiveAt(t) {
+			return &windows[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,192 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock implementation of MaintenanceRepository
+type mockMaintenanceRepository struct {
+	mock.Mock
+}
+
+func (m *mockMaintenanceRepository) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	args := m.Called(window)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetServerMaintenanceWindows(serverID string, t time.Time) ([]domain.MaintenanceWindow, error) {
+	args := m.Called(serverID, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) UpdateMaintenanceWindow(window *domain.MaintenanceWindow) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceRepository) DeleteMaintenanceWindow(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestCreateMaintenanceWindow_OneOff(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	window := &domain.MaintenanceWindow{
+		Name:      "Patching",
+		ServerIDs: []string{"srv-1"},
+		StartTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+	}
+	mockRepo.On("CreateMaintenanceWindow", window).Return("generated", nil)
+
+	id, err := svc.CreateMaintenanceWindow(window)
+	assert.NoError(t, err)
+	assert.Equal(t, "generated", id)
+	assert.NotEmpty(t, window.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMaintenanceWindow_GroupOnly(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	window := &domain.MaintenanceWindow{
+		Name:      "Rack move",
+		GroupIDs:  []string{"rack-1"},
+		StartTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+	}
+	mockRepo.On("CreateMaintenanceWindow", window).Return("generated", nil)
+
+	_, err := svc.CreateMaintenanceWindow(window)
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateMaintenanceWindow_Invalid(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]*domain.MaintenanceWindow{
+		"missing name":        {ServerIDs: []string{"srv-1"}, StartTime: start, EndTime: start.Add(time.Hour)},
+		"no servers":          {Name: "w", StartTime: start, EndTime: start.Add(time.Hour)},
+		"end before start":    {Name: "w", ServerIDs: []string{"srv-1"}, StartTime: start, EndTime: start.Add(-time.Hour)},
+		"invalid cron":        {Name: "w", ServerIDs: []string{"srv-1"}, StartTime: start, CronExpression: "every sunday", DurationMinutes: 30},
+		"recurring no length": {Name: "w", ServerIDs: []string{"srv-1"}, StartTime: start, CronExpression: "0 3 * * 0"},
+	}
+
+	for name, window := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mockMaintenanceRepository)
+			svc := service.NewMaintenanceService(mockRepo)
+
+			_, err := svc.CreateMaintenanceWindow(window)
+			assert.Error(t, err)
+			mockRepo.AssertNotCalled(t, "CreateMaintenanceWindow", mock.Anything)
+		})
+	}
+}
+
+func TestUpdateMaintenanceWindow_Success(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := &domain.MaintenanceWindow{ID: "window-1", Name: "Patching", ServerIDs: []string{"srv-1"}, StartTime: start, EndTime: start.Add(time.Hour)}
+	mockRepo.On("GetMaintenanceWindow", "window-1").Return(window, nil)
+	mockRepo.On("UpdateMaintenanceWindow", mock.MatchedBy(func(w *domain.MaintenanceWindow) bool {
+		return w.CronExpression == "0 3 * * 0" && w.DurationMinutes == 60 && w.ExcludeFromSLA
+	})).Return(nil)
+
+	err := svc.UpdateMaintenanceWindow("window-1", map[string]interface{}{
+		"cron_expression":  "0 3 * * 0",
+		"duration_minutes": 60,
+		"exclude_from_sla": true,
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateMaintenanceWindow_NotFound(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	mockRepo.On("GetMaintenanceWindow", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.UpdateMaintenanceWindow("missing", map[string]interface{}{"name": "x"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGetActiveMaintenanceWindow(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	// Every day at 02:00 for 30 minutes
+	nightly := domain.MaintenanceWindow{
+		ID:              "nightly",
+		Name:            "Nightly backup",
+		ServerIDs:       []string{"srv-1"},
+		StartTime:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		CronExpression:  "0 2 * * *",
+		DurationMinutes: 30,
+	}
+	during := time.Date(2025, 3, 10, 2, 15, 0, 0, time.UTC)
+	after := time.Date(2025, 3, 10, 2, 45, 0, 0, time.UTC)
+	mockRepo.On("GetServerMaintenanceWindows", "srv-1", during).Return([]domain.MaintenanceWindow{nightly}, nil)
+	mockRepo.On("GetServerMaintenanceWindows", "srv-1", after).Return([]domain.MaintenanceWindow{nightly}, nil)
+	mockRepo.On("GetServerMaintenanceWindows", "srv-2", during).Return([]domain.MaintenanceWindow{}, nil)
+
+	window, err := svc.GetActiveMaintenanceWindow("srv-1", during)
+	assert.NoError(t, err)
+	if assert.NotNil(t, window) {
+		assert.Equal(t, "nightly", window.ID)
+	}
+
+	window, err = svc.GetActiveMaintenanceWindow("srv-1", after)
+	assert.NoError(t, err)
+	assert.Nil(t, window)
+
+	window, err = svc.GetActiveMaintenanceWindow("srv-2", during)
+	assert.NoError(t, err)
+	assert.Nil(t, window)
+	mockRepo.AssertNotCalled(t, "GetMaintenanceWindows")
+}
+
+func TestGetActiveMaintenanceWindow_RepoError(t *testing.T) {
+	mockRepo := new(mockMaintenanceRepository)
+	svc := service.NewMaintenanceService(mockRepo)
+
+	mockRepo.On("GetServerMaintenanceWindows", "srv-1", mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := svc.GetActiveMaintenanceWindow("srv-1", time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+)
+
+var ErrInvalidBulk = errors.New("invalid bulk change")
+
+// MaxBulkServers is the most servers a bulk change may target.
+const MaxBulkServers = 10000
+
+const (
+	BulkOutcomeUpdated = "updated"
+	BulkOutcomeDeleted = "deleted"
+	// BulkOutcomeUnchanged is a server that already has the values of the update
+	BulkOutcomeUnchanged = "unchanged"
+	BulkOutcomeNotFound  = "not_found"
+)
+
+// The fields a bulk update may change; names and addresses are unique to a server, they can't be set on several
+var bulkUpdatableFields = map[string]bool{"sla_target": true, "labels": true, "notes": true}
+
+// BulkUpdateServers writes the same changes to every targeted server in one statement and reports the
+// outcome for each. Servers that already have the values are left alone, and a preview stops before writing.
+func (s *serverCRUDService) BulkUpdateServers(request dto.BulkRequest, updatedData map[string]interface{}, actor dto.Actor) (*dto.BulkResult, error) {
+	if len(updatedData) == 0 {
+		return nil, fmt.Errorf("%w: no changes given", ErrInvalidBulk)
+	}
+	for field := range updatedData {
+		if !bulkUpdatableFields[field] {
+			return nil, fmt.Errorf("%w: %s can't be changed on several servers at once", ErrInvalidBulk, field)
+		}
+	}
+	if err := validateServerUpdate(updatedData); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidBulk, err.Error())
+	}
+
+	servers, missing, err := s.bulkTargets(request)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make(map[string]string, len(servers))
+	changed := make([]string, 0, len(servers))
+	entries := make(map[string]domain.AuditEntry, len(servers))
+	for i := range servers {
+		after := servers[i]
+		applyServerUpdate(&after, updatedData)
+		entry := newAuditEntry(domain.AuditUpdate, &servers[i], &after)
+		if len(entry.Changes) == 0 {
+			outcomes[servers[i].ServerID] = BulkOutcomeUnchanged
+			continue
+		}
+		outcomes[servers[i].ServerID] = BulkOutcomeUpdated
+		changed = append(changed, servers[i].ServerID)
+		entries[servers[i].ServerID] = entry
+	}
+
+	if !request.Preview && len(changed) > 0 {
+		updated, err := s.serverCRUDRepository.UpdateServersByID(changed, updatedData)
+		if err != nil {
+			return nil, err
+		}
+		s.audit(actor, settleBulkOutcomes(outcomes, changed, updated, entries)...)
+	}
+	return bulkResult(request.Preview, servers, missing, outcomes), nil
+}
+
+// BulkDeleteServers moves every targeted server to the trash in one statement and reports the outcome
+// for each. A preview stops before deleting.
+func (s *serverCRUDService) BulkDeleteServers(request dto.BulkRequest, actor dto.Actor) (*dto.BulkResult, error) {
+	servers, missing, err := s.bulkTargets(request)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make(map[string]string, len(servers))
+	targeted := make([]string, 0, len(servers))
+	entries := make(map[string]domain.AuditEntry, len(servers))
+	for i := range servers {
+		outcomes[servers[i].ServerID] = BulkOutcomeDeleted
+		targeted = append(targeted, servers[i].ServerID)
+		entries[servers[i].ServerID] = newAuditEntry(domain.AuditDelete, &servers[i], nil)
+	}
+
+	if !request.Preview && len(targeted) > 0 {
+		deleted, err := s.serverCRUDRepository.DeleteServersByID(targeted)
+		if err != nil {
+			return nil, err
+		}
+		s.audit(actor, settleBulkOutcomes(outcomes, targeted, deleted, entries)...)
+	}
+	return bulkResult(request.Preview, servers, missing, outcomes), nil
+}
+
+// bulkTargets returns the servers a bulk change targets, sorted by ID, and the requested IDs that
+// aren't servers.
+func (s *serverCRUDService) bulkTargets(request dto.BulkRequest) ([]domain.Server, []string, error) {
+	if (len(request.ServerIDs) == 0) == (request.Filter == nil) {
+		return nil, nil, fmt.Errorf("%w: target the servers with either server_ids or a filter", ErrInvalidBulk)
+	}
+
+	if request.Filter != nil {
+		filter := request.Filter
+		if filter.ServerID == "" && filter.ServerName == "" && filter.Status == "" && filter.Address == "" &&
+			len(filter.LabelSelector) == 0 && filter.Expression == nil {
+			return nil, nil, fmt.Errorf("%w: the filter must have at least one condition", ErrInvalidBulk)
+		}
+		servers, err := s.serverCRUDRepository.ViewServers(filter, 0, MaxBulkServers+1, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(servers) > MaxBulkServers {
+			return nil, nil, fmt.Errorf("%w: the filter matches more than %d servers", ErrInvalidBulk, MaxBulkServers)
+		}
+		return servers, nil, nil
+	}
+
+	serverIDs := make([]string, 0, len(request.ServerIDs))
+	values := make([]interface{}, 0, len(request.ServerIDs))
+	seen := make(map[string]bool, len(request.ServerIDs))
+	for _, serverID := range request.ServerIDs {
+		if serverID == "" {
+			return nil, nil, fmt.Errorf("%w: server_ids can't have an empty ID", ErrInvalidBulk)
+		}
+		if seen[serverID] {
+			continue
+		}
+		seen[serverID] = true
+		serverIDs = append(serverIDs, serverID)
+		values = append(values, serverID)
+	}
+	if len(serverIDs) > MaxBulkServers {
+		return nil, nil, fmt.Errorf("%w: more than %d server IDs", ErrInvalidBulk, MaxBulkServers)
+	}
+
+	filter := &dto.ServerFilter{Expression: &domain.FilterExpression{
+		Field:    domain.FilterFieldServerID,
+		Operator: domain.FilterIn,
+		Values:   values,
+	}}
+	servers, err := s.serverCRUDRepository.ViewServers(filter, 0, len(serverIDs), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		found[server.ServerID] = true
+	}
+	var missing []string
+	for _, serverID := range serverIDs {
+		if !found[serverID] {
+			missing = append(missing, serverID)
+		}
+	}
+	return servers, missing, nil
+}
+
+// settleBulkOutcomes marks the targeted servers the write didn't reach, deleted in the meantime, as not
+// found, and returns the audit entries of the ones it did.
+func settleBulkOutcomes(outcomes map[string]string, targeted, written []string, entries map[string]domain.AuditEntry) []domain.AuditEntry {
+	reached := make(map[string]bool, len(written))
+	for _, serverID := range written {
+		reached[serverID] = true
+	}
+
+	audited := make([]domain.AuditEntry, 0, len(written))
+	for _, serverID := range targeted {
+		if !reached[serverID] {
+			outcomes[serverID] = BulkOutcomeNotFound
+			continue
+		}
+		audited = append(audited, entries[serverID])
+	}
+	return audited
+}
+
+func bulkResult(preview bool, servers []domain.Server, missing []string, outcomes map[string]string) *dto.BulkResult {
+	result := &dto.BulkResult{
+		Preview:  preview,
+		Outcomes: make([]dto.BulkOutcome, 0, len(servers)+len(missing)),
+	}
+	for _, server := range servers {
+		outcome := outcomes[server.ServerID]
+		if outcome != BulkOutcomeNotFound {
+			result.Matched++
+		}
+		if outcome == BulkOutcomeUpdated || outcome == BulkOutcomeDeleted {
+			result.Affected++
+		}
+		result.Outcomes = append(result.Outcomes, dto.BulkOutcome{ServerID: server.ServerID, Outcome: outcome})
+	}
+	for _, serverID := range missing {
+		result.Outcomes = append(result.Outcomes, dto.BulkOutcome{ServerID: serverID, Outcome: BulkOutcomeNotFound})
+	}
+	return result
+}
@@ -1,161 +1,848 @@
 package service
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/repository"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/flashhhhh/pkg/logging"
-	"github.com/xuri/excelize/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ServerCRUDService interface {
-	CreateServer(server_id, server_name, ipv4 string) (string, error)
-	ViewServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]domain.Server, error)
-	UpdateServer(server_id string, updatedData map[string]interface{}) error
-	DeleteServer(server_id string) error
-	ImportServers(buf []byte) ([]domain.Server, []domain.Server, error)
-	ExportServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]byte, error)
+	CreateServer(server_id, server_name string, addresses domain.Addresses, slaTarget float64, labels domain.Labels, notes string, actor dto.Actor) (string, error)
+	ViewServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error)
+	ViewServerPage(serverFilter *dto.ServerFilter, request dto.ServerPageRequest) (*dto.ServerPage, error)
+	GetServer(serverID string) (*domain.Server, error)
+	UpdateServer(server_id string, updatedData map[string]interface{}, version int64, actor dto.Actor) error
+	DeleteServer(server_id string, version int64, actor dto.Actor) error
+	ImportServers(buf []byte, options dto.ImportOptions) (*dto.ImportReport, error)
+	ExportServers(w io.Writer, options dto.ExportOptions) error
+	GetImportProgress(progressID string) (*dto.ImportProgress, error)
+	BulkUpdateServers(request dto.BulkRequest, updatedData map[string]interface{}, actor dto.Actor) (*dto.BulkResult, error)
+	BulkDeleteServers(request dto.BulkRequest, actor dto.Actor) (*dto.BulkResult, error)
 }
 
+var errInvalidSLATarget = errors.New("sla_target must be a percentage between 0 and 100 (exclusive)")
+
+var (
+	// ErrServerConflict is returned when another server, possibly in the trash, has the ID, name or an address
+	ErrServerConflict = errors.New("server conflict")
+	// ErrVersionConflict is returned by a change made against a version of the server that is no longer current
+	ErrVersionConflict = errors.New("server changed since that version")
+)
+
 type serverCRUDService struct {
 	serverCRUDRepository repository.ServerCRUDRepository
+	// statusService decommissions the servers a sync import removes
+	statusService ServerStatusService
+	// infoService measures the uptime exports ask for
+	infoService ServerInfoService
+	// progress tracks the imports given a progress ID
+	progress *importProgressTracker
+	// auditRepository records who changed which server, nothing is recorded without it
+	auditRepository repository.AuditRepository
 }
 
-func NewServerCRUDService(serverCRUDRepository repository.ServerCRUDRepository) ServerCRUDService {
+func NewServerCRUDService(serverCRUDRepository repository.ServerCRUDRepository, statusService ServerStatusService, infoService ServerInfoService, auditRepository repository.AuditRepository) ServerCRUDService {
 	return &serverCRUDService{
 		serverCRUDRepository: serverCRUDRepository,
+		statusService:        statusService,
+		infoService:          infoService,
+		progress:             newImportProgressTracker(),
+		auditRepository:      auditRepository,
 	}
 }
 
-func (s *serverCRUDService) CreateServer(server_id, server_name, ipv4 string) (string, error) {
+// CreateServer creates a server; a zero slaTarget means the default target.
+func (s *serverCRUDService) CreateServer(server_id, server_name string, addresses domain.Addresses, slaTarget float64, labels domain.Labels, notes string, actor dto.Actor) (string, error) {
+	if err := domain.ValidateServerID(server_id); err != nil {
+		return "", err
+	}
+	if err := domain.ValidateServerName(server_name); err != nil {
+		return "", err
+	}
+	if slaTarget == 0 {
+		slaTarget = domain.DefaultSLATarget
+	}
+	if !domain.ValidSLATarget(slaTarget) {
+		return "", fmt.Errorf("%w: %s", domain.ErrInvalidServer, errInvalidSLATarget.Error())
+	}
+	if err := labels.Validate(); err != nil {
+		return "", err
+	}
+	if labels == nil {
+		labels = domain.Labels{}
+	}
+	if err := domain.ValidateServerNotes(notes); err != nil {
+		return "", err
+	}
+	if err := addresses.Normalize(); err != nil {
+		return "", err
+	}
+	primary, _ := addresses.Primary()
+	if err := s.checkServerConflicts("", server_id, server_name, addresses); err != nil {
+		return "", err
+	}
+
 	server := &domain.Server{
 		ServerID:   server_id,
 		ServerName: server_name,
-		Status: "Off",
-		IPv4:  ipv4,
+		Status: domain.StatusUnknown,
+		PrimaryAddress: primary.Address,
+		Addresses: addresses,
+		SLATarget: slaTarget,
+		Labels: labels,
+		Notes: notes,
 	}
 
 	id, err := s.serverCRUDRepository.CreateServer(server)
 	if err != nil {
-		return "", err
+		return "", serverWriteError(err, 0)
 	}
+	s.audit(actor, newAuditEntry(domain.AuditCreate, nil, server))
 	return id, nil
 }
 
-func (s *serverCRUDService) ViewServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]domain.Server, error) {
-	servers, err := s.serverCRUDRepository.ViewServers(serverFilter, from, to, sortedColumn, order)
+func (s *serverCRUDService) ViewServers(serverFilter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error) {
+	servers, err := s.serverCRUDRepository.ViewServers(serverFilter, from, to, sort)
 	if err != nil {
 		return nil, err
 	}
 	return servers, nil
 }
 
-func (s *serverCRUDService) UpdateServer(server_id string, updatedData map[string]interface{}) error {
-	err := s.serverCRUDRepository.UpdateServer(server_id, updatedData)
-	return err
+// ViewServerPage returns a page of the matching servers through keyset pagination, with the cursors
+// of the pages around it and the number of matching servers. A page past the last has no next cursor,
+// and the first has no previous one.
+func (s *serverCRUDService) ViewServerPage(serverFilter *dto.ServerFilter, request dto.ServerPageRequest) (*dto.ServerPage, error) {
+	keyset, err := serverKeyset(request)
+	if err != nil {
+		return nil, err
+	}
+	limit := keyset.Limit
+
+	// One more than asked tells whether there's anything past the page
+	keyset.Limit++
+	servers, err := s.serverCRUDRepository.ViewServersByKey(serverFilter, keyset)
+	if err != nil {
+		return nil, err
+	}
+	more := len(servers) > limit
+	if more {
+		if keyset.Backward {
+			servers = servers[1:]
+		} else {
+			servers = servers[:limit]
+		}
+	}
+
+	total, err := s.serverCRUDRepository.CountServers(serverFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.ServerPage{Items: servers, Total: total}
+	if page.Items == nil {
+		page.Items = []domain.Server{}
+	}
+	if len(servers) == 0 {
+		return page, nil
+	}
+	// Going back, the page came from a later one; going forward, from an earlier one if it had a cursor
+	if keyset.Backward || more {
+		page.NextCursor = encodeServerCursor(keyset.Sort, servers[len(servers)-1], false)
+	}
+	if keyset.Backward && more || !keyset.Backward && keyset.After != nil {
+		page.PrevCursor = encodeServerCursor(keyset.Sort, servers[0], true)
+	}
+	return page, nil
 }
 
-func (s *serverCRUDService) DeleteServer(server_id string) error {
-	err := s.serverCRUDRepository.DeleteServer(server_id)
-	return err
+// GetServer returns the server with the ID.
+func (s *serverCRUDService) GetServer(serverID string) (*domain.Server, error) {
+	server, err := s.lookupServer(serverID)
+	if err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, ErrServerNotFound
+	}
+	return server, nil
+}
+
+// UpdateServer writes the changes to the server. A version other than 0 is the one the changes were made
+// against, they're refused with ErrVersionConflict when the server has changed since.
+func (s *serverCRUDService) UpdateServer(server_id string, updatedData map[string]interface{}, version int64, actor dto.Actor) error {
+	if err := validateServerUpdate(updatedData); err != nil {
+		return err
+	}
+
+	before, err := s.versionedServer(server_id, version)
+	if err != nil {
+		return err
+	}
+	serverName, _ := updatedData["server_name"].(string)
+	addresses, _ := updatedData["addresses"].(domain.Addresses)
+	if serverName != "" || len(addresses) > 0 {
+		if err := s.checkServerConflicts(server_id, "", serverName, addresses); err != nil {
+			return err
+		}
+	}
+	if err := s.serverCRUDRepository.UpdateServer(server_id, updatedData, version); err != nil {
+		return serverWriteError(err, version)
+	}
+	if before != nil {
+		after := *before
+		applyServerUpdate(&after, updatedData)
+		s.audit(actor, newAuditEntry(domain.AuditUpdate, before, &after))
+	}
+	return nil
+}
+
+// validateServerUpdate checks the fields of an update, and sets the primary address of new addresses.
+func validateServerUpdate(updatedData map[string]interface{}) error {
+	if serverName, ok := updatedData["server_name"].(string); ok {
+		if err := domain.ValidateServerName(serverName); err != nil {
+			return err
+		}
+	}
+	if slaTarget, ok := updatedData["sla_target"].(float64); ok && !domain.ValidSLATarget(slaTarget) {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidServer, errInvalidSLATarget.Error())
+	}
+	if labels, ok := updatedData["labels"].(domain.Labels); ok {
+		if err := labels.Validate(); err != nil {
+			return err
+		}
+	}
+	if notes, ok := updatedData["notes"].(string); ok {
+		if err := domain.ValidateServerNotes(notes); err != nil {
+			return err
+		}
+	}
+	if addresses, ok := updatedData["addresses"].(domain.Addresses); ok {
+		if err := addresses.Normalize(); err != nil {
+			return err
+		}
+		primary, _ := addresses.Primary()
+		updatedData["primary_address"] = primary.Address
+	}
+	return nil
+}
+
+// DeleteServer moves the server to the trash. A version other than 0 is checked as in UpdateServer.
+func (s *serverCRUDService) DeleteServer(server_id string, version int64, actor dto.Actor) error {
+	before, err := s.versionedServer(server_id, version)
+	if err != nil {
+		return err
+	}
+	if err := s.serverCRUDRepository.DeleteServer(server_id, version); err != nil {
+		return serverWriteError(err, version)
+	}
+	if before != nil {
+		s.audit(actor, newAuditEntry(domain.AuditDelete, before, nil))
+	}
+	return nil
 }
 
-func (s *serverCRUDService) ImportServers(buf []byte) ([]domain.Server, []domain.Server, error) {
-	f, err := excelize.OpenReader(strings.NewReader(string(buf)))
+// versionedServer returns the server as it is before a change, for the audit log, after checking that it's
+// at version when that isn't 0. The version is checked again as the change is written, this check only
+// tells a missing server from a changed one.
+func (s *serverCRUDService) versionedServer(serverID string, version int64) (*domain.Server, error) {
+	if version == 0 {
+		return s.auditedServer(serverID)
+	}
+	server, err := s.lookupServer(serverID)
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to open Excel file: "+err.Error(), "ERROR")
-		return nil, nil, err
+		return nil, err
+	}
+	if server == nil {
+		return nil, ErrServerNotFound
+	}
+	if server.Version != version {
+		return nil, ErrVersionConflict
+	}
+	return server, nil
+}
+
+// serverWriteError turns the error of writing a server into the service's: a write that found nothing to
+// write is ErrServerNotFound, or ErrVersionConflict when made against a version, the server having changed
+// or been deleted after the version was checked. A unique violation is a conflict that got in after
+// checkServerConflicts.
+func serverWriteError(err error, version int64) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound) && version != 0:
+		return ErrVersionConflict
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrServerNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return fmt.Errorf("%w: the server_id, server_name or primary address is taken", ErrServerConflict)
+	}
+	return err
+}
+
+// checkServerConflicts returns ErrServerConflict when a server other than self, in the trash or not, has
+// the ID, the name or one of the addresses; empty ones aren't checked. self is "" for a new server.
+func (s *serverCRUDService) checkServerConflicts(self, serverID, serverName string, addresses domain.Addresses) error {
+	var serverIDs, serverNames, lowerAddresses []string
+	if serverID != "" {
+		serverIDs = append(serverIDs, serverID)
+	}
+	if serverName != "" {
+		serverNames = append(serverNames, serverName)
+	}
+	for _, address := range addresses {
+		lowerAddresses = append(lowerAddresses, strings.ToLower(address.Address))
 	}
 
-	rows, err := f.GetRows("Servers")
+	existing, err := s.serverCRUDRepository.GetConflictingServers(serverIDs, serverNames, lowerAddresses)
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to get rows from Excel file: "+err.Error(), "ERROR")
-		return nil, nil, err
+		return err
 	}
 
-	if len(rows) < 2 {
-		logging.LogMessage("server_administration_service", "Excel files doesn't have any row data", "ERROR")
-		return nil, nil, errors.New("Excel files doesn't have any row data")
+	for _, server := range existing {
+		if server.ServerID == self {
+			continue
+		}
+		inTrash := ""
+		if server.DeletedAt.Valid {
+			inTrash = ", in the trash"
+		}
+		if server.ServerID == serverID {
+			return fmt.Errorf("%w: server_id %s is taken%s", ErrServerConflict, serverID, inTrash)
+		}
+		if server.ServerName == serverName {
+			return fmt.Errorf("%w: server_name %q is taken by server %s%s", ErrServerConflict, serverName, server.ServerID, inTrash)
+		}
+		taken := append(domain.Addresses{{Address: server.PrimaryAddress}}, server.Addresses...)
+		for _, address := range addresses {
+			for _, other := range taken {
+				if strings.EqualFold(address.Address, other.Address) {
+					return fmt.Errorf("%w: address %s is taken by server %s%s", ErrServerConflict, address.Address, server.ServerID, inTrash)
+				}
+			}
+		}
 	}
+	return nil
+}
 
-	servers := make([]domain.Server, 0)
+// ImportServers creates the servers of an xlsx, CSV, JSON, YAML or nmap XML file, and depending on the mode
+// updates the servers it already has and removes the ones missing from the file.
+// Every row is validated first, within the file and against the inventory, and the rows that fail are
+// reported with their reasons; a dry run stops there without writing anything.
+// An import given a progress ID can be followed with GetImportProgress while it runs.
+func (s *serverCRUDService) ImportServers(buf []byte, options dto.ImportOptions) (*dto.ImportReport, error) {
+	if options.ProgressID == "" {
+		return s.importServers(buf, options)
+	}
+	if !s.progress.start(options.ProgressID) {
+		return nil, fmt.Errorf("%w: an import with progress_id %q is already running", ErrInvalidImport, options.ProgressID)
+	}
+	report, err := s.importServers(buf, options)
+	s.progress.finish(options.ProgressID, err)
+	return report, err
+}
 
-	serverID_col := -1
-	serverName_col := -1
-	ipv4_col := -1
+// GetImportProgress returns how far the import with the progress ID has got.
+func (s *serverCRUDService) GetImportProgress(progressID string) (*dto.ImportProgress, error) {
+	progress, ok := s.progress.get(progressID)
+	if !ok {
+		return nil, ErrImportProgressNotFound
+	}
+	return progress, nil
+}
 
-	for id, val := range rows[0] {
-		if val == "Server ID" {
-			serverID_col = id
-		} else if val == "Server Name" {
-			serverName_col = id
-		} else if val == "IPv4" {
-			ipv4_col = id
+func (s *serverCRUDService) importServers(buf []byte, options dto.ImportOptions) (*dto.ImportReport, error) {
+	reportProgress := func(phase string, processed, total int) {
+		s.progress.update(options.ProgressID, phase, processed, total)
+		if options.OnProgress != nil {
+			options.OnProgress(phase, processed, total)
 		}
 	}
 
-	if serverID_col == -1 || serverName_col == -1 || ipv4_col == -1 {
+	mode := strings.ToLower(options.Mode)
+	if mode == "" {
+		mode = ImportModeInsert
+	}
+	removal := strings.ToLower(options.Removal)
+	if removal == "" {
+		removal = SyncRemovalDecommission
+	}
+	if mode != ImportModeInsert && mode != ImportModeUpsert && mode != ImportModeSync {
+		return nil, fmt.Errorf("%w: unknown mode %q, expected insert, upsert or sync", ErrInvalidImport, options.Mode)
+	}
+	if removal != SyncRemovalDecommission && removal != SyncRemovalDelete {
+		return nil, fmt.Errorf("%w: unknown removal %q, expected decommission or delete", ErrInvalidImport, options.Removal)
+	}
+
+	format := strings.ToLower(options.Format)
+	if format == "" {
+		format = detectImportFormat(buf, options.Filename)
+	}
+
+	records, err := readImportRecords(buf, format)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to read "+format+" servers file: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		logging.LogMessage("server_administration_service", "Servers file doesn't have any row data", "ERROR")
+		return nil, fmt.Errorf("%w: the file doesn't have any row data", ErrInvalidImport)
+	}
+
+	columns, err := importColumns(records, format, options.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if columns["server_id"] == "" || columns["server_name"] == "" || columns["addresses"] == "" {
 		logging.LogMessage("server_administration_service", "Servers file doesn't contain enough information for importing", "INFO")
-		return nil, nil, errors.New("Failed to import servers: Not enough information")
+		return nil, fmt.Errorf("%w: server ID, server name and addresses are required", ErrInvalidImport)
 	}
 
-	for _, row := range rows[1:] {
-		serverID := row[serverID_col]
-		serverName := row[serverName_col]
-		ipv4 := row[ipv4_col]
+	report := &dto.ImportReport{
+		DryRun:             options.DryRun,
+		Mode:               mode,
+		ImportedServers:    []domain.Server{},
+		NonImportedServers: []domain.Server{},
+		Errors:             []dto.ImportRowError{},
+		Summary: dto.ImportSummary{
+			Created:   []string{},
+			Updated:   []string{},
+			Unchanged: []string{},
+			Removed:   []string{},
+		},
+	}
+
+	rows := make([]importRow, 0, len(records))
+	for i, record := range records {
+		rows = append(rows, importServer(record, columns))
+		if (i+1)%importProgressInterval == 0 {
+			reportProgress(ImportPhaseValidating, i+1, len(records))
+		}
+	}
+	reportProgress(ImportPhaseValidating, len(records), len(records))
+	checkImportDuplicates(rows)
+
+	if err := s.checkImportConflicts(rows, mode != ImportModeInsert); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to check imported servers against the inventory: "+err.Error(), "ERROR")
+		return nil, err
+	}
 
-		server := domain.Server{
-			ServerID:   serverID,
-			ServerName: serverName,
-			Status: "Off",
-			IPv4:       ipv4,
+	var newServers, changedServers []domain.Server
+	rowOf := make(map[string]int, len(rows))
+	existingOf := make(map[string]*domain.Server)
+	for _, row := range rows {
+		if row.Existing != nil && columns["notes"] == "" {
+			// Files without a notes column, like the exports of before there was one, leave the notes alone
+			row.Server.Notes = row.Existing.Notes
 		}
+		switch {
+		case len(row.Errors) > 0:
+			report.NonImportedServers = append(report.NonImportedServers, row.Server)
+			report.Errors = append(report.Errors, row.Errors...)
+		case row.Existing == nil:
+			newServers = append(newServers, row.Server)
+		case sameImportedServer(*row.Existing, row.Server):
+			report.Summary.Unchanged = append(report.Summary.Unchanged, row.Server.ServerID)
+		default:
+			changedServers = append(changedServers, row.Server)
+			existingOf[row.Server.ServerID] = row.Existing
+		}
+		rowOf[row.Server.ServerID] = row.Row
+	}
 
-		servers = append(servers, server)
+	var removedServers []domain.Server
+	if mode == ImportModeSync {
+		if removedServers, err = s.syncRemovals(rows, removal); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to get the servers missing from the file: "+err.Error(), "ERROR")
+			return nil, err
+		}
+		token := syncConfirmationToken(buf, removal, removedServers)
+		if options.DryRun {
+			report.ConfirmationToken = token
+		} else if options.ConfirmationToken != token {
+			return nil, fmt.Errorf("%w: run a dry run of the sync and pass its confirmation_token", ErrImportNotConfirmed)
+		}
 	}
 
-	insertedServers, nonInsertedServers, err := s.serverCRUDRepository.CreateServers(servers)
+	if options.DryRun {
+		report.ImportedServers = append(append(report.ImportedServers, newServers...), changedServers...)
+		report.Summary.Created = appendServerIDs(report.Summary.Created, newServers)
+		report.Summary.Updated = appendServerIDs(report.Summary.Updated, changedServers)
+		report.Summary.Removed = appendServerIDs(report.Summary.Removed, removedServers)
+		logging.LogMessage("server_administration_service", "Validated servers file, "+strconv.Itoa(len(newServers)+len(changedServers))+" of "+strconv.Itoa(len(rows))+" rows would be imported", "INFO")
+		return report, nil
+	}
+
+	total := len(newServers) + len(changedServers) + len(removedServers)
+	written := 0
+	reportProgress(ImportPhaseWriting, written, total)
+
+	// Creations, updates and removals are committed together, or not at all
+	var insertedServers, nonInsertedServers []domain.Server
+	var removedIDs []string
+	err = s.serverCRUDRepository.Transaction(func(tx repository.ServerCRUDRepository) error {
+		if len(newServers) > 0 {
+			var err error
+			insertedServers, nonInsertedServers, err = tx.CreateServers(newServers, func(processed int) {
+				reportProgress(ImportPhaseWriting, processed, total)
+				logging.LogMessage("server_administration_service", "Inserted "+strconv.Itoa(processed)+" of "+strconv.Itoa(len(newServers))+" imported servers", "INFO")
+			})
+			if err != nil {
+				return err
+			}
+			written += len(newServers)
+		}
+
+		if len(changedServers) > 0 {
+			if err := tx.UpdateServers(changedServers); err != nil {
+				return err
+			}
+			written += len(changedServers)
+			reportProgress(ImportPhaseWriting, written, total)
+		}
+
+		if len(removedServers) > 0 {
+			var err error
+			if removedIDs, err = removeSyncedServers(tx, removedServers, removal); err != nil {
+				return err
+			}
+			written += len(removedServers)
+			reportProgress(ImportPhaseWriting, written, total)
+		}
+		return nil
+	})
 	if err != nil {
 		logging.LogMessage("server_administration_service", "Failed to import servers: "+err.Error(), "ERROR")
-		return nil, nil, err
+		return nil, err
+	}
+
+	report.ImportedServers = append(report.ImportedServers, insertedServers...)
+	report.NonImportedServers = append(report.NonImportedServers, nonInsertedServers...)
+	report.Summary.Created = appendServerIDs(report.Summary.Created, insertedServers)
+	// The checks ran before the insert, a server created meanwhile can still take an ID, name or address
+	for _, server := range nonInsertedServers {
+		report.Errors = append(report.Errors, dto.ImportRowError{
+			Row:      rowOf[server.ServerID],
+			ServerID: server.ServerID,
+			Reason:   "conflicts with a server created during the import",
+		})
+	}
+	report.ImportedServers = append(report.ImportedServers, changedServers...)
+	report.Summary.Updated = appendServerIDs(report.Summary.Updated, changedServers)
+	report.Summary.Removed = append(report.Summary.Removed, removedIDs...)
+
+	if removal == SyncRemovalDecommission && len(removedIDs) > 0 {
+		if err := s.statusService.RecordStatusChanges(removedIDs, domain.StatusDecommissioned); err != nil {
+			logging.LogMessage("server_administration_service", "Servers decommissioned by the import but missing from the status history: "+err.Error(), "ERROR")
+		}
+	}
+
+	entries := make([]domain.AuditEntry, 0, len(insertedServers)+len(changedServers)+len(removedIDs))
+	for i := range insertedServers {
+		entries = append(entries, newAuditEntry(domain.AuditCreate, nil, &insertedServers[i]))
+	}
+	for _, server := range changedServers {
+		before := existingOf[server.ServerID]
+		after := *before
+		after.ServerName, after.PrimaryAddress, after.Addresses = server.ServerName, server.PrimaryAddress, server.Addresses
+		after.SLATarget, after.Labels, after.Notes = server.SLATarget, server.Labels, server.Notes
+		entries = append(entries, newAuditEntry(domain.AuditUpdate, before, &after))
 	}
-	
-	logging.LogMessage("server_administration_service", "Servers imported successfully", "INFO")
-	return insertedServers, nonInsertedServers, nil	
+	removed := make(map[string]bool, len(removedIDs))
+	for _, serverID := range removedIDs {
+		removed[serverID] = true
+	}
+	for i, server := range removedServers {
+		if !removed[server.ServerID] {
+			continue
+		}
+		if removal == SyncRemovalDelete {
+			entries = append(entries, newAuditEntry(domain.AuditDelete, &removedServers[i], nil))
+			continue
+		}
+		after := server
+		after.Status = domain.StatusDecommissioned
+		entries = append(entries, newAuditEntry(domain.AuditDecommission, &removedServers[i], &after))
+	}
+	s.audit(options.Actor, entries...)
+
+	logging.LogMessage("server_administration_service", "Servers imported successfully in "+mode+" mode", "INFO")
+	return report, nil
 }
 
-func (s *serverCRUDService) ExportServers(serverFilter *dto.ServerFilter, from, to int, sortedColumn string, order string) ([]byte, error) {
-	servers, err := s.serverCRUDRepository.ViewServers(serverFilter, from, to, sortedColumn, order)
+// checkImportConflicts reports the valid rows whose ID, name or one of whose addresses a server already has.
+// When updates are allowed a server with the row's ID is the one the row updates rather than a conflict,
+// unless it's in the trash.
+func (s *serverCRUDService) checkImportConflicts(rows []importRow, allowUpdates bool) error {
+	var serverIDs, serverNames, addresses []string
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			continue
+		}
+		serverIDs = append(serverIDs, row.Server.ServerID)
+		serverNames = append(serverNames, row.Server.ServerName)
+		for _, address := range row.Server.Addresses {
+			addresses = append(addresses, strings.ToLower(address.Address))
+		}
+	}
+	if len(serverIDs) == 0 {
+		return nil
+	}
+
+	existing, err := s.serverCRUDRepository.GetConflictingServers(serverIDs, serverNames, addresses)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]domain.Server, len(existing))
+	byName := make(map[string]string, len(existing))
+	byAddress := make(map[string]string)
+	for _, server := range existing {
+		byID[server.ServerID] = server
+		byName[server.ServerName] = server.ServerID
+		byAddress[strings.ToLower(server.PrimaryAddress)] = server.ServerID
+		for _, address := range server.Addresses {
+			byAddress[strings.ToLower(address.Address)] = server.ServerID
+		}
+	}
+
+	for i := range rows {
+		if len(rows[i].Errors) > 0 {
+			continue
+		}
+		server := rows[i].Server
+		if current, ok := byID[server.ServerID]; ok {
+			if current.DeletedAt.Valid {
+				rows[i].fail("server_id", "server "+server.ServerID+" is in the trash, restore it or wait for it to be purged")
+			} else if !allowUpdates {
+				rows[i].fail("server_id", "server "+server.ServerID+" already exists")
+			} else {
+				rows[i].Existing = &current
+			}
+		}
+		if owner, ok := byName[server.ServerName]; ok && owner != server.ServerID {
+			rows[i].fail("server_name", "server name "+server.ServerName+" is already used by server "+owner)
+		}
+		for _, address := range server.Addresses {
+			if owner, ok := byAddress[strings.ToLower(address.Address)]; ok && owner != server.ServerID {
+				rows[i].fail("addresses", "address "+address.Address+" is already used by server "+owner)
+			}
+		}
+	}
+	return nil
+}
+
+// syncRemovals returns the servers a sync removes: those whose ID no row of the file has, even an invalid row,
+// leaving out the ones already decommissioned when removal decommissions.
+func (s *serverCRUDService) syncRemovals(rows []importRow, removal string) ([]domain.Server, error) {
+	inFile := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		inFile[row.Server.ServerID] = true
+	}
+
+	servers, err := s.serverCRUDRepository.GetAllServers()
 	if err != nil {
-		logging.LogMessage("server_administration_service", "Failed to export servers: "+err.Error(), "ERROR")
 		return nil, err
 	}
 
-	f := excelize.NewFile()
-	sheet := "Servers"
-	f.SetSheetName("Sheet1", sheet)
+	removed := []domain.Server{}
+	for _, server := range servers {
+		if inFile[server.ServerID] {
+			continue
+		}
+		if removal == SyncRemovalDecommission && domain.NormalizeStatus(server.Status) == domain.StatusDecommissioned {
+			continue
+		}
+		removed = append(removed, server)
+	}
+	return removed, nil
+}
+
+// removeSyncedServers deletes or decommissions, in the import's transaction, the servers a sync removes.
+// Every state but Decommissioned, which syncRemovals leaves out, may move to Decommissioned. It returns
+// the IDs of the servers removed, leaving out the ones deleted in the meantime.
+func removeSyncedServers(tx repository.ServerCRUDRepository, servers []domain.Server, removal string) ([]string, error) {
+	serverIDs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		serverIDs = append(serverIDs, server.ServerID)
+	}
+
+	if removal == SyncRemovalDelete {
+		return tx.DeleteServersByID(serverIDs)
+	}
+	return tx.UpdateServersByID(serverIDs, map[string]interface{}{"status": domain.StatusDecommissioned})
+}
+
+// auditedServer returns the server as it is before a change, for the audit log. It's nil when the server
+// doesn't exist, the change then fails on its own, or when there's no audit log to write to.
+func (s *serverCRUDService) auditedServer(serverID string) (*domain.Server, error) {
+	if s.auditRepository == nil {
+		return nil, nil
+	}
+	return s.lookupServer(serverID)
+}
+
+// lookupServer returns the server with the ID, nil when there's none.
+func (s *serverCRUDService) lookupServer(serverID string) (*domain.Server, error) {
+	filter := &dto.ServerFilter{Expression: &domain.FilterExpression{
+		Field:    domain.FilterFieldServerID,
+		Operator: domain.FilterEquals,
+		Values:   []interface{}{serverID},
+	}}
+	servers, err := s.serverCRUDRepository.ViewServers(filter, 0, 1, nil)
+	if err != nil || len(servers) == 0 {
+		return nil, err
+	}
+	return &servers[0], nil
+}
 
-	headers := []string{"Server ID", "Server Name", "Status", "IPv4"}
-	for i, header := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheet, cell, header)
+func (s *serverCRUDService) audit(actor dto.Actor, entries ...domain.AuditEntry) {
+	recordAudit(s.auditRepository, actor, entries...)
+}
+
+// recordAudit records the entries of a change made by actor, unless auditRepository is nil. The change
+// is already written, so failing to record it is logged rather than returned.
+func recordAudit(auditRepository repository.AuditRepository, actor dto.Actor, entries ...domain.AuditEntry) {
+	if auditRepository == nil || len(entries) == 0 {
+		return
+	}
+	for i := range entries {
+		entries[i].UserID = actor.UserID
+		entries[i].SourceIP = actor.SourceIP
+	}
+	if err := auditRepository.CreateAuditEntries(entries); err != nil {
+		logging.LogMessage("server_administration_service", "Failed to record "+strconv.Itoa(len(entries))+" audit entries of user "+actor.UserID+": "+err.Error(), "ERROR")
 	}
+}
 
-	for i, server := range servers {
-		f.SetCellValue(sheet, "A"+strconv.Itoa(i+2), server.ServerID)
-		f.SetCellValue(sheet, "B"+strconv.Itoa(i+2), server.ServerName)
-		f.SetCellValue(sheet, "C"+strconv.Itoa(i+2), server.Status)
-		f.SetCellValue(sheet, "D"+strconv.Itoa(i+2), server.IPv4)
+func newAuditEntry(action string, before, after *domain.Server) domain.AuditEntry {
+	entry := domain.AuditEntry{
+		ID:      uuid.New().String(),
+		Action:  action,
+		Changes: domain.DiffServers(before, after),
+	}
+	if before != nil {
+		entry.ServerID = before.ServerID
+	} else {
+		entry.ServerID = after.ServerID
 	}
+	return entry
+}
 
-	var buf bytes.Buffer
-	_ = f.Write(&buf)
+// applyServerUpdate sets the fields UpdateServer writes on server.
+func applyServerUpdate(server *domain.Server, updatedData map[string]interface{}) {
+	if serverName, ok := updatedData["server_name"].(string); ok {
+		server.ServerName = serverName
+	}
+	if addresses, ok := updatedData["addresses"].(domain.Addresses); ok {
+		server.Addresses = addresses
+	}
+	if primaryAddress, ok := updatedData["primary_address"].(string); ok {
+		server.PrimaryAddress = primaryAddress
+	}
+	if slaTarget, ok := updatedData["sla_target"].(float64); ok {
+		server.SLATarget = slaTarget
+	}
+	if labels, ok := updatedData["labels"].(domain.Labels); ok {
+		server.Labels = labels
+	}
+	if notes, ok := updatedData["notes"].(string); ok {
+		server.Notes = notes
+	}
+}
 
-	logging.LogMessage("server_administration_service", "Servers exported successfully", "INFO")
-	return buf.Bytes(), nil
-}
\ No newline at end of file
+// ExportServers writes the servers matching the options to w in their format, xlsx unless told otherwise.
+// The servers are streamed from the database as they're written, and the uptime over
+// [UpTimeFrom, UpTimeTo] is added when both are set. Everything is checked before anything is written,
+// so an error once writing has started can only come from the database or w itself. Report makes an
+// xlsx export a formatted workbook with a Summary sheet of counts, uptime and charts.
+func (s *serverCRUDService) ExportServers(w io.Writer, options dto.ExportOptions) error {
+	format, err := exportFormat(options.Format)
+	if err != nil {
+		return err
+	}
+
+	filter := options.Filter
+	if filter.LabelSelector, err = domain.ParseLabelSelector(options.LabelSelector); err != nil {
+		return err
+	}
+	if filter.Expression, err = domain.ParseFilterExpression(options.FilterExpression); err != nil {
+		return err
+	}
+	sort, err := domain.ParseServerSort(options.SortColumn, options.Order)
+	if err != nil {
+		return err
+	}
+
+	columns := exportColumns
+	title := "Servers exported " + time.Now().Format("2006-01-02 15:04")
+	var ratios map[string]float64
+	if options.UpTimeFrom != nil || options.UpTimeTo != nil {
+		if options.UpTimeFrom == nil || options.UpTimeTo == nil || !options.UpTimeFrom.Before(*options.UpTimeTo) {
+			return fmt.Errorf("%w: uptime_from and uptime_to must both be set, uptime_from first", ErrInvalidExport)
+		}
+		if s.infoService == nil {
+			return fmt.Errorf("%w: uptime isn't available", ErrInvalidExport)
+		}
+		if ratios, err = s.infoService.GetUpTimeRatios(*options.UpTimeFrom, *options.UpTimeTo); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to measure uptime for export: "+err.Error(), "ERROR")
+			return err
+		}
+		columns = append(append([]exportColumn{}, exportColumns...), upTimeColumn)
+		title += ", uptime from " + options.UpTimeFrom.Format(time.RFC3339) + " to " + options.UpTimeTo.Format(time.RFC3339)
+	}
+
+	var report *exportReport
+	if options.Report {
+		if format != ExportFormatXLSX {
+			return fmt.Errorf("%w: a report is only available as xlsx", ErrInvalidExport)
+		}
+		var trend []dto.UpTimePoint
+		if ratios != nil {
+			points := reportTrendPoints(*options.UpTimeFrom, *options.UpTimeTo)
+			if trend, err = s.infoService.GetUpTimeTrend(*options.UpTimeFrom, *options.UpTimeTo, points); err != nil {
+				logging.LogMessage("server_administration_service", "Failed to measure uptime trend for export: "+err.Error(), "ERROR")
+				return err
+			}
+		}
+		report = newExportReport(title, options.UpTimeFrom, options.UpTimeTo, trend)
+	}
+
+	writer, err := newServerExportWriter(w, format, title, columns, report)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to start "+format+" export: "+err.Error(), "ERROR")
+		return err
+	}
+
+	exported := 0
+	err = s.serverCRUDRepository.StreamServers(&filter, options.From, options.To, sort, func(server domain.Server) error {
+		row := exportedServer{Server: server}
+		if ratio, ok := ratios[server.ServerID]; ok {
+			ratio = math.Round(ratio*100) / 100
+			row.UpTime = &ratio
+		}
+		exported++
+		return writer.writeServer(row)
+	})
+	if err == nil {
+		err = writer.close()
+	}
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to export servers: "+err.Error(), "ERROR")
+		return err
+	}
+
+	logging.LogMessage("server_administration_service", strconv.Itoa(exported)+" servers exported successfully as "+format, "INFO")
+	return nil
+}
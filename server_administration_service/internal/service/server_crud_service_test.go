@@ -2,18 +2,28 @@ package service_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
 	"server_administration_service/internal/service"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
 )
 
+// byServerID is the sort of a listing or export that names no column
+var byServerID = domain.ServerSort{{Column: "server_id"}}
+
 // Mock for ServerCRUDRepository
 type mockServerCRUDRepository struct {
 	mock.Mock
@@ -24,45 +34,163 @@ func (m *mockServerCRUDRepository) CreateServer(server *domain.Server) (string,
 	return args.String(0), args.Error(1)
 }
 
-func (m *mockServerCRUDRepository) ViewServers(filter *dto.ServerFilter, from, to int, sortedColumn, order string) ([]domain.Server, error) {
-	args := m.Called(filter, from, to, sortedColumn, order)
+func (m *mockServerCRUDRepository) ViewServers(filter *dto.ServerFilter, from, to int, sort domain.ServerSort) ([]domain.Server, error) {
+	args := m.Called(filter, from, to, sort)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) ViewServersByKey(filter *dto.ServerFilter, keyset dto.ServerKeyset) ([]domain.Server, error) {
+	args := m.Called(filter, keyset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]domain.Server), args.Error(1)
 }
 
-func (m *mockServerCRUDRepository) UpdateServer(server_id string, updatedData map[string]interface{}) error {
-	args := m.Called(server_id, updatedData)
+func (m *mockServerCRUDRepository) CountServers(filter *dto.ServerFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) StreamServers(filter *dto.ServerFilter, from, to int, sort domain.ServerSort, fn func(server domain.Server) error) error {
+	args := m.Called(filter, from, to, sort)
+	if servers, ok := args.Get(0).([]domain.Server); ok {
+		for _, server := range servers {
+			if err := fn(server); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) UpdateServer(server_id string, updatedData map[string]interface{}, version int64) error {
+	args := m.Called(server_id, updatedData, version)
 	return args.Error(0)
 }
 
-func (m *mockServerCRUDRepository) DeleteServer(server_id string) error {
-	args := m.Called(server_id)
+func (m *mockServerCRUDRepository) DeleteServer(server_id string, version int64) error {
+	args := m.Called(server_id, version)
+	return args.Error(0)
+}
+
+func (m *mockServerCRUDRepository) UpdateServersByID(serverIDs []string, updatedData map[string]interface{}) ([]string, error) {
+	args := m.Called(serverIDs, updatedData)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) DeleteServersByID(serverIDs []string) ([]string, error) {
+	args := m.Called(serverIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) GetConflictingServers(serverIDs, serverNames, addresses []string) ([]domain.Server, error) {
+	args := m.Called(serverIDs, serverNames, addresses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) UpdateServers(servers []domain.Server) error {
+	args := m.Called(servers)
 	return args.Error(0)
 }
 
-func (m *mockServerCRUDRepository) CreateServers(servers []domain.Server) ([]domain.Server, []domain.Server, error) {
+func (m *mockServerCRUDRepository) GetAllServers() ([]domain.Server, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockServerCRUDRepository) CreateServers(servers []domain.Server, onBatch func(processed int)) ([]domain.Server, []domain.Server, error) {
 	args := m.Called(servers)
+	if onBatch != nil {
+		onBatch(len(servers))
+	}
 	if args.Get(0) == nil || args.Get(1) == nil {
 		return nil, nil, args.Error(2)
 	}
 	return args.Get(0).([]domain.Server), args.Get(1).([]domain.Server), args.Error(2)
 }
 
+// Transaction runs fn on the mock itself, the calls made in the transaction are expected on it
+func (m *mockServerCRUDRepository) Transaction(fn func(tx repository.ServerCRUDRepository) error) error {
+	return fn(m)
+}
+
+// Mock for ServerInfoService
+type mockServerInfoService struct {
+	service.ServerInfoService
+	mock.Mock
+}
+
+func (m *mockServerInfoService) GetUpTimeRatios(start, end time.Time) (map[string]float64, error) {
+	args := m.Called(start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]float64), args.Error(1)
+}
+
+func (m *mockServerInfoService) GetUpTimeTrend(start, end time.Time, points int) ([]dto.UpTimePoint, error) {
+	args := m.Called(start, end, points)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.UpTimePoint), args.Error(1)
+}
+
+// Mock for ServerStatusService
+type mockServerStatusService struct {
+	mock.Mock
+}
+
+func (m *mockServerStatusService) ChangeStatus(serverID, status string) error {
+	args := m.Called(serverID, status)
+	return args.Error(0)
+}
+
+func (m *mockServerStatusService) RecordStatusChanges(serverIDs []string, status string) error {
+	args := m.Called(serverIDs, status)
+	return args.Error(0)
+}
+
 func TestCreateServer_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	server := &domain.Server{
 		ServerID:   "srv1",
 		ServerName: "Server One",
-		Status:     "Off",
-		IPv4:       "192.168.1.1",
+		Status:     domain.StatusUnknown,
+		PrimaryAddress: "192.168.1.1",
+		Addresses: domain.Addresses{
+			{Type: domain.AddressTypeIPv4, Address: "192.168.1.1", Primary: true},
+			{Type: domain.AddressTypeIPv6, Address: "2001:db8::1"},
+			{Type: domain.AddressTypeFQDN, Address: "srv1.example.com"},
+		},
+		SLATarget:  domain.DefaultSLATarget,
+		Labels:     domain.Labels{"env": "prod"},
+		Notes:      "Rack 4, PSU replaced",
 	}
+	mockRepo.On("GetConflictingServers", []string{"srv1"}, []string{"Server One"}, []string{"192.168.1.1", "2001:db8::1", "srv1.example.com"}).Return([]domain.Server{}, nil)
 	mockRepo.On("CreateServer", server).Return("srv1", nil)
 
-	id, err := service.CreateServer("srv1", "Server One", "192.168.1.1")
+	// Types are detected and the first address becomes primary
+	addresses := domain.Addresses{{Address: "192.168.1.1"}, {Address: "2001:db8::1"}, {Address: "srv1.example.com"}}
+	id, err := service.CreateServer("srv1", "Server One", addresses, 0, domain.Labels{"env": "prod"}, "Rack 4, PSU replaced", dto.Actor{})
 	assert.NoError(t, err)
 	assert.Equal(t, "srv1", id)
 	mockRepo.AssertExpectations(t)
@@ -70,33 +198,191 @@ func TestCreateServer_Success(t *testing.T) {
 
 func TestCreateServer_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	server := &domain.Server{
 		ServerID:   "srv2",
 		ServerName: "Server Two",
-		Status:     "Off",
-		IPv4:       "10.0.0.2",
+		Status:     domain.StatusUnknown,
+		PrimaryAddress: "10.0.0.2",
+		Addresses:  domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.2", Primary: true}},
+		SLATarget:  99.5,
+		Labels:     domain.Labels{},
 	}
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
 	mockRepo.On("CreateServer", server).Return("", errors.New("db error"))
 
-	id, err := service.CreateServer("srv2", "Server Two", "10.0.0.2")
+	id, err := service.CreateServer("srv2", "Server Two", domain.Addresses{{Address: "10.0.0.2"}}, 99.5, nil, "", dto.Actor{})
 	assert.Error(t, err)
 	assert.Empty(t, id)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateServer_Conflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing domain.Server
+		message  string
+	}{
+		{"ID", domain.Server{ServerID: "srv1", ServerName: "Other"}, "server_id srv1 is taken"},
+		{"name in the trash", domain.Server{ServerID: "srv9", ServerName: "Server One", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+			`server_name "Server One" is taken by server srv9, in the trash`},
+		{"secondary address", domain.Server{ServerID: "srv9", ServerName: "Other", PrimaryAddress: "10.9.9.9",
+			Addresses: domain.Addresses{{Address: "10.9.9.9", Primary: true}, {Address: "SRV1.example.com"}}}, "address srv1.example.com is taken by server srv9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mockServerCRUDRepository)
+			svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+			mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{tt.existing}, nil)
+
+			_, err := svc.CreateServer("srv1", "Server One", domain.Addresses{{Address: "10.0.0.1"}, {Address: "srv1.example.com"}}, 0, nil, "", dto.Actor{})
+			assert.ErrorIs(t, err, service.ErrServerConflict)
+			assert.Contains(t, err.Error(), tt.message)
+			mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+		})
+	}
+}
+
+func TestCreateServer_DuplicateKey(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	// Another server got the name between the check and the insert
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServer", mock.Anything).Return("", gorm.ErrDuplicatedKey)
+
+	_, err := svc.CreateServer("srv1", "Server One", domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrServerConflict)
+}
+
+func TestCreateServer_InvalidIDAndName(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	_, err := svc.CreateServer("srv 1", "Server One", domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidServer)
+	_, err = svc.CreateServer("srv1", "", domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidServer)
+	_, err = svc.CreateServer("srv1", "Server\tOne", domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidServer)
+	mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+}
+
+func TestCreateServer_QuotedName(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	// Names are stored through parameters, quotes in them are just characters
+	mockRepo.On("GetConflictingServers", mock.Anything, []string{`O'Brien's "box"`}, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServer", mock.MatchedBy(func(server *domain.Server) bool {
+		return server.ServerName == `O'Brien's "box"`
+	})).Return("srv1", nil)
+
+	_, err := svc.CreateServer("srv1", `O'Brien's "box"`, domain.Addresses{{Address: "10.0.0.1"}}, 0, nil, "", dto.Actor{})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateServer_InvalidSLATarget(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	_, err := service.CreateServer("srv3", "Server Three", domain.Addresses{{Address: "10.0.0.3"}}, 100, nil, "", dto.Actor{})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+}
+
+func TestCreateServer_InvalidLabels(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	_, err := service.CreateServer("srv3", "Server Three", domain.Addresses{{Address: "10.0.0.3"}}, 0, domain.Labels{"bad key": "x"}, "", dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidLabel)
+	mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+}
+
+func TestCreateServer_InvalidAddresses(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	for _, addresses := range []domain.Addresses{
+		nil,
+		{{Address: "10.0.0.300"}},
+		{{Type: domain.AddressTypeIPv4, Address: "2001:db8::1"}},
+		{{Type: "mac", Address: "00:11:22:33:44:55"}},
+		{{Address: "bad_host.example.com"}},
+		{{Address: "10.0.0.1"}, {Address: "10.0.0.1"}},
+		{{Address: "10.0.0.1", Primary: true}, {Address: "10.0.0.2", Primary: true}},
+	} {
+		_, err := service.CreateServer("srv3", "Server Three", addresses, 0, nil, "", dto.Actor{})
+		assert.ErrorIs(t, err, domain.ErrInvalidAddress, "%+v", addresses)
+	}
+	mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+}
+
+func TestUpdateServer_Addresses(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("GetConflictingServers", []string(nil), []string(nil), []string{"10.0.0.1", "srv1.example.com"}).Return([]domain.Server{}, nil)
+	mockRepo.On("UpdateServer", "srv1", map[string]interface{}{
+		"addresses": domain.Addresses{
+			{Type: domain.AddressTypeIPv4, Address: "10.0.0.1"},
+			{Type: domain.AddressTypeFQDN, Address: "srv1.example.com", Primary: true},
+		},
+		"primary_address": "srv1.example.com",
+	}, int64(0)).Return(nil)
+
+	err := service.UpdateServer("srv1", map[string]interface{}{
+		"addresses": domain.Addresses{{Address: "10.0.0.1"}, {Address: "srv1.example.com", Primary: true}},
+	}, 0, dto.Actor{})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateServer_InvalidAddresses(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	err := service.UpdateServer("srv1", map[string]interface{}{"addresses": domain.Addresses{}}, 0, dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+	mockRepo.AssertNotCalled(t, "UpdateServer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateServer_NotesTooLong(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	notes := strings.Repeat("x", domain.MaxServerNotesLength+1)
+	_, err := service.CreateServer("srv3", "Server Three", domain.Addresses{{Address: "10.0.0.3"}}, 0, nil, notes, dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidServer)
+	err = service.UpdateServer("srv3", map[string]interface{}{"notes": notes}, 0, dto.Actor{})
+	assert.ErrorIs(t, err, domain.ErrInvalidServer)
+	mockRepo.AssertNotCalled(t, "CreateServer", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateServer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateServer_InvalidSLATarget(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	err := service.UpdateServer("srv1", map[string]interface{}{"sla_target": -1.0}, 0, dto.Actor{})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "UpdateServer", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestViewServers_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	filter := &dto.ServerFilter{}
 	expected := []domain.Server{
-		{ServerID: "srv1", ServerName: "Server One", Status: "Off", IPv4: "192.168.1.1"},
+		{ServerID: "srv1", ServerName: "Server One", Status: "Off", PrimaryAddress: "192.168.1.1"},
 	}
-	mockRepo.On("ViewServers", filter, 0, 10, "ServerID", "asc").Return(expected, nil)
+	mockRepo.On("ViewServers", filter, 0, 10, byServerID).Return(expected, nil)
 
-	servers, err := service.ViewServers(filter, 0, 10, "ServerID", "asc")
+	servers, err := service.ViewServers(filter, 0, 10, byServerID)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, servers)
 	mockRepo.AssertExpectations(t)
@@ -104,66 +390,203 @@ func TestViewServers_Success(t *testing.T) {
 
 func TestViewServers_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	filter := &dto.ServerFilter{}
-	mockRepo.On("ViewServers", filter, 0, 10, "ServerID", "asc").Return(nil, errors.New("db error"))
+	mockRepo.On("ViewServers", filter, 0, 10, byServerID).Return(nil, errors.New("db error"))
 
-	servers, err := service.ViewServers(filter, 0, 10, "ServerID", "asc")
+	servers, err := service.ViewServers(filter, 0, 10, byServerID)
 	assert.Error(t, err)
 	assert.Nil(t, servers)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestViewServerPage_Cursors(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	filter := &dto.ServerFilter{}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 123456000, time.UTC)
+	servers := make([]domain.Server, 5)
+	for i := range servers {
+		servers[i] = domain.Server{ServerID: fmt.Sprintf("srv%d", i+1), CreatedTime: base.Add(time.Duration(i) * time.Hour)}
+	}
+	mockRepo.On("CountServers", filter).Return(int64(5), nil)
+
+	// First page: one more than the limit is read to know there's a next page
+	byCreated := domain.ServerSort{{Column: "created_time", Desc: true}, {Column: "server_id"}}
+	mockRepo.On("ViewServersByKey", filter, dto.ServerKeyset{Sort: byCreated, Limit: 3}).Return(servers[:3], nil).Once()
+	first, err := svc.ViewServerPage(filter, dto.ServerPageRequest{Sort: byCreated, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, servers[:2], first.Items)
+	assert.Equal(t, int64(5), first.Total)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	// The cursor carries the sort and goes on from the last server of the page
+	mockRepo.On("ViewServersByKey", filter, dto.ServerKeyset{Sort: byCreated, Limit: 3,
+		After: &dto.ServerKey{Values: []interface{}{servers[1].CreatedTime, "srv2"}}}).Return(servers[2:4], nil).Once()
+	second, err := svc.ViewServerPage(filter, dto.ServerPageRequest{Limit: 2, Cursor: first.NextCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, servers[2:4], second.Items)
+	assert.Empty(t, second.NextCursor)
+	assert.NotEmpty(t, second.PrevCursor)
+
+	// Going back drops the extra server from the front
+	mockRepo.On("ViewServersByKey", filter, dto.ServerKeyset{Sort: byCreated, Limit: 3, Backward: true,
+		After: &dto.ServerKey{Values: []interface{}{servers[2].CreatedTime, "srv3"}}}).Return(servers[:2], nil).Once()
+	back, err := svc.ViewServerPage(filter, dto.ServerPageRequest{Sort: byCreated, Limit: 2, Cursor: second.PrevCursor})
+	assert.NoError(t, err)
+	assert.Equal(t, servers[:2], back.Items)
+	assert.NotEmpty(t, back.NextCursor)
+	assert.Empty(t, back.PrevCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestViewServerPage_Invalid(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("CountServers", mock.Anything).Return(int64(1), nil)
+	mockRepo.On("ViewServersByKey", mock.Anything, mock.Anything).Return([]domain.Server{{ServerID: "srv1", ServerName: "one"}, {ServerID: "srv2"}}, nil).Once()
+	byName := domain.ServerSort{{Column: "server_name"}, {Column: "server_id"}}
+	page, err := svc.ViewServerPage(&dto.ServerFilter{}, dto.ServerPageRequest{Sort: byName, Limit: 1})
+	assert.NoError(t, err)
+
+	for _, request := range []dto.ServerPageRequest{
+		{Limit: 5000},
+		{Cursor: "not a cursor"},
+		{Sort: domain.ServerSort{{Column: "server_id"}}, Cursor: page.NextCursor},
+	} {
+		_, err := svc.ViewServerPage(&dto.ServerFilter{}, request)
+		assert.ErrorIs(t, err, service.ErrInvalidPage, "%+v", request)
+	}
+	mockRepo.AssertNumberOfCalls(t, "ViewServersByKey", 1)
+}
+
 func TestUpdateServer_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	updatedData := map[string]interface{}{"ServerName": "Updated"}
-	mockRepo.On("UpdateServer", "srv1", updatedData).Return(nil)
+	mockRepo.On("UpdateServer", "srv1", updatedData, int64(0)).Return(nil)
 
-	err := service.UpdateServer("srv1", updatedData)
+	err := service.UpdateServer("srv1", updatedData, 0, dto.Actor{})
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestUpdateServer_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	updatedData := map[string]interface{}{"ServerName": "Updated"}
-	mockRepo.On("UpdateServer", "srv1", updatedData).Return(errors.New("update error"))
+	mockRepo.On("UpdateServer", "srv1", updatedData, int64(0)).Return(errors.New("update error"))
 
-	err := service.UpdateServer("srv1", updatedData)
+	err := service.UpdateServer("srv1", updatedData, 0, dto.Actor{})
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateServer_NotFound(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("UpdateServer", "srv9", map[string]interface{}{"notes": "Rack 5"}, int64(0)).Return(gorm.ErrRecordNotFound)
+
+	err := svc.UpdateServer("srv9", map[string]interface{}{"notes": "Rack 5"}, 0, dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrServerNotFound)
+}
+
+func TestUpdateServer_NameTaken(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	// The server keeping its own name is no conflict, another server having it is
+	mockRepo.On("GetConflictingServers", []string(nil), []string{"web-1"}, []string(nil)).
+		Return([]domain.Server{{ServerID: "srv1", ServerName: "web-1"}}, nil).Once()
+	mockRepo.On("UpdateServer", "srv1", mock.Anything, int64(0)).Return(nil)
+	assert.NoError(t, svc.UpdateServer("srv1", map[string]interface{}{"server_name": "web-1"}, 0, dto.Actor{}))
+
+	mockRepo.On("GetConflictingServers", []string(nil), []string{"web-1"}, []string(nil)).
+		Return([]domain.Server{{ServerID: "srv1", ServerName: "web-1"}}, nil)
+	err := svc.UpdateServer("srv2", map[string]interface{}{"server_name": "web-1"}, 0, dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrServerConflict)
+	mockRepo.AssertNumberOfCalls(t, "UpdateServer", 1)
+}
+
 func TestDeleteServer_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
-	mockRepo.On("DeleteServer", "srv1").Return(nil)
+	mockRepo.On("DeleteServer", "srv1", int64(0)).Return(nil)
 
-	err := service.DeleteServer("srv1")
+	err := service.DeleteServer("srv1", 0, dto.Actor{})
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestDeleteServer_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
-	mockRepo.On("DeleteServer", "srv1").Return(errors.New("delete error"))
+	mockRepo.On("DeleteServer", "srv1", int64(0)).Return(errors.New("delete error"))
 
-	err := service.DeleteServer("srv1")
+	err := service.DeleteServer("srv1", 0, dto.Actor{})
 	assert.Error(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetServer_NotFound(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{}, nil)
+
+	_, err := svc.GetServer("srv1")
+	assert.ErrorIs(t, err, service.ErrServerNotFound)
+}
+
+func TestUpdateServer_StaleVersion(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{{ServerID: "srv1", Version: 3}}, nil)
+
+	err := svc.UpdateServer("srv1", map[string]interface{}{"notes": "Rack 5"}, 2, dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrVersionConflict)
+	mockRepo.AssertNotCalled(t, "UpdateServer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateServer_ChangedWhileWriting(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	updatedData := map[string]interface{}{"notes": "Rack 5"}
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{{ServerID: "srv1", Version: 3}}, nil)
+	// Another edit got in between the check and the write
+	mockRepo.On("UpdateServer", "srv1", updatedData, int64(3)).Return(gorm.ErrRecordNotFound)
+
+	err := svc.UpdateServer("srv1", updatedData, 3, dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrVersionConflict)
+}
+
+func TestDeleteServer_Versioned(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("ViewServers", oneServer("srv1"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{{ServerID: "srv1", Version: 3}}, nil).Once()
+	mockRepo.On("DeleteServer", "srv1", int64(3)).Return(nil)
+	assert.NoError(t, svc.DeleteServer("srv1", 3, dto.Actor{}))
+
+	mockRepo.On("ViewServers", oneServer("srv2"), 0, 1, domain.ServerSort(nil)).Return([]domain.Server{}, nil)
+	assert.ErrorIs(t, svc.DeleteServer("srv2", 3, dto.Actor{}), service.ErrServerNotFound)
+	mockRepo.AssertNumberOfCalls(t, "DeleteServer", 1)
+}
+
 func TestImportServers_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	// Prepare Excel file in memory
 	buf := new(bytes.Buffer)
@@ -171,119 +594,690 @@ func TestImportServers_Success(t *testing.T) {
 	_ = f.Write(buf)
 
 	expectedServers := []domain.Server{
-		{ServerID: "srv1", ServerName: "Server One", Status: "Off", IPv4: "192.168.1.1"},
+		{ServerID: "srv1", ServerName: "Server One", Status: "Off", PrimaryAddress: "192.168.1.1"},
 	}
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
 	mockRepo.On("CreateServers", mock.Anything).Return(expectedServers, []domain.Server{}, nil)
 
-	inserted, nonInserted, err := service.ImportServers(buf.Bytes())
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedServers, report.ImportedServers)
+	assert.Empty(t, report.NonImportedServers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_Labels(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	f := createTestExcelFile()
+	f.SetCellValue("Servers", "D1", "Labels")
+	f.SetCellValue("Servers", "D2", "env=prod, role=db")
+	f.SetCellValue("Servers", "A3", "srv2")
+	f.SetCellValue("Servers", "B3", "Server Two")
+	f.SetCellValue("Servers", "C3", "192.168.1.2")
+	f.SetCellValue("Servers", "D3", "not a label")
+	buf := new(bytes.Buffer)
+	_ = f.Write(buf)
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].Labels["env"] == "prod" && servers[0].Labels["role"] == "db"
+	})).Return([]domain.Server{{ServerID: "srv1"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
 	assert.NoError(t, err)
-	assert.Equal(t, expectedServers, inserted)
-	assert.Empty(t, nonInserted)
+	assert.Len(t, report.ImportedServers, 1)
+	if assert.Len(t, report.NonImportedServers, 1) {
+		assert.Equal(t, "srv2", report.NonImportedServers[0].ServerID)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_Addresses(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	f := createTestExcelFile()
+	f.SetCellValue("Servers", "C1", "Addresses")
+	f.SetCellValue("Servers", "C2", "srv1.example.com, 192.168.1.1, 2001:db8::1")
+	f.SetCellValue("Servers", "A3", "srv2")
+	f.SetCellValue("Servers", "B3", "Server Two")
+	f.SetCellValue("Servers", "C3", "192.168.1.300")
+	buf := new(bytes.Buffer)
+	_ = f.Write(buf)
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].PrimaryAddress == "srv1.example.com" && len(servers[0].Addresses) == 3 &&
+			servers[0].Addresses[2].Type == domain.AddressTypeIPv6
+	})).Return([]domain.Server{{ServerID: "srv1"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.ImportedServers, 1)
+	if assert.Len(t, report.NonImportedServers, 1) {
+		assert.Equal(t, "srv2", report.NonImportedServers[0].ServerID)
+	}
 	mockRepo.AssertExpectations(t)
 }
 
 func TestImportServers_InvalidFile(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	invalidBuf := []byte("not an excel file")
-	inserted, nonInserted, err := service.ImportServers(invalidBuf)
+	report, err := service.ImportServers(invalidBuf, dto.ImportOptions{})
 	assert.Error(t, err)
-	assert.Nil(t, inserted)
-	assert.Nil(t, nonInserted)
+	assert.Nil(t, report)
 }
 
 func TestImportServers_MissingSheet(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	// Excel file with missing columns
 	buf := new(bytes.Buffer)
 	f := createTestExcelFileMissingSheet()
 	_ = f.Write(buf)
 
-	inserted, nonInserted, err := service.ImportServers(buf.Bytes())
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
 	assert.Error(t, err)
-	assert.Nil(t, inserted)
-	assert.Nil(t, nonInserted)
+	assert.Nil(t, report)
 }
 
 func TestImportServers_MissingColumns(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	// Excel file with missing columns
 	buf := new(bytes.Buffer)
 	f := createTestExcelFileMissingColumns()
 	_ = f.Write(buf)
 
-	inserted, nonInserted, err := service.ImportServers(buf.Bytes())
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
 	assert.Error(t, err)
-	assert.Nil(t, inserted)
-	assert.Nil(t, nonInserted)
+	assert.Nil(t, report)
 }
 
 func TestImportServers_MissingRows(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	// Excel file with missing rows
 	buf := new(bytes.Buffer)
 	f := createTestExcelFileMissingRows()
 	_ = f.Write(buf)
 
-	inserted, nonInserted, err := service.ImportServers(buf.Bytes())
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
 	assert.Error(t, err)
-	assert.Nil(t, inserted)
-	assert.Nil(t, nonInserted)
+	assert.Nil(t, report)
 }
 
 func TestImportServers_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
 	// Excel file with missing rows
 	buf := new(bytes.Buffer)
 	f := createTestExcelFile()
 	_ = f.Write(buf)
 
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
 	mockRepo.On("CreateServers", mock.Anything).Return(nil, nil, errors.New("import error"))
 
-	inserted, nonInserted, err := service.ImportServers(buf.Bytes())
+	report, err := service.ImportServers(buf.Bytes(), dto.ImportOptions{})
+	assert.Error(t, err)
+	assert.Nil(t, report)
+}
+
+func TestImportServers_CSVWithMapping(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := "hostname,description,ip,tags\n" +
+		"web-1,Web One,\"10.0.0.1,web-1.example.com\",env=prod\n"
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].ServerID == "web-1" && servers[0].ServerName == "Web One" &&
+			servers[0].PrimaryAddress == "10.0.0.1" && len(servers[0].Addresses) == 2 && servers[0].Labels["env"] == "prod"
+	})).Return([]domain.Server{{ServerID: "web-1"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers([]byte(csvFile), dto.ImportOptions{
+		Filename: "inventory.csv",
+		Mapping:  map[string]string{"server_id": "hostname", "server_name": "description", "addresses": "ip", "labels": "tags"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.ImportedServers, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_JSON(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	jsonFile := `[{"server_id": "db-1", "server_name": "DB One", "sla_target": 99.5, "labels": {"role": "db"},
+		"addresses": [{"address": "db-1.example.com"}, {"address": "10.0.0.2", "primary": true}]}]`
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].PrimaryAddress == "10.0.0.2" && servers[0].SLATarget == 99.5 &&
+			servers[0].Labels["role"] == "db"
+	})).Return([]domain.Server{{ServerID: "db-1"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers([]byte(jsonFile), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.ImportedServers, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_YAML(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	yamlFile := "- server_id: cache-1\n  server_name: Cache One\n  addresses: [10.0.0.3]\n"
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].ServerID == "cache-1" && servers[0].PrimaryAddress == "10.0.0.3"
+	})).Return([]domain.Server{{ServerID: "cache-1"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers([]byte(yamlFile), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.ImportedServers, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_Nmap(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	nmapFile := `<?xml version="1.0"?>
+<nmaprun scanner="nmap">
+  <host><status state="up"/><address addr="10.0.0.4" addrtype="ipv4"/><address addr="00:11:22:33:44:55" addrtype="mac"/>
+    <hostnames><hostname name="app-4.example.com" type="PTR"/></hostnames></host>
+  <host><status state="up"/><address addr="10.0.0.5" addrtype="ipv4"/></host>
+  <host><status state="down"/><address addr="10.0.0.6" addrtype="ipv4"/></host>
+</nmaprun>`
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 2 &&
+			servers[0].ServerID == "app-4.example.com" && servers[0].PrimaryAddress == "10.0.0.4" && len(servers[0].Addresses) == 2 &&
+			servers[1].ServerID == "10.0.0.5" && len(servers[1].Addresses) == 1
+	})).Return([]domain.Server{{ServerID: "app-4.example.com"}, {ServerID: "10.0.0.5"}}, []domain.Server{}, nil)
+
+	report, err := service.ImportServers([]byte(nmapFile), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, report.ImportedServers, 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_InvalidMapping(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n")
+
+	_, err := svc.ImportServers(csvFile, dto.ImportOptions{Mapping: map[string]string{"owner": "Server Name"}})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+	_, err = svc.ImportServers(csvFile, dto.ImportOptions{Mapping: map[string]string{"server_id": "hostname"}})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+	_, err = svc.ImportServers(csvFile, dto.ImportOptions{Format: "toml"})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+	mockRepo.AssertNotCalled(t, "CreateServers", mock.Anything)
+}
+
+func TestImportServers_DryRunReport(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := "Server ID,Server Name,Addresses,SLA Target\n" +
+		"srv1,Server One,10.0.0.1,99.5\n" +
+		"srv2,Server Two\n" +
+		"srv1,Server Three,10.0.0.3\n" +
+		"srv4,Server Four,10.0.0.1\n" +
+		"srv 5,Server Five,10.0.0.5,120\n" +
+		"srv6,Server Six,10.0.0.6\n"
+
+	mockRepo.On("GetConflictingServers", []string{"srv1", "srv6"}, []string{"Server One", "Server Six"}, []string{"10.0.0.1", "10.0.0.6"}).
+		Return([]domain.Server{{ServerID: "old-6", ServerName: "Old Six", PrimaryAddress: "10.0.0.6"}}, nil)
+
+	report, err := svc.ImportServers([]byte(csvFile), dto.ImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	if assert.Len(t, report.ImportedServers, 1) {
+		assert.Equal(t, "srv1", report.ImportedServers[0].ServerID)
+		assert.Equal(t, 99.5, report.ImportedServers[0].SLATarget)
+	}
+	assert.Len(t, report.NonImportedServers, 5)
+
+	reasons := map[int][]string{}
+	for _, rowError := range report.Errors {
+		reasons[rowError.Row] = append(reasons[rowError.Row], rowError.Field)
+	}
+	assert.Equal(t, map[int][]string{
+		3: {"addresses"},
+		4: {"server_id"},
+		5: {"addresses"},
+		6: {"server_id", "sla_target"},
+		7: {"addresses"},
+	}, reasons)
+	mockRepo.AssertNotCalled(t, "CreateServers", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_ConflictReasons(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := "Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\nsrv2,Server Two,10.0.0.2\n"
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).
+		Return([]domain.Server{{ServerID: "srv2", ServerName: "Other", PrimaryAddress: "10.0.0.9"}}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].ServerID == "srv1"
+	})).Return([]domain.Server{{ServerID: "srv1"}}, []domain.Server{}, nil)
+
+	report, err := svc.ImportServers([]byte(csvFile), dto.ImportOptions{})
+	assert.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Len(t, report.ImportedServers, 1)
+	assert.Equal(t, []dto.ImportRowError{{Row: 3, ServerID: "srv2", Field: "server_id", Reason: "server srv2 already exists"}}, report.Errors)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_Upsert(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := "Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\nsrv2,Server Two Renamed,10.0.0.2\nsrv3,Server Three,10.0.0.3\n"
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", SLATarget: domain.DefaultSLATarget,
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}}, Labels: domain.Labels{}},
+		{ServerID: "srv2", ServerName: "Server Two", PrimaryAddress: "10.0.0.2", SLATarget: domain.DefaultSLATarget,
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.2", Primary: true}}, Labels: domain.Labels{}},
+	}, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].ServerID == "srv3"
+	})).Return([]domain.Server{{ServerID: "srv3"}}, []domain.Server{}, nil)
+	mockRepo.On("UpdateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].ServerID == "srv2" && servers[0].ServerName == "Server Two Renamed"
+	})).Return(nil)
+
+	report, err := svc.ImportServers([]byte(csvFile), dto.ImportOptions{Mode: service.ImportModeUpsert})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, dto.ImportSummary{
+		Created:   []string{"srv3"},
+		Updated:   []string{"srv2"},
+		Unchanged: []string{"srv1"},
+		Removed:   []string{},
+	}, report.Summary)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_Notes(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	existing := []domain.Server{
+		{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", SLATarget: domain.DefaultSLATarget, Notes: "Rack 4",
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}}, Labels: domain.Labels{}},
+	}
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return(existing, nil)
+	mockRepo.On("CreateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].Notes == "Rack 6"
+	})).Return([]domain.Server{{ServerID: "srv2", Notes: "Rack 6"}}, []domain.Server{}, nil)
+	mockRepo.On("UpdateServers", mock.MatchedBy(func(servers []domain.Server) bool {
+		return len(servers) == 1 && servers[0].Notes == "Rack 5"
+	})).Return(nil)
+
+	csvFile := "Server ID,Server Name,Addresses,Notes\nsrv1,Server One,10.0.0.1,Rack 5\nsrv2,Server Two,10.0.0.2,Rack 6\n"
+	report, err := svc.ImportServers([]byte(csvFile), dto.ImportOptions{Mode: service.ImportModeUpsert})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv2"}, report.Summary.Created)
+	assert.Equal(t, []string{"srv1"}, report.Summary.Updated)
+	mockRepo.AssertExpectations(t)
+
+	// A file without a notes column leaves them as they are
+	report, err = svc.ImportServers([]byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n"), dto.ImportOptions{Mode: service.ImportModeUpsert, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv1"}, report.Summary.Unchanged)
+}
+
+func TestImportServers_TrashedServer(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := "Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n"
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+	}, nil)
+
+	report, err := svc.ImportServers([]byte(csvFile), dto.ImportOptions{Mode: service.ImportModeUpsert})
+	assert.NoError(t, err)
+	assert.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0].Reason, "in the trash")
+	mockRepo.AssertNotCalled(t, "UpdateServers", mock.Anything)
+}
+
+func TestImportServers_SyncNeedsConfirmation(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockStatus := new(mockServerStatusService)
+	svc := service.NewServerCRUDService(mockRepo, mockStatus, nil, nil)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n")
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("GetAllServers").Return([]domain.Server{
+		{ServerID: "old1", Status: domain.StatusUp},
+		{ServerID: "old2", Status: domain.StatusDecommissioned},
+	}, nil)
+
+	dryRun, err := svc.ImportServers(csvFile, dto.ImportOptions{Mode: service.ImportModeSync, DryRun: true})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, dryRun.ConfirmationToken)
+	assert.Equal(t, []string{"old1"}, dryRun.Summary.Removed)
+	assert.Equal(t, []string{"srv1"}, dryRun.Summary.Created)
+
+	_, err = svc.ImportServers(csvFile, dto.ImportOptions{Mode: service.ImportModeSync, ConfirmationToken: "guess"})
+	assert.ErrorIs(t, err, service.ErrImportNotConfirmed)
+	// The token of a decommissioning sync doesn't confirm a deleting one
+	_, err = svc.ImportServers(csvFile, dto.ImportOptions{Mode: service.ImportModeSync, Removal: service.SyncRemovalDelete, ConfirmationToken: dryRun.ConfirmationToken})
+	assert.ErrorIs(t, err, service.ErrImportNotConfirmed)
+	mockRepo.AssertNotCalled(t, "CreateServers", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateServersByID", mock.Anything, mock.Anything)
+
+	mockRepo.On("CreateServers", mock.Anything).Return([]domain.Server{{ServerID: "srv1"}}, []domain.Server{}, nil)
+	mockRepo.On("UpdateServersByID", []string{"old1"}, map[string]interface{}{"status": domain.StatusDecommissioned}).Return([]string{"old1"}, nil)
+	mockStatus.On("RecordStatusChanges", []string{"old1"}, domain.StatusDecommissioned).Return(nil)
+
+	report, err := svc.ImportServers(csvFile, dto.ImportOptions{Mode: service.ImportModeSync, ConfirmationToken: dryRun.ConfirmationToken})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv1"}, report.Summary.Created)
+	assert.Equal(t, []string{"old1"}, report.Summary.Removed)
+	mockStatus.AssertExpectations(t)
+}
+
+func TestImportServers_SyncDelete(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\nsrv 2,Bad Row,10.0.0.2\n")
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "Server One", PrimaryAddress: "10.0.0.1", SLATarget: domain.DefaultSLATarget,
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}}},
+	}, nil)
+	// A row that fails validation still keeps its server from being removed
+	mockRepo.On("GetAllServers").Return([]domain.Server{{ServerID: "srv1"}, {ServerID: "srv 2"}, {ServerID: "old1", Status: domain.StatusDecommissioned}}, nil)
+	mockRepo.On("DeleteServersByID", []string{"old1"}).Return([]string{"old1"}, nil)
+
+	options := dto.ImportOptions{Mode: service.ImportModeSync, Removal: service.SyncRemovalDelete, DryRun: true}
+	dryRun, err := svc.ImportServers(csvFile, options)
+	assert.NoError(t, err)
+
+	options.DryRun = false
+	options.ConfirmationToken = dryRun.ConfirmationToken
+	report, err := svc.ImportServers(csvFile, options)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"srv1"}, report.Summary.Unchanged)
+	assert.Equal(t, []string{"old1"}, report.Summary.Removed)
+	assert.Len(t, report.Errors, 1)
+	mockRepo.AssertNotCalled(t, "CreateServers", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_RemovalFailureWritesNothing(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockStatus := new(mockServerStatusService)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, mockStatus, nil, mockAudit)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n")
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("GetAllServers").Return([]domain.Server{{ServerID: "old1", Status: domain.StatusUp}}, nil)
+	mockRepo.On("CreateServers", mock.Anything).Return([]domain.Server{{ServerID: "srv1"}}, []domain.Server{}, nil)
+	mockRepo.On("UpdateServersByID", []string{"old1"}, mock.Anything).Return(nil, errors.New("db error"))
+
+	options := dto.ImportOptions{Mode: service.ImportModeSync, DryRun: true}
+	dryRun, err := svc.ImportServers(csvFile, options)
+	assert.NoError(t, err)
+
+	// The servers created before the failure are rolled back with it, so nothing is reported or audited
+	options.DryRun = false
+	options.ConfirmationToken = dryRun.ConfirmationToken
+	report, err := svc.ImportServers(csvFile, options)
 	assert.Error(t, err)
-	assert.Nil(t, inserted)
-	assert.Nil(t, nonInserted)
+	assert.Nil(t, report)
+	mockStatus.AssertNotCalled(t, "RecordStatusChanges", mock.Anything, mock.Anything)
+	mockAudit.AssertNotCalled(t, "CreateAuditEntries", mock.Anything)
+}
+
+func TestImportServers_InvalidMode(t *testing.T) {
+	svc := service.NewServerCRUDService(new(mockServerCRUDRepository), nil, nil, nil)
+
+	_, err := svc.ImportServers([]byte("Server ID,Server Name,Addresses\n"), dto.ImportOptions{Mode: "replace"})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+	_, err = svc.ImportServers([]byte("Server ID,Server Name,Addresses\n"), dto.ImportOptions{Mode: service.ImportModeSync, Removal: "archive"})
+	assert.ErrorIs(t, err, service.ErrInvalidImport)
+}
+
+func TestImportServers_Progress(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	csvFile := []byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\nsrv2,Server Two,10.0.0.2\n")
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return([]domain.Server{}, nil)
+	mockRepo.On("CreateServers", mock.Anything).Return([]domain.Server{{ServerID: "srv1"}, {ServerID: "srv2"}}, []domain.Server{}, nil)
+
+	_, err := svc.GetImportProgress("upload-1")
+	assert.ErrorIs(t, err, service.ErrImportProgressNotFound)
+
+	_, err = svc.ImportServers(csvFile, dto.ImportOptions{ProgressID: "upload-1"})
+	assert.NoError(t, err)
+
+	progress, err := svc.GetImportProgress("upload-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "upload-1", progress.ID)
+	assert.Equal(t, service.ImportPhaseDone, progress.Phase)
+	assert.Equal(t, 2, progress.Processed)
+	assert.Equal(t, 2, progress.Total)
+	assert.NotNil(t, progress.FinishedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportServers_ProgressFailed(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("GetConflictingServers", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := svc.ImportServers([]byte("Server ID,Server Name,Addresses\nsrv1,Server One,10.0.0.1\n"), dto.ImportOptions{ProgressID: "upload-1"})
+	assert.Error(t, err)
+
+	progress, err := svc.GetImportProgress("upload-1")
+	assert.NoError(t, err)
+	assert.Equal(t, service.ImportPhaseFailed, progress.Phase)
+	assert.Equal(t, "db error", progress.Error)
 }
 
 func TestExportServers_Success(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
-	filter := &dto.ServerFilter{}
 	servers := []domain.Server{
-		{ServerID: "srv1", ServerName: "Server One", Status: "Off", IPv4: "192.168.1.1"},
+		{ServerID: "srv1", ServerName: "Server One", Status: "Off", PrimaryAddress: "192.168.1.1"},
 	}
-	mockRepo.On("ViewServers", filter, 0, 10, "ServerID", "asc").Return(servers, nil)
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, byServerID).Return(servers, nil)
+
+	var buf bytes.Buffer
+	err := service.ExportServers(&buf, dto.ExportOptions{From: 0, To: 10, SortColumn: "server_id", Order: "asc"})
+	assert.NoError(t, err)
 
-	data, err := service.ExportServers(filter, 0, 10, "ServerID", "asc")
+	f, err := excelize.OpenReader(&buf)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, data)
+	rows, _ := f.GetRows("Servers")
+	assert.Equal(t, []string{"Server ID", "Server Name", "Status", "Primary Address", "Addresses", "SLA Target", "Labels", "Notes", "Created Time", "Last Updated"}, rows[0])
+	assert.Equal(t, "srv1", rows[1][0])
 	mockRepo.AssertExpectations(t)
 }
 
 func TestExportServers_Error(t *testing.T) {
 	mockRepo := new(mockServerCRUDRepository)
-	service := service.NewServerCRUDService(mockRepo)
+	service := service.NewServerCRUDService(mockRepo, nil, nil, nil)
 
-	filter := &dto.ServerFilter{}
-	mockRepo.On("ViewServers", filter, 0, 10, "ServerID", "asc").Return(nil, errors.New("db error"))
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, byServerID).Return(nil, errors.New("db error"))
 
-	data, err := service.ExportServers(filter, 0, 10, "ServerID", "asc")
+	var buf bytes.Buffer
+	err := service.ExportServers(&buf, dto.ExportOptions{From: 0, To: 10, SortColumn: "server_id", Order: "asc"})
 	assert.Error(t, err)
-	assert.Nil(t, data)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestExportServers_CSVWithUpTime(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockInfo := new(mockServerInfoService)
+	svc := service.NewServerCRUDService(mockRepo, nil, mockInfo, nil)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	created := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockInfo.On("GetUpTimeRatios", from, to).Return(map[string]float64{"srv1": 99.456}, nil)
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, byServerID).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "Server, One", Status: domain.StatusUp, PrimaryAddress: "10.0.0.1", SLATarget: 99.9,
+			Addresses: domain.Addresses{{Type: domain.AddressTypeIPv4, Address: "10.0.0.1", Primary: true}},
+			Labels: domain.Labels{"env": "prod"}, CreatedTime: created, LastUpdated: created},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportServers(&buf, dto.ExportOptions{From: 0, To: 10, SortColumn: "server_id", Order: "asc", Format: "csv", UpTimeFrom: &from, UpTimeTo: &to})
+	assert.NoError(t, err)
+	assert.Equal(t, "Server ID,Server Name,Status,Primary Address,Addresses,SLA Target,Labels,Notes,Created Time,Last Updated,Uptime (%)\n"+
+		"srv1,\"Server, One\","+domain.StatusUp+",10.0.0.1,10.0.0.1,99.9,env=prod,,2023-06-01T12:00:00Z,2023-06-01T12:00:00Z,99.46\n", buf.String())
+	mockInfo.AssertExpectations(t)
+}
+
+func TestExportServers_NDJSONAndJSON(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, byServerID).Return([]domain.Server{
+		{ServerID: "srv1"}, {ServerID: "srv2"},
+	}, nil)
+
+	var ndjson bytes.Buffer
+	assert.NoError(t, svc.ExportServers(&ndjson, dto.ExportOptions{To: 10, SortColumn: "server_id", Order: "asc", Format: "ndjson"}))
+	lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"server_id":"srv2"`)
+
+	var array bytes.Buffer
+	assert.NoError(t, svc.ExportServers(&array, dto.ExportOptions{To: 10, SortColumn: "server_id", Order: "asc", Format: "json"}))
+	var servers []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(array.Bytes(), &servers))
+	assert.Len(t, servers, 2)
+	assert.NotContains(t, servers[0], "uptime")
+}
+
+func TestExportServers_PDF(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	servers := make([]domain.Server, 120)
+	for i := range servers {
+		servers[i] = domain.Server{ServerID: fmt.Sprintf("srv%d", i), ServerName: "Server (" + strconv.Itoa(i) + ")"}
+	}
+	mockRepo.On("StreamServers", mock.Anything, 0, 200, byServerID).Return(servers, nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportServers(&buf, dto.ExportOptions{To: 200, SortColumn: "server_id", Order: "asc", Format: "pdf"}))
+	pdf := buf.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, `(Server \(119\)) Tj`)
+	assert.Contains(t, pdf, "/Count 3")
+}
+
+func TestExportServers_Report(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockInfo := new(mockServerInfoService)
+	svc := service.NewServerCRUDService(mockRepo, nil, mockInfo, nil)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	mockInfo.On("GetUpTimeRatios", from, to).Return(map[string]float64{"srv1": 99.99, "srv2": 90, "srv3": 100}, nil)
+	mockInfo.On("GetUpTimeTrend", from, to, 7).Return([]dto.UpTimePoint{{Time: from, UpTime: 95.5}, {Time: from.AddDate(0, 0, 1), UpTime: 97}}, nil)
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, byServerID).Return([]domain.Server{
+		{ServerID: "srv1", Status: domain.StatusUp, SLATarget: 99.9},
+		{ServerID: "srv2", Status: domain.StatusDown, SLATarget: 99.9},
+		{ServerID: "srv3", Status: domain.StatusUp, SLATarget: 99.9},
+	}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportServers(&buf, dto.ExportOptions{To: 10, SortColumn: "server_id", Order: "asc", UpTimeFrom: &from, UpTimeTo: &to, Report: true})
+	assert.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Servers", "Summary"}, f.GetSheetList())
+
+	rows, _ := f.GetRows("Servers")
+	assert.Len(t, rows, 4)
+	panes, _ := f.GetPanes("Servers")
+	assert.True(t, panes.Freeze)
+	assert.Equal(t, "A2", panes.TopLeftCell)
+	formats, _ := f.GetConditionalFormats("Servers")
+	assert.Len(t, formats["C2:C4"], 7)
+	style, _ := f.GetCellStyle("Servers", "A1")
+	assert.NotZero(t, style)
+
+	summary, _ := f.GetRows("Summary")
+	assert.Equal(t, []string{"Status", "Servers"}, summary[2])
+	assert.Equal(t, []string{domain.StatusUp, "2"}, summary[3])
+	assert.Equal(t, []string{domain.StatusDown, "1"}, summary[5])
+	assert.Equal(t, []string{"Total", "3"}, summary[10])
+	assert.Equal(t, []string{"Mean uptime (%)", "96.66"}, summary[14])
+	assert.Equal(t, []string{"Below SLA target", "1"}, summary[15])
+	assert.Equal(t, []string{"2024-01-02", "97"}, summary[19])
+	mockInfo.AssertExpectations(t)
+}
+
+func TestExportServers_MultiColumnSort(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	sort := domain.ServerSort{{Column: "status", Desc: true}, {Column: "server_name"}, {Column: "server_id"}}
+	mockRepo.On("StreamServers", mock.Anything, 0, 10, sort).Return([]domain.Server{}, nil)
+
+	var buf bytes.Buffer
+	err := svc.ExportServers(&buf, dto.ExportOptions{To: 10, SortColumn: "status,server_name", Order: "DESC,asc", Format: "csv"})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportServers_Invalid(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{Format: "docx"}), service.ErrInvalidExport)
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{UpTimeFrom: &from}), service.ErrInvalidExport)
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{LabelSelector: "env in prod"}), domain.ErrInvalidLabelSelector)
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{Format: "csv", Report: true}), service.ErrInvalidExport)
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{SortColumn: "labels"}), domain.ErrInvalidSort)
+	assert.ErrorIs(t, svc.ExportServers(&buf, dto.ExportOptions{SortColumn: "status, server_name", Order: "desc,asc,asc"}), domain.ErrInvalidSort)
+	assert.Zero(t, buf.Len())
+	mockRepo.AssertNotCalled(t, "StreamServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Helpers
 
 func createTestExcelFile() *excelize.File {
@@ -329,4 +1323,125 @@ func createTestExcelFileMissingRows() *excelize.File {
 	f.SetCellValue(sheet, "B1", "Server Name")
 	f.SetCellValue(sheet, "C1", "IPv4")
 	return f
-}
\ No newline at end of file
+}
+// serversIn matches the lookup of servers by a list of IDs
+func serversIn(serverIDs ...string) interface{} {
+	values := make([]interface{}, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		values = append(values, serverID)
+	}
+	return mock.MatchedBy(func(filter *dto.ServerFilter) bool {
+		return filter.Expression != nil && filter.Expression.Operator == domain.FilterIn &&
+			assert.ObjectsAreEqual(values, filter.Expression.Values)
+	})
+}
+
+func TestBulkUpdateServers_ByIDs(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	updatedData := map[string]interface{}{"notes": "Rack 5"}
+	mockRepo.On("ViewServers", serversIn("srv1", "srv2", "srv3"), 0, 3, domain.ServerSort(nil)).Return([]domain.Server{
+		{ServerID: "srv1", Notes: "Rack 4"},
+		{ServerID: "srv2", Notes: "Rack 5"},
+	}, nil)
+	mockRepo.On("UpdateServersByID", []string{"srv1"}, updatedData).Return([]string{"srv1"}, nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		return len(entries) == 1 && entries[0].ServerID == "srv1" && entries[0].Action == domain.AuditUpdate &&
+			entries[0].UserID == auditActor.UserID &&
+			assert.ObjectsAreEqual(domain.AuditChanges{"notes": {Before: "Rack 4", After: "Rack 5"}}, entries[0].Changes)
+	})).Return(nil)
+
+	result, err := svc.BulkUpdateServers(dto.BulkRequest{ServerIDs: []string{"srv1", "srv2", "srv3", "srv1"}}, updatedData, auditActor)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.BulkResult{Matched: 2, Affected: 1, Outcomes: []dto.BulkOutcome{
+		{ServerID: "srv1", Outcome: service.BulkOutcomeUpdated},
+		{ServerID: "srv2", Outcome: service.BulkOutcomeUnchanged},
+		{ServerID: "srv3", Outcome: service.BulkOutcomeNotFound},
+	}}, result)
+	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestBulkUpdateServers_PreviewByFilter(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	filter := &dto.ServerFilter{Status: "On"}
+	mockRepo.On("ViewServers", filter, 0, service.MaxBulkServers+1, domain.ServerSort(nil)).Return([]domain.Server{
+		{ServerID: "srv1", SLATarget: 99.9},
+		{ServerID: "srv2", SLATarget: 99.5},
+	}, nil)
+
+	result, err := svc.BulkUpdateServers(dto.BulkRequest{Filter: filter, Preview: true}, map[string]interface{}{"sla_target": 99.95}, dto.Actor{})
+	assert.NoError(t, err)
+	assert.True(t, result.Preview)
+	assert.Equal(t, 2, result.Matched)
+	assert.Equal(t, 2, result.Affected)
+	mockRepo.AssertNotCalled(t, "UpdateServersByID", mock.Anything, mock.Anything)
+}
+
+func TestBulkUpdateServers_Invalid(t *testing.T) {
+	svc := service.NewServerCRUDService(new(mockServerCRUDRepository), nil, nil, nil)
+	byID := dto.BulkRequest{ServerIDs: []string{"srv1"}}
+
+	tests := []struct {
+		name        string
+		request     dto.BulkRequest
+		updatedData map[string]interface{}
+	}{
+		{"no changes", byID, map[string]interface{}{}},
+		{"unique field", byID, map[string]interface{}{"server_name": "web"}},
+		{"invalid SLA target", byID, map[string]interface{}{"sla_target": 100.0}},
+		{"no target", dto.BulkRequest{}, map[string]interface{}{"notes": "Rack 5"}},
+		{"both targets", dto.BulkRequest{ServerIDs: []string{"srv1"}, Filter: &dto.ServerFilter{Status: "On"}}, map[string]interface{}{"notes": "Rack 5"}},
+		{"empty filter", dto.BulkRequest{Filter: &dto.ServerFilter{}}, map[string]interface{}{"notes": "Rack 5"}},
+		{"empty ID", dto.BulkRequest{ServerIDs: []string{""}}, map[string]interface{}{"notes": "Rack 5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.BulkUpdateServers(tt.request, tt.updatedData, dto.Actor{})
+			assert.ErrorIs(t, err, service.ErrInvalidBulk)
+		})
+	}
+}
+
+func TestBulkDeleteServers_DeletedMeanwhile(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, mockAudit)
+
+	mockRepo.On("ViewServers", serversIn("srv1", "srv2"), 0, 2, domain.ServerSort(nil)).Return([]domain.Server{
+		{ServerID: "srv1", ServerName: "web-1"},
+		{ServerID: "srv2", ServerName: "web-2"},
+	}, nil)
+	// srv2 was deleted by someone else between the lookup and the delete
+	mockRepo.On("DeleteServersByID", []string{"srv1", "srv2"}).Return([]string{"srv1"}, nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		return len(entries) == 1 && entries[0].ServerID == "srv1" && entries[0].Action == domain.AuditDelete
+	})).Return(nil)
+
+	result, err := svc.BulkDeleteServers(dto.BulkRequest{ServerIDs: []string{"srv1", "srv2"}}, auditActor)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Matched)
+	assert.Equal(t, 1, result.Affected)
+	assert.Equal(t, []dto.BulkOutcome{
+		{ServerID: "srv1", Outcome: service.BulkOutcomeDeleted},
+		{ServerID: "srv2", Outcome: service.BulkOutcomeNotFound},
+	}, result.Outcomes)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestBulkDeleteServers_FilterMatchesTooMany(t *testing.T) {
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerCRUDService(mockRepo, nil, nil, nil)
+
+	filter := &dto.ServerFilter{ServerName: "web"}
+	mockRepo.On("ViewServers", filter, 0, service.MaxBulkServers+1, domain.ServerSort(nil)).
+		Return(make([]domain.Server, service.MaxBulkServers+1), nil)
+
+	_, err := svc.BulkDeleteServers(dto.BulkRequest{Filter: filter}, dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrInvalidBulk)
+	mockRepo.AssertNotCalled(t, "DeleteServersByID", mock.Anything)
+}
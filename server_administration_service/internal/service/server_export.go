@@ -0,0 +1,302 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"server_administration_service/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrInvalidExport = errors.New("invalid export")
+
+const (
+	ExportFormatXLSX   = "xlsx"
+	ExportFormatCSV    = "csv"
+	ExportFormatJSON   = "json"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatPDF    = "pdf"
+)
+
+// exportedServer is a server as exported, with its uptime percentage when the export asked for a range.
+type exportedServer struct {
+	domain.Server
+	UpTime *float64 `json:"uptime,omitempty"`
+}
+
+// exportColumn is a column of the tabular formats. The headers are the ones the import reads back;
+// columns without a pdfWidth are left out of PDFs, which only have a landscape page's width.
+type exportColumn struct {
+	header   string
+	pdfWidth float64
+	value    func(server exportedServer) interface{}
+}
+
+var exportColumns = []exportColumn{
+	{"Server ID", 85, func(server exportedServer) interface{} { return server.ServerID }},
+	{"Server Name", 115, func(server exportedServer) interface{} { return server.ServerName }},
+	{"Status", 55, func(server exportedServer) interface{} { return server.Status }},
+	{"Primary Address", 90, func(server exportedServer) interface{} { return server.PrimaryAddress }},
+	{"Addresses", 150, func(server exportedServer) interface{} { return server.Addresses.String() }},
+	{"SLA Target", 45, func(server exportedServer) interface{} { return server.SLATarget }},
+	{"Labels", 150, func(server exportedServer) interface{} { return server.Labels.String() }},
+	{"Notes", 0, func(server exportedServer) interface{} { return server.Notes }},
+	{"Created Time", 0, func(server exportedServer) interface{} { return server.CreatedTime }},
+	{"Last Updated", 0, func(server exportedServer) interface{} { return server.LastUpdated }},
+}
+
+// upTimeColumn is added after the others when the export asks for the uptime over a range.
+var upTimeColumn = exportColumn{"Uptime (%)", 45, func(server exportedServer) interface{} {
+	if server.UpTime == nil {
+		return ""
+	}
+	return *server.UpTime
+}}
+
+// exportFormat returns the format to export to, xlsx unless told otherwise.
+func exportFormat(format string) (string, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case "":
+		return ExportFormatXLSX, nil
+	case ExportFormatXLSX, ExportFormatCSV, ExportFormatJSON, ExportFormatNDJSON, ExportFormatPDF:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: unknown format %q, expected xlsx, csv, json, ndjson or pdf", ErrInvalidExport, format)
+}
+
+// exportText is how a cell is written in the text formats.
+func exportText(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+// serverExportWriter writes exported servers to a file as they come.
+type serverExportWriter interface {
+	writeServer(server exportedServer) error
+	// close writes what the format needs after the last server
+	close() error
+}
+
+// newServerExportWriter starts an export; report is only for xlsx and may be nil.
+func newServerExportWriter(w io.Writer, format, title string, columns []exportColumn, report *exportReport) (serverExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w, columns)
+	case ExportFormatJSON, ExportFormatNDJSON:
+		return &jsonExportWriter{w: w, ndjson: format == ExportFormatNDJSON}, nil
+	case ExportFormatPDF:
+		return newPDFExportWriter(w, title, columns)
+	}
+	return newXLSXExportWriter(w, columns, report)
+}
+
+type csvExportWriter struct {
+	w       *csv.Writer
+	columns []exportColumn
+}
+
+func newCSVExportWriter(w io.Writer, columns []exportColumn) (*csvExportWriter, error) {
+	writer := &csvExportWriter{w: csv.NewWriter(w), columns: columns}
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	if err := writer.w.Write(headers); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (c *csvExportWriter) writeServer(server exportedServer) error {
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		record[i] = exportText(column.value(server))
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvExportWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonExportWriter writes a JSON array of servers, or one server per line for NDJSON.
+type jsonExportWriter struct {
+	w       io.Writer
+	ndjson  bool
+	written int
+}
+
+func (j *jsonExportWriter) writeServer(server exportedServer) error {
+	data, err := json.Marshal(server)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	switch {
+	case j.ndjson:
+		separator = ""
+	case j.written == 0:
+		separator = "[\n"
+	}
+	if j.ndjson {
+		data = append(data, '\n')
+	}
+	j.written++
+
+	if _, err := io.WriteString(j.w, separator); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) close() error {
+	if j.ndjson {
+		return nil
+	}
+	end := "\n]\n"
+	if j.written == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// xlsxExportWriter fills the "Servers" sheet through excelize's stream writer, which spills large
+// sheets to a temporary file; the workbook is written out on close. With a report, the sheet is
+// formatted and a Summary sheet is added on close.
+type xlsxExportWriter struct {
+	w       io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	columns []exportColumn
+	row     int
+	report  *exportReport
+}
+
+const xlsxServersSheet = "Servers"
+
+func newXLSXExportWriter(w io.Writer, columns []exportColumn, report *exportReport) (*xlsxExportWriter, error) {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", xlsxServersSheet)
+
+	stream, err := f.NewStreamWriter(xlsxServersSheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	headers := make([]interface{}, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	if report != nil {
+		if headers, err = report.startServers(f, stream, columns); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if err := stream.SetRow("A1", headers); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &xlsxExportWriter{w: w, file: f, stream: stream, columns: columns, row: 1, report: report}, nil
+}
+
+func (x *xlsxExportWriter) writeServer(server exportedServer) error {
+	x.row++
+	values := make([]interface{}, len(x.columns))
+	for i, column := range x.columns {
+		value := column.value(server)
+		if at, ok := value.(time.Time); ok {
+			value = exportText(at)
+		}
+		values[i] = value
+	}
+
+	if x.report != nil {
+		x.report.add(server)
+	}
+
+	cell, _ := excelize.CoordinatesToCellName(1, x.row)
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxExportWriter) close() error {
+	defer x.file.Close()
+	if x.report != nil {
+		// The stream writer writes the sheet's filter and conditional formats on flush
+		if err := x.report.finishServers(x.file, xlsxServersSheet, x.columns, x.row); err != nil {
+			return err
+		}
+	}
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	if x.report != nil {
+		if err := x.report.writeSummary(x.file); err != nil {
+			return err
+		}
+	}
+	return x.file.Write(x.w)
+}
+
+type pdfExportWriter struct {
+	table   *pdfTableWriter
+	columns []exportColumn
+}
+
+func newPDFExportWriter(w io.Writer, title string, columns []exportColumn) (*pdfExportWriter, error) {
+	var (
+		pdfColumns []exportColumn
+		headers    []string
+		widths     []float64
+	)
+	for _, column := range columns {
+		if column.pdfWidth == 0 {
+			continue
+		}
+		pdfColumns = append(pdfColumns, column)
+		headers = append(headers, column.header)
+		widths = append(widths, column.pdfWidth)
+	}
+
+	table, err := newPDFTableWriter(w, title, headers, widths)
+	if err != nil {
+		return nil, err
+	}
+	return &pdfExportWriter{table: table, columns: pdfColumns}, nil
+}
+
+func (p *pdfExportWriter) writeServer(server exportedServer) error {
+	cells := make([]string, len(p.columns))
+	for i, column := range p.columns {
+		cells[i] = exportText(column.value(server))
+	}
+	return p.table.writeRow(cells)
+}
+
+func (p *pdfExportWriter) close() error {
+	return p.table.close()
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+	"sort"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidGroup = errors.New("invalid server group")
+
+type ServerGroupService interface {
+	CreateGroup(group *domain.ServerGroup) (string, error)
+	GetGroup(id string) (*domain.ServerGroup, error)
+	GetGroups() ([]domain.ServerGroup, error)
+	UpdateGroup(id string, updatedData map[string]interface{}) error
+	DeleteGroup(id string) error
+	GetGroupMembers(id string) ([]string, error)
+	AddMembers(id string, serverIDs []string) error
+	RemoveMember(id, serverID string) error
+	GetGroupSummaries(startTime, endTime time.Time) ([]domain.GroupSummary, error)
+	GetGroupSummary(id string, startTime, endTime time.Time) (*domain.GroupSummary, error)
+	GetGroupCompliance(startTime, endTime time.Time) ([]domain.GroupSLACompliance, error)
+}
+
+type serverGroupService struct {
+	serverGroupRepository repository.ServerGroupRepository
+	serverInfoRepository  repository.ServerInfoRepository
+	maintenanceRepository repository.MaintenanceRepository
+}
+
+func NewServerGroupService(serverGroupRepository repository.ServerGroupRepository, serverInfoRepository repository.ServerInfoRepository, maintenanceRepository repository.MaintenanceRepository) ServerGroupService {
+	return &serverGroupService{
+		serverGroupRepository: serverGroupRepository,
+		serverInfoRepository:  serverInfoRepository,
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+// validateGroup checks the group's fields and that it can hang below its parent.
+func (s *serverGroupService) validateGroup(group *domain.ServerGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidGroup)
+	}
+	if !domain.ValidSLATarget(group.SLATarget) {
+		return fmt.Errorf("%w: %s", ErrInvalidGroup, errInvalidSLATarget.Error())
+	}
+	if group.ParentID == nil {
+		return nil
+	}
+
+	groups, err := s.serverGroupRepository.GetGroups()
+	if err != nil {
+		return err
+	}
+	tree := domain.NewGroupTree(groups)
+	if tree.Path(*group.ParentID) == "" {
+		return fmt.Errorf("%w: parent group %s not found", ErrInvalidGroup, *group.ParentID)
+	}
+	if !tree.CanMove(group.ID, group.ParentID) {
+		return domain.ErrGroupCycle
+	}
+	return nil
+}
+
+// CreateGroup creates a group; a zero SLA target means the default target.
+func (s *serverGroupService) CreateGroup(group *domain.ServerGroup) (string, error) {
+	if group.SLATarget == 0 {
+		group.SLATarget = domain.DefaultSLATarget
+	}
+	group.ID = uuid.New().String()
+
+	if err := s.validateGroup(group); err != nil {
+		return "", err
+	}
+
+	id, err := s.serverGroupRepository.CreateGroup(group)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create server group, err: "+err.Error(), "ERROR")
+		return "", err
+	}
+
+	logging.LogMessage("server_administration_service", "Server group "+id+" created", "INFO")
+	return id, nil
+}
+
+func (s *serverGroupService) GetGroup(id string) (*domain.ServerGroup, error) {
+	return s.serverGroupRepository.GetGroup(id)
+}
+
+func (s *serverGroupService) GetGroups() ([]domain.ServerGroup, error) {
+	return s.serverGroupRepository.GetGroups()
+}
+
+// UpdateGroup changes the name, SLA target or parent of a group. An empty parent_id moves the group to the root.
+func (s *serverGroupService) UpdateGroup(id string, updatedData map[string]interface{}) error {
+	group, err := s.serverGroupRepository.GetGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if name, ok := updatedData["name"].(string); ok {
+		group.Name = name
+	}
+	if slaTarget, ok := updatedData["sla_target"].(float64); ok {
+		group.SLATarget = slaTarget
+	}
+	if parentID, ok := updatedData["parent_id"].(string); ok {
+		group.ParentID = nil
+		if parentID != "" {
+			group.ParentID = &parentID
+		}
+	}
+
+	if err := s.validateGroup(group); err != nil {
+		return err
+	}
+
+	return s.serverGroupRepository.UpdateGroup(group)
+}
+
+// DeleteGroup deletes a group without subgroups; its servers stay in the inventory.
+func (s *serverGroupService) DeleteGroup(id string) error {
+	groups, err := s.serverGroupRepository.GetGroups()
+	if err != nil {
+		return err
+	}
+	if domain.NewGroupTree(groups).HasChildren(id) {
+		return domain.ErrGroupHasChildren
+	}
+
+	return s.serverGroupRepository.DeleteGroup(id)
+}
+
+func (s *serverGroupService) GetGroupMembers(id string) ([]string, error) {
+	if _, err := s.serverGroupRepository.GetGroup(id); err != nil {
+		return nil, err
+	}
+
+	return s.serverGroupRepository.GetGroupMembers(id)
+}
+
+func (s *serverGroupService) AddMembers(id string, serverIDs []string) error {
+	if len(serverIDs) == 0 {
+		return fmt.Errorf("%w: server_ids must not be empty", ErrInvalidGroup)
+	}
+	if _, err := s.serverGroupRepository.GetGroup(id); err != nil {
+		return err
+	}
+
+	unique := make([]string, 0, len(serverIDs))
+	seen := make(map[string]bool)
+	for _, serverID := range serverIDs {
+		if !seen[serverID] {
+			seen[serverID] = true
+			unique = append(unique, serverID)
+		}
+	}
+
+	if err := s.serverGroupRepository.AddMembers(id, unique); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServerNotFound
+		}
+		logging.LogMessage("server_administration_service", "Failed to add servers to group "+id+", err: "+err.Error(), "ERROR")
+		return err
+	}
+	return nil
+}
+
+func (s *serverGroupService) RemoveMember(id, serverID string) error {
+	return s.serverGroupRepository.RemoveMember(id, serverID)
+}
+
+// groupRollup is what a group adds up from all the servers below it over a period.
+type groupRollup struct {
+	group       domain.ServerGroup
+	path        string
+	statuses    []string
+	numUp       int
+	numDown     int
+	up, counted time.Duration
+}
+
+// rollUpGroups adds the status and uptime of every server up into each group above it, counting a
+// server once per group even when it's a member of several groups below it. The rollups come sorted by
+// group path, and the period is cut at now.
+func (s *serverGroupService) rollUpGroups(startTime, endTime time.Time) ([]groupRollup, time.Time, error) {
+	if now := time.Now(); endTime.After(now) {
+		endTime = now
+	}
+	if !endTime.After(startTime) {
+		return nil, endTime, ErrInvalidSLAPeriod
+	}
+
+	groups, err := s.serverGroupRepository.GetGroups()
+	if err != nil {
+		return nil, endTime, err
+	}
+	if len(groups) == 0 {
+		return nil, endTime, nil
+	}
+
+	members, err := s.serverGroupRepository.GetMembers()
+	if err != nil {
+		return nil, endTime, err
+	}
+
+	servers, err := s.serverInfoRepository.GetServers("")
+	if err != nil {
+		return nil, endTime, err
+	}
+
+	windows, err := s.maintenanceRepository.GetMaintenanceWindows()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get maintenance windows, err: "+err.Error(), "ERROR")
+		return nil, endTime, err
+	}
+	exclusions := domain.SLAExclusions(windows, startTime, endTime)
+
+	histories, err := s.serverInfoRepository.GetStatusHistories(startTime, endTime)
+	if err != nil {
+		return nil, endTime, err
+	}
+
+	type serverUsage struct {
+		status      string
+		up, counted time.Duration
+	}
+	usages := make(map[string]serverUsage, len(servers))
+	for _, server := range servers {
+		up, counted := serverUpTime(server, histories[server.ServerID], startTime, endTime, exclusions[server.ServerID])
+		usages[server.ServerID] = serverUsage{status: server.Status, up: up, counted: counted}
+	}
+
+	directMembers := make(map[string][]string)
+	for _, member := range members {
+		directMembers[member.GroupID] = append(directMembers[member.GroupID], member.ServerID)
+	}
+
+	tree := domain.NewGroupTree(groups)
+	rollups := make([]groupRollup, 0, len(groups))
+	for _, group := range groups {
+		rollup := groupRollup{group: group, path: tree.Path(group.ID)}
+
+		seen := make(map[string]bool)
+		for _, groupID := range tree.Subtree(group.ID) {
+			for _, serverID := range directMembers[groupID] {
+				usage, ok := usages[serverID]
+				if !ok || seen[serverID] {
+					continue
+				}
+				seen[serverID] = true
+
+				rollup.statuses = append(rollup.statuses, usage.status)
+				rollup.up += usage.up
+				rollup.counted += usage.counted
+				if domain.IsAvailableStatus(usage.status) {
+					rollup.numUp++
+				} else if domain.NormalizeStatus(usage.status) == domain.StatusDown {
+					rollup.numDown++
+				}
+			}
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	sort.Slice(rollups, func(i, j int) bool { return rollups[i].path < rollups[j].path })
+	return rollups, endTime, nil
+}
+
+// GetGroupSummaries rolls the status and uptime of every group up from all the servers below it.
+// The uptime is weighted by time, so servers that were Unknown or excluded for maintenance weigh less.
+// A period that isn't over yet is evaluated up to now.
+func (s *serverGroupService) GetGroupSummaries(startTime, endTime time.Time) ([]domain.GroupSummary, error) {
+	rollups, endTime, err := s.rollUpGroups(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.GroupSummary, 0, len(rollups))
+	for _, rollup := range rollups {
+		summary := domain.GroupSummary{
+			GroupID:     rollup.group.ID,
+			Name:        rollup.group.Name,
+			Path:        rollup.path,
+			ParentID:    rollup.group.ParentID,
+			Status:      domain.AggregateGroupStatus(rollup.statuses),
+			NumServers:  len(rollup.statuses),
+			NumUp:       rollup.numUp,
+			NumDown:     rollup.numDown,
+			UpTimeRatio: 100,
+			SLATarget:   rollup.group.SLATarget,
+			PeriodStart: startTime,
+			PeriodEnd:   endTime,
+		}
+		if rollup.counted > 0 {
+			summary.UpTimeRatio = float64(rollup.up) / float64(rollup.counted) * 100
+			summary.Breached = summary.UpTimeRatio < rollup.group.SLATarget
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// GetGroupCompliance reports every group against its own SLA target over [startTime, endTime], from the
+// uptime of all the servers below it weighted by time as in GetGroupSummaries.
+func (s *serverGroupService) GetGroupCompliance(startTime, endTime time.Time) ([]domain.GroupSLACompliance, error) {
+	rollups, endTime, err := s.rollUpGroups(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	compliances := make([]domain.GroupSLACompliance, 0, len(rollups))
+	for _, rollup := range rollups {
+		target := rollup.group.SLATarget
+		if !domain.ValidSLATarget(target) {
+			target = domain.DefaultSLATarget
+		}
+
+		compliance := domain.ComputeSLACompliance(target, rollup.up, rollup.counted)
+		compliances = append(compliances, domain.GroupSLACompliance{
+			GroupID:              rollup.group.ID,
+			Name:                 rollup.group.Name,
+			Path:                 rollup.path,
+			SLATarget:            compliance.SLATarget,
+			Achieved:             compliance.Achieved,
+			PeriodStart:          startTime,
+			PeriodEnd:            endTime,
+			ErrorBudgetSeconds:   compliance.ErrorBudgetSeconds,
+			ErrorBudgetRemaining: compliance.ErrorBudgetRemaining,
+			BurnRate:             compliance.BurnRate,
+			Breached:             compliance.Breached,
+		})
+	}
+	return compliances, nil
+}
+
+func (s *serverGroupService) GetGroupSummary(id string, startTime, endTime time.Time) (*domain.GroupSummary, error) {
+	summaries, err := s.GetGroupSummaries(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		if summaries[i].GroupID == id {
+			return &summaries[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock implementation of ServerGroupRepository
+type mockServerGroupRepository struct {
+	mock.Mock
+}
+
+func (m *mockServerGroupRepository) CreateGroup(group *domain.ServerGroup) (string, error) {
+	args := m.Called(group)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockServerGroupRepository) GetGroup(id string) (*domain.ServerGroup, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ServerGroup), args.Error(1)
+}
+
+func (m *mockServerGroupRepository) GetGroups() ([]domain.ServerGroup, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServerGroup), args.Error(1)
+}
+
+func (m *mockServerGroupRepository) UpdateGroup(group *domain.ServerGroup) error {
+	args := m.Called(group)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupRepository) DeleteGroup(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupRepository) GetMembers() ([]domain.ServerGroupMember, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ServerGroupMember), args.Error(1)
+}
+
+func (m *mockServerGroupRepository) GetGroupMembers(groupID string) ([]string, error) {
+	args := m.Called(groupID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerGroupRepository) AddMembers(groupID string, serverIDs []string) error {
+	args := m.Called(groupID, serverIDs)
+	return args.Error(0)
+}
+
+func (m *mockServerGroupRepository) RemoveMember(groupID, serverID string) error {
+	args := m.Called(groupID, serverID)
+	return args.Error(0)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+// dc-1 > rack-1 > cluster-a, plus a second root dc-2
+func testGroups() []domain.ServerGroup {
+	return []domain.ServerGroup{
+		{ID: "dc-1", Name: "dc-1", SLATarget: 99.9},
+		{ID: "rack-1", Name: "rack-1", ParentID: strPtr("dc-1"), SLATarget: 99.9},
+		{ID: "cluster-a", Name: "cluster-a", ParentID: strPtr("rack-1"), SLATarget: 40},
+		{ID: "dc-2", Name: "dc-2", SLATarget: 99.9},
+	}
+}
+
+func TestCreateGroup_DefaultSLATarget(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockRepo.On("GetGroups").Return(testGroups(), nil)
+	mockRepo.On("CreateGroup", mock.MatchedBy(func(g *domain.ServerGroup) bool {
+		return g.ID != "" && g.Name == "rack-2" && g.SLATarget == domain.DefaultSLATarget
+	})).Return("group-1", nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	id, err := service.CreateGroup(&domain.ServerGroup{Name: "rack-2", ParentID: strPtr("dc-1")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "group-1", id)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateGroup_Invalid(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockRepo.On("GetGroups").Return(testGroups(), nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	tests := map[string]*domain.ServerGroup{
+		"missing name":       {},
+		"invalid sla target": {Name: "rack-2", SLATarget: 120},
+		"unknown parent":     {Name: "rack-2", ParentID: strPtr("dc-9")},
+	}
+	for name, group := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.CreateGroup(group)
+			assert.ErrorIs(t, err, ErrInvalidGroup)
+		})
+	}
+	mockRepo.AssertNotCalled(t, "CreateGroup", mock.Anything)
+}
+
+func TestUpdateGroup_Cycle(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	groups := testGroups()
+	mockRepo.On("GetGroup", "dc-1").Return(&groups[0], nil)
+	mockRepo.On("GetGroups").Return(testGroups(), nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	err := service.UpdateGroup("dc-1", map[string]interface{}{"parent_id": "cluster-a"})
+
+	assert.ErrorIs(t, err, domain.ErrGroupCycle)
+	mockRepo.AssertNotCalled(t, "UpdateGroup", mock.Anything)
+}
+
+func TestUpdateGroup_MoveToRoot(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	groups := testGroups()
+	mockRepo.On("GetGroup", "rack-1").Return(&groups[1], nil)
+	mockRepo.On("UpdateGroup", mock.MatchedBy(func(g *domain.ServerGroup) bool {
+		return g.ID == "rack-1" && g.ParentID == nil
+	})).Return(nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	err := service.UpdateGroup("rack-1", map[string]interface{}{"parent_id": ""})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeleteGroup_HasChildren(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockRepo.On("GetGroups").Return(testGroups(), nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	err := service.DeleteGroup("rack-1")
+
+	assert.ErrorIs(t, err, domain.ErrGroupHasChildren)
+	mockRepo.AssertNotCalled(t, "DeleteGroup", mock.Anything)
+}
+
+func TestAddMembers_Deduplicates(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	groups := testGroups()
+	mockRepo.On("GetGroup", "rack-1").Return(&groups[1], nil)
+	mockRepo.On("AddMembers", "rack-1", []string{"srv-1", "srv-2"}).Return(nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	err := service.AddMembers("rack-1", []string{"srv-1", "srv-2", "srv-1"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddMembers_UnknownServer(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	groups := testGroups()
+	mockRepo.On("GetGroup", "rack-1").Return(&groups[1], nil)
+	mockRepo.On("AddMembers", "rack-1", []string{"srv-9"}).Return(gorm.ErrRecordNotFound)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	err := service.AddMembers("rack-1", []string{"srv-9"})
+
+	assert.ErrorIs(t, err, ErrServerNotFound)
+}
+
+func TestGetGroupSummaries(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockInfo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetGroups").Return(testGroups(), nil)
+	mockRepo.On("GetMembers").Return([]domain.ServerGroupMember{
+		{GroupID: "dc-1", ServerID: "srv-1"},
+		{GroupID: "cluster-a", ServerID: "srv-2"},
+		{GroupID: "cluster-a", ServerID: "srv-3"},
+		// srv-1 is in the subtree twice but only counts once
+		{GroupID: "rack-1", ServerID: "srv-1"},
+	}, nil)
+	mockInfo.On("GetServers", "").Return([]domain.Server{
+		{ServerID: "srv-1", Status: domain.StatusUp},
+		{ServerID: "srv-2", Status: domain.StatusDown},
+		{ServerID: "srv-3", Status: domain.StatusUnknown},
+	}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	mockInfo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		"srv-1": {{ID: "srv-1", Status: domain.StatusUp, Timestamp: start}},
+		"srv-2": {
+			{ID: "srv-2", Status: domain.StatusUp, Timestamp: start},
+			{ID: "srv-2", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+	}, nil)
+
+	service := NewServerGroupService(mockRepo, mockInfo, mockMaintenance)
+	summaries, err := service.GetGroupSummaries(start, end)
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 4)
+
+	byID := make(map[string]domain.GroupSummary)
+	for _, summary := range summaries {
+		byID[summary.GroupID] = summary
+	}
+
+	dc1 := byID["dc-1"]
+	assert.Equal(t, domain.GroupStatusPartiallyDown, dc1.Status)
+	assert.Equal(t, 3, dc1.NumServers)
+	assert.Equal(t, 1, dc1.NumUp)
+	assert.Equal(t, 1, dc1.NumDown)
+	// 15 of 20 counted hours up
+	assert.Equal(t, 75.0, dc1.UpTimeRatio)
+	assert.True(t, dc1.Breached)
+
+	cluster := byID["cluster-a"]
+	assert.Equal(t, "dc-1 / rack-1 / cluster-a", cluster.Path)
+	assert.Equal(t, domain.GroupStatusDown, cluster.Status)
+	assert.Equal(t, 50.0, cluster.UpTimeRatio)
+	assert.False(t, cluster.Breached)
+
+	dc2 := byID["dc-2"]
+	assert.Equal(t, domain.GroupStatusUnknown, dc2.Status)
+	assert.Equal(t, 100.0, dc2.UpTimeRatio)
+	assert.False(t, dc2.Breached)
+}
+
+func TestGetGroupSummaries_GroupMaintenance(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockInfo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetGroups").Return([]domain.ServerGroup{{ID: "dc-1", Name: "dc-1", SLATarget: 99.9}}, nil)
+	mockRepo.On("GetMembers").Return([]domain.ServerGroupMember{{GroupID: "dc-1", ServerID: "srv-1"}}, nil)
+	mockInfo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1", Status: domain.StatusUp}}, nil)
+	// The outage is covered by a window on the whole group
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{{
+		ID:             "window-1",
+		GroupIDs:       []string{"dc-1"},
+		GroupServerIDs: []string{"srv-1"},
+		StartTime:      start.Add(5 * time.Hour),
+		EndTime:        end,
+		ExcludeFromSLA: true,
+	}}, nil)
+	mockInfo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		"srv-1": {
+			{ID: "srv-1", Status: domain.StatusUp, Timestamp: start},
+			{ID: "srv-1", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+	}, nil)
+
+	service := NewServerGroupService(mockRepo, mockInfo, mockMaintenance)
+	summaries, err := service.GetGroupSummaries(start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, summaries[0].UpTimeRatio)
+	assert.False(t, summaries[0].Breached)
+}
+
+func TestGetGroupCompliance(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockInfo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetGroups").Return([]domain.ServerGroup{
+		{ID: "dc-1", Name: "dc-1", SLATarget: 90},
+		{ID: "rack-1", Name: "rack-1", ParentID: strPtr("dc-1"), SLATarget: 99},
+		{ID: "empty", Name: "empty", SLATarget: 99.9},
+	}, nil)
+	mockRepo.On("GetMembers").Return([]domain.ServerGroupMember{
+		{GroupID: "dc-1", ServerID: "srv-1"},
+		{GroupID: "rack-1", ServerID: "srv-2"},
+	}, nil)
+	mockInfo.On("GetServers", "").Return([]domain.Server{
+		{ServerID: "srv-1", Status: domain.StatusUp},
+		{ServerID: "srv-2", Status: domain.StatusUp},
+	}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	mockInfo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		"srv-1": {{ID: "srv-1", Status: domain.StatusUp, Timestamp: start}},
+		"srv-2": {
+			{ID: "srv-2", Status: domain.StatusUp, Timestamp: start},
+			{ID: "srv-2", Status: domain.StatusDown, Timestamp: start.Add(9 * time.Hour)},
+		},
+	}, nil)
+
+	service := NewServerGroupService(mockRepo, mockInfo, mockMaintenance)
+	compliances, err := service.GetGroupCompliance(start, end)
+	assert.NoError(t, err)
+	if !assert.Len(t, compliances, 3) {
+		return
+	}
+
+	// Sorted by path
+	dc1, rack, empty := compliances[0], compliances[1], compliances[2]
+	assert.Equal(t, "dc-1", dc1.GroupID)
+	assert.Equal(t, "dc-1 / rack-1", rack.Path)
+	assert.Equal(t, "empty", empty.GroupID)
+
+	// 19 of 20 counted hours up against 90%: half the 2 hour budget spent
+	assert.InDelta(t, 95.0, dc1.Achieved, 1e-9)
+	assert.InDelta(t, 2*3600.0, dc1.ErrorBudgetSeconds, 1e-6)
+	assert.InDelta(t, 50.0, dc1.ErrorBudgetRemaining, 1e-9)
+	assert.InDelta(t, 0.5, dc1.BurnRate, 1e-9)
+	assert.False(t, dc1.Breached)
+
+	// 9 of 10 hours up against the rack's own 99%
+	assert.Equal(t, 99.0, rack.SLATarget)
+	assert.InDelta(t, 90.0, rack.Achieved, 1e-9)
+	assert.InDelta(t, 10.0, rack.BurnRate, 1e-9)
+	assert.True(t, rack.Breached)
+	assert.Equal(t, start, rack.PeriodStart)
+	assert.Equal(t, end, rack.PeriodEnd)
+
+	// Nothing counted leaves the whole budget
+	assert.Equal(t, 100.0, empty.Achieved)
+	assert.Equal(t, 100.0, empty.ErrorBudgetRemaining)
+	assert.False(t, empty.Breached)
+}
+
+func TestGetGroupSummary_NotFound(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockRepo.On("GetGroups").Return([]domain.ServerGroup{}, nil)
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary, err := service.GetGroupSummary("missing", start, start.Add(time.Hour))
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, summary)
+}
+
+func TestGetGroupSummaries_RepoError(t *testing.T) {
+	mockRepo := new(mockServerGroupRepository)
+	mockRepo.On("GetGroups").Return(nil, errors.New("db error"))
+
+	service := NewServerGroupService(mockRepo, new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := service.GetGroupSummaries(start, start.Add(time.Hour))
+
+	assert.Error(t, err)
+}
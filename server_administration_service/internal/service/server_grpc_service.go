@@ -1,12 +1,13 @@
 package service
 
 import (
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/repository"
 )
 
 type ServerGRPCService interface {
-	GetServerAddresses() ([]dto.ServerAddress, error)
+	GetServerAddresses(labelSelector, filter string) ([]dto.ServerAddress, error)
 }
 
 type serverGRPCService struct {
@@ -19,6 +20,17 @@ func NewServerGRPCService(serverGRPCRepository repository.ServerGRPCRepository)
 	}
 }
 
-func (s *serverGRPCService) GetServerAddresses() ([]dto.ServerAddress, error) {
-	return s.serverGRPCRepository.GetServerAddresses()
+// GetServerAddresses returns the addresses of the probed servers matching both the label selector and
+// the filter expression, all of them when they're empty.
+func (s *serverGRPCService) GetServerAddresses(labelSelector, filter string) ([]dto.ServerAddress, error) {
+	selector, err := domain.ParseLabelSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	expression, err := domain.ParseFilterExpression(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.serverGRPCRepository.GetServerAddresses(selector, expression)
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"testing"
 
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/dto"
 	"server_administration_service/internal/service"
 
@@ -16,8 +17,8 @@ type mockServerGRPCRepository struct {
 	mock.Mock
 }
 
-func (m *mockServerGRPCRepository) GetServerAddresses() ([]dto.ServerAddress, error) {
-	args := m.Called()
+func (m *mockServerGRPCRepository) GetServerAddresses(selector domain.LabelSelector, expression *domain.FilterExpression) ([]dto.ServerAddress, error) {
+	args := m.Called(selector, expression)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -33,13 +34,13 @@ func (m *mockServerGRPCRepository) UpdateStatus(server_id, status string) error
 func TestServerGRPCService_GetServerAddresses_Success(t *testing.T) {
 	mockRepo := new(mockServerGRPCRepository)
 	expected := []dto.ServerAddress{
-		{ServerID: "1", IPv4: "127.0.0.1", Status: "On"},
-		{ServerID: "2", IPv4: "192.168.1.1", Status: "Off"},
+		{ServerID: "1", PrimaryAddress: "127.0.0.1", Status: "On"},
+		{ServerID: "2", PrimaryAddress: "192.168.1.1", Status: "Off"},
 	}
-	mockRepo.On("GetServerAddresses").Return(expected, nil)
+	mockRepo.On("GetServerAddresses", mock.Anything, mock.Anything).Return(expected, nil)
 
 	svc := service.NewServerGRPCService(mockRepo)
-	result, err := svc.GetServerAddresses()
+	result, err := svc.GetServerAddresses("", "")
 
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
@@ -53,10 +54,10 @@ func TestServerGRPCService_GetServerAddresses_Success(t *testing.T) {
 func TestServerGRPCService_GetServerAddresses_Error(t *testing.T) {
 	mockRepo := new(mockServerGRPCRepository)
 	mockErr := errors.New("db error")
-	mockRepo.On("GetServerAddresses").Return(nil, mockErr)
+	mockRepo.On("GetServerAddresses", mock.Anything, mock.Anything).Return(nil, mockErr)
 
 	svc := service.NewServerGRPCService(mockRepo)
-	result, err := svc.GetServerAddresses()
+	result, err := svc.GetServerAddresses("", "")
 
 	if err != mockErr {
 		t.Errorf("expected error %v, got %v", mockErr, err)
@@ -65,4 +66,48 @@ func TestServerGRPCService_GetServerAddresses_Error(t *testing.T) {
 		t.Errorf("expected nil result, got %v", result)
 	}
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+func TestServerGRPCService_GetServerAddresses_LabelSelector(t *testing.T) {
+	mockRepo := new(mockServerGRPCRepository)
+	svc := service.NewServerGRPCService(mockRepo)
+
+	selector := domain.LabelSelector{
+		{Key: "env", Operator: domain.SelectorEquals, Values: []string{"prod"}},
+		{Key: "role", Operator: domain.SelectorIn, Values: []string{"db", "cache"}},
+	}
+	mockRepo.On("GetServerAddresses", selector, (*domain.FilterExpression)(nil)).Return([]dto.ServerAddress{}, nil)
+
+	if _, err := svc.GetServerAddresses("env=prod, role in (db, cache)", ""); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerGRPCService_GetServerAddresses_InvalidSelector(t *testing.T) {
+	mockRepo := new(mockServerGRPCRepository)
+	svc := service.NewServerGRPCService(mockRepo)
+
+	_, err := svc.GetServerAddresses("env in (prod", "")
+	if !errors.Is(err, domain.ErrInvalidLabelSelector) {
+		t.Fatalf("expected ErrInvalidLabelSelector, got %v", err)
+	}
+	mockRepo.AssertNotCalled(t, "GetServerAddresses", mock.Anything, mock.Anything)
+}
+
+func TestServerGRPCService_GetServerAddresses_Filter(t *testing.T) {
+	mockRepo := new(mockServerGRPCRepository)
+	svc := service.NewServerGRPCService(mockRepo)
+
+	expression := &domain.FilterExpression{Field: domain.FilterFieldAddress, Operator: domain.FilterWithin, Values: []interface{}{"10.0.0.0/8"}}
+	mockRepo.On("GetServerAddresses", domain.LabelSelector(nil), expression).Return([]dto.ServerAddress{}, nil)
+
+	if _, err := svc.GetServerAddresses("", "address = 10.1.2.3/8"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	mockRepo.AssertExpectations(t)
+
+	_, err := svc.GetServerAddresses("", "address = ")
+	if !errors.Is(err, domain.ErrInvalidFilterExpression) {
+		t.Fatalf("expected ErrInvalidFilterExpression, got %v", err)
+	}
+}
@@ -0,0 +1,459 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidImport = errors.New("invalid servers file")
+	// ErrImportNotConfirmed is returned by a sync run without the confirmation token of its dry run
+	ErrImportNotConfirmed = errors.New("sync import not confirmed")
+)
+
+const (
+	ImportModeInsert = "insert"
+	ImportModeUpsert = "upsert"
+	ImportModeSync   = "sync"
+)
+
+const (
+	SyncRemovalDecommission = "decommission"
+	SyncRemovalDelete       = "delete"
+)
+
+const (
+	ImportFormatXLSX = "xlsx"
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+	ImportFormatYAML = "yaml"
+	// ImportFormatNmap is the XML output of nmap -oX, one server per host that is up
+	ImportFormatNmap = "nmap"
+)
+
+// The server fields an import fills and the columns read for them by default, the first one present wins.
+// Column names are compared ignoring case, spaces, dashes and underscores, so "Server ID" and server_id both match.
+var defaultImportColumns = map[string][]string{
+	"server_id":   {"Server ID"},
+	"server_name": {"Server Name"},
+	// Files exported before servers had several addresses have a single IPv4 column
+	"addresses":  {"Addresses", "IPv4"},
+	"sla_target": {"SLA Target"},
+	"labels":     {"Labels"},
+	"notes":      {"Notes"},
+}
+
+// importRecord is a row or object of a servers file with its values keyed by normalized column name.
+// Row is the line of tabular files, counting the header, and the position of the object otherwise.
+type importRecord struct {
+	Row    int
+	Values map[string]string
+}
+
+func normalizeColumn(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// detectImportFormat picks the format from the file extension, or from the first bytes of the file.
+func detectImportFormat(buf []byte, filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		return ImportFormatXLSX
+	case ".csv":
+		return ImportFormatCSV
+	case ".json":
+		return ImportFormatJSON
+	case ".yaml", ".yml":
+		return ImportFormatYAML
+	case ".xml":
+		return ImportFormatNmap
+	}
+
+	content := bytes.TrimSpace(bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf")))
+	switch {
+	case bytes.HasPrefix(buf, []byte("PK\x03\x04")):
+		// xlsx files are zip archives
+		return ImportFormatXLSX
+	case bytes.HasPrefix(content, []byte("<")):
+		return ImportFormatNmap
+	case bytes.HasPrefix(content, []byte("[")), bytes.HasPrefix(content, []byte("{")):
+		return ImportFormatJSON
+	case bytes.HasPrefix(content, []byte("---")), bytes.HasPrefix(content, []byte("- ")):
+		return ImportFormatYAML
+	}
+	return ImportFormatCSV
+}
+
+// readImportRecords parses a servers file. The records of tabular formats have every column of the header row.
+func readImportRecords(buf []byte, format string) ([]importRecord, error) {
+	switch format {
+	case ImportFormatXLSX:
+		f, err := excelize.OpenReader(bytes.NewReader(buf))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err.Error())
+		}
+		rows, err := f.GetRows("Servers")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err.Error())
+		}
+		return tableRecords(rows), nil
+	case ImportFormatCSV:
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(buf, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidImport, err.Error())
+		}
+		return tableRecords(rows), nil
+	case ImportFormatJSON:
+		var objects []map[string]interface{}
+		if err := json.Unmarshal(buf, &objects); err != nil {
+			return nil, fmt.Errorf("%w: expected a JSON array of objects, %s", ErrInvalidImport, err.Error())
+		}
+		return objectRecords(objects), nil
+	case ImportFormatYAML:
+		var objects []map[string]interface{}
+		if err := yaml.Unmarshal(buf, &objects); err != nil {
+			return nil, fmt.Errorf("%w: expected a YAML list of mappings, %s", ErrInvalidImport, err.Error())
+		}
+		return objectRecords(objects), nil
+	case ImportFormatNmap:
+		return nmapRecords(buf)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q, expected xlsx, csv, json, yaml or nmap", ErrInvalidImport, format)
+}
+
+// tableRecords turns rows under a header row into records, short rows leave their last columns empty.
+func tableRecords(rows [][]string) []importRecord {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	header := rows[0]
+	records := make([]importRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := importRecord{Row: i + 2, Values: make(map[string]string, len(header))}
+		for j, column := range header {
+			value := ""
+			if j < len(row) {
+				value = row[j]
+			}
+			record.Values[normalizeColumn(column)] = value
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func objectRecords(objects []map[string]interface{}) []importRecord {
+	records := make([]importRecord, 0, len(objects))
+	for i, object := range objects {
+		record := importRecord{Row: i + 1, Values: make(map[string]string, len(object))}
+		for key, value := range object {
+			record.Values[normalizeColumn(key)] = importValue(value)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// importValue renders a JSON or YAML value the way the tabular formats write it: lists comma separated,
+// with the primary one first for address objects, and mappings as key=value labels.
+func importValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if address, ok := item.(map[string]interface{}); ok {
+				if primary, _ := address["primary"].(bool); primary {
+					values = append([]string{importValue(address["address"])}, values...)
+					continue
+				}
+				item = address["address"]
+			}
+			values = append(values, importValue(item))
+		}
+		return strings.Join(values, ",")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			pairs = append(pairs, key+"="+importValue(v[key]))
+		}
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+type nmapRun struct {
+	Hosts []struct {
+		Status struct {
+			State string `xml:"state,attr"`
+		} `xml:"status"`
+		Addresses []struct {
+			Addr     string `xml:"addr,attr"`
+			AddrType string `xml:"addrtype,attr"`
+		} `xml:"address"`
+		Hostnames []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"hostnames>hostname"`
+	} `xml:"host"`
+}
+
+// nmapRecords makes a record of every host nmap found up. Its IP addresses come first, then its host names,
+// and the first host name, or IP address without one, is used as server ID and name.
+func nmapRecords(buf []byte) ([]importRecord, error) {
+	var run nmapRun
+	if err := xml.Unmarshal(buf, &run); err != nil {
+		return nil, fmt.Errorf("%w: expected nmap XML output, %s", ErrInvalidImport, err.Error())
+	}
+
+	records := []importRecord{}
+	for i, host := range run.Hosts {
+		if host.Status.State != "" && host.Status.State != "up" {
+			continue
+		}
+
+		addresses := []string{}
+		for _, address := range host.Addresses {
+			// MAC addresses can't be probed
+			if address.AddrType == "ipv4" || address.AddrType == "ipv6" {
+				addresses = append(addresses, address.Addr)
+			}
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+		name := addresses[0]
+		for j, hostname := range host.Hostnames {
+			if j == 0 {
+				name = hostname.Name
+			}
+			addresses = append(addresses, hostname.Name)
+		}
+
+		records = append(records, importRecord{Row: i + 1, Values: map[string]string{
+			normalizeColumn("Server ID"):   name,
+			normalizeColumn("Server Name"): name,
+			normalizeColumn("Addresses"):   strings.Join(addresses, ","),
+		}})
+	}
+	return records, nil
+}
+
+// importColumns resolves the column read for every server field, "" when the file has none.
+// A mapping overrides the default columns of a field; nmap records always use the default ones.
+func importColumns(records []importRecord, format string, mapping map[string]string) (map[string]string, error) {
+	present := make(map[string]bool)
+	for _, record := range records {
+		for column := range record.Values {
+			present[column] = true
+		}
+	}
+
+	columns := make(map[string]string, len(defaultImportColumns))
+	for field, candidates := range defaultImportColumns {
+		for _, candidate := range candidates {
+			if present[normalizeColumn(candidate)] {
+				columns[field] = normalizeColumn(candidate)
+				break
+			}
+		}
+	}
+	if format == ImportFormatNmap {
+		return columns, nil
+	}
+
+	for field, column := range mapping {
+		if _, ok := defaultImportColumns[field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q in mapping, expected server_id, server_name, addresses, sla_target, labels or notes", ErrInvalidImport, field)
+		}
+		if !present[normalizeColumn(column)] {
+			return nil, fmt.Errorf("%w: column %q mapped to %s isn't in the file", ErrInvalidImport, column, field)
+		}
+		columns[field] = normalizeColumn(column)
+	}
+	return columns, nil
+}
+
+// importRow is the server read from a record and everything wrong with it.
+// Existing is the server with the same ID already in the inventory when the import may update it.
+type importRow struct {
+	Row      int
+	Server   domain.Server
+	Existing *domain.Server
+	Errors   []dto.ImportRowError
+}
+
+func (r *importRow) fail(field, reason string) {
+	r.Errors = append(r.Errors, dto.ImportRowError{
+		Row:      r.Row,
+		ServerID: r.Server.ServerID,
+		Field:    field,
+		Reason:   reason,
+	})
+}
+
+// importServer reads the server of a record and validates each of its fields.
+// An empty SLA target is the default one, an empty labels column no labels and an empty notes column no notes.
+func importServer(record importRecord, columns map[string]string) importRow {
+	row := importRow{
+		Row: record.Row,
+		Server: domain.Server{
+			ServerID:   strings.TrimSpace(record.Values[columns["server_id"]]),
+			ServerName: strings.TrimSpace(record.Values[columns["server_name"]]),
+			Status:     domain.StatusUnknown,
+			SLATarget:  domain.DefaultSLATarget,
+			Labels:     domain.Labels{},
+		},
+	}
+
+	if err := domain.ValidateServerID(row.Server.ServerID); err != nil {
+		row.fail("server_id", err.Error())
+	}
+	if err := domain.ValidateServerName(row.Server.ServerName); err != nil {
+		row.fail("server_name", err.Error())
+	}
+
+	rawAddresses := record.Values[columns["addresses"]]
+	addresses, err := domain.ParseAddresses(rawAddresses)
+	if err != nil {
+		row.fail("addresses", err.Error())
+		row.Server.Addresses = domain.Addresses{{Address: rawAddresses}}
+	} else {
+		primary, _ := addresses.Primary()
+		row.Server.PrimaryAddress = primary.Address
+		row.Server.Addresses = addresses
+	}
+
+	if rawTarget := strings.TrimSpace(record.Values[columns["sla_target"]]); columns["sla_target"] != "" && rawTarget != "" {
+		slaTarget, err := strconv.ParseFloat(rawTarget, 64)
+		if err != nil || !domain.ValidSLATarget(slaTarget) {
+			row.fail("sla_target", errInvalidSLATarget.Error())
+		} else {
+			row.Server.SLATarget = slaTarget
+		}
+	}
+
+	if columns["labels"] != "" {
+		labels, err := domain.ParseLabels(record.Values[columns["labels"]])
+		if err != nil {
+			row.fail("labels", err.Error())
+		} else {
+			row.Server.Labels = labels
+		}
+	}
+
+	if columns["notes"] != "" {
+		row.Server.Notes = record.Values[columns["notes"]]
+		if err := domain.ValidateServerNotes(row.Server.Notes); err != nil {
+			row.fail("notes", err.Error())
+		}
+	}
+	return row
+}
+
+// checkImportDuplicates reports the rows that repeat the ID, name or an address of an earlier valid row.
+func checkImportDuplicates(rows []importRow) {
+	idRows := make(map[string]int)
+	nameRows := make(map[string]int)
+	addressRows := make(map[string]int)
+
+	for i := range rows {
+		if len(rows[i].Errors) > 0 {
+			continue
+		}
+		server := rows[i].Server
+		if first, ok := idRows[server.ServerID]; ok {
+			rows[i].fail("server_id", "duplicate server ID "+server.ServerID+" of row "+strconv.Itoa(first))
+		}
+		if first, ok := nameRows[server.ServerName]; ok {
+			rows[i].fail("server_name", "duplicate server name "+server.ServerName+" of row "+strconv.Itoa(first))
+		}
+		for _, address := range server.Addresses {
+			if first, ok := addressRows[strings.ToLower(address.Address)]; ok {
+				rows[i].fail("addresses", "duplicate address "+address.Address+" of row "+strconv.Itoa(first))
+			}
+		}
+		if len(rows[i].Errors) > 0 {
+			continue
+		}
+
+		idRows[server.ServerID] = rows[i].Row
+		nameRows[server.ServerName] = rows[i].Row
+		for _, address := range server.Addresses {
+			addressRows[strings.ToLower(address.Address)] = rows[i].Row
+		}
+	}
+}
+
+// sameImportedServer reports whether an import would leave the server as it is.
+func sameImportedServer(current, imported domain.Server) bool {
+	if current.ServerName != imported.ServerName || current.SLATarget != imported.SLATarget || current.Notes != imported.Notes ||
+		current.PrimaryAddress != imported.PrimaryAddress || current.Addresses.JSON() != imported.Addresses.JSON() {
+		return false
+	}
+	if len(current.Labels) != len(imported.Labels) {
+		return false
+	}
+	for key, value := range imported.Labels {
+		if current.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// syncConfirmationToken identifies a sync plan: the file and the servers it removes, and how.
+// It changes whenever the file or the servers missing from it change between the dry run and the sync.
+func syncConfirmationToken(buf []byte, removal string, removed []domain.Server) string {
+	hash := sha256.New()
+	hash.Write(buf)
+	hash.Write([]byte{0})
+	hash.Write([]byte(removal))
+	for _, server := range removed {
+		hash.Write([]byte{0})
+		hash.Write([]byte(server.ServerID))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func appendServerIDs(ids []string, servers []domain.Server) []string {
+	for _, server := range servers {
+		ids = append(ids, server.ServerID)
+	}
+	return ids
+}
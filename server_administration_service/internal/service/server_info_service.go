@@ -1,7 +1,12 @@
 package service
 
 import (
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
 	"server_administration_service/internal/repository"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
 )
 
 type ServerInfoService interface {
@@ -9,15 +14,19 @@ type ServerInfoService interface {
 	GetNumOnServers() (int, error)
 	GetNumOffServers() (int, error)
 	GetServerMeanUpTimeRatio(startTime, endTime string) (float64, error)
+	GetUpTimeRatios(start, end time.Time) (map[string]float64, error)
+	GetUpTimeTrend(start, end time.Time, points int) ([]dto.UpTimePoint, error)
 }
 
 type serverInfoService struct {
-	serverInfoRepository repository.ServerInfoRepository
+	serverInfoRepository  repository.ServerInfoRepository
+	maintenanceRepository repository.MaintenanceRepository
 }
 
-func NewServerInfoService(serverInfoRepository repository.ServerInfoRepository) ServerInfoService {
+func NewServerInfoService(serverInfoRepository repository.ServerInfoRepository, maintenanceRepository repository.MaintenanceRepository) ServerInfoService {
 	return &serverInfoService{
-		serverInfoRepository: serverInfoRepository,
+		serverInfoRepository:  serverInfoRepository,
+		maintenanceRepository: maintenanceRepository,
 	}
 }
 
@@ -34,16 +43,133 @@ func (s *serverInfoService) GetNumOffServers() (int, error) {
 }
 
 func (s *serverInfoService) GetServerMeanUpTimeRatio(startTime, endTime string) (float64, error) {
-	sumUpTimeRatio, err := s.serverInfoRepository.GetServerSumUpTimeRatio(startTime, endTime)
+	start, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to parse start time, err: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	end, err := time.Parse(time.RFC3339, endTime)
 	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to parse end time, err: "+err.Error(), "ERROR")
 		return 0, err
 	}
-	numServers, err := s.GetNumServers()
+
+	ratios, err := s.GetUpTimeRatios(start, end)
 	if err != nil {
 		return 0, err
 	}
-	if numServers == 0 {
+	if len(ratios) == 0 {
 		return 0, nil
 	}
-	return sumUpTimeRatio / float64(numServers), nil
-}
\ No newline at end of file
+
+	sumUpTimeRatio := 0.0
+	for _, ratio := range ratios {
+		sumUpTimeRatio += ratio
+	}
+
+	return sumUpTimeRatio / float64(len(ratios)), nil
+}
+
+// GetUpTimeRatios returns the uptime percentage of every server over [start, end], leaving out
+// their maintenance windows. A server with nothing counted in the range is at 100%.
+func (s *serverInfoService) GetUpTimeRatios(start, end time.Time) (map[string]float64, error) {
+	measure, err := s.measureUpTime(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return measure.ratios(start, end), nil
+}
+
+// GetUpTimeTrend splits [start, end] into the given number of equal periods and returns the mean
+// uptime of the servers over each, measured as GetUpTimeRatios does. The histories are read once.
+func (s *serverInfoService) GetUpTimeTrend(start, end time.Time, points int) ([]dto.UpTimePoint, error) {
+	if points < 1 || !start.Before(end) {
+		return []dto.UpTimePoint{}, nil
+	}
+	measure, err := s.measureUpTime(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	step := end.Sub(start) / time.Duration(points)
+	trend := make([]dto.UpTimePoint, 0, points)
+	for i := 0; i < points; i++ {
+		from := start.Add(time.Duration(i) * step)
+		to := from.Add(step)
+		if i == points-1 {
+			to = end
+		}
+
+		point := dto.UpTimePoint{Time: from, UpTime: 100}
+		if ratios := measure.ratios(from, to); len(ratios) > 0 {
+			sum := 0.0
+			for _, ratio := range ratios {
+				sum += ratio
+			}
+			point.UpTime = sum / float64(len(ratios))
+		}
+		trend = append(trend, point)
+	}
+	return trend, nil
+}
+
+// upTimeMeasure holds what measuring uptime over a range needs, so that parts of it can be measured
+// without going back to the database.
+type upTimeMeasure struct {
+	servers    []domain.Server
+	histories  map[string][]domain.StatusChange
+	exclusions map[string][]domain.TimeInterval
+}
+
+func (s *serverInfoService) measureUpTime(start, end time.Time) (*upTimeMeasure, error) {
+	servers, err := s.serverInfoRepository.GetServers("")
+	if err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return &upTimeMeasure{}, nil
+	}
+
+	windows, err := s.maintenanceRepository.GetMaintenanceWindows()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get maintenance windows, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+
+	histories, err := s.serverInfoRepository.GetStatusHistories(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upTimeMeasure{
+		servers:    servers,
+		histories:  histories,
+		exclusions: domain.SLAExclusions(windows, start, end),
+	}, nil
+}
+
+// ratios returns the uptime percentage of every server over [start, end], which must be inside the measured range.
+func (m *upTimeMeasure) ratios(start, end time.Time) map[string]float64 {
+	ratios := make(map[string]float64, len(m.servers))
+	for _, server := range m.servers {
+		up, counted := serverUpTime(server, m.histories[server.ServerID], start, end, m.exclusions[server.ServerID])
+		if counted == 0 {
+			// Nothing in the range counts against the server
+			ratios[server.ServerID] = 100
+			continue
+		}
+		ratios[server.ServerID] = float64(up) / float64(counted) * 100
+	}
+	return ratios
+}
+
+// serverUpTime measures a server over [start, end], starting no earlier than its creation.
+func serverUpTime(server domain.Server, history []domain.StatusChange, start, end time.Time, excluded []domain.TimeInterval) (up, counted time.Duration) {
+	if server.CreatedTime.After(start) {
+		start = server.CreatedTime
+	}
+	if !start.Before(end) {
+		return 0, 0
+	}
+	return domain.UpTime(history, start, end, excluded)
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"errors"
+	"server_administration_service/internal/domain"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -17,6 +19,14 @@ func (m *mockServerInfoRepository) GetNumServers() (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *mockServerInfoRepository) GetServers(serverID string) ([]domain.Server, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
 func (m *mockServerInfoRepository) GetNumOnServers() (int, error) {
 	args := m.Called()
 	return args.Int(0), args.Error(1)
@@ -27,16 +37,71 @@ func (m *mockServerInfoRepository) GetNumOffServers() (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
-func (m *mockServerInfoRepository) GetServerSumUpTimeRatio(startTime, endTime string) (float64, error) {
+func (m *mockServerInfoRepository) GetStatusHistories(startTime, endTime time.Time) (map[string][]domain.StatusChange, error) {
 	args := m.Called(startTime, endTime)
-	return args.Get(0).(float64), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]domain.StatusChange), args.Error(1)
+}
+
+func (m *mockServerInfoRepository) GetStatusHistory(serverID string, startTime, endTime time.Time) ([]domain.StatusChange, error) {
+	args := m.Called(serverID, startTime, endTime)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StatusChange), args.Error(1)
+}
+
+// Mock implementation of MaintenanceRepository
+type mockMaintenanceRepository struct {
+	mock.Mock
+}
+
+func (m *mockMaintenanceRepository) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	args := m.Called(window)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) GetServerMaintenanceWindows(serverID string, t time.Time) ([]domain.MaintenanceWindow, error) {
+	args := m.Called(serverID, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceRepository) UpdateMaintenanceWindow(window *domain.MaintenanceWindow) error {
+	args := m.Called(window)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceRepository) DeleteMaintenanceWindow(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
 }
 
 func TestGetNumServers(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
 	mockRepo.On("GetNumServers").Return(5, nil)
 
-	service := NewServerInfoService(mockRepo)
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
 	num, err := service.GetNumServers()
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -51,7 +116,7 @@ func TestGetNumOnServers(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
 	mockRepo.On("GetNumOnServers").Return(3, nil)
 
-	service := NewServerInfoService(mockRepo)
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
 	num, err := service.GetNumOnServers()
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -66,7 +131,7 @@ func TestGetNumOffServers(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
 	mockRepo.On("GetNumOffServers").Return(2, nil)
 
-	service := NewServerInfoService(mockRepo)
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
 	num, err := service.GetNumOffServers()
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -79,40 +144,70 @@ func TestGetNumOffServers(t *testing.T) {
 
 func TestGetServerMeanUpTimeRatio_Success(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
-	mockRepo.On("GetServerSumUpTimeRatio", "2024-01-01", "2024-01-31").Return(10.0, nil)
-	mockRepo.On("GetNumServers").Return(5, nil)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
 
-	service := NewServerInfoService(mockRepo)
-	ratio, err := service.GetServerMeanUpTimeRatio("2024-01-01", "2024-01-31")
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1"}, {ServerID: "srv-2"}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	mockRepo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		// Up before the range, Down halfway through
+		"srv-1": {
+			{ID: "srv-1", Status: domain.StatusUp, Timestamp: start.Add(-time.Hour)},
+			{ID: "srv-1", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+		// Degraded still counts as available
+		"srv-2": {
+			{ID: "srv-2", Status: domain.StatusDegraded, Timestamp: start},
+		},
+	}, nil)
+
+	service := NewServerInfoService(mockRepo, mockMaintenance)
+	ratio, err := service.GetServerMeanUpTimeRatio(start.Format(time.RFC3339), end.Format(time.RFC3339))
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	expected := 2.0
-	if ratio != expected {
-		t.Errorf("expected %v, got %v", expected, ratio)
+	if ratio != 75 {
+		t.Errorf("expected 75, got %v", ratio)
 	}
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetServerMeanUpTimeRatio_InvalidTime(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
+	if _, err := service.GetServerMeanUpTimeRatio("2024-01-01", "2024-01-31T00:00:00Z"); err == nil {
+		t.Fatal("expected error for invalid start time, got nil")
+	}
+	if _, err := service.GetServerMeanUpTimeRatio("2024-01-01T00:00:00Z", "2024-01-31"); err == nil {
+		t.Fatal("expected error for invalid end time, got nil")
+	}
+	mockRepo.AssertNotCalled(t, "GetServers", mock.Anything)
+}
+
 func TestGetServerMeanUpTimeRatio_RepoError(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
-	mockRepo.On("GetServerSumUpTimeRatio", "2024-01-01", "2024-01-31").Return(0.0, errors.New("repo error"))
+	mockMaintenance := new(mockMaintenanceRepository)
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1"}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	mockRepo.On("GetStatusHistories", mock.Anything, mock.Anything).Return(nil, errors.New("repo error"))
 
-	service := NewServerInfoService(mockRepo)
-	_, err := service.GetServerMeanUpTimeRatio("2024-01-01", "2024-01-31")
+	service := NewServerInfoService(mockRepo, mockMaintenance)
+	_, err := service.GetServerMeanUpTimeRatio("2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
 	mockRepo.AssertExpectations(t)
 }
 
-func TestGetServerMeanUpTimeRatio_NumServersError(t *testing.T) {
+func TestGetServerMeanUpTimeRatio_ServersError(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
-	mockRepo.On("GetServerSumUpTimeRatio", "2024-01-01", "2024-01-31").Return(10.0, nil)
-	mockRepo.On("GetNumServers").Return(0, errors.New("num servers error"))
+	mockRepo.On("GetServers", "").Return(nil, errors.New("db error"))
 
-	service := NewServerInfoService(mockRepo)
-	_, err := service.GetServerMeanUpTimeRatio("2024-01-01", "2024-01-31")
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
+	_, err := service.GetServerMeanUpTimeRatio("2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z")
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -121,11 +216,10 @@ func TestGetServerMeanUpTimeRatio_NumServersError(t *testing.T) {
 
 func TestGetServerMeanUpTimeRatio_ZeroServers(t *testing.T) {
 	mockRepo := new(mockServerInfoRepository)
-	mockRepo.On("GetServerSumUpTimeRatio", "2024-01-01", "2024-01-31").Return(10.0, nil)
-	mockRepo.On("GetNumServers").Return(0, nil)
+	mockRepo.On("GetServers", "").Return([]domain.Server{}, nil)
 
-	service := NewServerInfoService(mockRepo)
-	ratio, err := service.GetServerMeanUpTimeRatio("2024-01-01", "2024-01-31")
+	service := NewServerInfoService(mockRepo, new(mockMaintenanceRepository))
+	ratio, err := service.GetServerMeanUpTimeRatio("2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -133,4 +227,94 @@ func TestGetServerMeanUpTimeRatio_ZeroServers(t *testing.T) {
 		t.Errorf("expected 0, got %v", ratio)
 	}
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestGetServerMeanUpTimeRatio_ExcludesMaintenance(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	// srv-2 is down for the last 5 hours, all of which is maintenance excluded from SLA
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{{
+		ID:             "window-1",
+		Name:           "Upgrade",
+		ServerIDs:      []string{"srv-2"},
+		StartTime:      start.Add(5 * time.Hour),
+		EndTime:        end,
+		ExcludeFromSLA: true,
+	}}, nil)
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1"}, {ServerID: "srv-2"}}, nil)
+	mockRepo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		"srv-1": {
+			{ID: "srv-1", Status: domain.StatusUp, Timestamp: start},
+			{ID: "srv-1", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+		"srv-2": {
+			{ID: "srv-2", Status: domain.StatusUp, Timestamp: start},
+			{ID: "srv-2", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+	}, nil)
+
+	service := NewServerInfoService(mockRepo, mockMaintenance)
+	ratio, err := service.GetServerMeanUpTimeRatio(start.Format(time.RFC3339), end.Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// srv-1: 50%, srv-2: 100% of the counted time
+	if ratio != 75 {
+		t.Errorf("expected 75, got %v", ratio)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetServerMeanUpTimeRatio_MaintenanceRepoError(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1"}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return(nil, errors.New("db error"))
+
+	service := NewServerInfoService(mockRepo, mockMaintenance)
+	_, err := service.GetServerMeanUpTimeRatio("2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z")
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestGetUpTimeTrend(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1"}, {ServerID: "srv-2"}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	mockRepo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		// Up for the first half, Down for the second
+		"srv-1": {
+			{ID: "srv-1", Status: domain.StatusUp, Timestamp: start.Add(-time.Hour)},
+			{ID: "srv-1", Status: domain.StatusDown, Timestamp: start.Add(5 * time.Hour)},
+		},
+		"srv-2": {
+			{ID: "srv-2", Status: domain.StatusUp, Timestamp: start},
+		},
+	}, nil).Once()
+
+	service := NewServerInfoService(mockRepo, mockMaintenance)
+	trend, err := service.GetUpTimeTrend(start, end, 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(trend) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(trend))
+	}
+	if !trend[0].Time.Equal(start) || trend[0].UpTime != 100 {
+		t.Errorf("expected 100 from %v, got %v from %v", start, trend[0].UpTime, trend[0].Time)
+	}
+	if !trend[1].Time.Equal(start.Add(5*time.Hour)) || trend[1].UpTime != 50 {
+		t.Errorf("expected 50 from %v, got %v from %v", start.Add(5*time.Hour), trend[1].UpTime, trend[1].Time)
+	}
+	mockRepo.AssertExpectations(t)
+}
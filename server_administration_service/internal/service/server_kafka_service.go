@@ -1,7 +1,13 @@
 package service
 
 import (
+	"fmt"
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
 )
 
 type ServerKafkaService interface {
@@ -10,14 +16,135 @@ type ServerKafkaService interface {
 
 type serverKafkaService struct {
 	serverKafkaRepository repository.ServerKafkaRepository
+	maintenanceService    MaintenanceService
 }
 
-func NewServerKafaService(serverKafkaRepository repository.ServerKafkaRepository) ServerKafkaService {
+func NewServerKafaService(serverKafkaRepository repository.ServerKafkaRepository, maintenanceService MaintenanceService) ServerKafkaService {
 	return &serverKafkaService{
 		serverKafkaRepository: serverKafkaRepository,
+		maintenanceService:    maintenanceService,
 	}
 }
 
+// UpdateStatus applies a status observed by the health checks.
+// Servers an operator put in Maintenance or Decommissioned keep their status until the operator changes it,
+// and a server found Down while a server it depends on is down is marked Unreachable instead.
 func (s *serverKafkaService) UpdateStatus(server_id, status string) (error) {
-	return s.serverKafkaRepository.UpdateStatus(server_id, status)
-}
\ No newline at end of file
+	status = domain.NormalizeStatus(status)
+	if !domain.IsValidStatus(status) || !domain.IsProbedStatus(status) || status == domain.StatusUnreachable {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidStatus, status)
+	}
+
+	current, err := s.serverKafkaRepository.GetStatus(server_id)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the status of server "+server_id+", err: "+err.Error(), "ERROR")
+		return err
+	}
+	current = domain.NormalizeStatus(current)
+
+	if !domain.IsProbedStatus(current) {
+		logging.LogMessage("server_administration_service", "Ignoring status "+status+" for server "+server_id+" in state "+current, "INFO")
+		return nil
+	}
+	if status == domain.StatusDown {
+		parents, err := s.serverKafkaRepository.GetDownDependencies(server_id)
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Failed to get the dependencies of server "+server_id+", err: "+err.Error(), "ERROR")
+			return err
+		}
+		if len(parents) > 0 {
+			logging.LogMessage("server_administration_service", "Server "+server_id+" is unreachable, it depends on down servers "+strings.Join(parents, ", "), "INFO")
+			status = domain.StatusUnreachable
+		}
+	}
+
+	if current == status {
+		logging.LogMessage("server_administration_service", "Server "+server_id+" is already "+status, "DEBUG")
+		return nil
+	}
+
+	return recordStatusChange(s.serverKafkaRepository, s.maintenanceService, server_id, current, status)
+}
+
+// recordStatusChange validates the transition and stores it, tagged with the active maintenance window if any.
+// A server going Down or Unreachable takes the Down servers behind it to Unreachable, whatever order their
+// probes came in; one coming back gives them back the Down of their last probe, unless another server they
+// depend on is still down. The healthcheck doesn't publish Down for an Unreachable server, so nothing else
+// would.
+func recordStatusChange(serverKafkaRepository repository.ServerKafkaRepository, maintenanceService MaintenanceService, server_id, from, to string) error {
+	if !domain.CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", domain.ErrInvalidTransition, from, to)
+	}
+
+	if err := serverKafkaRepository.UpdateStatus(server_id, to, activeMaintenanceWindowID(maintenanceService, server_id)); err != nil {
+		return err
+	}
+
+	if to != domain.StatusDown && to != domain.StatusUnreachable {
+		if from == domain.StatusDown || from == domain.StatusUnreachable {
+			return releaseUnreachableDependents(serverKafkaRepository, maintenanceService, server_id)
+		}
+		return nil
+	}
+
+	dependents, err := serverKafkaRepository.GetDownDependents(server_id)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the dependents of server "+server_id+", err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	for _, dependent := range dependents {
+		logging.LogMessage("server_administration_service", "Server "+dependent+" is unreachable, it depends on down server "+server_id, "INFO")
+		if err := serverKafkaRepository.UpdateStatus(dependent, domain.StatusUnreachable, activeMaintenanceWindowID(maintenanceService, dependent)); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to mark server "+dependent+" unreachable, err: "+err.Error(), "ERROR")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseUnreachableDependents takes the Unreachable servers behind a server that came back to Down,
+// those of them with no other server they depend on down.
+func releaseUnreachableDependents(serverKafkaRepository repository.ServerKafkaRepository, maintenanceService MaintenanceService, server_id string) error {
+	dependents, err := serverKafkaRepository.GetUnreachableDependents(server_id)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the unreachable dependents of server "+server_id+", err: "+err.Error(), "ERROR")
+		return err
+	}
+
+	for _, dependent := range dependents {
+		parents, err := serverKafkaRepository.GetDownDependencies(dependent)
+		if err != nil {
+			logging.LogMessage("server_administration_service", "Failed to get the dependencies of server "+dependent+", err: "+err.Error(), "ERROR")
+			return err
+		}
+		if len(parents) > 0 {
+			logging.LogMessage("server_administration_service", "Server "+dependent+" stays unreachable, it depends on down servers "+strings.Join(parents, ", "), "INFO")
+			continue
+		}
+
+		logging.LogMessage("server_administration_service", "Server "+dependent+" is down, server "+server_id+" it depends on is back", "INFO")
+		if err := recordStatusChange(serverKafkaRepository, maintenanceService, dependent, domain.StatusUnreachable, domain.StatusDown); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to mark server "+dependent+" down, err: "+err.Error(), "ERROR")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// activeMaintenanceWindowID returns the ID of the maintenance window the server is in, if any.
+// Status changes during a maintenance window are tagged with it.
+func activeMaintenanceWindowID(maintenanceService MaintenanceService, server_id string) string {
+	window, err := maintenanceService.GetActiveMaintenanceWindow(server_id, time.Now())
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to check maintenance windows of server "+server_id+", err: "+err.Error(), "ERROR")
+		return ""
+	}
+	if window == nil {
+		return ""
+	}
+
+	return window.ID
+}
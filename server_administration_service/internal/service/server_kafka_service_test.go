@@ -3,7 +3,9 @@ package service_test
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"server_administration_service/internal/domain"
 	"server_administration_service/internal/service"
 
 	"github.com/stretchr/testify/mock"
@@ -14,21 +16,101 @@ type mockServerKafkaRepository struct {
 	mock.Mock
 }
 
-func (m *mockServerKafkaRepository) UpdateStatus(server_id, status string) error {
-	args := m.Called(server_id, status)
+func (m *mockServerKafkaRepository) GetStatus(server_id string) (string, error) {
+	args := m.Called(server_id)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockServerKafkaRepository) GetDownDependencies(server_id string) ([]string, error) {
+	args := m.Called(server_id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerKafkaRepository) GetDownDependents(server_id string) ([]string, error) {
+	args := m.Called(server_id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerKafkaRepository) GetUnreachableDependents(server_id string) ([]string, error) {
+	args := m.Called(server_id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockServerKafkaRepository) UpdateStatus(server_id, status, maintenanceWindowID string) error {
+	args := m.Called(server_id, status, maintenanceWindowID)
 	return args.Error(0)
 }
 
+func (m *mockServerKafkaRepository) RecordStatus(server_id, status, maintenanceWindowID string) error {
+	args := m.Called(server_id, status, maintenanceWindowID)
+	return args.Error(0)
+}
+
+// Mock implementation of MaintenanceService
+type mockMaintenanceService struct {
+	mock.Mock
+}
+
+func (m *mockMaintenanceService) CreateMaintenanceWindow(window *domain.MaintenanceWindow) (string, error) {
+	args := m.Called(window)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMaintenanceService) GetMaintenanceWindow(id string) (*domain.MaintenanceWindow, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceService) GetMaintenanceWindows() ([]domain.MaintenanceWindow, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MaintenanceWindow), args.Error(1)
+}
+
+func (m *mockMaintenanceService) UpdateMaintenanceWindow(id string, updatedData map[string]interface{}) error {
+	args := m.Called(id, updatedData)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceService) DeleteMaintenanceWindow(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *mockMaintenanceService) GetActiveMaintenanceWindow(serverID string, t time.Time) (*domain.MaintenanceWindow, error) {
+	args := m.Called(serverID, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.MaintenanceWindow), args.Error(1)
+}
+
 func TestServerKafkaService_UpdateStatus_Success(t *testing.T) {
 	mockRepo := new(mockServerKafkaRepository)
-	service := service.NewServerKafaService(mockRepo)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
 
 	serverID := "server123"
-	status := "active"
 
-	mockRepo.On("UpdateStatus", serverID, status).Return(nil)
+	mockRepo.On("GetStatus", serverID).Return(domain.StatusUp, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", serverID, mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", serverID, domain.StatusDegraded, "").Return(nil)
 
-	err := service.UpdateStatus(serverID, status)
+	err := service.UpdateStatus(serverID, domain.StatusDegraded)
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -36,17 +118,39 @@ func TestServerKafkaService_UpdateStatus_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestServerKafkaService_UpdateStatus_LegacyStatus(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	// On/Off from older health checks map onto Up/Down
+	mockRepo.On("GetStatus", "server123").Return("On", nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, nil)
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{}, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusDown, "").Return(nil)
+	mockRepo.On("GetDownDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("server123", "Off"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestServerKafkaService_UpdateStatus_Error(t *testing.T) {
 	mockRepo := new(mockServerKafkaRepository)
-	service := service.NewServerKafaService(mockRepo)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
 
 	serverID := "server123"
-	status := "inactive"
 	expectedErr := errors.New("update failed")
 
-	mockRepo.On("UpdateStatus", serverID, status).Return(expectedErr)
+	mockRepo.On("GetStatus", serverID).Return(domain.StatusUnknown, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", serverID, mock.Anything).Return(nil, nil)
+	mockRepo.On("GetDownDependencies", serverID).Return([]string{}, nil)
+	mockRepo.On("UpdateStatus", serverID, domain.StatusDown, "").Return(expectedErr)
 
-	err := service.UpdateStatus(serverID, status)
+	err := service.UpdateStatus(serverID, domain.StatusDown)
 	if err == nil {
 		t.Errorf("expected error, got nil")
 	}
@@ -55,4 +159,243 @@ func TestServerKafkaService_UpdateStatus_Error(t *testing.T) {
 	}
 
 	mockRepo.AssertExpectations(t)
-}
\ No newline at end of file
+}
+
+func TestServerKafkaService_UpdateStatus_InvalidStatus(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+	// Probes may not decide on operator-only states either
+	for _, status := range []string{"active", domain.StatusMaintenance, domain.StatusDecommissioned} {
+		if err := service.UpdateStatus("server123", status); !errors.Is(err, domain.ErrInvalidStatus) {
+			t.Errorf("expected ErrInvalidStatus for %q, got %v", status, err)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_InvalidTransition(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+	// A known server never goes back to Unknown on its own
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUp, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusUnknown); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_Unchanged(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUp, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusUp); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_OperatorStateKept(t *testing.T) {
+	for _, current := range []string{domain.StatusMaintenance, domain.StatusDecommissioned} {
+		mockRepo := new(mockServerKafkaRepository)
+		service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+		mockRepo.On("GetStatus", "server123").Return(current, nil)
+
+		if err := service.UpdateStatus("server123", domain.StatusDown); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+
+		mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestServerKafkaService_UpdateStatus_DuringMaintenance(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUp, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).
+		Return(&domain.MaintenanceWindow{ID: "window-1"}, nil)
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{}, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusDown, "window-1").Return(nil)
+	mockRepo.On("GetDownDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusDown); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerKafkaService_UpdateStatus_MaintenanceLookupFails(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	// The status is still recorded, just without a maintenance tag
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusDown, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, errors.New("db error"))
+	mockRepo.On("UpdateStatus", "server123", domain.StatusUp, "").Return(nil)
+	mockRepo.On("GetUnreachableDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusUp); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerKafkaService_UpdateStatus_ParentDown(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUp, nil)
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{"switch-1"}, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusUnreachable, "").Return(nil)
+	mockRepo.On("GetDownDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusDown); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerKafkaService_UpdateStatus_StillUnreachable(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+	// The probe keeps reporting Down while the switch is down
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUnreachable, nil)
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{"switch-1"}, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusDown); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_ParentBackUp(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	// Once the switch is back a server that still fails its probe is really Down
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUnreachable, nil)
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{}, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusDown, "").Return(nil)
+	mockRepo.On("GetDownDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("server123", domain.StatusDown); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerKafkaService_UpdateStatus_UnreachableReported(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerKafaService(mockRepo, new(mockMaintenanceService))
+
+	// Unreachable is derived from the dependencies, never probed
+	if err := service.UpdateStatus("server123", domain.StatusUnreachable); !errors.Is(err, domain.ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "GetStatus", mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_DependentsBecomeUnreachable(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	// The probes of the servers behind the switch were consumed first, they are Down until the switch is
+	mockRepo.On("GetStatus", "switch-1").Return(domain.StatusUp, nil)
+	mockRepo.On("GetDownDependencies", "switch-1").Return([]string{}, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "switch-1", mock.Anything).Return(nil, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server456", mock.Anything).Return(&domain.MaintenanceWindow{ID: "window-1"}, nil)
+	mockRepo.On("UpdateStatus", "switch-1", domain.StatusDown, "").Return(nil)
+	mockRepo.On("GetDownDependents", "switch-1").Return([]string{"server123", "server456"}, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusUnreachable, "").Return(nil)
+	mockRepo.On("UpdateStatus", "server456", domain.StatusUnreachable, "window-1").Return(nil)
+
+	if err := service.UpdateStatus("switch-1", domain.StatusDown); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetDownDependents", "server123")
+}
+
+func TestServerKafkaService_UpdateStatus_DependentsLookupFails(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	expectedErr := errors.New("db error")
+	mockRepo.On("GetStatus", "switch-1").Return(domain.StatusUp, nil)
+	mockRepo.On("GetDownDependencies", "switch-1").Return([]string{}, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "switch-1", mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "switch-1", domain.StatusDown, "").Return(nil)
+	mockRepo.On("GetDownDependents", "switch-1").Return(nil, expectedErr)
+
+	if err := service.UpdateStatus("switch-1", domain.StatusDown); !errors.Is(err, expectedErr) {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestServerKafkaService_UpdateStatus_BackUpReleasesDependents(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	mockRepo.On("GetStatus", "switch-1").Return(domain.StatusDown, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "switch-1", domain.StatusUp, "").Return(nil)
+	mockRepo.On("GetUnreachableDependents", "switch-1").Return([]string{"server123", "server456"}, nil)
+	// server123 was down behind the switch alone, server456 is also behind another switch still down
+	mockRepo.On("GetDownDependencies", "server123").Return([]string{}, nil)
+	mockRepo.On("GetDownDependencies", "server456").Return([]string{"switch-2"}, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusDown, "").Return(nil)
+	mockRepo.On("GetDownDependents", "server123").Return([]string{}, nil)
+
+	if err := service.UpdateStatus("switch-1", domain.StatusUp); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetDownDependents", "switch-1")
+	mockRepo.AssertNotCalled(t, "UpdateStatus", "server456", mock.Anything, mock.Anything)
+}
+
+func TestServerKafkaService_UpdateStatus_UpToUpLeavesDependents(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerKafaService(mockRepo, mockMaintenance)
+
+	mockRepo.On("GetStatus", "switch-1").Return(domain.StatusDegraded, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "switch-1", mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "switch-1", domain.StatusUp, "").Return(nil)
+
+	if err := service.UpdateStatus("switch-1", domain.StatusUp); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "GetUnreachableDependents", mock.Anything)
+	mockRepo.AssertNotCalled(t, "GetDownDependents", mock.Anything)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"slices"
+)
+
+var ErrInvalidPage = errors.New("invalid page")
+
+const (
+	defaultServerPageSize = 50
+	maxServerPageSize     = 1000
+)
+
+// serverCursor is what a page cursor holds: the sort it was made for and the position of the server to
+// go on from, the last of its page for a next cursor and the first for a previous one. Clients only see
+// it as base64 so that they don't build their own.
+type serverCursor struct {
+	Columns  string            `json:"s"`
+	Orders   string            `json:"o"`
+	Values   []json.RawMessage `json:"v"`
+	Backward bool              `json:"b,omitempty"`
+}
+
+func encodeServerCursor(sort domain.ServerSort, server domain.Server, backward bool) string {
+	cursor := serverCursor{Backward: backward}
+	cursor.Columns, cursor.Orders = sort.Format()
+	for _, value := range sort.Values(server) {
+		data, _ := json.Marshal(value)
+		cursor.Values = append(cursor.Values, data)
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// serverKeyset turns a page request into the keyset to read, checking its limit and cursor.
+func serverKeyset(request dto.ServerPageRequest) (dto.ServerKeyset, error) {
+	keyset := dto.ServerKeyset{Sort: request.Sort, Limit: request.Limit}
+	if keyset.Limit == 0 {
+		keyset.Limit = defaultServerPageSize
+	}
+	if keyset.Limit < 0 || keyset.Limit > maxServerPageSize {
+		return keyset, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, maxServerPageSize)
+	}
+
+	if request.Cursor != "" {
+		sort, after, backward, err := decodeServerCursor(request.Cursor)
+		if err != nil {
+			return keyset, err
+		}
+		if keyset.Sort != nil && !slices.Equal(keyset.Sort, sort) {
+			return keyset, fmt.Errorf("%w: the cursor is for another sort", ErrInvalidPage)
+		}
+		keyset.Sort, keyset.After, keyset.Backward = sort, after, backward
+	}
+
+	if keyset.Sort == nil {
+		keyset.Sort = domain.ServerSort{{Column: "server_id"}}
+	}
+	return keyset, nil
+}
+
+func decodeServerCursor(encoded string) (domain.ServerSort, *dto.ServerKey, bool, error) {
+	var cursor serverCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || cursor.Columns == "" {
+		return nil, nil, false, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+
+	sort, err := domain.ParseServerSort(cursor.Columns, cursor.Orders)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	// JSON loses the types of the values, the sort's columns give them back
+	values, err := sort.ParseValues(cursor.Values)
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	return sort, &dto.ServerKey{Values: values}, cursor.Backward, nil
+}
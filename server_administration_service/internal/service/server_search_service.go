@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"strconv"
+	"strings"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+const (
+	defaultServerSearchLimit = 20
+	maxServerSearchLimit     = 100
+	maxServerSearchLength    = 256
+)
+
+type ServerSearchService interface {
+	SearchServers(query string, limit int) (*dto.ServerSearchResult, error)
+	PrepareIndex() error
+	Reindex() (int, error)
+}
+
+type serverSearchService struct {
+	searchRepository repository.ServerSearchRepository
+	serverRepository repository.ServerCRUDRepository
+}
+
+func NewServerSearchService(searchRepository repository.ServerSearchRepository, serverRepository repository.ServerCRUDRepository) ServerSearchService {
+	return &serverSearchService{
+		searchRepository: searchRepository,
+		serverRepository: serverRepository,
+	}
+}
+
+// SearchServers returns the servers best matching query, at most limit of them, best first, with
+// the fragments that matched. The servers are read from Postgres, so they are always current;
+// hits on servers that no longer exist are dropped and removed from the index.
+func (s *serverSearchService) SearchServers(query string, limit int) (*dto.ServerSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > maxServerSearchLength {
+		return nil, fmt.Errorf("%w: the query must be 1 to %d characters", ErrInvalidSearch, maxServerSearchLength)
+	}
+	if limit == 0 {
+		limit = defaultServerSearchLimit
+	}
+	if limit < 0 || limit > maxServerSearchLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, maxServerSearchLimit)
+	}
+
+	result, err := s.searchRepository.SearchServers(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Hits) == 0 {
+		return result, nil
+	}
+
+	serverIDs := make([]interface{}, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		serverIDs = append(serverIDs, hit.ServerID)
+	}
+	filter := &dto.ServerFilter{Expression: &domain.FilterExpression{
+		Field:    domain.FilterFieldServerID,
+		Operator: domain.FilterIn,
+		Values:   serverIDs,
+	}}
+	servers, err := s.serverRepository.ViewServers(filter, 0, len(serverIDs), nil)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Server, len(servers))
+	for _, server := range servers {
+		byID[server.ServerID] = server
+	}
+
+	hits := result.Hits[:0]
+	var missing []string
+	for _, hit := range result.Hits {
+		server, ok := byID[hit.ServerID]
+		if !ok {
+			missing = append(missing, hit.ServerID)
+			continue
+		}
+		hit.Server = &server
+		hits = append(hits, hit)
+	}
+	result.Hits = hits
+	if len(missing) > 0 {
+		result.Total -= int64(len(missing))
+		if err := s.searchRepository.DeleteServers(missing); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to remove deleted servers from the search index: "+err.Error(), "ERROR")
+		}
+	}
+	return result, nil
+}
+
+// PrepareIndex creates the search index when it is missing and fills it with every server.
+func (s *serverSearchService) PrepareIndex() error {
+	created, err := s.searchRepository.CreateIndex()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to create the search index: "+err.Error(), "ERROR")
+		return err
+	}
+	if !created {
+		return nil
+	}
+	_, err = s.Reindex()
+	return err
+}
+
+// Reindex writes every server into the search index again, for an index that missed some writes.
+// It returns how many servers were indexed.
+func (s *serverSearchService) Reindex() (int, error) {
+	servers, err := s.serverRepository.GetAllServers()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to read servers to index: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	if err := s.searchRepository.IndexServers(servers); err != nil {
+		return 0, err
+	}
+
+	logging.LogMessage("server_administration_service", strconv.Itoa(len(servers))+" servers indexed for search", "INFO")
+	return len(servers), nil
+}
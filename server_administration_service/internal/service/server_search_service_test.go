@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock for ServerSearchRepository
+type mockServerSearchRepository struct {
+	mock.Mock
+}
+
+func (m *mockServerSearchRepository) CreateIndex() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockServerSearchRepository) IndexServers(servers []domain.Server) error {
+	args := m.Called(servers)
+	return args.Error(0)
+}
+
+func (m *mockServerSearchRepository) DeleteServers(serverIDs []string) error {
+	args := m.Called(serverIDs)
+	return args.Error(0)
+}
+
+func (m *mockServerSearchRepository) SearchServers(query string, limit int) (*dto.ServerSearchResult, error) {
+	args := m.Called(query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ServerSearchResult), args.Error(1)
+}
+
+func TestSearchServers_LoadsServers(t *testing.T) {
+	mockSearch := new(mockServerSearchRepository)
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerSearchService(mockSearch, mockRepo)
+
+	mockSearch.On("SearchServers", "web", 20).Return(&dto.ServerSearchResult{
+		Hits: []dto.ServerSearchHit{
+			{ServerID: "srv-2", Score: 3, Highlights: map[string][]string{"server_name": {"<em>web</em>-2"}}},
+			{ServerID: "gone", Score: 2},
+			{ServerID: "srv-1", Score: 1},
+		},
+		Total: 3,
+	}, nil)
+	// The servers come back in server_id order; the hits keep theirs
+	mockRepo.On("ViewServers", mock.MatchedBy(func(filter *dto.ServerFilter) bool {
+		expression := filter.Expression
+		return expression.Field == domain.FilterFieldServerID && expression.Operator == domain.FilterIn &&
+			assert.ObjectsAreEqual([]interface{}{"srv-2", "gone", "srv-1"}, expression.Values)
+	}), 0, 3, domain.ServerSort(nil)).Return([]domain.Server{
+		{ServerID: "srv-1", ServerName: "web-1"},
+		{ServerID: "srv-2", ServerName: "web-2"},
+	}, nil)
+	mockSearch.On("DeleteServers", []string{"gone"}).Return(nil)
+
+	result, err := svc.SearchServers("  web ", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Len(t, result.Hits, 2)
+	assert.Equal(t, "web-2", result.Hits[0].Server.ServerName)
+	assert.Equal(t, []string{"<em>web</em>-2"}, result.Hits[0].Highlights["server_name"])
+	assert.Equal(t, "web-1", result.Hits[1].Server.ServerName)
+	mockSearch.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSearchServers_NoHits(t *testing.T) {
+	mockSearch := new(mockServerSearchRepository)
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerSearchService(mockSearch, mockRepo)
+
+	mockSearch.On("SearchServers", "nothing", 5).Return(&dto.ServerSearchResult{Hits: []dto.ServerSearchHit{}}, nil)
+
+	result, err := svc.SearchServers("nothing", 5)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Hits)
+	mockRepo.AssertNotCalled(t, "ViewServers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchServers_Invalid(t *testing.T) {
+	mockSearch := new(mockServerSearchRepository)
+	svc := service.NewServerSearchService(mockSearch, new(mockServerCRUDRepository))
+
+	for _, c := range []struct {
+		query string
+		limit int
+	}{
+		{"", 0},
+		{"   ", 0},
+		{strings.Repeat("x", 257), 0},
+		{"web", -1},
+		{"web", 101},
+	} {
+		_, err := svc.SearchServers(c.query, c.limit)
+		assert.ErrorIs(t, err, service.ErrInvalidSearch)
+	}
+	mockSearch.AssertNotCalled(t, "SearchServers", mock.Anything, mock.Anything)
+}
+
+func TestPrepareIndex(t *testing.T) {
+	servers := []domain.Server{{ServerID: "srv-1"}, {ServerID: "srv-2"}}
+
+	// A new index is filled with every server
+	mockSearch := new(mockServerSearchRepository)
+	mockRepo := new(mockServerCRUDRepository)
+	mockSearch.On("CreateIndex").Return(true, nil)
+	mockRepo.On("GetAllServers").Return(servers, nil)
+	mockSearch.On("IndexServers", servers).Return(nil)
+	assert.NoError(t, service.NewServerSearchService(mockSearch, mockRepo).PrepareIndex())
+	mockSearch.AssertExpectations(t)
+
+	// An existing one is left as it is
+	mockSearch = new(mockServerSearchRepository)
+	mockRepo = new(mockServerCRUDRepository)
+	mockSearch.On("CreateIndex").Return(false, nil)
+	assert.NoError(t, service.NewServerSearchService(mockSearch, mockRepo).PrepareIndex())
+	mockRepo.AssertNotCalled(t, "GetAllServers")
+}
+
+func TestReindex_Error(t *testing.T) {
+	mockSearch := new(mockServerSearchRepository)
+	mockRepo := new(mockServerCRUDRepository)
+	svc := service.NewServerSearchService(mockSearch, mockRepo)
+
+	mockRepo.On("GetAllServers").Return([]domain.Server{{ServerID: "srv-1"}}, nil)
+	mockSearch.On("IndexServers", mock.Anything).Return(errors.New("can't send bulk request to ES"))
+
+	indexed, err := svc.Reindex()
+	assert.Error(t, err)
+	assert.Zero(t, indexed)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+type ServerStatusService interface {
+	ChangeStatus(serverID, status string) error
+	RecordStatusChanges(serverIDs []string, status string) error
+}
+
+type serverStatusService struct {
+	serverKafkaRepository repository.ServerKafkaRepository
+	maintenanceService    MaintenanceService
+}
+
+func NewServerStatusService(serverKafkaRepository repository.ServerKafkaRepository, maintenanceService MaintenanceService) ServerStatusService {
+	return &serverStatusService{
+		serverKafkaRepository: serverKafkaRepository,
+		maintenanceService:    maintenanceService,
+	}
+}
+
+// ChangeStatus moves a server to the status an operator asked for, e.g. into Maintenance or out of Decommissioned.
+// Unreachable follows from the dependencies and can't be set by hand.
+func (s *serverStatusService) ChangeStatus(serverID, status string) error {
+	if !domain.IsValidStatus(status) || status == domain.StatusUnreachable {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidStatus, status)
+	}
+
+	current, err := s.serverKafkaRepository.GetStatus(serverID)
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get the status of server "+serverID+", err: "+err.Error(), "ERROR")
+		return err
+	}
+	current = domain.NormalizeStatus(current)
+
+	if current == status {
+		return nil
+	}
+
+	return recordStatusChange(s.serverKafkaRepository, s.maintenanceService, serverID, current, status)
+}
+
+// RecordStatusChanges adds to the status history the servers another write already moved to the status,
+// e.g. the ones a sync import decommissions in its transaction. It records every server it can and
+// returns the last error.
+func (s *serverStatusService) RecordStatusChanges(serverIDs []string, status string) error {
+	var lastErr error
+	for _, serverID := range serverIDs {
+		if err := s.serverKafkaRepository.RecordStatus(serverID, status, activeMaintenanceWindowID(s.maintenanceService, serverID)); err != nil {
+			logging.LogMessage("server_administration_service", "Failed to record status "+status+" of server "+serverID+", err: "+err.Error(), "ERROR")
+			lastErr = err
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestServerStatusService_ChangeStatus_Success(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerStatusService(mockRepo, mockMaintenance)
+
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusUp, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "server123", mock.Anything).Return(nil, nil)
+	mockRepo.On("UpdateStatus", "server123", domain.StatusDecommissioned, "").Return(nil)
+
+	if err := service.ChangeStatus("server123", domain.StatusDecommissioned); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServerStatusService_ChangeStatus_InvalidStatus(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerStatusService(mockRepo, new(mockMaintenanceService))
+
+	for _, status := range []string{"On", domain.StatusUnreachable} {
+		if err := service.ChangeStatus("server123", status); !errors.Is(err, domain.ErrInvalidStatus) {
+			t.Errorf("expected ErrInvalidStatus for %s, got %v", status, err)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "GetStatus", mock.Anything)
+}
+
+func TestServerStatusService_ChangeStatus_InvalidTransition(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerStatusService(mockRepo, new(mockMaintenanceService))
+
+	// Decommissioned servers have to be re-enrolled through Unknown
+	mockRepo.On("GetStatus", "server123").Return(domain.StatusDecommissioned, nil)
+
+	if err := service.ChangeStatus("server123", domain.StatusUp); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServerStatusService_ChangeStatus_NotFound(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	service := service.NewServerStatusService(mockRepo, new(mockMaintenanceService))
+
+	mockRepo.On("GetStatus", "missing").Return("", gorm.ErrRecordNotFound)
+
+	if err := service.ChangeStatus("missing", domain.StatusMaintenance); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestServerStatusService_RecordStatusChanges(t *testing.T) {
+	mockRepo := new(mockServerKafkaRepository)
+	mockMaintenance := new(mockMaintenanceService)
+	service := service.NewServerStatusService(mockRepo, mockMaintenance)
+
+	// Every server is recorded even when one of them fails
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-1", mock.Anything).Return(nil, nil)
+	mockMaintenance.On("GetActiveMaintenanceWindow", "srv-2", mock.Anything).Return(&domain.MaintenanceWindow{ID: "window-1"}, nil)
+	mockRepo.On("RecordStatus", "srv-1", domain.StatusDecommissioned, "").Return(errors.New("es error"))
+	mockRepo.On("RecordStatus", "srv-2", domain.StatusDecommissioned, "window-1").Return(nil)
+
+	if err := service.RecordStatusChanges([]string{"srv-1", "srv-2"}, domain.StatusDecommissioned); err == nil {
+		t.Errorf("expected an error, got nil")
+	}
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/repository"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+)
+
+var (
+	ErrServerNotFound   = errors.New("server not found")
+	ErrInvalidSLAPeriod = errors.New("the SLA period must have started already")
+)
+
+type SLAService interface {
+	GetCompliance(serverID string, startTime, endTime time.Time) ([]domain.SLACompliance, error)
+}
+
+type slaService struct {
+	serverInfoRepository  repository.ServerInfoRepository
+	maintenanceRepository repository.MaintenanceRepository
+}
+
+func NewSLAService(serverInfoRepository repository.ServerInfoRepository, maintenanceRepository repository.MaintenanceRepository) SLAService {
+	return &slaService{
+		serverInfoRepository:  serverInfoRepository,
+		maintenanceRepository: maintenanceRepository,
+	}
+}
+
+// GetCompliance reports every server (or only serverID) against its SLA target over [startTime, endTime].
+// A period that isn't over yet is evaluated up to now, and a server only counts from its creation.
+func (s *slaService) GetCompliance(serverID string, startTime, endTime time.Time) ([]domain.SLACompliance, error) {
+	if now := time.Now(); endTime.After(now) {
+		endTime = now
+	}
+	if !endTime.After(startTime) {
+		return nil, ErrInvalidSLAPeriod
+	}
+
+	servers, err := s.serverInfoRepository.GetServers(serverID)
+	if err != nil {
+		return nil, err
+	}
+	if serverID != "" && len(servers) == 0 {
+		return nil, ErrServerNotFound
+	}
+
+	windows, err := s.maintenanceRepository.GetMaintenanceWindows()
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to get maintenance windows, err: "+err.Error(), "ERROR")
+		return nil, err
+	}
+	exclusions := domain.SLAExclusions(windows, startTime, endTime)
+
+	histories, err := s.statusHistories(serverID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	compliances := make([]domain.SLACompliance, 0, len(servers))
+	for _, server := range servers {
+		up, counted := serverUpTime(server, histories[server.ServerID], startTime, endTime, exclusions[server.ServerID])
+
+		target := server.SLATarget
+		if !domain.ValidSLATarget(target) {
+			target = domain.DefaultSLATarget
+		}
+
+		compliance := domain.ComputeSLACompliance(target, up, counted)
+		compliance.ServerID = server.ServerID
+		compliance.ServerName = server.ServerName
+		compliance.PeriodStart = startTime
+		compliance.PeriodEnd = endTime
+		compliances = append(compliances, compliance)
+	}
+
+	return compliances, nil
+}
+
+// statusHistories returns the status history of the server, or of the whole fleet in a single search
+// when serverID is empty.
+func (s *slaService) statusHistories(serverID string, startTime, endTime time.Time) (map[string][]domain.StatusChange, error) {
+	if serverID == "" {
+		return s.serverInfoRepository.GetStatusHistories(startTime, endTime)
+	}
+
+	history, err := s.serverInfoRepository.GetStatusHistory(serverID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]domain.StatusChange{serverID: history}, nil
+}
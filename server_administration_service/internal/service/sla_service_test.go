@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"server_administration_service/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestGetCompliance_Breached(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetServers", "srv-1").Return([]domain.Server{{ServerID: "srv-1", ServerName: "db", SLATarget: 99}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{}, nil)
+	// Down for 2.4 hours of the day: 90% achieved, ten times the allowed error rate
+	mockRepo.On("GetStatusHistory", "srv-1", start, end).Return([]domain.StatusChange{
+		{ID: "srv-1", Status: "On", Timestamp: start},
+		{ID: "srv-1", Status: "Off", Timestamp: end.Add(-144 * time.Minute)},
+	}, nil)
+
+	service := NewSLAService(mockRepo, mockMaintenance)
+	compliances, err := service.GetCompliance("srv-1", start, end)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(compliances) != 1 {
+		t.Fatalf("expected 1 compliance, got %d", len(compliances))
+	}
+
+	compliance := compliances[0]
+	if math.Abs(compliance.Achieved-90) > 1e-9 || !compliance.Breached {
+		t.Errorf("expected a 90%% breach, got %+v", compliance)
+	}
+	if math.Abs(compliance.BurnRate-10) > 1e-9 {
+		t.Errorf("expected burn rate 10, got %v", compliance.BurnRate)
+	}
+	if math.Abs(compliance.ErrorBudgetRemaining+900) > 1e-9 {
+		t.Errorf("expected -900%% error budget remaining, got %v", compliance.ErrorBudgetRemaining)
+	}
+	if math.Abs(compliance.ErrorBudgetSeconds-864) > 1e-9 {
+		t.Errorf("expected an error budget of 864s, got %v", compliance.ErrorBudgetSeconds)
+	}
+}
+
+func TestGetCompliance_MaintenanceAndCreationExcluded(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockMaintenance := new(mockMaintenanceRepository)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	created := start.Add(12 * time.Hour)
+
+	mockRepo.On("GetServers", "").Return([]domain.Server{{ServerID: "srv-1", SLATarget: 99.9, CreatedTime: created}}, nil)
+	mockMaintenance.On("GetMaintenanceWindows").Return([]domain.MaintenanceWindow{{
+		ServerIDs:      []string{"srv-1"},
+		StartTime:      end.Add(-time.Hour),
+		EndTime:        end,
+		ExcludeFromSLA: true,
+	}}, nil)
+	// The whole fleet comes from one search, however many servers there are
+	mockRepo.On("GetStatusHistories", start, end).Return(map[string][]domain.StatusChange{
+		"srv-1": {
+			{ID: "srv-1", Status: "On", Timestamp: created},
+			{ID: "srv-1", Status: "Off", Timestamp: end.Add(-time.Hour)},
+		},
+	}, nil)
+
+	service := NewSLAService(mockRepo, mockMaintenance)
+	compliances, err := service.GetCompliance("", start, end)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if compliances[0].Achieved != 100 || compliances[0].Breached {
+		t.Errorf("expected full compliance, got %+v", compliances[0])
+	}
+	if compliances[0].PeriodStart != start {
+		t.Errorf("expected the period to start at %v, got %v", start, compliances[0].PeriodStart)
+	}
+	mockRepo.AssertNotCalled(t, "GetStatusHistory", "srv-1", created, end)
+}
+
+func TestGetCompliance_ServerNotFound(t *testing.T) {
+	mockRepo := new(mockServerInfoRepository)
+	mockRepo.On("GetServers", "missing").Return([]domain.Server{}, nil)
+
+	service := NewSLAService(mockRepo, new(mockMaintenanceRepository))
+	_, err := service.GetCompliance("missing", time.Now().Add(-time.Hour), time.Now())
+	if !errors.Is(err, ErrServerNotFound) {
+		t.Errorf("expected ErrServerNotFound, got %v", err)
+	}
+}
+
+func TestGetCompliance_FuturePeriod(t *testing.T) {
+	service := NewSLAService(new(mockServerInfoRepository), new(mockMaintenanceRepository))
+	_, err := service.GetCompliance("", time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if !errors.Is(err, ErrInvalidSLAPeriod) {
+		t.Errorf("expected ErrInvalidSLAPeriod, got %v", err)
+	}
+}
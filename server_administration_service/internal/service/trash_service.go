@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/repository"
+	"strconv"
+	"time"
+
+	"github.com/flashhhhh/pkg/logging"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrServerNotInTrash = errors.New("server not in trash")
+
+// DefaultTrashRetention is how long a deleted server stays in the trash before it's purged.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+type TrashService interface {
+	GetTrash() ([]dto.TrashedServer, error)
+	RestoreServer(serverID string, actor dto.Actor) (*domain.Server, error)
+	PurgeExpired() (int, error)
+}
+
+type trashService struct {
+	trashRepository repository.TrashRepository
+	// searchRepository, when set, gets the restored servers back into the search index
+	searchRepository repository.ServerSearchRepository
+	auditRepository  repository.AuditRepository
+	retention        time.Duration
+	// purgeHistory also deletes the status history of the purged servers from Elasticsearch
+	purgeHistory bool
+}
+
+func NewTrashService(trashRepository repository.TrashRepository, searchRepository repository.ServerSearchRepository, auditRepository repository.AuditRepository, retention time.Duration, purgeHistory bool) TrashService {
+	return &trashService{
+		trashRepository:  trashRepository,
+		searchRepository: searchRepository,
+		auditRepository:  auditRepository,
+		retention:        retention,
+		purgeHistory:     purgeHistory,
+	}
+}
+
+// GetTrash returns the deleted servers, most recently deleted first, with when each will be purged.
+func (s *trashService) GetTrash() ([]dto.TrashedServer, error) {
+	servers, err := s.trashRepository.GetTrashedServers()
+	if err != nil {
+		return nil, err
+	}
+
+	trash := make([]dto.TrashedServer, 0, len(servers))
+	for _, server := range servers {
+		trash = append(trash, dto.TrashedServer{
+			Server:    server,
+			DeletedAt: server.DeletedAt.Time,
+			PurgeAt:   server.DeletedAt.Time.Add(s.retention),
+		})
+	}
+	return trash, nil
+}
+
+// RestoreServer takes a server out of the trash as it was when it was deleted.
+func (s *trashService) RestoreServer(serverID string, actor dto.Actor) (*domain.Server, error) {
+	server, err := s.trashRepository.RestoreServer(serverID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrServerNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.searchRepository != nil {
+		if err := s.searchRepository.IndexServers([]domain.Server{*server}); err != nil {
+			logging.LogMessage("server_administration_service", "Server "+serverID+" restored but not indexed for search: "+err.Error(), "ERROR")
+		}
+	}
+	recordAudit(s.auditRepository, actor, domain.AuditEntry{
+		ID:       uuid.New().String(),
+		ServerID: serverID,
+		Action:   domain.AuditRestore,
+		Changes:  domain.AuditChanges{},
+	})
+	return server, nil
+}
+
+// PurgeExpired deletes for good the servers in the trash longer than the retention, with their group
+// memberships and dependencies, and their status history when configured to. It returns how many servers
+// were purged.
+func (s *trashService) PurgeExpired() (int, error) {
+	servers, err := s.trashRepository.PurgeServers(time.Now().Add(-s.retention))
+	if err != nil {
+		logging.LogMessage("server_administration_service", "Failed to purge the trash: "+err.Error(), "ERROR")
+		return 0, err
+	}
+	if len(servers) == 0 {
+		return 0, nil
+	}
+
+	serverIDs := make([]string, 0, len(servers))
+	entries := make([]domain.AuditEntry, 0, len(servers))
+	for _, server := range servers {
+		serverIDs = append(serverIDs, server.ServerID)
+		entries = append(entries, domain.AuditEntry{
+			ID:       uuid.New().String(),
+			ServerID: server.ServerID,
+			Action:   domain.AuditPurge,
+			Changes:  domain.AuditChanges{},
+		})
+	}
+	recordAudit(s.auditRepository, dto.Actor{}, entries...)
+	logging.LogMessage("server_administration_service", strconv.Itoa(len(servers))+" servers purged from the trash", "INFO")
+
+	if s.purgeHistory {
+		deleted, err := s.trashRepository.DeleteStatusHistory(serverIDs)
+		if err != nil {
+			// The servers are gone already, the next purge won't find them again
+			logging.LogMessage("server_administration_service", "Failed to delete the status history of purged servers: "+err.Error(), "ERROR")
+			return len(servers), nil
+		}
+		logging.LogMessage("server_administration_service", strconv.FormatInt(deleted, 10)+" status documents of purged servers deleted", "INFO")
+	}
+	return len(servers), nil
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"server_administration_service/internal/domain"
+	"server_administration_service/internal/dto"
+	"server_administration_service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// Mock for TrashRepository
+type mockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *mockTrashRepository) GetTrashedServers() ([]domain.Server, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockTrashRepository) RestoreServer(serverID string) (*domain.Server, error) {
+	args := m.Called(serverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Server), args.Error(1)
+}
+
+func (m *mockTrashRepository) PurgeServers(deletedBefore time.Time) ([]domain.Server, error) {
+	args := m.Called(deletedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Server), args.Error(1)
+}
+
+func (m *mockTrashRepository) DeleteStatusHistory(serverIDs []string) (int64, error) {
+	args := m.Called(serverIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestGetTrash(t *testing.T) {
+	mockTrash := new(mockTrashRepository)
+	svc := service.NewTrashService(mockTrash, nil, nil, 7*24*time.Hour, false)
+
+	deletedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockTrash.On("GetTrashedServers").Return([]domain.Server{
+		{ServerID: "srv-1", ServerName: "web-1", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+	}, nil)
+
+	trash, err := svc.GetTrash()
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Equal(t, "web-1", trash[0].ServerName)
+	assert.Equal(t, deletedAt, trash[0].DeletedAt)
+	assert.Equal(t, time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC), trash[0].PurgeAt)
+}
+
+func TestRestoreServer_Success(t *testing.T) {
+	mockTrash := new(mockTrashRepository)
+	mockSearch := new(mockServerSearchRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewTrashService(mockTrash, mockSearch, mockAudit, service.DefaultTrashRetention, false)
+
+	restored := &domain.Server{ServerID: "srv-1", ServerName: "web-1"}
+	mockTrash.On("RestoreServer", "srv-1").Return(restored, nil)
+	mockSearch.On("IndexServers", []domain.Server{*restored}).Return(nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		return len(entries) == 1 && entries[0].Action == domain.AuditRestore && entries[0].UserID == auditActor.UserID
+	})).Return(nil)
+
+	server, err := svc.RestoreServer("srv-1", auditActor)
+	assert.NoError(t, err)
+	assert.Equal(t, restored, server)
+	mockSearch.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestRestoreServer_NotInTrash(t *testing.T) {
+	mockTrash := new(mockTrashRepository)
+	svc := service.NewTrashService(mockTrash, nil, nil, service.DefaultTrashRetention, false)
+
+	mockTrash.On("RestoreServer", "srv-1").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := svc.RestoreServer("srv-1", dto.Actor{})
+	assert.ErrorIs(t, err, service.ErrServerNotInTrash)
+}
+
+func TestPurgeExpired(t *testing.T) {
+	mockTrash := new(mockTrashRepository)
+	mockAudit := new(mockAuditRepository)
+	svc := service.NewTrashService(mockTrash, nil, mockAudit, 30*24*time.Hour, true)
+
+	mockTrash.On("PurgeServers", mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 30*24*time.Hour-time.Minute && time.Since(cutoff) < 30*24*time.Hour+time.Minute
+	})).Return([]domain.Server{{ServerID: "srv-1"}, {ServerID: "srv-2"}}, nil)
+	mockAudit.On("CreateAuditEntries", mock.MatchedBy(func(entries []domain.AuditEntry) bool {
+		return len(entries) == 2 && entries[0].Action == domain.AuditPurge && entries[0].UserID == ""
+	})).Return(nil)
+	mockTrash.On("DeleteStatusHistory", []string{"srv-1", "srv-2"}).Return(int64(1200), nil)
+
+	purged, err := svc.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	mockTrash.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestPurgeExpired_KeepsHistory(t *testing.T) {
+	mockTrash := new(mockTrashRepository)
+	svc := service.NewTrashService(mockTrash, nil, nil, service.DefaultTrashRetention, false)
+
+	mockTrash.On("PurgeServers", mock.Anything).Return([]domain.Server{{ServerID: "srv-1"}}, nil)
+
+	purged, err := svc.PurgeExpired()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	mockTrash.AssertNotCalled(t, "DeleteStatusHistory", mock.Anything)
+}
@@ -21,14 +21,20 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	ServerAdministrationService_GetAddressAndStatus_FullMethodName   = "/server_administration_service.ServerAdministrationService/GetAddressAndStatus"
 	ServerAdministrationService_GetServersInformation_FullMethodName = "/server_administration_service.ServerAdministrationService/GetServersInformation"
+	ServerAdministrationService_GetNotificationStatus_FullMethodName = "/server_administration_service.ServerAdministrationService/GetNotificationStatus"
+	ServerAdministrationService_GetSLACompliance_FullMethodName      = "/server_administration_service.ServerAdministrationService/GetSLACompliance"
+	ServerAdministrationService_GetGroupsInformation_FullMethodName  = "/server_administration_service.ServerAdministrationService/GetGroupsInformation"
 )
 
 // ServerAdministrationServiceClient is the client API for ServerAdministrationService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ServerAdministrationServiceClient interface {
-	GetAddressAndStatus(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error)
+	GetAddressAndStatus(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error)
 	GetServersInformation(ctx context.Context, in *TimeRequest, opts ...grpc.CallOption) (*ServersInformationResponse, error)
+	GetNotificationStatus(ctx context.Context, in *ServerIDRequest, opts ...grpc.CallOption) (*NotificationStatus, error)
+	GetSLACompliance(ctx context.Context, in *TimeRequest, opts ...grpc.CallOption) (*SLAComplianceList, error)
+	GetGroupsInformation(ctx context.Context, in *TimeRequest, opts ...grpc.CallOption) (*GroupSummaryList, error)
 }
 
 type serverAdministrationServiceClient struct {
@@ -39,7 +45,7 @@ func NewServerAdministrationServiceClient(cc grpc.ClientConnInterface) ServerAdm
 	return &serverAdministrationServiceClient{cc}
 }
 
-func (c *serverAdministrationServiceClient) GetAddressAndStatus(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error) {
+func (c *serverAdministrationServiceClient) GetAddressAndStatus(ctx context.Context, in *AddressRequest, opts ...grpc.CallOption) (*IDAddressAndStatusList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(IDAddressAndStatusList)
 	err := c.cc.Invoke(ctx, ServerAdministrationService_GetAddressAndStatus_FullMethodName, in, out, cOpts...)
@@ -59,12 +65,45 @@ func (c *serverAdministrationServiceClient) GetServersInformation(ctx context.Co
 	return out, nil
 }
 
+func (c *serverAdministrationServiceClient) GetNotificationStatus(ctx context.Context, in *ServerIDRequest, opts ...grpc.CallOption) (*NotificationStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationStatus)
+	err := c.cc.Invoke(ctx, ServerAdministrationService_GetNotificationStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverAdministrationServiceClient) GetSLACompliance(ctx context.Context, in *TimeRequest, opts ...grpc.CallOption) (*SLAComplianceList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SLAComplianceList)
+	err := c.cc.Invoke(ctx, ServerAdministrationService_GetSLACompliance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serverAdministrationServiceClient) GetGroupsInformation(ctx context.Context, in *TimeRequest, opts ...grpc.CallOption) (*GroupSummaryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GroupSummaryList)
+	err := c.cc.Invoke(ctx, ServerAdministrationService_GetGroupsInformation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServerAdministrationServiceServer is the server API for ServerAdministrationService service.
 // All implementations must embed UnimplementedServerAdministrationServiceServer
 // for forward compatibility.
 type ServerAdministrationServiceServer interface {
-	GetAddressAndStatus(context.Context, *EmptyRequest) (*IDAddressAndStatusList, error)
+	GetAddressAndStatus(context.Context, *AddressRequest) (*IDAddressAndStatusList, error)
 	GetServersInformation(context.Context, *TimeRequest) (*ServersInformationResponse, error)
+	GetNotificationStatus(context.Context, *ServerIDRequest) (*NotificationStatus, error)
+	GetSLACompliance(context.Context, *TimeRequest) (*SLAComplianceList, error)
+	GetGroupsInformation(context.Context, *TimeRequest) (*GroupSummaryList, error)
 	mustEmbedUnimplementedServerAdministrationServiceServer()
 }
 
@@ -75,12 +114,21 @@ type ServerAdministrationServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedServerAdministrationServiceServer struct{}
 
-func (UnimplementedServerAdministrationServiceServer) GetAddressAndStatus(context.Context, *EmptyRequest) (*IDAddressAndStatusList, error) {
+func (UnimplementedServerAdministrationServiceServer) GetAddressAndStatus(context.Context, *AddressRequest) (*IDAddressAndStatusList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAddressAndStatus not implemented")
 }
 func (UnimplementedServerAdministrationServiceServer) GetServersInformation(context.Context, *TimeRequest) (*ServersInformationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetServersInformation not implemented")
 }
+func (UnimplementedServerAdministrationServiceServer) GetNotificationStatus(context.Context, *ServerIDRequest) (*NotificationStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationStatus not implemented")
+}
+func (UnimplementedServerAdministrationServiceServer) GetSLACompliance(context.Context, *TimeRequest) (*SLAComplianceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLACompliance not implemented")
+}
+func (UnimplementedServerAdministrationServiceServer) GetGroupsInformation(context.Context, *TimeRequest) (*GroupSummaryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroupsInformation not implemented")
+}
 func (UnimplementedServerAdministrationServiceServer) mustEmbedUnimplementedServerAdministrationServiceServer() {
 }
 func (UnimplementedServerAdministrationServiceServer) testEmbeddedByValue() {}
@@ -104,7 +152,7 @@ func RegisterServerAdministrationServiceServer(s grpc.ServiceRegistrar, srv Serv
 }
 
 func _ServerAdministrationService_GetAddressAndStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EmptyRequest)
+	in := new(AddressRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -116,7 +164,7 @@ func _ServerAdministrationService_GetAddressAndStatus_Handler(srv interface{}, c
 		FullMethod: ServerAdministrationService_GetAddressAndStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ServerAdministrationServiceServer).GetAddressAndStatus(ctx, req.(*EmptyRequest))
+		return srv.(ServerAdministrationServiceServer).GetAddressAndStatus(ctx, req.(*AddressRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -139,6 +187,60 @@ func _ServerAdministrationService_GetServersInformation_Handler(srv interface{},
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ServerAdministrationService_GetNotificationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServerIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerAdministrationServiceServer).GetNotificationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerAdministrationService_GetNotificationStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerAdministrationServiceServer).GetNotificationStatus(ctx, req.(*ServerIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerAdministrationService_GetSLACompliance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerAdministrationServiceServer).GetSLACompliance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerAdministrationService_GetSLACompliance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerAdministrationServiceServer).GetSLACompliance(ctx, req.(*TimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ServerAdministrationService_GetGroupsInformation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServerAdministrationServiceServer).GetGroupsInformation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ServerAdministrationService_GetGroupsInformation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServerAdministrationServiceServer).GetGroupsInformation(ctx, req.(*TimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ServerAdministrationService_ServiceDesc is the grpc.ServiceDesc for ServerAdministrationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -154,6 +256,18 @@ var ServerAdministrationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetServersInformation",
 			Handler:    _ServerAdministrationService_GetServersInformation_Handler,
 		},
+		{
+			MethodName: "GetNotificationStatus",
+			Handler:    _ServerAdministrationService_GetNotificationStatus_Handler,
+		},
+		{
+			MethodName: "GetSLACompliance",
+			Handler:    _ServerAdministrationService_GetSLACompliance_Handler,
+		},
+		{
+			MethodName: "GetGroupsInformation",
+			Handler:    _ServerAdministrationService_GetGroupsInformation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/server.proto",